@@ -22,11 +22,16 @@ Options could be:
 --duration <1m|1h|1w>
 --type <egress|ingress>
 --rule <nw-rule>
+--since <unix-time>
+--until <unix-time>
+--limit <page-size>
+--pagetoken <next-page-token>
+--sortorder <asc|desc>
 EOF
 	exit 1
 }
 
-OPTS=`getopt -o s: --long req: --long source: --long containername: --long clustername: --long namespace: --long labels: --long fromsource: --long duration: --long type: --long rule: -n 'parse-options' -- "$@"`
+OPTS=`getopt -o s: --long req: --long source: --long containername: --long clustername: --long namespace: --long labels: --long fromsource: --long duration: --long type: --long rule: --long since: --long until: --long limit: --long pagetoken: --long sortorder: -n 'parse-options' -- "$@"`
 eval set -- "$OPTS"
 while true; do
     case "$1" in
@@ -40,12 +45,17 @@ while true; do
         --duration ) DURATION="$2"; shift 2;;
         --type ) TYPE="$2"; shift 2;;
         --rule ) RULE="$2"; shift 2;;
+        --since ) SINCE="$2"; shift 2;;
+        --until ) UNTIL="$2"; shift 2;;
+        --limit ) LIMIT="$2"; shift 2;;
+        --pagetoken ) PAGE_TOKEN="$2"; shift 2;;
+        --sortorder ) SORT_ORDER="$2"; shift 2;;
         -- ) shift; break ;;
         * ) break ;;
     esac
 done
 [[ "$REQUEST" == "" ]] && echo "request type [observe|dbclear] not found." && usage
 
-DATA='{"request": "'$REQUEST'", "source": "'$SOURCE'", "clusterName": "'$CLUSTER_NAME'", "namespace":"'$NAMESPACE'", "containerName":"'$CONTAINER_NAME'", "labels":"'$LABELS'", "fromSource":"'$FROM_SOURCE'", "duration":"'$DURATION'", "type": "'$TYPE'", "rule": "'$RULE'"}'
+DATA='{"request": "'$REQUEST'", "source": "'$SOURCE'", "clusterName": "'$CLUSTER_NAME'", "namespace":"'$NAMESPACE'", "containerName":"'$CONTAINER_NAME'", "labels":"'$LABELS'", "fromSource":"'$FROM_SOURCE'", "duration":"'$DURATION'", "type": "'$TYPE'", "rule": "'$RULE'", "since": "'${SINCE:-0}'", "until": "'${UNTIL:-0}'", "limit": '${LIMIT:-0}', "pageToken": "'$PAGE_TOKEN'", "sortOrder": "'$SORT_ORDER'"}'
 
 grpcurl -plaintext -d "$DATA" localhost:9089 v1.insight.Insight.GetInsightData
@@ -0,0 +1,40 @@
+#!/bin/bash
+
+## Run script command in below format"
+## ./scripts/service_graph.sh --clustername default --namespace wordpress-mysql --labels app=mysql --format dot --export
+
+usage() 
+{
+	cat << EOF2
+Usage: $0 <options>
+
+Options could be:
+--clustername <clustername>
+--namespace <namespace>
+--labels <set-of-labels> ... for e.g. --labels "xyz=123,abc=456"
+--format <json|dot|graphml>
+--export ... write the graph to the network policy dir
+EOF2
+	exit 1
+}
+
+EXPORT=false
+OPTS=`getopt -o h --long clustername: --long namespace: --long labels: --long format: --long export -n 'parse-options' -- "$@"`
+[[ $? -ne 0 ]] && usage
+eval set -- "$OPTS"
+while true; do
+    case "$1" in
+        --clustername ) CLUSTER_NAME="$2"; shift 2;;
+        --namespace ) NAMESPACE="$2"; shift 2;;
+        --labels ) LABELS="$2"; shift 2;;
+        --format ) FORMAT="$2"; shift 2;;
+        --export ) EXPORT=true; shift;;
+        -h ) usage;;
+        -- ) shift; break ;;
+        * ) break ;;
+    esac
+done
+
+DATA='{"clusterName": "'$CLUSTER_NAME'", "namespace":"'$NAMESPACE'", "labels":"'$LABELS'", "format":"'$FORMAT'", "export": '$EXPORT'}'
+
+grpcurl -plaintext -d "$DATA" localhost:9089 v1.insight.Insight.GetServiceGraph
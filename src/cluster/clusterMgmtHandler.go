@@ -7,7 +7,6 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -335,12 +334,6 @@ func GetEndpointsFromCluster(cluster types.Cluster) []types.Endpoint {
 // == Pod == //
 // ========= //
 
-var skippedLabelKeys []string = []string{
-	"pod-template-hash",                  // common k8s hash label
-	"controller-revision-hash",           // from istana robot-shop
-	"statefulset.kubernetes.io/pod-name", // from istana robot-shop
-}
-
 func GetPodsFromCluster(cluster types.Cluster) []types.Pod {
 	results := []types.Pod{}
 
@@ -400,13 +393,9 @@ func GetPodsFromCluster(cluster types.Cluster) []types.Pod {
 				continue
 			}
 
-			if libs.ContainsElement(skippedLabelKeys, key) {
-				continue
-			}
-
 			pod.Labels = append(pod.Labels, key+"="+val)
 		}
-		sort.Strings(pod.Labels)
+		pod.Labels = SelectLabels(pod.Namespace, pod.Labels)
 
 		results = append(results, pod)
 	}
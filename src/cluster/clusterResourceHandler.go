@@ -12,6 +12,15 @@ func GetPods(clusterName string) []types.Pod {
 
 	if config.GetCfgClusterInfoFrom() == "k8sclient" { // get from k8s client api
 		pods = GetPodsFromK8sClient()
+
+		// Append k8s nodes as pod type to pods, host logs are keyed by the node name
+		for _, node := range GetNodesFromK8sClient() {
+			pods = append(pods, types.Pod{
+				Namespace: types.PolicyDiscoveryNodeNamespace,
+				PodName:   node.NodeName,
+				Labels:    node.Labels,
+			})
+		}
 	} else {
 		clusterInstance := GetClusterFromClusterName(clusterName)
 		if clusterInstance.ClusterID == 0 { // cluster not onboarded
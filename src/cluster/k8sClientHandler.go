@@ -9,8 +9,9 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/accuknox/auto-policy-discovery/src/libs"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -40,6 +41,32 @@ func ConnectK8sClient() *kubernetes.Clientset {
 }
 
 func ConnectLocalAPIClient() *kubernetes.Clientset {
+	config, err := getLocalRestConfig()
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return nil
+	}
+
+	// creates the clientset
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return nil
+	}
+
+	return clientset
+}
+
+// getK8sRestConfig returns the config of the in-cluster or the local kubeconfig api client
+func getK8sRestConfig() (*rest.Config, error) {
+	if isInCluster() {
+		return getInClusterRestConfig()
+	}
+
+	return getLocalRestConfig()
+}
+
+func getLocalRestConfig() (*rest.Config, error) {
 	if !parsed {
 		homeDir := ""
 		if h := os.Getenv("HOME"); h != "" {
@@ -64,23 +91,25 @@ func ConnectLocalAPIClient() *kubernetes.Clientset {
 	}
 
 	// use the current context in kubeconfig
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	return clientcmd.BuildConfigFromFlags("", *kubeconfig)
+}
+
+func ConnectInClusterAPIClient() *kubernetes.Clientset {
+	kubeConfig, err := getInClusterRestConfig()
 	if err != nil {
 		log.Error().Msg(err.Error())
 		return nil
 	}
 
-	// creates the clientset
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
+	if client, err := kubernetes.NewForConfig(kubeConfig); err != nil {
 		log.Error().Msg(err.Error())
 		return nil
+	} else {
+		return client
 	}
-
-	return clientset
 }
 
-func ConnectInClusterAPIClient() *kubernetes.Clientset {
+func getInClusterRestConfig() (*rest.Config, error) {
 	host := ""
 	port := ""
 	token := ""
@@ -99,8 +128,7 @@ func ConnectInClusterAPIClient() *kubernetes.Clientset {
 
 	read, err := ioutil.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/token")
 	if err != nil {
-		log.Error().Msg(err.Error())
-		return nil
+		return nil, err
 	}
 
 	token = string(read)
@@ -114,12 +142,7 @@ func ConnectInClusterAPIClient() *kubernetes.Clientset {
 		},
 	}
 
-	if client, err := kubernetes.NewForConfig(kubeConfig); err != nil {
-		log.Error().Msg(err.Error())
-		return nil
-	} else {
-		return client
-	}
+	return kubeConfig, nil
 }
 
 // =============== //
@@ -152,6 +175,42 @@ func GetNamespacesFromK8sClient() []string {
 	return results
 }
 
+// ========== //
+// == Node == //
+// ========== //
+
+func GetNodesFromK8sClient() []types.Node {
+	results := []types.Node{}
+
+	client := ConnectK8sClient()
+	if client == nil {
+		return results
+	}
+
+	// get nodes from k8s api client
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return results
+	}
+
+	for _, node := range nodes.Items {
+		group := types.Node{
+			NodeName: node.Name,
+			Labels:   []string{},
+		}
+
+		for k, v := range node.Labels {
+			group.Labels = append(group.Labels, k+"="+v)
+		}
+		sort.Strings(group.Labels)
+
+		results = append(results, group)
+	}
+
+	return results
+}
+
 // ========= //
 // == Pod == //
 // ========= //
@@ -159,7 +218,177 @@ func GetNamespacesFromK8sClient() []string {
 var skipLabelKey []string = []string{
 	"pod-template-hash",                  // common k8s hash label
 	"controller-revision-hash",           // from istana robot-shop
-	"statefulset.kubernetes.io/pod-name"} // from istana robot-shop
+	"statefulset.kubernetes.io/pod-name", // from istana robot-shop
+	"controller-uid",                     // job controller labels
+	"job-name",
+	"batch.kubernetes.io/controller-uid",
+	"batch.kubernetes.io/job-name"}
+
+// NormalizeWorkloadLabels drops the controller generated labels, so that the pods of
+// the same workload share the labels across replicas and rollouts, the label policy is applied
+func NormalizeWorkloadLabels(namespace string, labels []string) []string {
+	return SelectLabels(namespace, labels)
+}
+
+// WorkloadOwner Structure
+type WorkloadOwner struct {
+	Kind   string
+	Name   string
+	Labels []string
+}
+
+func getSelectorLabels(namespace string, selector *metav1.LabelSelector) []string {
+	if selector == nil {
+		return []string{}
+	}
+
+	return selectLabelMap(namespace, selector.MatchLabels)
+}
+
+// WorkloadOwnerCacheTTL how long the workloads listed from the cluster are reused
+var WorkloadOwnerCacheTTL = 5 * time.Minute
+
+// workloadOwners the workloads of the cluster, and the owners of the pods they template
+type workloadOwners struct {
+	workloads   map[string]WorkloadOwner // key: namespace/kind/name
+	replicaSets map[string]string        // key: namespace/replicaset - val: deployment
+	templates   map[string][]string      // key: namespace/template labels - val: workload labels
+	updated     time.Time
+}
+
+var ownerCache workloadOwners
+var ownerCacheMutex = &sync.Mutex{}
+
+// getTemplateKey returns the key of the pod template labels of a workload in the namespace
+func getTemplateKey(namespace string, labels []string) string {
+	return namespace + "/" + strings.Join(labels, ",")
+}
+
+func addWorkloadOwner(owners *workloadOwners, namespace, kind, name string, selector *metav1.LabelSelector, template map[string]string) {
+	owner := WorkloadOwner{Kind: kind, Name: name, Labels: getSelectorLabels(namespace, selector)}
+	owners.workloads[namespace+"/"+kind+"/"+name] = owner
+
+	templateLabels := selectLabelMap(namespace, template)
+	if len(owner.Labels) == 0 { // selector with matchExpressions only
+		owner.Labels = templateLabels
+	}
+	owners.templates[getTemplateKey(namespace, templateLabels)] = owner.Labels
+}
+
+// getWorkloadOwners returns the workloads by namespace/kind/name, the replicaset -> deployment mapping, and
+// the workload labels of the pod template labels, the replicasets of the previous rollouts included
+func getWorkloadOwners(client *kubernetes.Clientset) workloadOwners {
+	owners := workloadOwners{
+		workloads:   map[string]WorkloadOwner{},
+		replicaSets: map[string]string{},
+		templates:   map[string][]string{},
+		updated:     time.Now(),
+	}
+
+	if deployments, err := client.AppsV1().Deployments("").List(context.Background(), metav1.ListOptions{}); err == nil {
+		for _, d := range deployments.Items {
+			addWorkloadOwner(&owners, d.Namespace, "Deployment", d.Name, d.Spec.Selector, d.Spec.Template.Labels)
+		}
+	} else {
+		log.Error().Msg(err.Error())
+	}
+
+	if statefulSets, err := client.AppsV1().StatefulSets("").List(context.Background(), metav1.ListOptions{}); err == nil {
+		for _, s := range statefulSets.Items {
+			addWorkloadOwner(&owners, s.Namespace, "StatefulSet", s.Name, s.Spec.Selector, s.Spec.Template.Labels)
+		}
+	} else {
+		log.Error().Msg(err.Error())
+	}
+
+	if daemonSets, err := client.AppsV1().DaemonSets("").List(context.Background(), metav1.ListOptions{}); err == nil {
+		for _, d := range daemonSets.Items {
+			addWorkloadOwner(&owners, d.Namespace, "DaemonSet", d.Name, d.Spec.Selector, d.Spec.Template.Labels)
+		}
+	} else {
+		log.Error().Msg(err.Error())
+	}
+
+	if rsList, err := client.AppsV1().ReplicaSets("").List(context.Background(), metav1.ListOptions{}); err == nil {
+		for i, rs := range rsList.Items {
+			owner := metav1.GetControllerOf(&rsList.Items[i])
+			if owner == nil || owner.Kind != "Deployment" {
+				continue
+			}
+			owners.replicaSets[rs.Namespace+"/"+rs.Name] = owner.Name
+
+			// the pods of an old rollout are owned by the deployment through its replicaset
+			if deployment, ok := owners.workloads[rs.Namespace+"/Deployment/"+owner.Name]; ok {
+				templateKey := getTemplateKey(rs.Namespace, selectLabelMap(rs.Namespace, rs.Spec.Template.Labels))
+				if _, ok := owners.templates[templateKey]; !ok && len(deployment.Labels) > 0 {
+					owners.templates[templateKey] = deployment.Labels
+				}
+			}
+		}
+	} else {
+		log.Error().Msg(err.Error())
+	}
+
+	return owners
+}
+
+// getCachedWorkloadOwners returns the workloads of the cluster, listed again once the cache expires
+func getCachedWorkloadOwners(client *kubernetes.Clientset) workloadOwners {
+	ownerCacheMutex.Lock()
+	defer ownerCacheMutex.Unlock()
+
+	if ownerCache.workloads == nil || time.Since(ownerCache.updated) > WorkloadOwnerCacheTTL {
+		ownerCache = getWorkloadOwners(client)
+	}
+
+	return ownerCache
+}
+
+// GetWorkloadTemplateLabels returns the labels of the owning workloads by the namespace and the pod template
+// labels, as the pods the workloads created are labeled
+func GetWorkloadTemplateLabels() map[string][]string {
+	client := ConnectK8sClient()
+	if client == nil {
+		return map[string][]string{}
+	}
+
+	return getCachedWorkloadOwners(client).templates
+}
+
+// resolveWorkloadOwner sets the owning workload of the pod, the pod labels are used as the workload labels of a job
+func resolveWorkloadOwner(pod *types.Pod, owner *metav1.OwnerReference, workloads map[string]WorkloadOwner, replicaSets map[string]string) {
+	if owner == nil {
+		return
+	}
+
+	kind, name := owner.Kind, owner.Name
+	if kind == "ReplicaSet" {
+		deployment, ok := replicaSets[pod.Namespace+"/"+name]
+		if !ok {
+			return
+		}
+		kind, name = "Deployment", deployment
+	}
+
+	if kind == "Job" {
+		pod.WorkloadKind = kind
+		pod.WorkloadName = name
+		pod.WorkloadLabels = pod.Labels
+		return
+	}
+
+	workload, ok := workloads[pod.Namespace+"/"+kind+"/"+name]
+	if !ok {
+		return
+	}
+
+	pod.WorkloadKind = workload.Kind
+	pod.WorkloadName = workload.Name
+	pod.WorkloadLabels = workload.Labels
+	if len(pod.WorkloadLabels) == 0 { // selector with matchExpressions only
+		pod.WorkloadLabels = pod.Labels
+	}
+}
 
 func GetPodsFromK8sClient() []types.Pod {
 	results := []types.Pod{}
@@ -176,22 +405,31 @@ func GetPodsFromK8sClient() []types.Pod {
 		return results
 	}
 
-	for _, pod := range pods.Items {
+	owners := getCachedWorkloadOwners(client)
+
+	for i, pod := range pods.Items {
 		group := types.Pod{
 			Namespace: pod.Namespace,
 			PodName:   pod.Name,
-			Labels:    []string{},
 		}
 
-		for k, v := range pod.Labels {
-			// skip hash or microservice default label key
-			if libs.ContainsElement(skipLabelKey, k) {
-				continue
-			}
+		// skip hash or microservice default label key, and the labels not selected by the label policy
+		group.Labels = selectLabelMap(pod.Namespace, pod.Labels)
 
-			group.Labels = append(group.Labels, k+"="+v)
+		resolveWorkloadOwner(&group, metav1.GetControllerOf(&pods.Items[i]), owners.workloads, owners.replicaSets)
+
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.Name == "" {
+					continue
+				}
+				group.NamedPorts = append(group.NamedPorts, types.ContainerPort{
+					Name:     port.Name,
+					Port:     int(port.ContainerPort),
+					Protocol: string(port.Protocol),
+				})
+			}
 		}
-		sort.Strings(group.Labels)
 
 		results = append(results, group)
 	}
@@ -3,7 +3,9 @@ package cluster
 import (
 	"testing"
 
+	"github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestGetK8sNamespaces(t *testing.T) {
@@ -41,3 +43,34 @@ func TestGetEndpoints(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalizeWorkloadLabels(t *testing.T) {
+	actual := NormalizeWorkloadLabels("default", []string{"pod-template-hash=5d8f7c", "app=cart", "controller-uid=abc"})
+
+	assert.Equal(t, []string{"app=cart"}, actual)
+}
+
+func TestResolveWorkloadOwner(t *testing.T) {
+	workloads := map[string]WorkloadOwner{
+		"default/Deployment/cart": {Kind: "Deployment", Name: "cart", Labels: []string{"app=cart"}},
+	}
+	replicaSets := map[string]string{"default/cart-5d8f7c": "cart"}
+
+	pod := types.Pod{Namespace: "default", PodName: "cart-5d8f7c-x1", Labels: []string{"app=cart", "version=v1"}}
+	resolveWorkloadOwner(&pod, &metav1.OwnerReference{Kind: "ReplicaSet", Name: "cart-5d8f7c"}, workloads, replicaSets)
+
+	assert.Equal(t, "Deployment", pod.WorkloadKind)
+	assert.Equal(t, "cart", pod.WorkloadName)
+	assert.Equal(t, []string{"app=cart"}, pod.WorkloadLabels)
+}
+
+func TestAddWorkloadOwner(t *testing.T) {
+	owners := workloadOwners{workloads: map[string]WorkloadOwner{}, templates: map[string][]string{}}
+
+	addWorkloadOwner(&owners, "default", "Deployment", "cart",
+		&metav1.LabelSelector{MatchLabels: map[string]string{"app": "cart"}},
+		map[string]string{"app": "cart", "version": "v2", "pod-template-hash": "7c9e1a"})
+
+	assert.Equal(t, []string{"app=cart"}, owners.workloads["default/Deployment/cart"].Labels)
+	assert.Equal(t, []string{"app=cart"}, owners.templates["default/app=cart,version=v2"])
+}
@@ -0,0 +1,118 @@
+package cluster
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ================== //
+// == Label Policy == //
+// ================== //
+
+// getLabelPolicy returns the label policy of the namespace, the lists the override does not set are the global ones,
+// the controller hash labels are ignored on top of the configured ones
+func getLabelPolicy(namespace string) types.LabelPolicyOverride {
+	cfgPolicy := config.GetCfgLabelPolicy()
+
+	policy := types.LabelPolicyOverride{
+		Namespace: namespace,
+		Priority:  cfgPolicy.Priority,
+		Allow:     cfgPolicy.Allow,
+		Deny:      cfgPolicy.Deny,
+		Ignore:    cfgPolicy.Ignore,
+	}
+
+	for _, override := range cfgPolicy.Overrides {
+		if override.Namespace != namespace {
+			continue
+		}
+
+		if len(override.Priority) > 0 {
+			policy.Priority = override.Priority
+		}
+		if len(override.Allow) > 0 {
+			policy.Allow = override.Allow
+		}
+		if len(override.Deny) > 0 {
+			policy.Deny = override.Deny
+		}
+		if len(override.Ignore) > 0 {
+			policy.Ignore = override.Ignore
+		}
+		break
+	}
+
+	// the controller hash labels are always ignored
+	policy.Ignore = append(append([]string{}, policy.Ignore...), skipLabelKey...)
+
+	return policy
+}
+
+// matchLabelKey returns true if the key is one of the given keys, a trailing '*' matches a prefix
+func matchLabelKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key || (strings.HasSuffix(k, "*") && strings.HasPrefix(key, strings.TrimSuffix(k, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func selectLabels(policy types.LabelPolicyOverride, labels []string) []string {
+	// the volatile and denied labels are never used
+	candidates := []string{}
+	for _, label := range labels {
+		key := strings.Split(label, "=")[0]
+		if matchLabelKey(policy.Ignore, key) || matchLabelKey(policy.Deny, key) {
+			continue
+		}
+		candidates = append(candidates, label)
+	}
+	sort.Strings(candidates)
+
+	// the app identity label of the highest priority
+	for _, priority := range policy.Priority {
+		for _, label := range candidates {
+			if strings.Split(label, "=")[0] == priority {
+				return []string{label}
+			}
+		}
+	}
+
+	if len(policy.Allow) == 0 {
+		return candidates
+	}
+
+	results := []string{}
+	for _, label := range candidates {
+		if matchLabelKey(policy.Allow, strings.Split(label, "=")[0]) {
+			results = append(results, label)
+		}
+	}
+
+	// a pod without any allowed label keeps its labels to be selectable
+	if len(results) == 0 {
+		return candidates
+	}
+
+	return results
+}
+
+// SelectLabels returns the labels of a pod in the namespace used in the selectors, the matchLabels and
+// the workload keys, as configured by the label policy
+func SelectLabels(namespace string, labels []string) []string {
+	return selectLabels(getLabelPolicy(namespace), labels)
+}
+
+// selectLabelMap returns the selected labels of a label map
+func selectLabelMap(namespace string, labelMap map[string]string) []string {
+	labels := []string{}
+	for k, v := range labelMap {
+		labels = append(labels, k+"="+v)
+	}
+
+	return SelectLabels(namespace, labels)
+}
@@ -0,0 +1,67 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectLabels(t *testing.T) {
+	labels := []string{
+		"app.kubernetes.io/name=cart",
+		"app=cart-v2",
+		"helm.sh/chart=cart-1.2.0",
+		"pod-template-hash=5d8f7c",
+		"version=v1",
+	}
+
+	// the volatile labels are dropped by default
+	policy := types.LabelPolicyOverride{Ignore: skipLabelKey}
+	assert.Equal(t, []string{"app.kubernetes.io/name=cart", "app=cart-v2", "helm.sh/chart=cart-1.2.0", "version=v1"},
+		selectLabels(policy, labels))
+
+	// denylist with a prefix
+	policy.Deny = []string{"helm.sh/*", "version"}
+	assert.Equal(t, []string{"app.kubernetes.io/name=cart", "app=cart-v2"}, selectLabels(policy, labels))
+
+	// allowlist, a pod without any allowed label keeps its labels
+	policy.Allow = []string{"app"}
+	assert.Equal(t, []string{"app=cart-v2"}, selectLabels(policy, labels))
+	assert.Equal(t, []string{"tier=web"}, selectLabels(policy, []string{"tier=web"}))
+
+	// the app identity of the highest priority
+	policy.Priority = []string{"app.kubernetes.io/name", "app", "k8s-app"}
+	assert.Equal(t, []string{"app.kubernetes.io/name=cart"}, selectLabels(policy, labels))
+	assert.Equal(t, []string{"app=cart-v2"}, selectLabels(policy, []string{"app=cart-v2", "version=v1"}))
+}
+
+func TestGetLabelPolicy(t *testing.T) {
+	cfgPolicy := config.CurrentCfg.ConfigLabelPolicy
+	defer func() { config.CurrentCfg.ConfigLabelPolicy = cfgPolicy }()
+
+	config.CurrentCfg.ConfigLabelPolicy = types.ConfigLabelPolicy{
+		Priority: []string{"app"},
+		Deny:     []string{"version"},
+		Overrides: []types.LabelPolicyOverride{
+			{Namespace: "kube-system", Priority: []string{"k8s-app"}},
+		},
+	}
+
+	labels := []string{"app=coredns", "k8s-app=kube-dns", "version=v1"}
+	assert.Equal(t, []string{"app=coredns"}, SelectLabels("default", labels))
+	assert.Equal(t, []string{"k8s-app=kube-dns"}, SelectLabels("kube-system", labels))
+
+	// the lists not overridden are the global ones
+	assert.Equal(t, []string{"tier=control-plane"}, SelectLabels("kube-system", []string{"tier=control-plane", "version=v1"}))
+	assert.Equal(t, skipLabelKey, getLabelPolicy("default").Ignore)
+
+	// an override of the ignored labels keeps the controller hash labels ignored
+	config.CurrentCfg.ConfigLabelPolicy.Overrides = append(config.CurrentCfg.ConfigLabelPolicy.Overrides,
+		types.LabelPolicyOverride{Namespace: "shop", Ignore: []string{"build"}})
+
+	assert.Equal(t, append([]string{"build"}, skipLabelKey...), getLabelPolicy("shop").Ignore)
+	assert.Equal(t, []string{"app=cart"},
+		SelectLabels("shop", []string{"app=cart", "build=1234", "pod-template-hash=5d8f7c", "controller-revision-hash=7b9c"}))
+}
@@ -0,0 +1,420 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// ==================== //
+// == Policy Applier == //
+// ==================== //
+
+// ownership label and rollback annotation of the policy objects
+const (
+	PolicyManagedByLabel = "app.kubernetes.io/managed-by"
+	PolicyManagedByValue = "knoxautopolicy"
+
+	// the spec of the version replaced by the last update
+	PolicyPreviousSpecAnnotation = "knoxautopolicy.accuknox.com/previous-spec"
+)
+
+// actions of an apply or rollback
+const (
+	PolicyActionCreated    = "created"
+	PolicyActionUpdated    = "updated"
+	PolicyActionUnchanged  = "unchanged"
+	PolicyActionRolledBack = "rolledback"
+)
+
+// policyResource the resource of a policy kind
+type policyResource struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+// policyResources the resources of the policy kinds the applier manages
+var policyResources = map[string]policyResource{
+	"CiliumNetworkPolicy": {
+		gvr:        schema.GroupVersionResource{Group: "cilium.io", Version: "v2", Resource: "ciliumnetworkpolicies"},
+		namespaced: true,
+	},
+	"CiliumClusterwideNetworkPolicy": {
+		gvr: schema.GroupVersionResource{Group: "cilium.io", Version: "v2", Resource: "ciliumclusterwidenetworkpolicies"},
+	},
+	"KubeArmorPolicy": {
+		gvr:        schema.GroupVersionResource{Group: "security.kubearmor.com", Version: "v1", Resource: "kubearmorpolicies"},
+		namespaced: true,
+	},
+	"KubeArmorHostPolicy": {
+		gvr: schema.GroupVersionResource{Group: "security.kubearmor.com", Version: "v1", Resource: "kubearmorhostpolicies"},
+	},
+}
+
+// PolicyApplier creates, updates and rolls back the policy objects through the dynamic client
+type PolicyApplier struct {
+	client dynamic.Interface
+}
+
+// NewPolicyApplier returns an applier of the client, a fake dynamic client in the tests
+func NewPolicyApplier(client dynamic.Interface) *PolicyApplier {
+	return &PolicyApplier{client: client}
+}
+
+// ConnectDynamicClient returns the dynamic client of the in-cluster or the local kubeconfig api
+func ConnectDynamicClient() dynamic.Interface {
+	config, err := getK8sRestConfig()
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return nil
+	}
+
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return nil
+	}
+
+	return client
+}
+
+func getDryRun(dryRun bool) []string {
+	if dryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
+func (a *PolicyApplier) getResource(kind, namespace string) (dynamic.ResourceInterface, error) {
+	if a.client == nil {
+		return nil, errors.New("no k8s api client to apply the policy")
+	}
+
+	resource, ok := policyResources[kind]
+	if !ok {
+		return nil, fmt.Errorf("policy kind %s is not supported", kind)
+	}
+
+	if resource.namespaced {
+		return a.client.Resource(resource.gvr).Namespace(namespace), nil
+	}
+	return a.client.Resource(resource.gvr), nil
+}
+
+func isManagedPolicy(obj *unstructured.Unstructured) bool {
+	return obj.GetLabels()[PolicyManagedByLabel] == PolicyManagedByValue
+}
+
+// toPolicyObject converts a cilium or kubearmor policy to the object of its kind,
+// only the name and namespace of the policy metadata are object metadata
+func toPolicyObject(policy interface{}) (*unstructured.Unstructured, error) {
+	raw, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := struct {
+		APIVersion string                 `json:"apiVersion"`
+		Kind       string                 `json:"kind"`
+		Metadata   map[string]string      `json:"metadata"`
+		Spec       map[string]interface{} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	if fields.Metadata["name"] == "" {
+		return nil, errors.New("the policy has no name")
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": fields.APIVersion,
+		"kind":       fields.Kind,
+		"spec":       fields.Spec,
+	}}
+	obj.SetName(fields.Metadata["name"])
+	if resource, ok := policyResources[fields.Kind]; ok && resource.namespaced {
+		obj.SetNamespace(fields.Metadata["namespace"])
+	}
+	obj.SetLabels(map[string]string{PolicyManagedByLabel: PolicyManagedByValue})
+
+	return obj, nil
+}
+
+func newPolicyApplyResult(obj *unstructured.Unstructured, action string, dryRun bool) types.PolicyApplyResult {
+	return types.PolicyApplyResult{
+		Kind:            obj.GetKind(),
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		Action:          action,
+		DryRun:          dryRun,
+		ResourceVersion: obj.GetResourceVersion(),
+	}
+}
+
+// Apply creates the object of the cilium or kubearmor policy or updates the existing one, the objects
+// not labeled as managed by the auto policy discovery are not touched, the replaced spec is kept for a rollback
+func (a *PolicyApplier) Apply(policy interface{}, dryRun bool) (types.PolicyApplyResult, error) {
+	return a.ApplyReplacing(policy, nil, dryRun)
+}
+
+// ApplyReplacing applies the policy as Apply and deletes the managed objects of its previous versions, named
+// by replaced from the nearest version, a created object keeps the spec of the nearest one for a rollback
+func (a *PolicyApplier) ApplyReplacing(policy interface{}, replaced []string, dryRun bool) (types.PolicyApplyResult, error) {
+	obj, err := toPolicyObject(policy)
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	resource, err := a.getResource(obj.GetKind(), obj.GetNamespace())
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	previousObjs := []*unstructured.Unstructured{}
+	for _, name := range replaced {
+		if name == obj.GetName() {
+			continue
+		}
+		previous, err := resource.Get(context.Background(), name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return types.PolicyApplyResult{}, err
+		}
+		if isManagedPolicy(previous) {
+			previousObjs = append(previousObjs, previous)
+		}
+	}
+
+	if len(previousObjs) > 0 {
+		previous, err := json.Marshal(previousObjs[0].Object["spec"])
+		if err != nil {
+			return types.PolicyApplyResult{}, err
+		}
+		obj.SetAnnotations(map[string]string{PolicyPreviousSpecAnnotation: string(previous)})
+	}
+
+	result, err := applyPolicyObject(resource, obj, dryRun)
+	if err != nil {
+		return result, err
+	}
+
+	for _, previous := range previousObjs {
+		if err := resource.Delete(context.Background(), previous.GetName(), metav1.DeleteOptions{DryRun: getDryRun(dryRun)}); err != nil && !apierrors.IsNotFound(err) {
+			return result, err
+		}
+		result.Replaced = append(result.Replaced, previous.GetName())
+	}
+
+	return result, nil
+}
+
+// applyPolicyObject creates the object or updates the existing managed one
+func applyPolicyObject(resource dynamic.ResourceInterface, obj *unstructured.Unstructured, dryRun bool) (types.PolicyApplyResult, error) {
+	existing, err := resource.Get(context.Background(), obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		created, err := resource.Create(context.Background(), obj, metav1.CreateOptions{DryRun: getDryRun(dryRun)})
+		if err != nil {
+			return types.PolicyApplyResult{}, err
+		}
+		return newPolicyApplyResult(created, PolicyActionCreated, dryRun), nil
+	} else if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	if !isManagedPolicy(existing) {
+		return types.PolicyApplyResult{}, fmt.Errorf("%s %s is not managed by the auto policy discovery, not updated", obj.GetKind(), obj.GetName())
+	}
+
+	// compared as json, the api returns the integers of the spec as int64
+	previous, err := json.Marshal(existing.Object["spec"])
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+	spec, err := json.Marshal(obj.Object["spec"])
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+	if bytes.Equal(previous, spec) {
+		return newPolicyApplyResult(existing, PolicyActionUnchanged, dryRun), nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Object["spec"] = obj.Object["spec"]
+	annotations := updated.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[PolicyPreviousSpecAnnotation] = string(previous)
+	updated.SetAnnotations(annotations)
+
+	updated, err = resource.Update(context.Background(), updated, metav1.UpdateOptions{DryRun: getDryRun(dryRun)})
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+	return newPolicyApplyResult(updated, PolicyActionUpdated, dryRun), nil
+}
+
+// Rollback restores the spec replaced by the last update of the policy object,
+// the current spec is kept so a second rollback undoes the first one
+func (a *PolicyApplier) Rollback(kind, namespace, name string, dryRun bool) (types.PolicyApplyResult, error) {
+	resource, err := a.getResource(kind, namespace)
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	existing, err := resource.Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	if !isManagedPolicy(existing) {
+		return types.PolicyApplyResult{}, fmt.Errorf("%s %s is not managed by the auto policy discovery, not rolled back", kind, name)
+	}
+
+	previous, ok := existing.GetAnnotations()[PolicyPreviousSpecAnnotation]
+	if !ok {
+		return types.PolicyApplyResult{}, fmt.Errorf("%s %s has no previous version", kind, name)
+	}
+
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(previous), &spec); err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	current, err := json.Marshal(existing.Object["spec"])
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	rolledBack := existing.DeepCopy()
+	rolledBack.Object["spec"] = spec
+	annotations := rolledBack.GetAnnotations()
+	annotations[PolicyPreviousSpecAnnotation] = string(current)
+	rolledBack.SetAnnotations(annotations)
+
+	rolledBack, err = resource.Update(context.Background(), rolledBack, metav1.UpdateOptions{DryRun: getDryRun(dryRun)})
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+	return newPolicyApplyResult(rolledBack, PolicyActionRolledBack, dryRun), nil
+}
+
+// GetPolicyObjectKey returns the kind, namespace and name of the object of a cilium or kubearmor policy
+func GetPolicyObjectKey(policy interface{}) (string, string, string, error) {
+	obj, err := toPolicyObject(policy)
+	if err != nil {
+		return "", "", "", err
+	}
+	return obj.GetKind(), obj.GetNamespace(), obj.GetName(), nil
+}
+
+// ========================= //
+// == Policies in Cluster == //
+// ========================= //
+
+// listUnmanagedPolicies returns the objects of the policy kind the auto policy discovery does not manage,
+// e.g. the hand-written policies, no object if the resource of the kind is not installed in the cluster
+func (a *PolicyApplier) listUnmanagedPolicies(kind string) ([]unstructured.Unstructured, error) {
+	if a.client == nil {
+		return nil, errors.New("no k8s api client to list the policies")
+	}
+
+	resource, ok := policyResources[kind]
+	if !ok {
+		return nil, fmt.Errorf("policy kind %s is not supported", kind)
+	}
+
+	list, err := a.client.Resource(resource.gvr).List(context.Background(), metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	objects := []unstructured.Unstructured{}
+	for _, obj := range list.Items {
+		if !isManagedPolicy(&obj) {
+			objects = append(objects, obj)
+		}
+	}
+
+	return objects, nil
+}
+
+// fromPolicyObject converts the policy object to a cilium or kubearmor policy,
+// only the name and namespace of the object metadata are kept
+func fromPolicyObject(obj unstructured.Unstructured, policy interface{}) error {
+	raw, err := json.Marshal(map[string]interface{}{
+		"apiVersion": obj.GetAPIVersion(),
+		"kind":       obj.GetKind(),
+		"metadata": map[string]string{
+			"name":      obj.GetName(),
+			"namespace": obj.GetNamespace(),
+		},
+		"spec": obj.Object["spec"],
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, policy)
+}
+
+// GetCiliumPolicies returns the cilium network and clusterwide policies in the cluster
+// not managed by the auto policy discovery
+func (a *PolicyApplier) GetCiliumPolicies() ([]types.CiliumNetworkPolicy, error) {
+	policies := []types.CiliumNetworkPolicy{}
+
+	for _, kind := range []string{"CiliumNetworkPolicy", "CiliumClusterwideNetworkPolicy"} {
+		objects, err := a.listUnmanagedPolicies(kind)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range objects {
+			policy := types.CiliumNetworkPolicy{}
+			if err := fromPolicyObject(obj, &policy); err != nil {
+				log.Warn().Msgf("skipping %s %s/%s: %s", kind, obj.GetNamespace(), obj.GetName(), err.Error())
+				continue
+			}
+			policies = append(policies, policy)
+		}
+	}
+
+	return policies, nil
+}
+
+// GetKubeArmorPolicies returns the kubearmor policies and host policies in the cluster
+// not managed by the auto policy discovery
+func (a *PolicyApplier) GetKubeArmorPolicies() ([]types.KubeArmorPolicy, error) {
+	policies := []types.KubeArmorPolicy{}
+
+	for _, kind := range []string{"KubeArmorPolicy", "KubeArmorHostPolicy"} {
+		objects, err := a.listUnmanagedPolicies(kind)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range objects {
+			policy := types.KubeArmorPolicy{}
+			if err := fromPolicyObject(obj, &policy); err != nil {
+				log.Warn().Msgf("skipping %s %s/%s: %s", kind, obj.GetNamespace(), obj.GetName(), err.Error())
+				continue
+			}
+			policies = append(policies, policy)
+		}
+	}
+
+	return policies, nil
+}
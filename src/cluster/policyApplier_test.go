@@ -0,0 +1,309 @@
+package cluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// fakeDynamicClient an in-memory dynamic client of the policy objects,
+// only the get, create, update and delete the applier uses are implemented
+type fakeDynamicClient struct {
+	objects map[string]*unstructured.Unstructured
+}
+
+type fakeResource struct {
+	dynamic.NamespaceableResourceInterface
+	client    *fakeDynamicClient
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+func (c *fakeDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &fakeResource{client: c, gvr: gvr}
+}
+
+func (r *fakeResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &fakeResource{client: r.client, gvr: r.gvr, namespace: namespace}
+}
+
+func (r *fakeResource) key(name string) string {
+	return r.gvr.Resource + "/" + r.namespace + "/" + name
+}
+
+func (r *fakeResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	obj, ok := r.client.objects[r.key(name)]
+	if !ok {
+		return nil, apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (r *fakeResource) Create(ctx context.Context, obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if _, ok := r.client.objects[r.key(obj.GetName())]; ok {
+		return nil, apierrors.NewAlreadyExists(r.gvr.GroupResource(), obj.GetName())
+	}
+	created := obj.DeepCopy()
+	created.SetResourceVersion("1")
+	if len(options.DryRun) == 0 {
+		r.client.objects[r.key(obj.GetName())] = created
+	}
+	return created.DeepCopy(), nil
+}
+
+func (r *fakeResource) Update(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if _, ok := r.client.objects[r.key(obj.GetName())]; !ok {
+		return nil, apierrors.NewNotFound(r.gvr.GroupResource(), obj.GetName())
+	}
+	updated := obj.DeepCopy()
+	updated.SetResourceVersion(updated.GetResourceVersion() + "1")
+	if len(options.DryRun) == 0 {
+		r.client.objects[r.key(obj.GetName())] = updated
+	}
+	return updated.DeepCopy(), nil
+}
+
+func (r *fakeResource) Delete(ctx context.Context, name string, options metav1.DeleteOptions, subresources ...string) error {
+	if _, ok := r.client.objects[r.key(name)]; !ok {
+		return apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+	if len(options.DryRun) == 0 {
+		delete(r.client.objects, r.key(name))
+	}
+	return nil
+}
+
+func (r *fakeResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	for key, obj := range r.client.objects {
+		if strings.HasPrefix(key, r.gvr.Resource+"/") {
+			list.Items = append(list.Items, *obj.DeepCopy())
+		}
+	}
+	return list, nil
+}
+
+func newFakePolicyApplier(objects ...*unstructured.Unstructured) (*PolicyApplier, *fakeDynamicClient) {
+	client := &fakeDynamicClient{objects: map[string]*unstructured.Unstructured{}}
+	for _, obj := range objects {
+		resource := client.Resource(policyResources[obj.GetKind()].gvr).Namespace(obj.GetNamespace()).(*fakeResource)
+		client.objects[resource.key(obj.GetName())] = obj
+	}
+	return NewPolicyApplier(client), client
+}
+
+func newTestCiliumPolicy(cidr string) types.CiliumNetworkPolicy {
+	return types.CiliumNetworkPolicy{
+		APIVersion: "cilium.io/v2",
+		Kind:       "CiliumNetworkPolicy",
+		Metadata: map[string]string{
+			"name":      "autopol-egress-default-web-1a2b3c4d",
+			"namespace": "default",
+		},
+		Spec: types.CiliumSpec{
+			EndpointSelector: types.Selector{
+				MatchLabels: map[string]string{"app": "web"},
+			},
+			Egress: []types.CiliumEgress{
+				{ToCIDRs: []string{cidr}},
+			},
+		},
+	}
+}
+
+func TestPolicyApplierApplyAndRollback(t *testing.T) {
+	applier, client := newFakePolicyApplier()
+	resource := client.Resource(policyResources["CiliumNetworkPolicy"].gvr).Namespace("default")
+
+	result, err := applier.Apply(newTestCiliumPolicy("10.0.0.1/32"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyActionCreated, result.Action)
+	assert.Equal(t, "default", result.Namespace)
+
+	obj, err := resource.Get(context.Background(), "autopol-egress-default-web-1a2b3c4d", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyManagedByValue, obj.GetLabels()[PolicyManagedByLabel])
+
+	result, err = applier.Apply(newTestCiliumPolicy("10.0.0.1/32"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyActionUnchanged, result.Action)
+
+	result, err = applier.Apply(newTestCiliumPolicy("10.0.0.2/32"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyActionUpdated, result.Action)
+
+	obj, _ = resource.Get(context.Background(), "autopol-egress-default-web-1a2b3c4d", metav1.GetOptions{})
+	cidrs, _, _ := unstructured.NestedSlice(obj.Object, "spec", "egress")
+	assert.Equal(t, []interface{}{"10.0.0.2/32"}, cidrs[0].(map[string]interface{})["toCIDR"])
+	assert.Contains(t, obj.GetAnnotations()[PolicyPreviousSpecAnnotation], "10.0.0.1/32")
+
+	result, err = applier.Rollback("CiliumNetworkPolicy", "default", "autopol-egress-default-web-1a2b3c4d", false)
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyActionRolledBack, result.Action)
+
+	obj, _ = resource.Get(context.Background(), "autopol-egress-default-web-1a2b3c4d", metav1.GetOptions{})
+	cidrs, _, _ = unstructured.NestedSlice(obj.Object, "spec", "egress")
+	assert.Equal(t, []interface{}{"10.0.0.1/32"}, cidrs[0].(map[string]interface{})["toCIDR"])
+}
+
+func TestPolicyApplierApplyReplacing(t *testing.T) {
+	applier, client := newFakePolicyApplier()
+	resource := client.Resource(policyResources["CiliumNetworkPolicy"].gvr).Namespace("default")
+
+	_, err := applier.Apply(newTestCiliumPolicy("10.0.0.1/32"), false)
+	assert.NoError(t, err)
+
+	// the newer version of the policy of the workload
+	newer := newTestCiliumPolicy("10.0.0.2/32")
+	newer.Metadata["name"] = "autopol-egress-default-web-1a2b3c4d-2"
+
+	result, err := applier.ApplyReplacing(newer, []string{"autopol-egress-default-web-1a2b3c4d"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"autopol-egress-default-web-1a2b3c4d"}, result.Replaced)
+	_, err = resource.Get(context.Background(), "autopol-egress-default-web-1a2b3c4d", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	result, err = applier.ApplyReplacing(newer, []string{"autopol-egress-default-web-1a2b3c4d"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyActionCreated, result.Action)
+	assert.Equal(t, []string{"autopol-egress-default-web-1a2b3c4d"}, result.Replaced)
+
+	_, err = resource.Get(context.Background(), "autopol-egress-default-web-1a2b3c4d", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// the previous version is restored by a rollback
+	_, err = applier.Rollback("CiliumNetworkPolicy", "default", "autopol-egress-default-web-1a2b3c4d-2", false)
+	assert.NoError(t, err)
+	obj, _ := resource.Get(context.Background(), "autopol-egress-default-web-1a2b3c4d-2", metav1.GetOptions{})
+	cidrs, _, _ := unstructured.NestedSlice(obj.Object, "spec", "egress")
+	assert.Equal(t, []interface{}{"10.0.0.1/32"}, cidrs[0].(map[string]interface{})["toCIDR"])
+}
+
+func newTestKubeArmorPolicy(name, path string) types.KubeArmorPolicy {
+	return types.KubeArmorPolicy{
+		APIVersion: "security.kubearmor.com/v1",
+		Kind:       "KubeArmorPolicy",
+		Metadata: map[string]string{
+			"name":      name,
+			"namespace": "default",
+		},
+		Spec: types.KnoxSystemSpec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "web"}},
+			File:     types.KnoxSys{MatchPaths: []types.KnoxMatchPaths{{Path: path}}},
+			Action:   "Allow",
+		},
+	}
+}
+
+func TestPolicyApplierApplyReplacingKubeArmor(t *testing.T) {
+	applier, client := newFakePolicyApplier()
+	resource := client.Resource(policyResources["KubeArmorPolicy"].gvr).Namespace("default")
+
+	_, err := applier.Apply(newTestKubeArmorPolicy("autopol-system-default-web-1a2b3c4d", "/etc/app.conf"), false)
+	assert.NoError(t, err)
+
+	// the newer version of the policy of the workload
+	newer := newTestKubeArmorPolicy("autopol-system-default-web-1a2b3c4d-2", "/etc/app.yaml")
+
+	result, err := applier.ApplyReplacing(newer, []string{"autopol-system-default-web-1a2b3c4d"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"autopol-system-default-web-1a2b3c4d"}, result.Replaced)
+	_, err = resource.Get(context.Background(), "autopol-system-default-web-1a2b3c4d", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	result, err = applier.ApplyReplacing(newer, []string{"autopol-system-default-web-1a2b3c4d"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyActionCreated, result.Action)
+	assert.Equal(t, []string{"autopol-system-default-web-1a2b3c4d"}, result.Replaced)
+
+	_, err = resource.Get(context.Background(), "autopol-system-default-web-1a2b3c4d", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// the previous version is restored by a rollback
+	_, err = applier.Rollback("KubeArmorPolicy", "default", "autopol-system-default-web-1a2b3c4d-2", false)
+	assert.NoError(t, err)
+	obj, _ := resource.Get(context.Background(), "autopol-system-default-web-1a2b3c4d-2", metav1.GetOptions{})
+	paths, _, _ := unstructured.NestedSlice(obj.Object, "spec", "file", "matchPaths")
+	assert.Equal(t, "/etc/app.conf", paths[0].(map[string]interface{})["path"])
+}
+
+func TestPolicyApplierNotManaged(t *testing.T) {
+	existing := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cilium.io/v2",
+		"kind":       "CiliumNetworkPolicy",
+		"spec":       map[string]interface{}{},
+	}}
+	existing.SetName("autopol-egress-default-web-1a2b3c4d")
+	existing.SetNamespace("default")
+
+	applier, _ := newFakePolicyApplier(existing)
+
+	_, err := applier.Apply(newTestCiliumPolicy("10.0.0.1/32"), false)
+	assert.Error(t, err)
+
+	_, err = applier.Rollback("CiliumNetworkPolicy", "default", "autopol-egress-default-web-1a2b3c4d", false)
+	assert.Error(t, err)
+}
+
+func TestPolicyApplierHostPolicy(t *testing.T) {
+	applier, _ := newFakePolicyApplier()
+
+	result, err := applier.Apply(types.KubeArmorPolicy{
+		APIVersion: "security.kubearmor.com/v1",
+		Kind:       "KubeArmorHostPolicy",
+		Metadata: map[string]string{
+			"name":        "autopol-process-node-1a2b3c4d",
+			"namespace":   types.PolicyDiscoveryNodeNamespace,
+			"clusterName": "default",
+		},
+	}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyActionCreated, result.Action)
+	// the host policies are not namespaced
+	assert.Empty(t, result.Namespace)
+	assert.True(t, result.DryRun)
+}
+
+func TestGetCiliumPolicies(t *testing.T) {
+	handWritten := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cilium.io/v2",
+		"kind":       "CiliumNetworkPolicy",
+		"metadata": map[string]interface{}{
+			"name":      "allow-dns",
+			"namespace": "default",
+			"labels":    map[string]interface{}{"team": "platform"},
+		},
+		"spec": map[string]interface{}{
+			"endpointSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+			"egress": []interface{}{
+				map[string]interface{}{"toEntities": []interface{}{"kube-apiserver"}},
+			},
+		},
+	}}
+
+	applier, _ := newFakePolicyApplier(handWritten)
+
+	// the managed policy is not listed
+	_, err := applier.Apply(newTestCiliumPolicy("10.0.0.1/32"), false)
+	assert.NoError(t, err)
+
+	policies, err := applier.GetCiliumPolicies()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(policies))
+	assert.Equal(t, "allow-dns", policies[0].Metadata["name"])
+	assert.Equal(t, "default", policies[0].Metadata["namespace"])
+	assert.Equal(t, map[string]string{"app": "web"}, policies[0].Spec.EndpointSelector.MatchLabels)
+	assert.Equal(t, []string{"kube-apiserver"}, policies[0].Spec.Egress[0].ToEntities)
+
+	kubePolicies, err := applier.GetKubeArmorPolicies()
+	assert.NoError(t, err)
+	assert.Empty(t, kubePolicies)
+}
@@ -72,6 +72,32 @@ func LoadConfigDB() types.ConfigDB {
 	return cfgDB
 }
 
+func getObsDBConfigString(key, fallback string) string {
+	if viper.IsSet("observability.database." + key) {
+		return viper.GetString("observability.database." + key)
+	}
+	return fallback
+}
+
+// LoadConfigObservabilityDB loads the observability store config, the keys not set
+// fall back to the policy database, and the sqlite path to observability.dbname
+func LoadConfigObservabilityDB() types.ConfigDB {
+	policyDB := LoadConfigDB()
+
+	return types.ConfigDB{
+		DBDriver:        getObsDBConfigString("driver", policyDB.DBDriver),
+		DBHost:          getObsDBConfigString("host", policyDB.DBHost),
+		DBPort:          getObsDBConfigString("port", policyDB.DBPort),
+		DBUser:          getObsDBConfigString("user", policyDB.DBUser),
+		DBPass:          getObsDBConfigString("password", policyDB.DBPass),
+		DBName:          getObsDBConfigString("dbname", policyDB.DBName),
+		SQLiteDBPath:    getObsDBConfigString("sqlite-db-path", viper.GetString("observability.dbname")),
+		MaxOpenConns:    viper.GetInt("observability.database.max-open-conns"),
+		MaxIdleConns:    viper.GetInt("observability.database.max-idle-conns"),
+		ConnMaxLifetime: viper.GetString("observability.database.conn-max-lifetime"),
+	}
+}
+
 func LoadConfigCiliumHubble() types.ConfigCiliumHubble {
 	cfgHubble := types.ConfigCiliumHubble{}
 
@@ -141,6 +167,18 @@ func LoadConfigFromFile() {
 		NetPolicyL7Level: 1,
 
 		NetSkipCertVerification: viper.GetBool("application.network.skip-cert-verification"),
+
+		NetClusterPolicySeed: viper.GetBool("application.network.cluster-policy-seed"),
+
+		NetRuleAgingDays: viper.GetInt("application.network.rule-aging-days"),
+
+		NetPortRangeMinPorts: viper.GetInt("application.network.port-range.min-ports"),
+		NetPortRangeMaxGap:   viper.GetInt("application.network.port-range.max-gap"),
+		NetNamedPorts:        viper.GetBool("application.network.named-ports"),
+
+		NetFQDNPatternThreshold: viper.GetInt("application.network.fqdn-pattern-threshold"),
+
+		NetClusterWideMinWorkloads: viper.GetInt("application.network.cluster-wide.min-workloads"),
 	}
 
 	var ns, notNs []string
@@ -167,6 +205,7 @@ func LoadConfigFromFile() {
 		SystemLogFile:    viper.GetString("application.system.system-log-file"),
 		SystemPolicyTo:   viper.GetString("application.system.system-policy-to"),
 		SystemPolicyDir:  viper.GetString("application.system.system-policy-dir"),
+		HostPolicyDir:    viper.GetString("application.system.host-policy-dir"),
 		SysPolicyTypes:   viper.GetInt("application.system.system-policy-types"),
 		DeprecateOldMode: viper.GetBool("application.system.deprecate-old-mode"),
 
@@ -174,6 +213,16 @@ func LoadConfigFromFile() {
 
 		ProcessFromSource: true,
 		FileFromSource:    true,
+
+		LearningMinObservationTime: viper.GetString("application.system.learning.min-observation-time"),
+		LearningStableIntervals:    viper.GetInt("application.system.learning.stable-intervals"),
+		LearningCandidateIntervals: viper.GetInt("application.system.learning.candidate-intervals"),
+
+		DefaultDenyAudit: viper.GetBool("application.system.default-deny-audit"),
+
+		SysClusterPolicySeed: viper.GetBool("application.system.cluster-policy-seed"),
+
+		SysRuleAgingDays: viper.GetInt("application.system.rule-aging-days"),
 	}
 
 	// load cluster resource info
@@ -186,8 +235,66 @@ func LoadConfigFromFile() {
 		Enable:              viper.GetBool("observability.enable"),
 		CronJobTimeInterval: "@every " + viper.GetString("observability.cron-job-time-interval"),
 		DBName:              viper.GetString("observability.dbname"),
+		ExportDir:           viper.GetString("observability.export-dir"),
 		SysObservability:    viper.GetBool("observability.system-observability"),
 		NetObservability:    viper.GetBool("observability.network-observability"),
+		Database:            LoadConfigObservabilityDB(),
+		Anomaly: types.ConfigObsAnomaly{
+			Enable:           viper.GetBool("observability.anomaly.enable"),
+			PublisherDriver:  viper.GetString("observability.anomaly.publisher.driver"),
+			PublisherServers: viper.GetStringSlice("observability.anomaly.publisher.servers"),
+			PublisherTopic:   viper.GetString("observability.anomaly.publisher.topic"),
+		},
+		Retention: types.ConfigObsRetention{
+			Enable:              viper.GetBool("observability.retention.enable"),
+			CronJobTimeInterval: "@every " + viper.GetString("observability.retention.cron-job-time-interval"),
+			MaxAge:              viper.GetString("observability.retention.max-age"),
+			MaxRows:             viper.GetInt("observability.retention.max-rows"),
+			HourlyRollupAfter:   viper.GetString("observability.retention.compaction.hourly-after"),
+			DailyRollupAfter:    viper.GetString("observability.retention.compaction.daily-after"),
+			Vacuum:              viper.GetBool("observability.retention.vacuum"),
+		},
+	}
+
+	CurrentCfg.ConfigObservability.Retention.NamespaceMaxRows = []types.NamespaceMaxRows{}
+	if err := viper.UnmarshalKey("observability.retention.namespace-max-rows", &CurrentCfg.ConfigObservability.Retention.NamespaceMaxRows); err != nil {
+		CurrentCfg.ConfigObservability.Retention.NamespaceMaxRows = []types.NamespaceMaxRows{}
+	}
+
+	CurrentCfg.ConfigSysPolicy.NoisePathRules = []types.NoisePathRule{}
+	if err := viper.UnmarshalKey("application.system.noise-paths.rules", &CurrentCfg.ConfigSysPolicy.NoisePathRules); err != nil {
+		CurrentCfg.ConfigSysPolicy.NoisePathRules = []types.NoisePathRule{}
+	}
+
+	CurrentCfg.ConfigSysPolicy.NoisePathOverrides = []types.NoisePathOverride{}
+	if err := viper.UnmarshalKey("application.system.noise-paths.overrides", &CurrentCfg.ConfigSysPolicy.NoisePathOverrides); err != nil {
+		CurrentCfg.ConfigSysPolicy.NoisePathOverrides = []types.NoisePathOverride{}
+	}
+
+	CurrentCfg.ConfigTelemetry = types.ConfigTelemetry{
+		Enable:             viper.GetBool("telemetry.otlp.enable"),
+		Endpoint:           viper.GetString("telemetry.otlp.endpoint"),
+		Headers:            viper.GetStringMapString("telemetry.otlp.headers"),
+		ServiceName:        viper.GetString("telemetry.otlp.service-name"),
+		ExportTimeInterval: "@every " + viper.GetString("telemetry.otlp.export-time-interval"),
+	}
+
+	CurrentCfg.ConfigLabelPolicy = types.ConfigLabelPolicy{
+		Priority: viper.GetStringSlice("application.label-policy.priority"),
+		Allow:    viper.GetStringSlice("application.label-policy.allow"),
+		Deny:     viper.GetStringSlice("application.label-policy.deny"),
+		Ignore:   viper.GetStringSlice("application.label-policy.ignore"),
+	}
+
+	CurrentCfg.ConfigLabelPolicy.Overrides = []types.LabelPolicyOverride{}
+	if err := viper.UnmarshalKey("application.label-policy.overrides", &CurrentCfg.ConfigLabelPolicy.Overrides); err != nil {
+		CurrentCfg.ConfigLabelPolicy.Overrides = []types.LabelPolicyOverride{}
+	}
+
+	// load policy templates
+	CurrentCfg.ConfigPolicyTemplates = []types.PolicyTemplate{}
+	if err := viper.UnmarshalKey("application.policy-templates", &CurrentCfg.ConfigPolicyTemplates); err != nil {
+		CurrentCfg.ConfigPolicyTemplates = []types.PolicyTemplate{}
 	}
 
 	// load database
@@ -310,6 +417,34 @@ func GetCfgNetworkSkipCertVerification() bool {
 	return CurrentCfg.ConfigNetPolicy.NetSkipCertVerification
 }
 
+func GetCfgNetworkClusterPolicySeed() bool {
+	return CurrentCfg.ConfigNetPolicy.NetClusterPolicySeed
+}
+
+func GetCfgNetworkRuleAgingDays() int {
+	return CurrentCfg.ConfigNetPolicy.NetRuleAgingDays
+}
+
+func GetCfgNetworkPortRangeMinPorts() int {
+	return CurrentCfg.ConfigNetPolicy.NetPortRangeMinPorts
+}
+
+func GetCfgNetworkPortRangeMaxGap() int {
+	return CurrentCfg.ConfigNetPolicy.NetPortRangeMaxGap
+}
+
+func GetCfgNetworkNamedPorts() bool {
+	return CurrentCfg.ConfigNetPolicy.NetNamedPorts
+}
+
+func GetCfgNetworkFQDNPatternThreshold() int {
+	return CurrentCfg.ConfigNetPolicy.NetFQDNPatternThreshold
+}
+
+func GetCfgNetworkClusterWideMinWorkloads() int {
+	return CurrentCfg.ConfigNetPolicy.NetClusterWideMinWorkloads
+}
+
 // ============================ //
 // == Get System Config Info == //
 // ============================ //
@@ -356,6 +491,10 @@ func GetCfgSystemPolicyDir() string {
 	return CurrentCfg.ConfigSysPolicy.SystemPolicyDir
 }
 
+func GetCfgHostPolicyDir() string {
+	return CurrentCfg.ConfigSysPolicy.HostPolicyDir
+}
+
 func GetCfgSystemkPolicyTypes() int {
 	return CurrentCfg.ConfigSysPolicy.SysPolicyTypes
 }
@@ -372,6 +511,38 @@ func GetCfgSystemFileFromSource() bool {
 	return CurrentCfg.ConfigSysPolicy.FileFromSource
 }
 
+func GetCfgSystemLearningMinObservationTime() string {
+	return CurrentCfg.ConfigSysPolicy.LearningMinObservationTime
+}
+
+func GetCfgSystemLearningStableIntervals() int {
+	return CurrentCfg.ConfigSysPolicy.LearningStableIntervals
+}
+
+func GetCfgSystemLearningCandidateIntervals() int {
+	return CurrentCfg.ConfigSysPolicy.LearningCandidateIntervals
+}
+
+func GetCfgSystemDefaultDenyAudit() bool {
+	return CurrentCfg.ConfigSysPolicy.DefaultDenyAudit
+}
+
+func GetCfgSystemClusterPolicySeed() bool {
+	return CurrentCfg.ConfigSysPolicy.SysClusterPolicySeed
+}
+
+func GetCfgSystemRuleAgingDays() int {
+	return CurrentCfg.ConfigSysPolicy.SysRuleAgingDays
+}
+
+func GetCfgSystemNoisePathRules() []types.NoisePathRule {
+	return CurrentCfg.ConfigSysPolicy.NoisePathRules
+}
+
+func GetCfgSystemNoisePathOverrides() []types.NoisePathOverride {
+	return CurrentCfg.ConfigSysPolicy.NoisePathOverrides
+}
+
 // ============================= //
 // == Get Cluster Config Info == //
 // ============================= //
@@ -384,6 +555,10 @@ func GetCfgClusterMgmtURL() string {
 	return CurrentCfg.ConfigClusterMgmt.ClusterMgmtURL
 }
 
+func GetCfgLabelPolicy() types.ConfigLabelPolicy {
+	return CurrentCfg.ConfigLabelPolicy
+}
+
 // ============================ //
 // == Get Observability Info == //
 // ============================ //
@@ -400,6 +575,10 @@ func GetCfgObservabilityDBName() string {
 	return CurrentCfg.ConfigObservability.DBName
 }
 
+func GetCfgObservabilityExportDir() string {
+	return CurrentCfg.ConfigObservability.ExportDir
+}
+
 func GetCfgObservabilitySysObsStatus() bool {
 	return CurrentCfg.ConfigObservability.SysObservability
 }
@@ -407,3 +586,31 @@ func GetCfgObservabilitySysObsStatus() bool {
 func GetCfgObservabilityNetObsStatus() bool {
 	return CurrentCfg.ConfigObservability.NetObservability
 }
+
+func GetCfgObservabilityRetention() types.ConfigObsRetention {
+	return CurrentCfg.ConfigObservability.Retention
+}
+
+func GetCfgObservabilityDB() types.ConfigDB {
+	return CurrentCfg.ConfigObservability.Database
+}
+
+func GetCfgObservabilityAnomaly() types.ConfigObsAnomaly {
+	return CurrentCfg.ConfigObservability.Anomaly
+}
+
+// ======================== //
+// == Get Telemetry Info == //
+// ======================== //
+
+func GetCfgTelemetry() types.ConfigTelemetry {
+	return CurrentCfg.ConfigTelemetry
+}
+
+// ============================== //
+// == Get Policy Template Info == //
+// ============================== //
+
+func GetCfgPolicyTemplates() []types.PolicyTemplate {
+	return CurrentCfg.ConfigPolicyTemplates
+}
@@ -26,6 +26,12 @@ application:
     system-log-file: "./log.json"
     system-policy-to: "db|file"
     system-policy-dir: "./"
+  policy-templates:
+    - namespace: "default"
+      labels: ["app=checkout"]
+      action: "Audit"
+      severity: 5
+      tags: ["PCI-DSS"]
   cluster:
     #accuknox-cluster-mgmt: "http://cluster-management-service.accuknox-dev-cluster-mgmt.svc.cluster.local/cm"
     cluster-mgmt: "http://localhost:8080"
@@ -151,6 +157,9 @@ func TestLoadDefaultConfig(t *testing.T) {
 	assert.NotEmpty(t, CurrentCfg.ConfigSysPolicy.SystemLogFile, "System log file should not be empty")
 	assert.NotEmpty(t, CurrentCfg.ConfigSysPolicy.SystemPolicyTo, "System policy to should not be empty")
 	assert.NotEmpty(t, CurrentCfg.ConfigSysPolicy.SystemPolicyDir, "System policy dir should not be empty")
+
+	assert.Equal(t, 1, len(CurrentCfg.ConfigPolicyTemplates), "Policy templates should be loaded")
+	assert.Equal(t, "Audit", CurrentCfg.ConfigPolicyTemplates[0].Action, "Policy template action should be \"Audit\"")
 }
 
 func TestSetLogFile(t *testing.T) {
@@ -158,3 +167,21 @@ func TestSetLogFile(t *testing.T) {
 
 	assert.Equal(t, CurrentCfg.ConfigNetPolicy.NetworkLogFile, "test_log.log", "network log file should be \"test_log.log\"")
 }
+
+func TestLoadConfigObservabilityDB(t *testing.T) {
+	initMockYaml()
+
+	cfg := LoadConfigObservabilityDB()
+
+	assert.Equal(t, "mysql", cfg.DBDriver, "Observability DB driver should fall back to the policy DB")
+	assert.Equal(t, "networkflowdb", cfg.DBName, "Observability DB name should fall back to the policy DB")
+
+	viper.Set("observability.database.dbname", "observabilitydb")
+	viper.Set("observability.database.max-open-conns", 16)
+
+	cfg = LoadConfigObservabilityDB()
+
+	assert.Equal(t, "observabilitydb", cfg.DBName, "Observability DB name should be \"observabilitydb\"")
+	assert.Equal(t, "127.0.0.1", cfg.DBHost, "Observability DB host should fall back to the policy DB")
+	assert.Equal(t, 16, cfg.MaxOpenConns, "Observability DB max open conns should be 16")
+}
@@ -420,6 +420,7 @@ func (cfc *KnoxFeedConsumer) processSystemLogMessage(message []byte) error {
 					Resource:      syslog.Resource,
 					Data:          syslog.Data,
 					Result:        syslog.Result,
+					Type:          syslog.Type,
 				}
 
 				knoxLog, err := plugin.ConvertKubeArmorLogToKnoxSystemLog(&log)
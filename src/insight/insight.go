@@ -1,6 +1,11 @@
 package insight
 
 import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
 	ipb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/insight"
 	types "github.com/accuknox/auto-policy-discovery/src/types"
 )
@@ -17,8 +22,10 @@ func populateAggregatedResp(sysInsightData []ipb.SystemInsightData, netInsightDa
 			for locindex := range response.Res {
 				if response.Res[locindex].ClusterName == sysdata.ClusterName && response.Res[locindex].NameSpace == sysdata.Namespace && response.Res[locindex].Labels == sysdata.Labels {
 					response.Res[locindex].SystemResource = append(response.Res[locindex].SystemResource, &ipb.SystemInsightData{
-						ContainerName: sysdata.ContainerName,
-						SysResource:   sysdata.SysResource})
+						ContainerName:   sysdata.ContainerName,
+						SysResource:     sysdata.SysResource,
+						LearningState:   sysdata.LearningState,
+						StableIntervals: sysdata.StableIntervals})
 					break
 				} else {
 					idx++
@@ -53,23 +60,84 @@ func populateAggregatedResp(sysInsightData []ipb.SystemInsightData, netInsightDa
 	return response, nil
 }
 
+// getAllInsightData aggregates a page of the system workloads and a page of the network policies, the page token
+// holds the offset of the next page of each source
 func getAllInsightData(req types.InsightRequest) (ipb.Response, error) {
-
-	sysData, err := GetSysInsightData(req)
+	offsets, err := decodeInsightPageToken(req.PageToken, 2)
 	if err != nil {
 		return ipb.Response{}, err
 	}
 
-	netData, err := GetNetInsightData(req)
-	if err != nil {
-		return ipb.Response{}, err
+	var sysData []ipb.SystemInsightData
+	sysOffset := -1
+	if offsets[0] >= 0 {
+		if sysData, sysOffset, err = GetSysInsightData(req, offsets[0]); err != nil {
+			return ipb.Response{}, err
+		}
+	}
+
+	var netData []ipb.NetworkInsightData
+	netOffset := -1
+	if offsets[1] >= 0 {
+		if netData, netOffset, err = GetNetInsightData(req, offsets[1]); err != nil {
+			return ipb.Response{}, err
+		}
 	}
 
 	insightResponse, err := populateAggregatedResp(sysData, netData)
+	insightResponse.NextPageToken = encodeInsightPageToken(sysOffset, netOffset)
 
 	return insightResponse, err
 }
 
+func getInsightKey(fields ...string) string {
+	return strings.Join(fields, types.RecordSeparator)
+}
+
+// encodeInsightPageToken encodes the offset of the next page of each source, -1 if the source has no next page,
+// the token is empty if none of the sources has
+func encodeInsightPageToken(offsets ...int) string {
+	values := []string{}
+	last := true
+	for _, offset := range offsets {
+		if offset >= 0 {
+			last = false
+		}
+		values = append(values, strconv.Itoa(offset))
+	}
+
+	if last {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(values, ":")))
+}
+
+// decodeInsightPageToken decodes the offsets of the next page of the count sources, the first page starts at 0
+func decodeInsightPageToken(token string, count int) ([]int, error) {
+	offsets := make([]int, count)
+	if token == "" {
+		return offsets, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("invalid page token")
+	}
+
+	values := strings.Split(string(b), ":")
+	if len(values) != count {
+		return nil, errors.New("invalid page token")
+	}
+
+	for idx, value := range values {
+		if offsets[idx], err = strconv.Atoi(value); err != nil || offsets[idx] < -1 {
+			return nil, errors.New("invalid page token")
+		}
+	}
+
+	return offsets, nil
+}
+
 func GetInsightData(req types.InsightRequest) (ipb.Response, error) {
 
 	if req.Source == "system" {
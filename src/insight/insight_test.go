@@ -0,0 +1,54 @@
+package insight
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	sys "github.com/accuknox/auto-policy-discovery/src/systempolicy"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsightPageToken(t *testing.T) {
+	offsets, err := decodeInsightPageToken("", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 0}, offsets)
+
+	offsets, err = decodeInsightPageToken(encodeInsightPageToken(20, -1), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{20, -1}, offsets)
+
+	// none of the sources has a next page
+	assert.Empty(t, encodeInsightPageToken(-1, -1))
+
+	_, err = decodeInsightPageToken(encodeInsightPageToken(20), 2)
+	assert.Error(t, err)
+	_, err = decodeInsightPageToken("!", 1)
+	assert.Error(t, err)
+}
+
+func TestGetWorkloadPage(t *testing.T) {
+	_, mock := libs.NewMock()
+	defer func() { libs.MockDB = nil }()
+
+	sys.CfgDB = types.ConfigDB{DBDriver: "mysql"}
+	defer func() { sys.CfgDB = types.ConfigDB{} }()
+
+	mock.ExpectQuery("^SELECT DISTINCT clusterName,namespace,labels,containerName FROM workload_process_fileset "+
+		"WHERE namespace = \\? and updatedTime >= \\? "+
+		"ORDER BY clusterName DESC, namespace DESC, labels DESC, containerName DESC LIMIT 3 OFFSET 4").
+		WithArgs("default", 100).
+		WillReturnRows(mock.NewRows([]string{"clusterName", "namespace", "labels", "containerName"}).
+			AddRow("default", "default", "app=web", "web").
+			AddRow("default", "default", "app=db", "db"))
+
+	selector, err := libs.ParseLabelSelector("app=web")
+	assert.NoError(t, err)
+
+	req := types.InsightRequest{Since: 100, Limit: 3, SortOrder: "desc"}
+	workloads, nextOffset, err := getWorkloadPage(types.WorkloadProcessFileSet{Namespace: "default"}, req, selector, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.WorkloadProcessFileSet{{ClusterName: "default", Namespace: "default", Labels: "app=web", ContainerName: "web"}}, workloads)
+	assert.Equal(t, -1, nextOffset)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
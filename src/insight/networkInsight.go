@@ -2,6 +2,7 @@ package insight
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/accuknox/auto-policy-discovery/src/libs"
 	network "github.com/accuknox/auto-policy-discovery/src/networkpolicy"
@@ -82,9 +83,7 @@ func populateNwInsightData(policy types.KnoxNetworkPolicy) ipb.NetworkData {
 	pbNetSpec := &ipb.NetworkData{}
 
 	// Spec
-	for k, v := range policy.Spec.Selector.MatchLabels {
-		pbNetSpec.Labels = k + "=" + v
-	}
+	pbNetSpec.Labels = libs.LabelMapToString(policy.Spec.Selector.MatchLabels)
 
 	// Spec Egress
 	for _, egress := range policy.Spec.Egress {
@@ -187,11 +186,56 @@ func populateNwInsightData(policy types.KnoxNetworkPolicy) ipb.NetworkData {
 	return *pbNetSpec
 }
 
-func GetNetInsightData(req types.InsightRequest) ([]ipb.NetworkInsightData, error) {
+// getNetworkPolicyPage returns the latest network policies of the request matching the label selector from the
+// offset on, and the offset of the next page, or -1 if the page is the last one
+func getNetworkPolicyPage(req types.InsightRequest, selector libs.LabelSelector, offset int) ([]types.KnoxNetworkPolicy, int, error) {
+	page := types.InsightQuery{
+		Since:     req.Since,
+		Until:     req.Until,
+		Limit:     req.Limit,
+		Offset:    offset,
+		SortOrder: req.SortOrder,
+	}
+
+	policies := []types.KnoxNetworkPolicy{}
+	for {
+		nwpolicies, err := libs.GetNetworkPolicyPage(network.CfgDB, req.ClusterName, req.Namespace, "latest", req.Type, req.Rule, page)
+		if err != nil {
+			return nil, -1, err
+		}
+
+		for idx, nwpolicy := range nwpolicies {
+			if !selector.Matches(strings.Split(libs.LabelMapToString(nwpolicy.Spec.Selector.MatchLabels), ",")) {
+				continue
+			}
+
+			policies = append(policies, nwpolicy)
+			if req.Limit > 0 && len(policies) == req.Limit {
+				return policies, page.Offset + idx + 1, nil
+			}
+		}
+
+		// the labels are matched out of the db, so the next rows fill the rest of the page
+		if req.Limit <= 0 || len(nwpolicies) < req.Limit {
+			return policies, -1, nil
+		}
+		page.Offset += len(nwpolicies)
+	}
+}
+
+func GetNetInsightData(req types.InsightRequest, offset int) ([]ipb.NetworkInsightData, int, error) {
 
 	var networkData []ipb.NetworkInsightData
 
-	nwpolicies := libs.GetNetworkPolicies(network.CfgDB, req.ClusterName, req.Namespace, "latest", req.Type, req.Rule)
+	selector, err := libs.ParseLabelSelector(req.Labels)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	nwpolicies, nextOffset, err := getNetworkPolicyPage(req, selector, offset)
+	if err != nil {
+		return nil, -1, err
+	}
 
 	for _, nwpolicy := range nwpolicies {
 
@@ -207,44 +251,46 @@ func GetNetInsightData(req types.InsightRequest) ([]ipb.NetworkInsightData, erro
 		nwPbSpec.Labels = ""
 		locRes.NetResource = append(locRes.NetResource, &nwPbSpec)
 
-		if (req.Labels == "") || (req.Labels != "" && locRes.Labels == req.Labels) {
-			networkData = append(networkData, ipb.NetworkInsightData{
-				ClusterName: locRes.ClusterName,
-				Namespace:   locRes.Namespace,
-				Labels:      locRes.Labels,
-				Type:        locRes.Type,
-				Rule:        locRes.Rule,
-				NetResource: locRes.NetResource,
-			})
-		}
+		networkData = append(networkData, ipb.NetworkInsightData{
+			ClusterName: locRes.ClusterName,
+			Namespace:   locRes.Namespace,
+			Labels:      locRes.Labels,
+			Type:        locRes.Type,
+			Rule:        locRes.Rule,
+			NetResource: locRes.NetResource,
+		})
 	}
 
 	newNetData := aggregateNetInsightData(networkData)
 
-	return newNetData, nil
+	return newNetData, nextOffset, nil
 }
 
-func getNetworkData(request types.InsightRequest) ([]ipb.NetworkInsightData, error) {
+func getNetworkData(request types.InsightRequest) ([]ipb.NetworkInsightData, int, error) {
 
 	if request.Request == "dbclear" {
 		ClearNetworkDB()
-		return nil, nil
+		return nil, -1, nil
 	} else if request.Request == "observe" {
-		nwdata, err := GetNetInsightData(request)
-		return nwdata, err
+		offsets, err := decodeInsightPageToken(request.PageToken, 1)
+		if err != nil {
+			return nil, -1, err
+		}
+		return GetNetInsightData(request, offsets[0])
 	}
 
-	return nil, errors.New("not a valid request, use observe/dbclear")
+	return nil, -1, errors.New("not a valid request, use observe/dbclear")
 }
 
 func GetNetworkInsightData(req types.InsightRequest) (ipb.Response, error) {
 	var resp ipb.Response
 
-	netData, err := getNetworkData(req)
+	netData, nextOffset, err := getNetworkData(req)
 
 	if req.Request != "observe" || netData == nil || len(netData) == 0 {
 		return resp, err
 	}
+	resp.NextPageToken = encodeInsightPageToken(nextOffset)
 
 	locInsightNetResp := ipb.InsightResponse{}
 	idx := 0
@@ -0,0 +1,74 @@
+package insight
+
+import (
+	"errors"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	network "github.com/accuknox/auto-policy-discovery/src/networkpolicy"
+	ipb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/insight"
+	sys "github.com/accuknox/auto-policy-discovery/src/systempolicy"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// =================== //
+// == Rule Evidence == //
+// =================== //
+
+func getRuleEvidences(req types.RuleEvidenceRequest) ([]types.RuleEvidence, error) {
+	if req.PolicyName == "" {
+		return nil, errors.New("no policy name")
+	}
+
+	switch req.PolicyType {
+	case "", "network":
+		for _, policy := range libs.GetNetworkPolicies(network.CfgDB, req.ClusterName, req.Namespace, "", "", "") {
+			if policy.Metadata["name"] == req.PolicyName {
+				return network.GetNetworkPolicyRuleEvidences(policy)
+			}
+		}
+	case "system":
+		for _, policy := range libs.GetSystemPolicies(sys.CfgDB, req.Namespace, "") {
+			if policy.Metadata["name"] == req.PolicyName &&
+				(req.ClusterName == "" || policy.Metadata["clusterName"] == req.ClusterName) {
+				return sys.GetSystemPolicyRuleEvidences(policy)
+			}
+		}
+	default:
+		return nil, errors.New("no policy type, choose network or system, not [" + req.PolicyType + "]")
+	}
+
+	return nil, errors.New("policy [" + req.PolicyName + "] not found")
+}
+
+// GetRuleEvidence returns the logs observed for each rule of the policy, so that the rules can be justified
+func GetRuleEvidence(req types.RuleEvidenceRequest) (*ipb.EvidenceResponse, error) {
+	resp := &ipb.EvidenceResponse{
+		PolicyType:  req.PolicyType,
+		ClusterName: req.ClusterName,
+		Namespace:   req.Namespace,
+		PolicyName:  req.PolicyName,
+	}
+	if resp.PolicyType == "" {
+		resp.PolicyType = "network"
+	}
+
+	evidences, err := getRuleEvidences(req)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, evidence := range evidences {
+		resp.Evidences = append(resp.Evidences, &ipb.RuleEvidence{
+			RuleKey:   evidence.RuleKey,
+			Rule:      evidence.Rule,
+			FirstSeen: evidence.FirstSeen,
+			LastSeen:  evidence.LastSeen,
+			Count:     evidence.Count,
+			SrcPods:   evidence.SrcPods,
+			DstPods:   evidence.DstPods,
+			SampleLog: evidence.SampleLog,
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,646 @@
+package insight
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	network "github.com/accuknox/auto-policy-discovery/src/networkpolicy"
+	ipb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/insight"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	GraphNodeWorkload = "workload"
+	GraphNodeService  = "service"
+	GraphNodeFQDN     = "fqdn"
+	GraphNodeCIDR     = "cidr"
+	GraphNodeEntity   = "entity"
+)
+
+const (
+	GraphFormatJSON    = "json"
+	GraphFormatDOT     = "dot"
+	GraphFormatGraphML = "graphml"
+)
+
+// =================== //
+// == Service Graph == //
+// =================== //
+
+type serviceGraphBuilder struct {
+	nodes map[string]*types.ServiceGraphNode
+	edges map[string]*types.ServiceGraphEdge
+}
+
+func newServiceGraphBuilder() *serviceGraphBuilder {
+	return &serviceGraphBuilder{
+		nodes: map[string]*types.ServiceGraphNode{},
+		edges: map[string]*types.ServiceGraphEdge{},
+	}
+}
+
+func (b *serviceGraphBuilder) addNode(node types.ServiceGraphNode) string {
+	if _, ok := b.nodes[node.ID]; !ok {
+		b.nodes[node.ID] = &node
+	}
+	return node.ID
+}
+
+func (b *serviceGraphBuilder) getEdge(src, dst, protocol, port, l7 string) *types.ServiceGraphEdge {
+	key := strings.Join([]string{src, dst, protocol, port, l7}, types.RecordSeparator)
+
+	edge, ok := b.edges[key]
+	if !ok {
+		edge = &types.ServiceGraphEdge{
+			Source:   src,
+			Target:   dst,
+			Protocol: protocol,
+			Port:     port,
+			L7:       l7,
+		}
+		b.edges[key] = edge
+	}
+
+	return edge
+}
+
+func (b *serviceGraphBuilder) graph() types.ServiceGraph {
+	graph := types.ServiceGraph{
+		Nodes: []types.ServiceGraphNode{},
+		Edges: []types.ServiceGraphEdge{},
+	}
+
+	for _, node := range b.nodes {
+		graph.Nodes = append(graph.Nodes, *node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+
+	for _, edge := range b.edges {
+		sort.Strings(edge.Policies)
+		graph.Edges = append(graph.Edges, *edge)
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		x, y := graph.Edges[i], graph.Edges[j]
+		if x.Source != y.Source {
+			return x.Source < y.Source
+		}
+		if x.Target != y.Target {
+			return x.Target < y.Target
+		}
+		if x.Protocol != y.Protocol {
+			return x.Protocol < y.Protocol
+		}
+		if x.Port != y.Port {
+			return x.Port < y.Port
+		}
+		return x.L7 < y.L7
+	})
+
+	return graph
+}
+
+func workloadNode(namespace, labels string) types.ServiceGraphNode {
+	return types.ServiceGraphNode{
+		ID:        namespace + "/" + labels,
+		Type:      GraphNodeWorkload,
+		Name:      labels,
+		Namespace: namespace,
+		Labels:    labels,
+	}
+}
+
+func serviceNode(namespace, name string) types.ServiceGraphNode {
+	return types.ServiceGraphNode{
+		ID:        "svc:" + namespace + "/" + name,
+		Type:      GraphNodeService,
+		Name:      name,
+		Namespace: namespace,
+	}
+}
+
+func fqdnNode(name string) types.ServiceGraphNode {
+	return types.ServiceGraphNode{ID: "fqdn:" + name, Type: GraphNodeFQDN, Name: name}
+}
+
+func cidrNode(cidr string) types.ServiceGraphNode {
+	return types.ServiceGraphNode{ID: "cidr:" + cidr, Type: GraphNodeCIDR, Name: cidr}
+}
+
+func entityNode(entity string) types.ServiceGraphNode {
+	return types.ServiceGraphNode{ID: "entity:" + entity, Type: GraphNodeEntity, Name: entity}
+}
+
+// getFlowEndpointNode maps an endpoint of a cilium flow to a graph node
+func getFlowEndpointNode(namespace, labels, ip, svcNamespace, svcName string) types.ServiceGraphNode {
+	if svcName != "" {
+		return serviceNode(svcNamespace, svcName)
+	}
+
+	if namespace != "" {
+		return workloadNode(namespace, libs.SortLabels(strings.Split(labels, ",")))
+	}
+
+	for _, label := range strings.Split(labels, ",") {
+		if label == "reserved:world" {
+			break
+		}
+		if strings.HasPrefix(label, "reserved:") {
+			return entityNode(strings.TrimPrefix(label, "reserved:"))
+		}
+	}
+
+	if strings.Contains(ip, ":") {
+		return cidrNode(ip + "/128")
+	}
+	return cidrNode(ip + "/32")
+}
+
+func getFlowL4(ciliumLog types.CiliumLog) (string, string) {
+	if ciliumLog.L4TCPDestinationPort != 0 {
+		return "TCP", strconv.Itoa(int(ciliumLog.L4TCPDestinationPort))
+	} else if ciliumLog.L4UDPDestinationPort != 0 {
+		return "UDP", strconv.Itoa(int(ciliumLog.L4UDPDestinationPort))
+	} else if ciliumLog.L4ICMPv4Type != 0 {
+		return "ICMP", strconv.Itoa(int(ciliumLog.L4ICMPv4Type))
+	} else if ciliumLog.L4ICMPv6Type != 0 {
+		return "ICMPv6", strconv.Itoa(int(ciliumLog.L4ICMPv6Type))
+	}
+	return "", ""
+}
+
+func getHTTPRule(method, path string) string {
+	if u, err := url.Parse(path); err == nil && u.Path != "" {
+		path = u.Path
+	}
+	return strings.TrimSpace(method + " " + path)
+}
+
+func matchGraphLabels(node types.ServiceGraphNode, labels string) bool {
+	if labels == "" {
+		return true
+	}
+	if node.Type != GraphNodeWorkload {
+		return false
+	}
+
+	nodeLabels := strings.Split(node.Labels, ",")
+	for _, label := range strings.Split(labels, ",") {
+		if !libs.ContainsElement(nodeLabels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchGraphNode(node types.ServiceGraphNode, namespace, labels string) bool {
+	if namespace != "" && node.Namespace != namespace {
+		return false
+	}
+	return matchGraphLabels(node, labels)
+}
+
+func getGraphCiliumLogs(namespace string) ([]types.CiliumLog, []uint32, error) {
+	obsDB := config.GetCfgObservabilityDB()
+	if namespace == "" {
+		return libs.GetCiliumLogs(obsDB, types.CiliumLog{})
+	}
+
+	// flows from and to the namespace
+	srcLogs, srcTotal, err := libs.GetCiliumLogs(obsDB, types.CiliumLog{SourceNamespace: namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dstLogs, dstTotal, err := libs.GetCiliumLogs(obsDB, types.CiliumLog{DestinationNamespace: namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, ciliumLog := range dstLogs {
+		if ciliumLog.SourceNamespace != namespace {
+			srcLogs = append(srcLogs, ciliumLog)
+			srcTotal = append(srcTotal, dstTotal[i])
+		}
+	}
+
+	return srcLogs, srcTotal, nil
+}
+
+// addFlowEdges adds the observed flows, every flow is counted once at the egress of its source,
+// unless the source is out of the cluster
+func (b *serviceGraphBuilder) addFlowEdges(req types.ServiceGraphRequest, ciliumLogs []types.CiliumLog, totals []uint32) {
+	for i, ciliumLog := range ciliumLogs {
+		if ciliumLog.IsReply {
+			continue
+		}
+		if ciliumLog.TrafficDirection == "INGRESS" && ciliumLog.SourceNamespace != "" {
+			continue
+		}
+
+		src := getFlowEndpointNode(ciliumLog.SourceNamespace, ciliumLog.SourceLabels, ciliumLog.IpSource,
+			ciliumLog.SourceServiceNamespace, ciliumLog.SourceServiceName)
+		// the service of a source is a backend one, the flow originates from the workload
+		if src.Type == GraphNodeService && ciliumLog.SourceNamespace != "" {
+			src = workloadNode(ciliumLog.SourceNamespace, libs.SortLabels(strings.Split(ciliumLog.SourceLabels, ",")))
+		}
+		dst := getFlowEndpointNode(ciliumLog.DestinationNamespace, ciliumLog.DestinationLabels, ciliumLog.IpDestination,
+			ciliumLog.DestinationServiceNamespace, ciliumLog.DestinationServiceName)
+
+		if !matchGraphNode(src, req.Namespace, req.Labels) && !matchGraphNode(dst, req.Namespace, req.Labels) {
+			continue
+		}
+
+		protocol, port := getFlowL4(ciliumLog)
+		l7 := ""
+		if ciliumLog.L7HttpMethod != "" {
+			l7 = getHTTPRule(ciliumLog.L7HttpMethod, ciliumLog.L7HttpUrl)
+		}
+
+		edge := b.getEdge(b.addNode(src), b.addNode(dst), protocol, port, l7)
+		if ciliumLog.Verdict == "DROPPED" {
+			edge.DropCount += int64(totals[i])
+		} else {
+			edge.FlowCount += int64(totals[i])
+		}
+		if ciliumLog.UpdatedTime > edge.LastSeen {
+			edge.LastSeen = ciliumLog.UpdatedTime
+		}
+	}
+}
+
+// getPolicyWorkloadNodes resolves a policy selector to the observed workloads it selects
+func (b *serviceGraphBuilder) getPolicyWorkloadNodes(namespace string, matchLabels map[string]string) []string {
+	labels := libs.LabelMapToString(matchLabels)
+
+	results := []string{}
+	for id, node := range b.nodes {
+		if node.Namespace == namespace && matchGraphLabels(*node, labels) {
+			results = append(results, id)
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, b.addNode(workloadNode(namespace, labels)))
+	}
+
+	return results
+}
+
+func getPolicyL4L7(toPorts []types.SpecPort, toHTTPs []types.SpecHTTP) [][3]string {
+	ports := [][2]string{}
+	for _, toPort := range toPorts {
+		ports = append(ports, [2]string{strings.ToUpper(toPort.Protocol), toPort.Port})
+	}
+	if len(ports) == 0 {
+		ports = append(ports, [2]string{"", ""})
+	}
+
+	l7s := []string{}
+	for _, toHTTP := range toHTTPs {
+		l7s = append(l7s, getHTTPRule(toHTTP.Method, toHTTP.Path))
+	}
+	if len(l7s) == 0 {
+		l7s = append(l7s, "")
+	}
+
+	results := [][3]string{}
+	for _, port := range ports {
+		for _, l7 := range l7s {
+			results = append(results, [3]string{port[0], port[1], l7})
+		}
+	}
+
+	return results
+}
+
+func (b *serviceGraphBuilder) addPolicyEdge(src, dst string, l4l7s [][3]string, policyName string) {
+	for _, l4l7 := range l4l7s {
+		edge := b.getEdge(src, dst, l4l7[0], l4l7[1], l4l7[2])
+		if !libs.ContainsElement(edge.Policies, policyName) {
+			edge.Policies = append(edge.Policies, policyName)
+		}
+	}
+}
+
+func getPolicyPeerNamespace(matchLabels map[string]string, namespace string) string {
+	if ns, ok := matchLabels["k8s:io.kubernetes.pod.namespace"]; ok {
+		return ns
+	} else if ns, ok := matchLabels["io.kubernetes.pod.namespace"]; ok {
+		return ns
+	}
+	return namespace
+}
+
+// addPolicyEdges adds the connections allowed by the latest discovered policies
+func (b *serviceGraphBuilder) addPolicyEdges(policies []types.KnoxNetworkPolicy) {
+	for _, policy := range policies {
+		namespace := policy.Metadata["namespace"]
+		policyName := policy.Metadata["name"]
+		selected := b.getPolicyWorkloadNodes(namespace, policy.Spec.Selector.MatchLabels)
+
+		for _, egress := range policy.Spec.Egress {
+			dsts := []string{}
+			if len(egress.MatchLabels) > 0 {
+				dsts = append(dsts, b.getPolicyWorkloadNodes(getPolicyPeerNamespace(egress.MatchLabels, namespace), egress.MatchLabels)...)
+			}
+			for _, toCIDR := range egress.ToCIDRs {
+				for _, cidr := range toCIDR.CIDRs {
+					dsts = append(dsts, b.addNode(cidrNode(cidr)))
+				}
+			}
+			for _, entity := range egress.ToEntities {
+				dsts = append(dsts, b.addNode(entityNode(entity)))
+			}
+			for _, toService := range egress.ToServices {
+				dsts = append(dsts, b.addNode(serviceNode(toService.Namespace, toService.ServiceName)))
+			}
+			for _, toFQDN := range egress.ToFQDNs {
+				for _, name := range toFQDN.MatchNames {
+					dsts = append(dsts, b.addNode(fqdnNode(name)))
+				}
+				// the aggregated names are a node of their wildcard pattern
+				for _, pattern := range toFQDN.MatchPatterns {
+					dsts = append(dsts, b.addNode(fqdnNode(pattern)))
+				}
+			}
+
+			l4l7s := getPolicyL4L7(egress.ToPorts, egress.ToHTTPs)
+			for _, src := range selected {
+				for _, dst := range dsts {
+					b.addPolicyEdge(src, dst, l4l7s, policyName)
+				}
+			}
+		}
+
+		for _, ingress := range policy.Spec.Ingress {
+			srcs := []string{}
+			if len(ingress.MatchLabels) > 0 {
+				srcs = append(srcs, b.getPolicyWorkloadNodes(getPolicyPeerNamespace(ingress.MatchLabels, namespace), ingress.MatchLabels)...)
+			}
+			for _, fromCIDR := range ingress.FromCIDRs {
+				for _, cidr := range fromCIDR.CIDRs {
+					srcs = append(srcs, b.addNode(cidrNode(cidr)))
+				}
+			}
+			for _, entity := range ingress.FromEntities {
+				srcs = append(srcs, b.addNode(entityNode(entity)))
+			}
+
+			l4l7s := getPolicyL4L7(ingress.ToPorts, ingress.ToHTTPs)
+			for _, src := range srcs {
+				for _, dst := range selected {
+					b.addPolicyEdge(src, dst, l4l7s, policyName)
+				}
+			}
+		}
+	}
+}
+
+// BuildServiceGraph builds the workload level connectivity graph from the cilium flows and the discovered policies
+func BuildServiceGraph(req types.ServiceGraphRequest) (types.ServiceGraph, error) {
+	ciliumLogs, totals, err := getGraphCiliumLogs(req.Namespace)
+	if err != nil {
+		return types.ServiceGraph{}, err
+	}
+
+	policies := libs.GetNetworkPolicies(network.CfgDB, req.ClusterName, req.Namespace, "latest", "", "")
+
+	builder := newServiceGraphBuilder()
+	builder.addFlowEdges(req, ciliumLogs, totals)
+	builder.addPolicyEdges(policies)
+
+	return builder.graph(), nil
+}
+
+// ========================== //
+// == Service Graph Format == //
+// ========================== //
+
+func getEdgeLabel(edge types.ServiceGraphEdge) string {
+	label := edge.Protocol
+	if edge.Port != "" {
+		label = label + "/" + edge.Port
+	}
+	if edge.L7 != "" {
+		label = strings.TrimSpace(label + " " + edge.L7)
+	}
+	if edge.FlowCount > 0 {
+		label = strings.TrimSpace(label + " (" + strconv.FormatInt(edge.FlowCount, 10) + ")")
+	}
+	return label
+}
+
+func dotQuote(str string) string {
+	str = strings.ReplaceAll(str, `\`, `\\`)
+	str = strings.ReplaceAll(str, `"`, `\"`)
+	return `"` + str + `"`
+}
+
+func getDotNodeShape(nodeType string) string {
+	switch nodeType {
+	case GraphNodeWorkload:
+		return "box"
+	case GraphNodeService:
+		return "hexagon"
+	case GraphNodeFQDN:
+		return "note"
+	case GraphNodeCIDR:
+		return "parallelogram"
+	}
+	return "ellipse"
+}
+
+func renderServiceGraphDOT(graph types.ServiceGraph) string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph \"service-graph\" {\n")
+	sb.WriteString("  rankdir=LR;\n")
+
+	for _, node := range graph.Nodes {
+		label := node.Name
+		if node.Namespace != "" {
+			label = node.Namespace + "\n" + label
+		}
+		sb.WriteString("  " + dotQuote(node.ID) + " [label=" + dotQuote(label) + ", shape=" + getDotNodeShape(node.Type) + "];\n")
+	}
+
+	for _, edge := range graph.Edges {
+		attrs := "label=" + dotQuote(getEdgeLabel(edge))
+		if edge.FlowCount == 0 {
+			// allowed by a policy, but not observed
+			attrs = attrs + ", style=dashed"
+		}
+		sb.WriteString("  " + dotQuote(edge.Source) + " -> " + dotQuote(edge.Target) + " [" + attrs + "];\n")
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLDoc struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+func appendGraphMLData(data []graphMLData, key, value string) []graphMLData {
+	if value == "" {
+		return data
+	}
+	return append(data, graphMLData{Key: key, Value: value})
+}
+
+func renderServiceGraphGraphML(graph types.ServiceGraph) (string, error) {
+	doc := graphMLDoc{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "type", For: "node", AttrName: "type", AttrType: "string"},
+			{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+			{ID: "namespace", For: "node", AttrName: "namespace", AttrType: "string"},
+			{ID: "labels", For: "node", AttrName: "labels", AttrType: "string"},
+			{ID: "protocol", For: "edge", AttrName: "protocol", AttrType: "string"},
+			{ID: "port", For: "edge", AttrName: "port", AttrType: "string"},
+			{ID: "l7", For: "edge", AttrName: "l7", AttrType: "string"},
+			{ID: "flowCount", For: "edge", AttrName: "flowCount", AttrType: "long"},
+			{ID: "dropCount", For: "edge", AttrName: "dropCount", AttrType: "long"},
+			{ID: "lastSeen", For: "edge", AttrName: "lastSeen", AttrType: "long"},
+			{ID: "policies", For: "edge", AttrName: "policies", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: "service-graph", EdgeDefault: "directed"},
+	}
+
+	for _, node := range graph.Nodes {
+		data := []graphMLData{}
+		data = appendGraphMLData(data, "type", node.Type)
+		data = appendGraphMLData(data, "name", node.Name)
+		data = appendGraphMLData(data, "namespace", node.Namespace)
+		data = appendGraphMLData(data, "labels", node.Labels)
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node.ID, Data: data})
+	}
+
+	for _, edge := range graph.Edges {
+		data := []graphMLData{}
+		data = appendGraphMLData(data, "protocol", edge.Protocol)
+		data = appendGraphMLData(data, "port", edge.Port)
+		data = appendGraphMLData(data, "l7", edge.L7)
+		data = appendGraphMLData(data, "flowCount", strconv.FormatInt(edge.FlowCount, 10))
+		data = appendGraphMLData(data, "dropCount", strconv.FormatInt(edge.DropCount, 10))
+		data = appendGraphMLData(data, "lastSeen", strconv.FormatInt(edge.LastSeen, 10))
+		data = appendGraphMLData(data, "policies", strings.Join(edge.Policies, ","))
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: edge.Source, Target: edge.Target, Data: data})
+	}
+
+	b, err := xml.MarshalIndent(&doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(b) + "\n", nil
+}
+
+// RenderServiceGraph renders the graph in json, dot or graphml
+func RenderServiceGraph(graph types.ServiceGraph, format string) (string, error) {
+	switch format {
+	case "", GraphFormatJSON:
+		b, err := json.MarshalIndent(&graph, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	case GraphFormatDOT:
+		return renderServiceGraphDOT(graph), nil
+	case GraphFormatGraphML:
+		return renderServiceGraphGraphML(graph)
+	}
+
+	return "", errors.New("not a valid graph format, use json/dot/graphml")
+}
+
+func GetServiceGraph(req types.ServiceGraphRequest) (*ipb.GraphResponse, error) {
+	resp := &ipb.GraphResponse{Format: req.Format}
+	if resp.Format == "" {
+		resp.Format = GraphFormatJSON
+	}
+
+	graph, err := BuildServiceGraph(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := RenderServiceGraph(graph, resp.Format)
+	if err != nil {
+		return resp, err
+	}
+
+	if req.Export {
+		libs.WriteServiceGraphToFile(data, resp.Format)
+	}
+
+	for _, node := range graph.Nodes {
+		resp.Nodes = append(resp.Nodes, &ipb.GraphNode{
+			Id:        node.ID,
+			Type:      node.Type,
+			Name:      node.Name,
+			Namespace: node.Namespace,
+			Labels:    node.Labels,
+		})
+	}
+
+	for _, edge := range graph.Edges {
+		resp.Edges = append(resp.Edges, &ipb.GraphEdge{
+			Source:    edge.Source,
+			Target:    edge.Target,
+			Protocol:  edge.Protocol,
+			Port:      edge.Port,
+			L7:        edge.L7,
+			FlowCount: edge.FlowCount,
+			DropCount: edge.DropCount,
+			LastSeen:  edge.LastSeen,
+			Policies:  edge.Policies,
+		})
+	}
+	resp.Data = data
+
+	return resp, nil
+}
@@ -0,0 +1,99 @@
+package insight
+
+import (
+	"strings"
+	"testing"
+
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildServiceGraph(t *testing.T) {
+	ciliumLogs := []types.CiliumLog{
+		// egress of the frontend, observed at both ends
+		{SourceNamespace: "shop", SourceLabels: "version=v1,app=front", DestinationNamespace: "shop", DestinationLabels: "app=cart",
+			L4TCPDestinationPort: 8080, L7HttpMethod: "GET", L7HttpUrl: "http://cart:8080/items?id=1", TrafficDirection: "EGRESS", UpdatedTime: 100},
+		{SourceNamespace: "shop", SourceLabels: "version=v1,app=front", DestinationNamespace: "shop", DestinationLabels: "app=cart",
+			L4TCPDestinationPort: 8080, L7HttpMethod: "GET", L7HttpUrl: "http://cart:8080/items?id=1", TrafficDirection: "INGRESS", UpdatedTime: 100},
+		// reply
+		{SourceNamespace: "shop", SourceLabels: "app=cart", DestinationNamespace: "shop", DestinationLabels: "app=front,version=v1",
+			IsReply: true, TrafficDirection: "EGRESS"},
+		// to the world
+		{SourceNamespace: "shop", SourceLabels: "app=cart", DestinationLabels: "reserved:world", IpDestination: "8.8.8.8",
+			L4UDPDestinationPort: 53, TrafficDirection: "EGRESS", Verdict: "DROPPED", UpdatedTime: 200},
+	}
+	totals := []uint32{3, 3, 3, 2}
+
+	policies := []types.KnoxNetworkPolicy{
+		{
+			Metadata: map[string]string{"name": "autopol-egress-1", "namespace": "shop"},
+			Spec: types.Spec{
+				Selector: types.Selector{MatchLabels: map[string]string{"app": "front"}},
+				Egress: []types.Egress{
+					{
+						MatchLabels: map[string]string{"app": "cart", "k8s:io.kubernetes.pod.namespace": "shop"},
+						ToPorts:     []types.SpecPort{{Port: "8080", Protocol: "tcp"}},
+						ToHTTPs:     []types.SpecHTTP{{Method: "GET", Path: "/items"}},
+					},
+					{
+						ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"api.example.com"}}},
+						ToPorts: []types.SpecPort{{Port: "443", Protocol: "tcp"}},
+					},
+					{
+						ToFQDNs: []types.SpecFQDN{{MatchPatterns: []string{"*.cdn.example.com"}}},
+						ToPorts: []types.SpecPort{{Port: "443", Protocol: "tcp"}},
+					},
+				},
+			},
+		},
+	}
+
+	builder := newServiceGraphBuilder()
+	builder.addFlowEdges(types.ServiceGraphRequest{Namespace: "shop"}, ciliumLogs, totals)
+	builder.addPolicyEdges(policies)
+	graph := builder.graph()
+
+	assert.Equal(t, []types.ServiceGraphNode{
+		{ID: "cidr:8.8.8.8/32", Type: GraphNodeCIDR, Name: "8.8.8.8/32"},
+		{ID: "fqdn:*.cdn.example.com", Type: GraphNodeFQDN, Name: "*.cdn.example.com"},
+		{ID: "fqdn:api.example.com", Type: GraphNodeFQDN, Name: "api.example.com"},
+		{ID: "shop/app=cart", Type: GraphNodeWorkload, Name: "app=cart", Namespace: "shop", Labels: "app=cart"},
+		{ID: "shop/app=front,version=v1", Type: GraphNodeWorkload, Name: "app=front,version=v1", Namespace: "shop", Labels: "app=front,version=v1"},
+	}, graph.Nodes)
+
+	assert.Equal(t, []types.ServiceGraphEdge{
+		{Source: "shop/app=cart", Target: "cidr:8.8.8.8/32", Protocol: "UDP", Port: "53", DropCount: 2, LastSeen: 200},
+		{Source: "shop/app=front,version=v1", Target: "fqdn:*.cdn.example.com", Protocol: "TCP", Port: "443", Policies: []string{"autopol-egress-1"}},
+		{Source: "shop/app=front,version=v1", Target: "fqdn:api.example.com", Protocol: "TCP", Port: "443", Policies: []string{"autopol-egress-1"}},
+		{Source: "shop/app=front,version=v1", Target: "shop/app=cart", Protocol: "TCP", Port: "8080", L7: "GET /items", FlowCount: 3, LastSeen: 100, Policies: []string{"autopol-egress-1"}},
+	}, graph.Edges)
+}
+
+func TestRenderServiceGraph(t *testing.T) {
+	graph := types.ServiceGraph{
+		Nodes: []types.ServiceGraphNode{
+			{ID: "shop/app=front", Type: GraphNodeWorkload, Name: "app=front", Namespace: "shop", Labels: "app=front"},
+			{ID: "fqdn:api.example.com", Type: GraphNodeFQDN, Name: "api.example.com"},
+		},
+		Edges: []types.ServiceGraphEdge{
+			{Source: "shop/app=front", Target: "fqdn:api.example.com", Protocol: "TCP", Port: "443", FlowCount: 5},
+		},
+	}
+
+	dot, err := RenderServiceGraph(graph, GraphFormatDOT)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(dot, "digraph \"service-graph\" {"))
+	assert.Contains(t, dot, "\"shop/app=front\" -> \"fqdn:api.example.com\" [label=\"TCP/443 (5)\"];")
+
+	graphml, err := RenderServiceGraph(graph, GraphFormatGraphML)
+	assert.NoError(t, err)
+	assert.Contains(t, graphml, "<edge source=\"shop/app=front\" target=\"fqdn:api.example.com\">")
+	assert.Contains(t, graphml, "<data key=\"flowCount\">5</data>")
+
+	json, err := RenderServiceGraph(graph, GraphFormatJSON)
+	assert.NoError(t, err)
+	assert.Contains(t, json, "\"flow-count\": 5")
+
+	_, err = RenderServiceGraph(graph, "png")
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package insight
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/accuknox/auto-policy-discovery/src/libs"
@@ -16,6 +17,8 @@ func ConvertSysInsDataToInsResponse(sysdata ipb.SystemInsightData) ipb.InsightRe
 
 	locsysinsdata.ContainerName = sysdata.ContainerName
 	locsysinsdata.SysResource = sysdata.SysResource
+	locsysinsdata.LearningState = sysdata.LearningState
+	locsysinsdata.StableIntervals = sysdata.StableIntervals
 
 	insresp.ClusterName = sysdata.ClusterName
 	insresp.NameSpace = sysdata.Namespace
@@ -30,9 +33,19 @@ func ConvertSysInsDataToInsResponse(sysdata ipb.SystemInsightData) ipb.InsightRe
 	}
 }
 
-func convertWPFSToInsightData(wpfsSet types.ResourceSetMap) types.SysInsightResponseData {
+// convertWPFSToInsightData groups the sets by workload, in the order of the workloads of the page
+func convertWPFSToInsightData(wpfsSet types.ResourceSetMap, workloads []types.WorkloadProcessFileSet) types.SysInsightResponseData {
 	var resData types.SysInsightResponseData
 
+	for _, workload := range workloads {
+		resData.SysData = append(resData.SysData, types.SysInsightData{
+			ClusterName:   workload.ClusterName,
+			Namespace:     workload.Namespace,
+			Labels:        workload.Labels,
+			ContainerName: workload.ContainerName,
+		})
+	}
+
 	for wpfs, fsset := range wpfsSet {
 		var locFsData types.SystemData
 		var locObsData types.SysInsightData
@@ -83,6 +96,29 @@ func convertWPFSToInsightData(wpfsSet types.ResourceSetMap) types.SysInsightResp
 	return resData
 }
 
+func updateLearningStateInsightData(resData *types.SysInsightResponseData, wpfs types.WorkloadProcessFileSet) {
+	states, err := libs.GetWorkloadLearningStates(sys.CfgDB, types.WorkloadLearningState{
+		ClusterName:   wpfs.ClusterName,
+		Namespace:     wpfs.Namespace,
+		ContainerName: wpfs.ContainerName,
+		Labels:        wpfs.Labels,
+	})
+	if err != nil {
+		return
+	}
+
+	for idx, locResData := range resData.SysData {
+		for _, state := range states {
+			if locResData.ClusterName == state.ClusterName && locResData.Namespace == state.Namespace &&
+				locResData.Labels == state.Labels && locResData.ContainerName == state.ContainerName {
+				resData.SysData[idx].LearningState = state.State
+				resData.SysData[idx].StableIntervals = state.StableIntervals
+				break
+			}
+		}
+	}
+}
+
 func convertSysInsDataToResponse(resData types.SysInsightResponseData) []ipb.SystemInsightData {
 	response := []ipb.SystemInsightData{}
 
@@ -93,6 +129,8 @@ func convertSysInsDataToResponse(resData types.SysInsightResponseData) []ipb.Sys
 		locInsData.Namespace = locResData.Namespace
 		locInsData.Labels = locResData.Labels
 		locInsData.ContainerName = locResData.ContainerName
+		locInsData.LearningState = locResData.LearningState
+		locInsData.StableIntervals = int32(locResData.StableIntervals)
 
 		for _, fsset := range locResData.SysProcessFileData {
 			locfsset := ipb.SystemData{}
@@ -110,13 +148,112 @@ func convertSysInsDataToResponse(resData types.SysInsightResponseData) []ipb.Sys
 	return response
 }
 
-func getSysInsightData(wpfs types.WorkloadProcessFileSet) ([]ipb.SystemInsightData, error) {
+// filterWPFSByLabels keeps the sets of the workloads whose labels match the selector
+func filterWPFSByLabels(wpfsSet types.ResourceSetMap, selector libs.LabelSelector) types.ResourceSetMap {
+	if selector.Empty() {
+		return wpfsSet
+	}
+
+	filtered := types.ResourceSetMap{}
+	for wpfs, fsset := range wpfsSet {
+		if selector.Matches(strings.Split(wpfs.Labels, ",")) {
+			filtered[wpfs] = fsset
+		}
+	}
+	return filtered
+}
+
+// getWorkloadPage returns the workloads of the request matching the label selector from the offset on, and the
+// offset of the next page, or -1 if the page is the last one
+func getWorkloadPage(wpfs types.WorkloadProcessFileSet, req types.InsightRequest, selector libs.LabelSelector, offset int) ([]types.WorkloadProcessFileSet, int, error) {
+	page := types.InsightQuery{
+		Since:     req.Since,
+		Until:     req.Until,
+		Limit:     req.Limit,
+		Offset:    offset,
+		SortOrder: req.SortOrder,
+	}
+
+	workloads := []types.WorkloadProcessFileSet{}
+	for {
+		rows, err := libs.GetWorkloadPage(sys.CfgDB, wpfs, page)
+		if err != nil {
+			return nil, -1, err
+		}
+
+		for idx, workload := range rows {
+			if !selector.Matches(strings.Split(workload.Labels, ",")) {
+				continue
+			}
+
+			workloads = append(workloads, workload)
+			if req.Limit > 0 && len(workloads) == req.Limit {
+				return workloads, page.Offset + idx + 1, nil
+			}
+		}
+
+		// the labels are matched out of the db, so the next rows fill the rest of the page
+		if req.Limit <= 0 || len(rows) < req.Limit {
+			return workloads, -1, nil
+		}
+		page.Offset += len(rows)
+	}
+}
+
+// getWorkloadSets returns the sets of the workloads updated in the time range of the request, a page of the
+// workloads loads the sets of each workload of the page only
+func getWorkloadSets(wpfs types.WorkloadProcessFileSet, workloads []types.WorkloadProcessFileSet, req types.InsightRequest, selector libs.LabelSelector) (types.ResourceSetMap, error) {
+	if req.Limit <= 0 {
+		res, _, err := libs.GetWorkloadProcessFileSetInRange(sys.CfgDB, wpfs, req.Since, req.Until)
+		if err != nil {
+			return nil, err
+		}
+		return filterWPFSByLabels(res, selector), nil
+	}
+
+	res := types.ResourceSetMap{}
+	for _, workload := range workloads {
+		filter := wpfs
+		filter.ClusterName = workload.ClusterName
+		filter.Namespace = workload.Namespace
+		filter.Labels = workload.Labels
+		filter.ContainerName = workload.ContainerName
+
+		sets, _, err := libs.GetWorkloadProcessFileSetInRange(sys.CfgDB, filter, req.Since, req.Until)
+		if err != nil {
+			return nil, err
+		}
+		for set, fsset := range sets {
+			res[set] = fsset
+		}
+	}
+
+	return res, nil
+}
+
+func getSysInsightData(wpfs types.WorkloadProcessFileSet, req types.InsightRequest, offset int) ([]ipb.SystemInsightData, int, error) {
 
 	systemData := types.SysInsightResponseData{}
 
-	res, _, _ := libs.GetWorkloadProcessFileSet(sys.CfgDB, wpfs)
+	// the labels are a selector, matched against the labels of each workload
+	selector, err := libs.ParseLabelSelector(wpfs.Labels)
+	if err != nil {
+		return nil, -1, err
+	}
+	wpfs.Labels = ""
+
+	workloads, nextOffset, err := getWorkloadPage(wpfs, req, selector, offset)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	res, err := getWorkloadSets(wpfs, workloads, req, selector)
+	if err != nil {
+		return nil, -1, err
+	}
 
-	systemData = convertWPFSToInsightData(res)
+	systemData = convertWPFSToInsightData(res, workloads)
+	updateLearningStateInsightData(&systemData, wpfs)
 
 	// Write Observability data to json file
 	//libs.WriteSysObsDataToJsonFile(sysObsResData)
@@ -124,7 +261,7 @@ func getSysInsightData(wpfs types.WorkloadProcessFileSet) ([]ipb.SystemInsightDa
 	// Generate json response gRPC
 	sysInsightData := convertSysInsDataToResponse(systemData)
 
-	return sysInsightData, nil
+	return sysInsightData, nextOffset, nil
 }
 
 func ClearSysDb(wpfs types.WorkloadProcessFileSet, durationStr string) error {
@@ -139,7 +276,7 @@ func ClearSysDb(wpfs types.WorkloadProcessFileSet, durationStr string) error {
 	return err
 }
 
-func GetSysInsightData(request types.InsightRequest) ([]ipb.SystemInsightData, error) {
+func GetSysInsightData(request types.InsightRequest, offset int) ([]ipb.SystemInsightData, int, error) {
 
 	var wpfs types.WorkloadProcessFileSet
 
@@ -151,23 +288,28 @@ func GetSysInsightData(request types.InsightRequest) ([]ipb.SystemInsightData, e
 
 	if request.Request == "dbclear" {
 		err := ClearSysDb(wpfs, request.Duration)
-		return nil, err
+		return nil, -1, err
 	} else if request.Request == "observe" {
-		sysData, err := getSysInsightData(wpfs)
-		return sysData, err
+		return getSysInsightData(wpfs, request, offset)
 	}
 
-	return nil, errors.New("not a valid request, use observe/dbclear")
+	return nil, -1, errors.New("not a valid request, use observe/dbclear")
 }
 
 func GetSystemInsightData(req types.InsightRequest) (ipb.Response, error) {
 	var resp ipb.Response
 
-	sysData, err := GetSysInsightData(req)
+	offsets, err := decodeInsightPageToken(req.PageToken, 1)
+	if err != nil {
+		return resp, err
+	}
+
+	sysData, nextOffset, err := GetSysInsightData(req, offsets[0])
 
 	if req.Request != "observe" || sysData == nil || len(sysData) == 0 {
 		return resp, err
 	}
+	resp.NextPageToken = encodeInsightPageToken(nextOffset)
 
 	locInsightSysResp := ipb.InsightResponse{}
 	idx := 0
@@ -0,0 +1,27 @@
+package insight
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterWPFSByLabels(t *testing.T) {
+	web := types.WorkloadProcessFileSet{Namespace: "default", Labels: "tier=front,app=web"}
+	db := types.WorkloadProcessFileSet{Namespace: "default", Labels: "app=db"}
+	wpfsSet := types.ResourceSetMap{web: {"/bin/sh"}, db: {"/usr/sbin/mysqld"}}
+
+	selector, err := libs.ParseLabelSelector("app=web,tier=front")
+	assert.NoError(t, err)
+	assert.Equal(t, types.ResourceSetMap{web: {"/bin/sh"}}, filterWPFSByLabels(wpfsSet, selector))
+
+	selector, err = libs.ParseLabelSelector("app in (web,db)")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(filterWPFSByLabels(wpfsSet, selector)))
+
+	selector, err = libs.ParseLabelSelector("")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(filterWPFSByLabels(wpfsSet, selector)))
+}
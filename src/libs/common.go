@@ -84,6 +84,26 @@ func SetDefaultConfig() {
 
 	// Observability module
 	viper.SetDefault("observability", false)
+	viper.SetDefault("observability.export-dir", "./exports")
+	viper.SetDefault("observability.retention.enable", false)
+	viper.SetDefault("observability.retention.cron-job-time-interval", "1h0m0s")
+	viper.SetDefault("observability.retention.max-age", "0h0m0s")
+	viper.SetDefault("observability.retention.max-rows", 0)
+	viper.SetDefault("observability.retention.compaction.hourly-after", "0h0m0s")
+	viper.SetDefault("observability.retention.compaction.daily-after", "0h0m0s")
+	viper.SetDefault("observability.retention.vacuum", true)
+	viper.SetDefault("observability.database.max-open-conns", 0)
+	viper.SetDefault("observability.database.max-idle-conns", 2)
+	viper.SetDefault("observability.database.conn-max-lifetime", "0h0m0s")
+	viper.SetDefault("observability.anomaly.enable", false)
+	viper.SetDefault("observability.anomaly.publisher.driver", "")
+	viper.SetDefault("observability.anomaly.publisher.topic", "knoxautopolicy-anomaly-alerts")
+
+	// Telemetry config
+	viper.SetDefault("telemetry.otlp.enable", false)
+	viper.SetDefault("telemetry.otlp.endpoint", "http://localhost:4318")
+	viper.SetDefault("telemetry.otlp.service-name", "knoxautopolicy")
+	viper.SetDefault("telemetry.otlp.export-time-interval", "0h0m10s")
 
 	// Application->Network config
 	viper.SetDefault("application.network.operation-mode", 1)
@@ -94,6 +114,13 @@ func SetDefaultConfig() {
 	viper.SetDefault("application.network.network-policy-to", "db|file")
 	viper.SetDefault("application.network.network-policy-dir", "./")
 	viper.SetDefault("application.network.skip-cert-verification", true)
+	viper.SetDefault("application.network.cluster-policy-seed", false)
+	viper.SetDefault("application.network.rule-aging-days", 0)
+	viper.SetDefault("application.network.port-range.min-ports", 0)
+	viper.SetDefault("application.network.port-range.max-gap", 32)
+	viper.SetDefault("application.network.named-ports", false)
+	viper.SetDefault("application.network.fqdn-pattern-threshold", 0)
+	viper.SetDefault("application.network.cluster-wide.min-workloads", 0)
 
 	// Application->System config
 	viper.SetDefault("application.system.operation-mode", 1)
@@ -103,8 +130,15 @@ func SetDefaultConfig() {
 	viper.SetDefault("application.system.system-log-from", "kubearmor")
 	viper.SetDefault("application.system.system-policy-to", "db|file")
 	viper.SetDefault("application.system.system-policy-dir", "./")
+	viper.SetDefault("application.system.host-policy-dir", "./")
 	viper.SetDefault("application.system.system-policy-types", 7)
 	viper.SetDefault("application.system.deprecate-old-mode", false)
+	viper.SetDefault("application.system.learning.min-observation-time", "0h0m0s")
+	viper.SetDefault("application.system.learning.stable-intervals", 0)
+	viper.SetDefault("application.system.learning.candidate-intervals", 0)
+	viper.SetDefault("application.system.default-deny-audit", false)
+	viper.SetDefault("application.system.cluster-policy-seed", false)
+	viper.SetDefault("application.system.rule-aging-days", 0)
 
 	// Application->cluster config
 	viper.SetDefault("application.cluster.cluster-info-from", "k8sclient")
@@ -166,12 +200,37 @@ func CheckCommandLineConfig() {
 	flag.Var(&cmdlineCfg, "cfg", "Configuration key=val")
 
 	configFilePath := flag.String("config-path", "conf/", "conf/")
+
+	migrateStatus := flag.Bool("migrate-status", false, "print the schema migration status and exit")
+	migrateDryRun := flag.Bool("migrate-dry-run", false, "print the pending schema migrations and exit")
+
+	flag.StringVar(&LogExportFormat, "export-logs", "", "export the observability logs as csv/parquet and exit")
+	flag.StringVar(&LogExportRequest.Type, "export-type", "", "system/network logs to export, both if empty")
+	flag.StringVar(&LogExportRequest.Namespace, "export-namespace", "", "namespace of the exported logs")
+	flag.StringVar(&LogExportRequest.Labels, "export-label", "", "labels of the exported logs")
+	exportSince := flag.String("export-since", "", "export the logs updated since the time (unix or RFC3339)")
+	exportUntil := flag.String("export-until", "", "export the logs updated until the time (unix or RFC3339)")
+	flag.StringVar(&LogExportRequest.Dir, "export-dir", ".", "directory of the exported files")
 	flag.Parse()
 
 	if *version1 || *version2 {
 		os.Exit(0)
 	}
 
+	if *migrateStatus {
+		SchemaCommand = SchemaCommandStatus
+	} else if *migrateDryRun {
+		SchemaCommand = SchemaCommandDryRun
+	}
+
+	var err error
+	if LogExportRequest.Since, err = parseLogExportTime(*exportSince); err != nil {
+		log.Panic().Msgf("invalid export-since: %s\n", *exportSince)
+	}
+	if LogExportRequest.Until, err = parseLogExportTime(*exportUntil); err != nil {
+		log.Panic().Msgf("invalid export-until: %s\n", *exportUntil)
+	}
+
 	viper.SetConfigName(GetEnv("CONF_FILE_NAME", "conf"))
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath(*configFilePath)
@@ -496,7 +555,15 @@ func WriteCiliumPolicyToYamlFile(namespace string, policies []types.CiliumNetwor
 }
 
 func WriteKubeArmorPolicyToYamlFile(fname string, policies []types.KubeArmorPolicy) {
-	fileName := getPolicyDir(cfg.CurrentCfg.ConfigSysPolicy.SystemPolicyDir)
+	writeKubeArmorPolicyToYamlFile(cfg.CurrentCfg.ConfigSysPolicy.SystemPolicyDir, fname, policies)
+}
+
+func WriteKubeArmorHostPolicyToYamlFile(fname string, policies []types.KubeArmorPolicy) {
+	writeKubeArmorPolicyToYamlFile(cfg.CurrentCfg.ConfigSysPolicy.HostPolicyDir, fname, policies)
+}
+
+func writeKubeArmorPolicyToYamlFile(policyDir, fname string, policies []types.KubeArmorPolicy) {
+	fileName := getPolicyDir(policyDir)
 	fileName = fileName + fname + ".yaml"
 
 	if err := os.Remove(fileName); err != nil {
@@ -558,6 +625,56 @@ func WriteSysObsDataToJsonFile(obsData types.SysInsightResponseData) {
 
 }
 
+func WriteServiceGraphToFile(data, format string) {
+	fileName := getPolicyDir(cfg.CurrentCfg.ConfigNetPolicy.NetworkPolicyDir)
+	fileName = fileName + "service_graph." + format
+
+	if err := os.Remove(fileName); err != nil {
+		if !strings.Contains(err.Error(), NoSuchFileOrDir) {
+			log.Error().Msg(err.Error())
+		}
+	}
+
+	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return
+	}
+
+	writeJsonByte(f, []byte(data))
+
+	if err := f.Close(); err != nil {
+		log.Error().Msg(err.Error())
+	}
+}
+
+func WriteNoisePathReportToJsonFile(report []types.NoisePathCollapse) {
+	fileName := getPolicyDir(cfg.CurrentCfg.ConfigSysPolicy.SystemPolicyDir)
+	fileName = fileName + "noise_path_report" + ".json"
+
+	if err := os.Remove(fileName); err != nil {
+		if !strings.Contains(err.Error(), NoSuchFileOrDir) {
+			log.Error().Msg(err.Error())
+		}
+	}
+
+	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return
+	}
+
+	b, err := json.Marshal(&report)
+	if err != nil {
+		log.Error().Msg(err.Error())
+	}
+	writeJsonByte(f, b)
+
+	if err := f.Close(); err != nil {
+		log.Error().Msg(err.Error())
+	}
+}
+
 // ========== //
 // == Time == //
 // ========== //
@@ -27,7 +27,7 @@ func GetNetworkPolicies(cfg types.ConfigDB, cluster, namespace, status, nwtype,
 		}
 		results = docs
 	} else if cfg.DBDriver == "sqlite3" {
-		docs, err := GetNetworkPoliciesFromSQLite(cfg, cluster, namespace, status)
+		docs, err := GetNetworkPoliciesFromSQLite(cfg, cluster, namespace, status, nwtype, rule)
 		if err != nil {
 			return results
 		}
@@ -37,6 +37,16 @@ func GetNetworkPolicies(cfg types.ConfigDB, cluster, namespace, status, nwtype,
 	return results
 }
 
+// GetNetworkPolicyPage returns the network policies of the time range in the requested page
+func GetNetworkPolicyPage(cfg types.ConfigDB, cluster, namespace, status, nwtype, rule string, page types.InsightQuery) ([]types.KnoxNetworkPolicy, error) {
+	if cfg.DBDriver == "mysql" {
+		return GetNetworkPolicyPageFromMySQL(cfg, cluster, namespace, status, nwtype, rule, page)
+	} else if cfg.DBDriver == "sqlite3" {
+		return GetNetworkPolicyPageFromSQLite(cfg, cluster, namespace, status, nwtype, rule, page)
+	}
+	return nil, errors.New("no db driver")
+}
+
 func GetNetworkPoliciesBySelector(cfg types.ConfigDB, cluster, namespace, status string, selector map[string]string) ([]types.KnoxNetworkPolicy, error) {
 	results := []types.KnoxNetworkPolicy{}
 
@@ -47,7 +57,7 @@ func GetNetworkPoliciesBySelector(cfg types.ConfigDB, cluster, namespace, status
 		}
 		results = docs
 	} else if cfg.DBDriver == "sqlite3" {
-		docs, err := GetNetworkPoliciesFromSQLite(cfg, cluster, namespace, status)
+		docs, err := GetNetworkPoliciesFromSQLite(cfg, cluster, namespace, status, "", "")
 		if err != nil {
 			return nil, err
 		}
@@ -76,12 +86,18 @@ func GetNetworkPoliciesBySelector(cfg types.ConfigDB, cluster, namespace, status
 }
 
 func UpdateOutdatedNetworkPolicy(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string) {
+	UpdateOutdatedNetworkPolicyReview(cfg, outdatedPolicy, latestPolicy, ReviewStateSuperseded)
+}
+
+// UpdateOutdatedNetworkPolicyReview marks the policy outdated by the latest one with the review state,
+// a reviewed policy keeps its state as the version to roll back to
+func UpdateOutdatedNetworkPolicyReview(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string, reviewState string) {
 	if cfg.DBDriver == "mysql" {
-		if err := UpdateOutdatedNetworkPolicyFromMySQL(cfg, outdatedPolicy, latestPolicy); err != nil {
+		if err := UpdateOutdatedNetworkPolicyFromMySQL(cfg, outdatedPolicy, latestPolicy, reviewState); err != nil {
 			log.Error().Msg(err.Error())
 		}
 	} else if cfg.DBDriver == "sqlite3" {
-		if err := UpdateOutdatedNetworkPolicyFromSQLite(cfg, outdatedPolicy, latestPolicy); err != nil {
+		if err := UpdateOutdatedNetworkPolicyFromSQLite(cfg, outdatedPolicy, latestPolicy, reviewState); err != nil {
 			log.Error().Msg(err.Error())
 		}
 	}
@@ -131,12 +147,18 @@ var LastSysAlertID int64 = 0
 // =================== //
 
 func UpdateOutdatedSystemPolicy(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string) {
+	UpdateOutdatedSystemPolicyReview(cfg, outdatedPolicy, latestPolicy, ReviewStateSuperseded)
+}
+
+// UpdateOutdatedSystemPolicyReview marks the policy outdated by the latest one with the review state,
+// a reviewed policy keeps its state as the version to roll back to
+func UpdateOutdatedSystemPolicyReview(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string, reviewState string) {
 	if cfg.DBDriver == "mysql" {
-		if err := UpdateOutdatedNetworkPolicyFromMySQL(cfg, outdatedPolicy, latestPolicy); err != nil {
+		if err := UpdateOutdatedSystemPolicyFromMySQL(cfg, outdatedPolicy, latestPolicy, reviewState); err != nil {
 			log.Error().Msg(err.Error())
 		}
 	} else if cfg.DBDriver == "sqlite3" {
-		if err := UpdateOutdatedNetworkPolicyFromSQLite(cfg, outdatedPolicy, latestPolicy); err != nil {
+		if err := UpdateOutdatedSystemPolicyFromSQLite(cfg, outdatedPolicy, latestPolicy, reviewState); err != nil {
 			log.Error().Msg(err.Error())
 		}
 	}
@@ -187,6 +209,21 @@ func UpdateSystemPolicy(cfg types.ConfigDB, policy types.KnoxSystemPolicy) {
 
 }
 
+// UpdatePolicyReview moves a network or system policy to the review state of the request
+func UpdatePolicyReview(cfg types.ConfigDB, review *types.PolicyReview) error {
+	if review.PolicyType != "network" && review.PolicyType != "system" {
+		return errors.New("no policy type, choose network or system, not [" + review.PolicyType + "]")
+	}
+
+	if cfg.DBDriver == "mysql" {
+		return UpdatePolicyReviewToMySQL(cfg, review)
+	} else if cfg.DBDriver == "sqlite3" {
+		return UpdatePolicyReviewToSQLite(cfg, review)
+	}
+
+	return errors.New("no db driver")
+}
+
 func GetWorkloadProcessFileSet(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet) (map[types.WorkloadProcessFileSet][]string, types.PolicyNameMap, error) {
 	if cfg.DBDriver == "mysql" {
 		res, pnMap, err := GetWorkloadProcessFileSetMySQL(cfg, wpfs)
@@ -204,6 +241,26 @@ func GetWorkloadProcessFileSet(cfg types.ConfigDB, wpfs types.WorkloadProcessFil
 	return nil, nil, errors.New("no db driver")
 }
 
+// GetWorkloadProcessFileSetInRange returns the sets of the workloads updated in the time range
+func GetWorkloadProcessFileSetInRange(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, since, until int64) (map[types.WorkloadProcessFileSet][]string, types.PolicyNameMap, error) {
+	if cfg.DBDriver == "mysql" {
+		return GetWorkloadProcessFileSetInRangeMySQL(cfg, wpfs, since, until)
+	} else if cfg.DBDriver == "sqlite3" {
+		return GetWorkloadProcessFileSetInRangeSQLite(cfg, wpfs, since, until)
+	}
+	return nil, nil, errors.New("no db driver")
+}
+
+// GetWorkloadPage returns the workloads of the sets updated in the time range in the requested page
+func GetWorkloadPage(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, page types.InsightQuery) ([]types.WorkloadProcessFileSet, error) {
+	if cfg.DBDriver == "mysql" {
+		return GetWorkloadPageMySQL(cfg, wpfs, page)
+	} else if cfg.DBDriver == "sqlite3" {
+		return GetWorkloadPageSQLite(cfg, wpfs, page)
+	}
+	return nil, errors.New("no db driver")
+}
+
 func InsertWorkloadProcessFileSet(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, fs []string) error {
 	if cfg.DBDriver == "mysql" {
 		return InsertWorkloadProcessFileSetMySQL(cfg, wpfs, fs)
@@ -213,6 +270,15 @@ func InsertWorkloadProcessFileSet(cfg types.ConfigDB, wpfs types.WorkloadProcess
 	return errors.New("no db driver")
 }
 
+func UpdateWorkloadProcessFileSet(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, fs []string) error {
+	if cfg.DBDriver == "mysql" {
+		return UpdateWorkloadProcessFileSetMySQL(cfg, wpfs, fs)
+	} else if cfg.DBDriver == "sqlite3" {
+		return UpdateWorkloadProcessFileSetSQLite(cfg, wpfs, fs)
+	}
+	return errors.New("no db driver")
+}
+
 func ClearWPFSDb(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, duration int64) error {
 	if cfg.DBDriver == "mysql" {
 		return ClearWPFSDbMySQL(cfg, wpfs, duration)
@@ -222,6 +288,45 @@ func ClearWPFSDb(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, duration
 	return errors.New("no db driver")
 }
 
+// ============================= //
+// == Workload Learning State == //
+// ============================= //
+
+func GetWorkloadLearningStates(cfg types.ConfigDB, filter types.WorkloadLearningState) ([]types.WorkloadLearningState, error) {
+	if cfg.DBDriver == "mysql" {
+		res, err := GetWorkloadLearningStatesMySQL(cfg, filter)
+		if err != nil {
+			log.Error().Msg(err.Error())
+		}
+		return res, err
+	} else if cfg.DBDriver == "sqlite3" {
+		res, err := GetWorkloadLearningStatesSQLite(cfg, filter)
+		if err != nil {
+			log.Error().Msg(err.Error())
+		}
+		return res, err
+	}
+	return nil, errors.New("no db driver")
+}
+
+func InsertWorkloadLearningState(cfg types.ConfigDB, state types.WorkloadLearningState) error {
+	if cfg.DBDriver == "mysql" {
+		return InsertWorkloadLearningStateMySQL(cfg, state)
+	} else if cfg.DBDriver == "sqlite3" {
+		return InsertWorkloadLearningStateSQLite(cfg, state)
+	}
+	return errors.New("no db driver")
+}
+
+func UpdateWorkloadLearningState(cfg types.ConfigDB, state types.WorkloadLearningState) error {
+	if cfg.DBDriver == "mysql" {
+		return UpdateWorkloadLearningStateMySQL(cfg, state)
+	} else if cfg.DBDriver == "sqlite3" {
+		return UpdateWorkloadLearningStateSQLite(cfg, state)
+	}
+	return errors.New("no db driver")
+}
+
 // =========== //
 // == Table == //
 // =========== //
@@ -257,10 +362,13 @@ func CreateTablesIfNotExist(cfg types.ConfigDB) {
 		if err := CreateTableWorkLoadProcessFileSetMySQL(cfg); err != nil {
 			log.Error().Msg(err.Error())
 		}
-		if err := CreateTableSystemLogsMySQL(cfg); err != nil {
+		if err := CreateTableWorkloadLearningStateMySQL(cfg); err != nil {
 			log.Error().Msg(err.Error())
 		}
-		if err := CreateTableNetworkLogsMySQL(cfg); err != nil {
+		if err := CreateTablePolicyChangeMySQL(cfg); err != nil {
+			log.Error().Msg(err.Error())
+		}
+		if err := CreateTableRuleEvidenceMySQL(cfg); err != nil {
 			log.Error().Msg(err.Error())
 		}
 	} else if cfg.DBDriver == "sqlite3" {
@@ -273,10 +381,13 @@ func CreateTablesIfNotExist(cfg types.ConfigDB) {
 		if err := CreateTableWorkLoadProcessFileSetSQLite(cfg); err != nil {
 			log.Error().Msg(err.Error())
 		}
-		if err := CreateTableSystemLogsSQLite(cfg); err != nil {
+		if err := CreateTableWorkloadLearningStateSQLite(cfg); err != nil {
+			log.Error().Msg(err.Error())
+		}
+		if err := CreateTablePolicyChangeSQLite(cfg); err != nil {
 			log.Error().Msg(err.Error())
 		}
-		if err := CreateTableNetworkLogsSQLite(cfg); err != nil {
+		if err := CreateTableRuleEvidenceSQLite(cfg); err != nil {
 			log.Error().Msg(err.Error())
 		}
 	}
@@ -285,46 +396,64 @@ func CreateTablesIfNotExist(cfg types.ConfigDB) {
 // =================== //
 // == Observability == //
 // =================== //
-func UpdateOrInsertKubearmorLogs(cfg types.ConfigDB, kubearmorLogs []types.KubeArmorLog) error {
+// UpdateOrInsertKubearmorLogs counts the kubearmor logs, and returns the logs seen for the first time
+func UpdateOrInsertKubearmorLogs(cfg types.ConfigDB, kubearmorLogs []types.KubeArmorLog) ([]types.KubeArmorLog, error) {
+	inserted := []types.KubeArmorLog{}
 	var err = errors.New("unknown db driver")
 	if cfg.DBDriver == "mysql" {
-		err = UpdateOrInsertKubearmorLogsMySQL(cfg, kubearmorLogs)
+		inserted, err = UpdateOrInsertKubearmorLogsMySQL(cfg, kubearmorLogs)
 	} else if cfg.DBDriver == "sqlite3" {
-		err = UpdateOrInsertKubearmorLogsSQLite(cfg, kubearmorLogs)
+		inserted, err = UpdateOrInsertKubearmorLogsSQLite(cfg, kubearmorLogs)
 	}
-	return err
+	return inserted, err
 }
 
 func GetKubearmorLogs(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]types.KubeArmorLog, []uint32, error) {
+	kubearmorLog, totalCount, _, err := GetKubearmorLogsByQuery(cfg, filterLog, types.LogQuery{})
+	return kubearmorLog, totalCount, err
+}
+
+// GetKubearmorLogsByQuery returns a page of the kubearmor logs and the token of the next page
+func GetKubearmorLogsByQuery(cfg types.ConfigDB, filterLog types.KubeArmorLog, logQuery types.LogQuery) ([]types.KubeArmorLog, []uint32, string, error) {
 	kubearmorLog := []types.KubeArmorLog{}
 	totalCount := []uint32{}
+	nextPageToken := ""
 	var err = errors.New("unknown db driver")
 	if cfg.DBDriver == "mysql" {
-		kubearmorLog, totalCount, err = GetSystemLogsMySQL(cfg, filterLog)
+		kubearmorLog, totalCount, nextPageToken, err = GetSystemLogsMySQL(cfg, filterLog, logQuery)
 	} else if cfg.DBDriver == "sqlite3" {
-		kubearmorLog, totalCount, err = GetSystemLogsSQLite(cfg, filterLog)
+		kubearmorLog, totalCount, nextPageToken, err = GetSystemLogsSQLite(cfg, filterLog, logQuery)
 	}
-	return kubearmorLog, totalCount, err
+	return kubearmorLog, totalCount, nextPageToken, err
 }
 
-func UpdateOrInsertCiliumLogs(cfg types.ConfigDB, ciliumLogs []types.CiliumLog) error {
+// UpdateOrInsertCiliumLogs counts the cilium logs, and returns the logs seen for the first time
+func UpdateOrInsertCiliumLogs(cfg types.ConfigDB, ciliumLogs []types.CiliumLog) ([]types.CiliumLog, error) {
+	inserted := []types.CiliumLog{}
 	var err = errors.New("unknown db driver")
 	if cfg.DBDriver == "mysql" {
-		err = UpdateOrInsertCiliumLogsMySQL(cfg, ciliumLogs)
+		inserted, err = UpdateOrInsertCiliumLogsMySQL(cfg, ciliumLogs)
 	} else if cfg.DBDriver == "sqlite3" {
-		err = UpdateOrInsertCiliumLogsSQLite(cfg, ciliumLogs)
+		inserted, err = UpdateOrInsertCiliumLogsSQLite(cfg, ciliumLogs)
 	}
-	return err
+	return inserted, err
 }
 
 func GetCiliumLogs(cfg types.ConfigDB, ciliumFilter types.CiliumLog) ([]types.CiliumLog, []uint32, error) {
+	ciliumLogs, ciliumTotalCount, _, err := GetCiliumLogsByQuery(cfg, ciliumFilter, types.LogQuery{})
+	return ciliumLogs, ciliumTotalCount, err
+}
+
+// GetCiliumLogsByQuery returns a page of the cilium logs and the token of the next page
+func GetCiliumLogsByQuery(cfg types.ConfigDB, ciliumFilter types.CiliumLog, logQuery types.LogQuery) ([]types.CiliumLog, []uint32, string, error) {
 	ciliumLogs := []types.CiliumLog{}
 	ciliumTotalCount := []uint32{}
+	nextPageToken := ""
 	var err = errors.New("unknown db driver")
 	if cfg.DBDriver == "mysql" {
-		ciliumLogs, ciliumTotalCount, err = GetCiliumLogsMySQL(cfg, ciliumFilter)
+		ciliumLogs, ciliumTotalCount, nextPageToken, err = GetCiliumLogsMySQL(cfg, ciliumFilter, logQuery)
 	} else if cfg.DBDriver == "sqlite3" {
-		ciliumLogs, ciliumTotalCount, err = GetCiliumLogsSQLite(cfg, ciliumFilter)
+		ciliumLogs, ciliumTotalCount, nextPageToken, err = GetCiliumLogsSQLite(cfg, ciliumFilter, logQuery)
 	}
-	return ciliumLogs, ciliumTotalCount, err
+	return ciliumLogs, ciliumTotalCount, nextPageToken, err
 }
@@ -43,8 +43,10 @@ func TestGetNetworkPolicies(t *testing.T) {
 		"spec",          // []byte
 		"generatedTime", // uint64
 		"updatedTime",   // uint64
+		"review_state",  // str
+		"rule_stats",    // []byte
 	}).
-		AddRow("", "test", flowID, "", "", "", "", "", "", "", spec, 0, 0)
+		AddRow("", "test", flowID, "", "", "", "", "", "", "", spec, 0, 0, nil, nil)
 
 	mock.ExpectQuery("^SELECT (.+) FROM network_policy*").
 		WillReturnRows(rows)
@@ -85,6 +87,7 @@ func TestInsertNetworkPolicies(t *testing.T) {
 			spec,             // []byte
 			sqlmock.AnyArg(), // uint64
 			sqlmock.AnyArg(), // uint64
+			nil,              // []byte
 		).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	nfe := []types.KnoxNetworkPolicy{
@@ -101,6 +104,51 @@ func TestInsertNetworkPolicies(t *testing.T) {
 	}
 }
 
+func TestUpdateOutdatedNetworkPolicyReview(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+	defer func() { MockDB = nil }()
+
+	// the applied version is outdated by its tightened version and keeps its review state
+	mock.ExpectPrepare("UPDATE network_policy SET status=\\?,review_state=\\? WHERE name=\\?").
+		ExpectExec().
+		WithArgs("outdated", ReviewStateApplied, "autopol-egress-default-web").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("UPDATE network_policy SET outdated=\\? WHERE name=\\?").
+		ExpectExec().
+		WithArgs("autopol-egress-default-web-2", "autopol-egress-default-web").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	UpdateOutdatedNetworkPolicyReview(types.ConfigDB{DBDriver: "mysql"}, "autopol-egress-default-web",
+		"autopol-egress-default-web-2", ReviewStateApplied)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestUpdateOutdatedSystemPolicy(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+	defer func() { MockDB = nil }()
+
+	// the outdated version points to the latest one in the system policy table
+	mock.ExpectPrepare("UPDATE system_policy SET status=\\?,review_state=\\? WHERE name=\\?").
+		ExpectExec().
+		WithArgs("outdated", ReviewStateSuperseded, "autopol-system-default-web").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("UPDATE system_policy SET outdated=\\? WHERE name=\\?").
+		ExpectExec().
+		WithArgs("autopol-system-default-web-2", "autopol-system-default-web").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	UpdateOutdatedSystemPolicy(types.ConfigDB{DBDriver: "mysql"}, "autopol-system-default-web", "autopol-system-default-web-2")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
 func TestInsertNetworkPoliciesSQLite(t *testing.T) {
 	// prepare mock sqlite
 	_, mock := NewMock()
@@ -129,6 +177,7 @@ func TestInsertNetworkPoliciesSQLite(t *testing.T) {
 			spec,             // []byte
 			sqlmock.AnyArg(), // uint64
 			sqlmock.AnyArg(), // uint64
+			nil,              // []byte
 		).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	nfe := []types.KnoxNetworkPolicy{
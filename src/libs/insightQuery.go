@@ -0,0 +1,55 @@
+package libs
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ========================== //
+// == Insight Query Clause == //
+// ========================== //
+
+const (
+	networkPolicyTimeColumn = "COALESCE(NULLIF(updatedTime, 0), generatedTime)"
+	wpfsTimeColumn          = "updatedTime"
+)
+
+var (
+	networkPolicyKeyColumns = []string{"cluster_name", "namespace", "type", "rule", "name"}
+	wpfsKeyColumns          = []string{"clusterName", "namespace", "labels", "containerName"}
+)
+
+// concatInsightQueryClause appends the time range of the time column to the where clause, and returns the
+// clause ordering the rows by the key columns, limited to the requested page
+func concatInsightQueryClause(whereClause *string, args *[]interface{}, timeColumn string, keyColumns []string, query types.InsightQuery) (string, error) {
+	sortOrder, err := getLogSortOrder(query.SortOrder)
+	if err != nil {
+		return "", err
+	}
+
+	if query.Since != 0 {
+		concatWhereClauseExpr(whereClause, timeColumn+" >= ?")
+		*args = append(*args, query.Since)
+	}
+	if query.Until != 0 {
+		concatWhereClauseExpr(whereClause, timeColumn+" <= ?")
+		*args = append(*args, query.Until)
+	}
+
+	orders := []string{}
+	for _, column := range keyColumns {
+		orders = append(orders, column+" "+sortOrder)
+	}
+	orderClause := " ORDER BY " + strings.Join(orders, ", ")
+
+	if query.Limit > 0 {
+		orderClause = orderClause + " LIMIT " + strconv.Itoa(query.Limit)
+		if query.Offset > 0 {
+			orderClause = orderClause + " OFFSET " + strconv.Itoa(query.Offset)
+		}
+	}
+
+	return orderClause, nil
+}
@@ -0,0 +1,35 @@
+package libs
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcatInsightQueryClause(t *testing.T) {
+	var whereClause string
+	var args []interface{}
+
+	orderClause, err := concatInsightQueryClause(&whereClause, &args, networkPolicyTimeColumn, networkPolicyKeyColumns, types.InsightQuery{
+		Since:     100,
+		Until:     200,
+		Limit:     10,
+		Offset:    20,
+		SortOrder: LogSortOrderDesc,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, " WHERE COALESCE(NULLIF(updatedTime, 0), generatedTime) >= ? and COALESCE(NULLIF(updatedTime, 0), generatedTime) <= ?", whereClause)
+	assert.Equal(t, []interface{}{int64(100), int64(200)}, args)
+	assert.Equal(t, " ORDER BY cluster_name DESC, namespace DESC, type DESC, rule DESC, name DESC LIMIT 10 OFFSET 20", orderClause)
+
+	// no page without a limit
+	whereClause, args = "", nil
+	orderClause, err = concatInsightQueryClause(&whereClause, &args, wpfsTimeColumn, wpfsKeyColumns, types.InsightQuery{Offset: 20})
+	assert.NoError(t, err)
+	assert.Empty(t, whereClause)
+	assert.Equal(t, " ORDER BY clusterName ASC, namespace ASC, labels ASC, containerName ASC", orderClause)
+
+	_, err = concatInsightQueryClause(&whereClause, &args, wpfsTimeColumn, wpfsKeyColumns, types.InsightQuery{SortOrder: "up"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,224 @@
+package libs
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ==================== //
+// == Label Selector == //
+// ==================== //
+
+// label selector operators, as in the kubernetes label selectors
+const (
+	LabelSelectorEquals       = "="
+	LabelSelectorNotEquals    = "!="
+	LabelSelectorIn           = "in"
+	LabelSelectorNotIn        = "notin"
+	LabelSelectorExists       = "exists"
+	LabelSelectorDoesNotExist = "!"
+)
+
+// LabelRequirement a requirement of a label selector on the value of a label key
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+// LabelSelector the requirements of a selector, a set of labels matches if all of them are met
+type LabelSelector []LabelRequirement
+
+// splitLabelSelector splits the selector by the commas not in the value set of in/notin
+func splitLabelSelector(selector string) ([]string, error) {
+	terms := []string{}
+	depth, start := 0, 0
+
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, errors.New("unbalanced parentheses in label selector " + selector)
+			}
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, errors.New("unbalanced parentheses in label selector " + selector)
+	}
+
+	return append(terms, selector[start:]), nil
+}
+
+func parseLabelSetRequirement(term, operator string) (LabelRequirement, error) {
+	idx := strings.Index(term, " "+operator+" ")
+	key := strings.TrimSpace(term[:idx])
+	set := strings.TrimSpace(term[idx+len(operator)+2:])
+
+	if key == "" || !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+		return LabelRequirement{}, errors.New("invalid label selector requirement " + term)
+	}
+
+	values := []string{}
+	for _, value := range strings.Split(set[1:len(set)-1], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return LabelRequirement{Key: key, Operator: operator, Values: values}, nil
+}
+
+func parseLabelRequirement(term string) (LabelRequirement, error) {
+	term = strings.TrimSpace(term)
+
+	switch {
+	case strings.Contains(term, " "+LabelSelectorNotIn+" "):
+		return parseLabelSetRequirement(term, LabelSelectorNotIn)
+	case strings.Contains(term, " "+LabelSelectorIn+" "):
+		return parseLabelSetRequirement(term, LabelSelectorIn)
+	}
+
+	for _, operator := range []string{"!=", "==", "="} {
+		if idx := strings.Index(term, operator); idx > 0 {
+			requirement := LabelRequirement{
+				Key:      strings.TrimSpace(term[:idx]),
+				Operator: LabelSelectorEquals,
+				Values:   []string{strings.TrimSpace(term[idx+len(operator):])},
+			}
+			if operator == "!=" {
+				requirement.Operator = LabelSelectorNotEquals
+			}
+			return requirement, nil
+		}
+	}
+
+	if strings.HasPrefix(term, "!") {
+		if key := strings.TrimSpace(term[1:]); key != "" {
+			return LabelRequirement{Key: key, Operator: LabelSelectorDoesNotExist}, nil
+		}
+	} else if term != "" && !strings.ContainsAny(term, "=!() ") {
+		return LabelRequirement{Key: term, Operator: LabelSelectorExists}, nil
+	}
+
+	return LabelRequirement{}, errors.New("invalid label selector requirement " + term)
+}
+
+// ParseLabelSelector parses a selector of comma separated requirements,
+// e.g. "app=web,tier!=db,env in (prod,staging),!canary"
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	parsed := LabelSelector{}
+	if strings.TrimSpace(selector) == "" {
+		return parsed, nil
+	}
+
+	terms, err := splitLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, term := range terms {
+		requirement, err := parseLabelRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, requirement)
+	}
+
+	return parsed, nil
+}
+
+// Empty returns true if the selector matches every set of labels
+func (s LabelSelector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches returns true if the labels (key=value) meet all the requirements of the selector
+func (s LabelSelector) Matches(labels []string) bool {
+	labelMap := map[string]string{}
+	for _, label := range labels {
+		kv := strings.SplitN(strings.TrimSpace(label), "=", 2)
+		if len(kv) == 2 {
+			labelMap[kv[0]] = kv[1]
+		}
+	}
+	return s.MatchesMap(labelMap)
+}
+
+// MatchesMap returns true if the labels meet all the requirements of the selector
+func (s LabelSelector) MatchesMap(labels map[string]string) bool {
+	for _, requirement := range s {
+		value, ok := labels[requirement.Key]
+
+		switch requirement.Operator {
+		case LabelSelectorEquals, LabelSelectorIn:
+			if !ok || !ContainsElement(requirement.Values, value) {
+				return false
+			}
+		case LabelSelectorNotEquals, LabelSelectorNotIn:
+			if ok && ContainsElement(requirement.Values, value) {
+				return false
+			}
+		case LabelSelectorExists:
+			if !ok {
+				return false
+			}
+		case LabelSelectorDoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// LabelSelectorFromMap returns the selector of the policy match labels, the k8s source prefix is ignored and the
+// namespace label is left to the namespace of the policy
+func LabelSelectorFromMap(matchLabels map[string]string) LabelSelector {
+	selector := LabelSelector{}
+	for k, v := range matchLabels {
+		if k == "k8s:io.kubernetes.pod.namespace" || k == "io.kubernetes.pod.namespace" {
+			continue
+		}
+		selector = append(selector, LabelRequirement{Key: strings.TrimPrefix(k, "k8s:"), Operator: LabelSelectorEquals, Values: []string{v}})
+	}
+	return selector
+}
+
+// =================== //
+// == Label Strings == //
+// =================== //
+
+// SortLabels joins the non-empty labels in order
+func SortLabels(labels []string) string {
+	results := []string{}
+	for _, label := range labels {
+		if label != "" {
+			results = append(results, label)
+		}
+	}
+	sort.Strings(results)
+
+	return strings.Join(results, ",")
+}
+
+// LabelMapToString converts the policy match labels to the sorted key=val labels, without the namespace label
+func LabelMapToString(labels map[string]string) string {
+	results := []string{}
+	for k, v := range labels {
+		if k == "k8s:io.kubernetes.pod.namespace" || k == "io.kubernetes.pod.namespace" {
+			continue
+		}
+		results = append(results, strings.TrimPrefix(k, "k8s:")+"="+v)
+	}
+
+	return SortLabels(results)
+}
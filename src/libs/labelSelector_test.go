@@ -0,0 +1,61 @@
+package libs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	selector, err := ParseLabelSelector("app=web, tier!=db,env in (prod, staging),!canary,team")
+	assert.NoError(t, err)
+	assert.Equal(t, LabelSelector{
+		{Key: "app", Operator: LabelSelectorEquals, Values: []string{"web"}},
+		{Key: "tier", Operator: LabelSelectorNotEquals, Values: []string{"db"}},
+		{Key: "env", Operator: LabelSelectorIn, Values: []string{"prod", "staging"}},
+		{Key: "canary", Operator: LabelSelectorDoesNotExist},
+		{Key: "team", Operator: LabelSelectorExists},
+	}, selector)
+
+	selector, err = ParseLabelSelector("")
+	assert.NoError(t, err)
+	assert.True(t, selector.Empty())
+
+	_, err = ParseLabelSelector("env in (prod")
+	assert.Error(t, err)
+	_, err = ParseLabelSelector("env in prod")
+	assert.Error(t, err)
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := []string{"tier=front", "app=web", "env=prod"}
+
+	for selector, matches := range map[string]bool{
+		"app=web,env=prod":       true,
+		"env=prod,app=web":       true,
+		"app==web":               true,
+		"app=db":                 false,
+		"app=web,role=admin":     false,
+		"env in (prod,staging)":  true,
+		"env notin (prod,dev)":   false,
+		"app in (web),tier!=db":  true,
+		"!canary":                true,
+		"!tier":                  false,
+		"tier":                   true,
+		"role notin (admin)":     true,
+		"role in (admin, users)": false,
+	} {
+		parsed, err := ParseLabelSelector(selector)
+		assert.NoError(t, err)
+		assert.Equal(t, matches, parsed.Matches(labels), selector)
+	}
+}
+
+func TestLabelSelectorFromMap(t *testing.T) {
+	selector := LabelSelectorFromMap(map[string]string{"k8s:app": "web", "k8s:io.kubernetes.pod.namespace": "default"})
+	assert.True(t, selector.Matches([]string{"app=web", "tier=front"}))
+	assert.False(t, selector.Matches([]string{"app=db"}))
+
+	assert.Equal(t, "app=web", LabelMapToString(map[string]string{"k8s:app": "web", "io.kubernetes.pod.namespace": "default"}))
+	assert.Equal(t, "app=web,tier=front", SortLabels([]string{"tier=front", "", "app=web"}))
+}
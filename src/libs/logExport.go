@@ -0,0 +1,409 @@
+package libs
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ==================== //
+// == Log Export     == //
+// ==================== //
+
+const (
+	LogExportCSV     = "csv"
+	LogExportParquet = "parquet"
+
+	LogExportSystem  = "system"
+	LogExportNetwork = "network"
+
+	// number of logs read from the db per query
+	logExportPageSize = 1000
+)
+
+// LogExportFormat set by the command line to export the observability logs instead of running the server
+var LogExportFormat string = ""
+
+// LogExportRequest set by the command line to select the exported logs
+var LogExportRequest types.LogExportRequest
+
+// logExportColumnName returns the snake case name of the json tag, so the columns of the kubearmor
+// and cilium logs are named alike, e.g. ContainerID -> container_id, HostPPID -> host_ppid
+func logExportColumnName(tag string) string {
+	runes := []rune(strings.Split(tag, ",")[0])
+	name := []rune{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				name = append(name, '_')
+			}
+		}
+		name = append(name, unicode.ToLower(r))
+	}
+	return string(name)
+}
+
+// LogExportColumns returns the columns of the log type, one per field named after its json tag in snake case,
+// the columns of types.KubeArmorLog are followed by its total count
+func LogExportColumns(logType string) ([]ParquetColumn, error) {
+	var typ reflect.Type
+	switch logType {
+	case LogExportSystem:
+		typ = reflect.TypeOf(types.KubeArmorLog{})
+	case LogExportNetwork:
+		typ = reflect.TypeOf(types.CiliumLog{})
+	default:
+		return nil, errors.New("invalid log type " + logType)
+	}
+
+	columns := []ParquetColumn{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		column := ParquetColumn{Name: logExportColumnName(field.Tag.Get("json"))}
+		switch field.Type.Kind() {
+		case reflect.String:
+			column.Type = ParquetByteArray
+		case reflect.Bool:
+			column.Type = ParquetBoolean
+		case reflect.Int32:
+			column.Type = ParquetInt32
+		case reflect.Uint32:
+			column.Type, column.Unsigned = ParquetInt32, true
+		case reflect.Int64:
+			column.Type = ParquetInt64
+		default:
+			return nil, errors.New("unsupported field type of " + field.Name)
+		}
+		columns = append(columns, column)
+	}
+
+	if logType == LogExportSystem {
+		columns = append(columns, ParquetColumn{Name: "total", Type: ParquetInt64})
+	}
+
+	return columns, nil
+}
+
+// logExportRow returns the values of the fields of the log, in the order of LogExportColumns
+func logExportRow(log interface{}) []interface{} {
+	value := reflect.ValueOf(log)
+	row := make([]interface{}, 0, value.NumField()+1)
+	for i := 0; i < value.NumField(); i++ {
+		row = append(row, value.Field(i).Interface())
+	}
+	return row
+}
+
+// LogExportWriter writes the rows of a log type to a file
+type LogExportWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// csvLogWriter writes the rows as csv, with a header of the column names
+type csvLogWriter struct {
+	w *csv.Writer
+}
+
+func newCSVLogWriter(w io.Writer, columns []ParquetColumn) (*csvLogWriter, error) {
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, column.Name)
+	}
+
+	cw := &csvLogWriter{w: csv.NewWriter(w)}
+	if err := cw.w.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvLogWriter) WriteRow(values []interface{}) error {
+	record := make([]string, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			record = append(record, v)
+		case bool:
+			record = append(record, strconv.FormatBool(v))
+		case int32:
+			record = append(record, strconv.FormatInt(int64(v), 10))
+		case uint32:
+			record = append(record, strconv.FormatUint(uint64(v), 10))
+		case int64:
+			record = append(record, strconv.FormatInt(v, 10))
+		default:
+			return errors.New("unsupported csv value")
+		}
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvLogWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// NewLogExportWriter returns the csv or parquet writer of the columns
+func NewLogExportWriter(w io.Writer, format string, columns []ParquetColumn) (LogExportWriter, error) {
+	switch format {
+	case LogExportCSV:
+		return newCSVLogWriter(w, columns)
+	case LogExportParquet:
+		return NewParquetWriter(w, columns)
+	}
+	return nil, errors.New("invalid export format " + format)
+}
+
+// exportSystemLogs writes the kubearmor logs of the request page by page
+func exportSystemLogs(cfg types.ConfigDB, request types.LogExportRequest, writer LogExportWriter) (int64, error) {
+	filter := types.KubeArmorLog{NamespaceName: request.Namespace, Labels: request.Labels}
+	query := types.LogQuery{Since: request.Since, Until: request.Until, Limit: logExportPageSize}
+
+	count := int64(0)
+	for {
+		logs, totals, nextPageToken, err := GetKubearmorLogsByQuery(cfg, filter, query)
+		if err != nil {
+			return count, err
+		}
+		for i, sysLog := range logs {
+			row := append(logExportRow(sysLog), int64(totals[i]))
+			if err := writer.WriteRow(row); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if nextPageToken == "" {
+			return count, nil
+		}
+		query.PageToken = nextPageToken
+	}
+}
+
+// exportNetworkLogs writes the cilium logs of the request page by page, the logs from the namespace/labels
+// of the request are followed by the logs to them
+func exportNetworkLogs(cfg types.ConfigDB, request types.LogExportRequest, writer LogExportWriter) (int64, error) {
+	srcFilter := types.CiliumLog{SourceNamespace: request.Namespace, SourceLabels: request.Labels}
+	count, err := exportCiliumLogs(cfg, request, srcFilter, nil, writer)
+	if err != nil || (request.Namespace == "" && request.Labels == "") {
+		return count, err
+	}
+
+	// the logs between two endpoints of the request were written by the source pass
+	isSource := func(netLog types.CiliumLog) bool {
+		return (request.Namespace == "" || netLog.SourceNamespace == request.Namespace) &&
+			(request.Labels == "" || netLog.SourceLabels == request.Labels)
+	}
+	dstFilter := types.CiliumLog{DestinationNamespace: request.Namespace, DestinationLabels: request.Labels}
+	dstCount, err := exportCiliumLogs(cfg, request, dstFilter, isSource, writer)
+	return count + dstCount, err
+}
+
+// exportCiliumLogs writes the cilium logs of the filter page by page, except the logs skipped by skip
+func exportCiliumLogs(cfg types.ConfigDB, request types.LogExportRequest, filter types.CiliumLog, skip func(types.CiliumLog) bool, writer LogExportWriter) (int64, error) {
+	query := types.LogQuery{Since: request.Since, Until: request.Until, Limit: logExportPageSize}
+
+	count := int64(0)
+	for {
+		logs, totals, nextPageToken, err := GetCiliumLogsByQuery(cfg, filter, query)
+		if err != nil {
+			return count, err
+		}
+		for i, netLog := range logs {
+			if skip != nil && skip(netLog) {
+				continue
+			}
+			netLog.Total = int64(totals[i])
+			if err := writer.WriteRow(logExportRow(netLog)); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if nextPageToken == "" {
+			return count, nil
+		}
+		query.PageToken = nextPageToken
+	}
+}
+
+// ResolveLogExportDir returns the export directory of a request under the base directory, the directories
+// of the request resolving outside of the base directory, also through symlinks, are rejected
+func ResolveLogExportDir(baseDir, dir string) (string, error) {
+	if filepath.IsAbs(dir) {
+		return "", errors.New("the export directory must be relative to the configured export directory")
+	}
+
+	base, err := filepath.Abs(baseDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(base, 0750); err != nil {
+		return "", err
+	}
+	realBase, err := filepath.EvalSymlinks(base)
+	if err != nil {
+		return "", err
+	}
+
+	resolved := filepath.Join(realBase, dir)
+	if !isSubPath(realBase, resolved) {
+		return "", errors.New("the export directory " + dir + " is outside of the configured export directory")
+	}
+
+	// the deepest existing directory may be a symlink out of the base directory
+	existing := resolved
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+	realExisting, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", err
+	}
+	if !isSubPath(realBase, realExisting) {
+		return "", errors.New("the export directory " + dir + " is outside of the configured export directory")
+	}
+
+	if err := os.MkdirAll(resolved, 0750); err != nil {
+		return "", err
+	}
+	realResolved, err := filepath.EvalSymlinks(resolved)
+	if err != nil {
+		return "", err
+	}
+	if !isSubPath(realBase, realResolved) {
+		return "", errors.New("the export directory " + dir + " is outside of the configured export directory")
+	}
+
+	return realResolved, nil
+}
+
+// isSubPath reports whether the path is the base path or under it
+func isSubPath(base, path string) bool {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ExportLogs writes the system and/or network logs of the request to
+// <dir>/kubearmor_logs.<format> and <dir>/cilium_logs.<format>
+func ExportLogs(cfg types.ConfigDB, request types.LogExportRequest) ([]types.LogExportFile, error) {
+	if request.Format != LogExportCSV && request.Format != LogExportParquet {
+		return nil, errors.New("invalid export format " + request.Format)
+	}
+
+	logTypes := []string{}
+	switch request.Type {
+	case "":
+		logTypes = []string{LogExportSystem, LogExportNetwork}
+	case LogExportSystem, LogExportNetwork:
+		logTypes = []string{request.Type}
+	default:
+		return nil, errors.New("invalid log type " + request.Type)
+	}
+
+	if request.Dir == "" {
+		request.Dir = "."
+	}
+	if err := os.MkdirAll(request.Dir, 0750); err != nil {
+		return nil, err
+	}
+
+	files := []types.LogExportFile{}
+	for _, logType := range logTypes {
+		file, err := exportLogType(cfg, request, logType)
+		if err != nil {
+			return files, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func exportLogType(cfg types.ConfigDB, request types.LogExportRequest, logType string) (types.LogExportFile, error) {
+	exported := types.LogExportFile{Type: logType}
+
+	columns, err := LogExportColumns(logType)
+	if err != nil {
+		return exported, err
+	}
+
+	name := "kubearmor_logs"
+	if logType == LogExportNetwork {
+		name = "cilium_logs"
+	}
+	exported.Path = filepath.Join(request.Dir, name+"."+request.Format)
+
+	// an existing file is overwritten, but not through a symlink
+	if info, err := os.Lstat(exported.Path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return exported, errors.New("the export file " + exported.Path + " is a symlink")
+	}
+
+	// #nosec G304 the path is the export directory of the request
+	file, err := os.Create(exported.Path)
+	if err != nil {
+		return exported, err
+	}
+	defer file.Close()
+
+	writer, err := NewLogExportWriter(file, request.Format, columns)
+	if err != nil {
+		return exported, err
+	}
+
+	if logType == LogExportSystem {
+		exported.Rows, err = exportSystemLogs(cfg, request, writer)
+	} else {
+		exported.Rows, err = exportNetworkLogs(cfg, request, writer)
+	}
+	if err != nil {
+		return exported, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return exported, err
+	}
+	return exported, file.Close()
+}
+
+// parseLogExportTime parses a unix time or an RFC3339 time of the command line
+func parseLogExportTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return unix, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Unix(), nil
+}
+
+// RunLogExportCommand exports the observability logs selected by the command line
+func RunLogExportCommand(obsDB types.ConfigDB) error {
+	LogExportRequest.Format = LogExportFormat
+
+	files, err := ExportLogs(obsDB, LogExportRequest)
+	for _, file := range files {
+		log.Info().Msgf("exported %d %s logs to %s", file.Rows, file.Type, file.Path)
+	}
+	return err
+}
@@ -0,0 +1,134 @@
+package libs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func TestLogExportColumns(t *testing.T) {
+	columns, err := LogExportColumns(LogExportSystem)
+	assert.NoError(t, err)
+	assert.Equal(t, ParquetColumn{Name: "timestamp", Type: ParquetInt64}, columns[0])
+	assert.Equal(t, ParquetColumn{Name: "cluster_name", Type: ParquetByteArray}, columns[2])
+	assert.Equal(t, ParquetColumn{Name: "host_ppid", Type: ParquetInt32}, columns[12])
+	assert.Equal(t, ParquetColumn{Name: "total", Type: ParquetInt64}, columns[len(columns)-1])
+	assert.Equal(t, len(columns), len(logExportRow(types.KubeArmorLog{}))+1)
+
+	columns, err = LogExportColumns(LogExportNetwork)
+	assert.NoError(t, err)
+	assert.Equal(t, ParquetColumn{Name: "ip_encrypted", Type: ParquetBoolean}, columns[4])
+	assert.Equal(t, ParquetColumn{Name: "l4_tcp_source_port", Type: ParquetInt32, Unsigned: true}, columns[5])
+	assert.Equal(t, ParquetColumn{Name: "total", Type: ParquetInt64}, columns[len(columns)-1])
+	assert.Equal(t, len(columns), len(logExportRow(types.CiliumLog{})))
+
+	_, err = LogExportColumns("audit")
+	assert.Error(t, err)
+}
+
+func TestCSVLogWriter(t *testing.T) {
+	var buf bytes.Buffer
+	columns := []ParquetColumn{
+		{Name: "name", Type: ParquetByteArray},
+		{Name: "reply", Type: ParquetBoolean},
+		{Name: "port", Type: ParquetInt32, Unsigned: true},
+		{Name: "total", Type: ParquetInt64},
+	}
+
+	writer, err := NewLogExportWriter(&buf, LogExportCSV, columns)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.WriteRow([]interface{}{"app=web,tier=front", true, uint32(80), int64(3)}))
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t, "name,reply,port,total\n\"app=web,tier=front\",true,80,3\n", buf.String())
+}
+
+func TestLogExportColumnName(t *testing.T) {
+	assert.Equal(t, "container_id", logExportColumnName("ContainerID,omitempty"))
+	assert.Equal(t, "host_ppid", logExportColumnName("HostPPID,omitempty"))
+	assert.Equal(t, "pid", logExportColumnName("PID,omitempty"))
+	assert.Equal(t, "in_out", logExportColumnName("InOut,omitempty"))
+	assert.Equal(t, "l4_icmpv4_type", logExportColumnName("l4_icmpv4_type,omitempty"))
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	columns := []ParquetColumn{
+		{Name: "name", Type: ParquetByteArray},
+		{Name: "port", Type: ParquetInt32, Unsigned: true},
+		{Name: "total", Type: ParquetInt64},
+	}
+
+	writer, err := NewLogExportWriter(&buf, LogExportParquet, columns)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.WriteRow([]interface{}{"web", uint32(80), int64(3)}))
+	assert.NoError(t, writer.WriteRow([]interface{}{"db", uint32(3306), int64(5)}))
+	assert.Error(t, writer.WriteRow([]interface{}{"db"}))
+	assert.NoError(t, writer.Close())
+
+	// read the file back with the parquet reader
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetColumnReader(pf, 1)
+	assert.NoError(t, err)
+	defer pr.ReadStop()
+
+	assert.Equal(t, int64(2), pr.GetNumRows())
+	assert.Equal(t, ParquetCreatedBy, pr.Footer.GetCreatedBy())
+
+	names, _, _, err := pr.ReadColumnByIndex(0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"web", "db"}, names)
+
+	ports, _, _, err := pr.ReadColumnByIndex(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int32(80), int32(3306)}, ports)
+
+	totals, _, _, err := pr.ReadColumnByIndex(2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(3), int64(5)}, totals)
+}
+
+func TestParquetWriterInvalidValue(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewParquetWriter(&buf, []ParquetColumn{{Name: "total", Type: ParquetInt64}})
+	assert.NoError(t, err)
+	assert.Error(t, writer.WriteRow([]interface{}{"3"}))
+	assert.NoError(t, writer.Close())
+}
+
+func TestResolveLogExportDir(t *testing.T) {
+	base := t.TempDir()
+	outside := t.TempDir()
+	realBase, err := filepath.EvalSymlinks(base)
+	assert.NoError(t, err)
+
+	dir, err := ResolveLogExportDir(base, "")
+	assert.NoError(t, err)
+	assert.Equal(t, realBase, dir)
+
+	dir, err = ResolveLogExportDir(base, "daily/web")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(realBase, "daily", "web"), dir)
+
+	_, err = ResolveLogExportDir(base, "../escaped")
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(filepath.Dir(base), "escaped"))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = ResolveLogExportDir(base, outside)
+	assert.Error(t, err)
+
+	// a symlink of the base directory pointing outside of it
+	assert.NoError(t, os.Symlink(outside, filepath.Join(base, "link")))
+	_, err = ResolveLogExportDir(base, "link/nested")
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(outside, "nested"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,151 @@
+package libs
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	LogSortByTime  = "time"
+	LogSortByCount = "count"
+
+	LogSortOrderAsc  = "asc"
+	LogSortOrderDesc = "desc"
+)
+
+// ====================== //
+// == Log Query Clause == //
+// ====================== //
+
+func getLogSortColumn(sortBy, idColumn string) (string, error) {
+	switch sortBy {
+	case "":
+		return idColumn, nil
+	case LogSortByTime:
+		return "updated_time", nil
+	case LogSortByCount:
+		return "total", nil
+	}
+	return "", errors.New("not a valid sort, use time/count")
+}
+
+func getLogSortOrder(sortOrder string) (string, error) {
+	switch strings.ToLower(sortOrder) {
+	case "", LogSortOrderAsc:
+		return "ASC", nil
+	case LogSortOrderDesc:
+		return "DESC", nil
+	}
+	return "", errors.New("not a valid sort order, use asc/desc")
+}
+
+// EncodeLogPageToken encodes the sort value and the id of the last row of a page
+func EncodeLogPageToken(value, id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(value, 10) + ":" + strconv.FormatInt(id, 10)))
+}
+
+// DecodeLogPageToken decodes the sort value and the id of the last row of the previous page
+func DecodeLogPageToken(token string) (int64, int64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, 0, errors.New("invalid page token")
+	}
+
+	vals := strings.Split(string(b), ":")
+	if len(vals) != 2 {
+		return 0, 0, errors.New("invalid page token")
+	}
+
+	value, err := strconv.ParseInt(vals[0], 10, 64)
+	if err != nil {
+		return 0, 0, errors.New("invalid page token")
+	}
+	id, err := strconv.ParseInt(vals[1], 10, 64)
+	if err != nil {
+		return 0, 0, errors.New("invalid page token")
+	}
+
+	return value, id, nil
+}
+
+func concatWhereClauseExpr(whereClause *string, expr string) {
+	if *whereClause == "" {
+		*whereClause = " WHERE "
+	} else {
+		*whereClause = *whereClause + " and "
+	}
+	*whereClause = *whereClause + expr
+}
+
+// concatLogQueryClause appends the time range and the page cursor (keyset on the sort column and the row id)
+// to the where clause, and returns the order and limit clause
+func concatLogQueryClause(whereClause *string, args *[]interface{}, idColumn string, query types.LogQuery) (string, error) {
+	sortColumn, err := getLogSortColumn(query.SortBy, idColumn)
+	if err != nil {
+		return "", err
+	}
+
+	sortOrder, err := getLogSortOrder(query.SortOrder)
+	if err != nil {
+		return "", err
+	}
+
+	if query.Since != 0 {
+		concatWhereClauseExpr(whereClause, "updated_time >= ?")
+		*args = append(*args, query.Since)
+	}
+	if query.Until != 0 {
+		concatWhereClauseExpr(whereClause, "updated_time <= ?")
+		*args = append(*args, query.Until)
+	}
+
+	if query.PageToken != "" {
+		value, id, err := DecodeLogPageToken(query.PageToken)
+		if err != nil {
+			return "", err
+		}
+
+		op := ">"
+		if sortOrder == "DESC" {
+			op = "<"
+		}
+
+		if sortColumn == idColumn {
+			concatWhereClauseExpr(whereClause, idColumn+" "+op+" ?")
+			*args = append(*args, id)
+		} else {
+			concatWhereClauseExpr(whereClause, "("+sortColumn+" "+op+" ? or ("+sortColumn+" = ? and "+idColumn+" "+op+" ?))")
+			*args = append(*args, value, value, id)
+		}
+	}
+
+	orderClause := " ORDER BY " + sortColumn + " " + sortOrder
+	if sortColumn != idColumn {
+		orderClause = orderClause + ", " + idColumn + " " + sortOrder
+	}
+
+	if query.Limit > 0 {
+		orderClause = orderClause + " LIMIT " + strconv.Itoa(query.Limit)
+	}
+
+	return orderClause, nil
+}
+
+// getNextLogPageToken returns the token of the next page, or empty if the page is the last one
+func getNextLogPageToken(query types.LogQuery, count int, updatedTime, total, id int64) string {
+	if query.Limit <= 0 || count < query.Limit {
+		return ""
+	}
+
+	switch query.SortBy {
+	case LogSortByTime:
+		return EncodeLogPageToken(updatedTime, id)
+	case LogSortByCount:
+		return EncodeLogPageToken(total, id)
+	}
+	return EncodeLogPageToken(id, id)
+}
@@ -0,0 +1,82 @@
+package libs
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcatLogQueryClause(t *testing.T) {
+	var whereClause string
+	var args []interface{}
+
+	orderClause, err := concatLogQueryClause(&whereClause, &args, "id", types.LogQuery{
+		Since:     100,
+		Until:     200,
+		Limit:     10,
+		PageToken: EncodeLogPageToken(150, 7),
+		SortBy:    LogSortByTime,
+		SortOrder: LogSortOrderDesc,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, " WHERE updated_time >= ? and updated_time <= ? and (updated_time < ? or (updated_time = ? and id < ?))", whereClause)
+	assert.Equal(t, []interface{}{int64(100), int64(200), int64(150), int64(150), int64(7)}, args)
+	assert.Equal(t, " ORDER BY updated_time DESC, id DESC LIMIT 10", orderClause)
+
+	_, err = concatLogQueryClause(&whereClause, &args, "id", types.LogQuery{SortBy: "name"})
+	assert.Error(t, err)
+
+	_, err = concatLogQueryClause(&whereClause, &args, "id", types.LogQuery{PageToken: "invalid"})
+	assert.Error(t, err)
+}
+
+func TestGetKubearmorLogsByQuery(t *testing.T) {
+	_, mock := NewMock()
+
+	rows := mock.NewRows([]string{
+		"id", "cluster_name", "host_name", "namespace_name", "pod_name", "container_id", "container_name",
+		"uid", "type", "source", "operation", "resource", "labels", "data", "category", "action",
+		"start_time", "updated_time", "result", "total",
+	}).
+		AddRow(3, "default", "node", "wordpress", "wp-1", "", "", 0, "", "", "File", "/etc/passwd", "", "", "", "Allow", 10, 20, "", 5).
+		AddRow(4, "default", "node", "wordpress", "wp-1", "", "", 0, "", "", "Process", "/bin/sh", "", "", "", "Allow", 10, 30, "", 1)
+
+	mock.ExpectQuery("^SELECT id,(.+) FROM system_logs WHERE namespace_name = \\? and id > \\? ORDER BY id ASC LIMIT 2").
+		WithArgs("wordpress", int64(2)).
+		WillReturnRows(rows)
+
+	logs, totals, nextPageToken, err := GetKubearmorLogsByQuery(types.ConfigDB{DBDriver: "mysql"},
+		types.KubeArmorLog{NamespaceName: "wordpress"}, types.LogQuery{Limit: 2, PageToken: EncodeLogPageToken(2, 2)})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(logs))
+	assert.Equal(t, []uint32{5, 1}, totals)
+
+	value, id, err := DecodeLogPageToken(nextPageToken)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), value)
+	assert.Equal(t, int64(4), id)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestGetCiliumLogsByQuery(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectQuery("^SELECT id,(.+) FROM network_logs WHERE updated_time >= \\? ORDER BY total DESC, id DESC LIMIT 5").
+		WithArgs(int64(100)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	logs, _, nextPageToken, err := GetCiliumLogsByQuery(types.ConfigDB{DBDriver: "mysql"}, types.CiliumLog{},
+		types.LogQuery{Since: 100, Limit: 5, SortBy: LogSortByCount, SortOrder: LogSortOrderDesc})
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+	assert.Empty(t, nextPageToken)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
@@ -0,0 +1,221 @@
+package libs
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	ObsLogSystem  = "system"
+	ObsLogNetwork = "network"
+)
+
+// obsLogTable describes how an observability log table is pruned and rolled up
+type obsLogTable struct {
+	name            string
+	namespaceColumn string
+	// columns kept by a rollup
+	groupColumns []string
+	// high cardinality columns reset by a rollup, and their reset value
+	resetColumns []string
+	resetValues  []string
+}
+
+var obsLogTables = map[string]obsLogTable{
+	ObsLogSystem: {
+		name:            TableSystemLogs_TableName,
+		namespaceColumn: "namespace_name",
+		groupColumns: []string{"cluster_name", "namespace_name", "pod_name", "container_name", "uid", "type", "source",
+			"operation", "resource", "labels", "data", "category", "action", "result"},
+		resetColumns: []string{"host_name", "container_id"},
+		resetValues:  []string{"''", "''"},
+	},
+	ObsLogNetwork: {
+		name:            TableNetworkLogs_TableName,
+		namespaceColumn: "source_namespace",
+		groupColumns: []string{"verdict", "ip_destination", "ip_version", "ip_encrypted", "l4_tcp_destination_port",
+			"l4_udp_destination_port", "l4_icmpv4_type", "l4_icmpv4_code", "l4_icmpv6_type", "l4_icmpv6_code",
+			"source_namespace", "source_labels", "source_pod_name", "destination_namespace", "destination_labels",
+			"destination_pod_name", "type", "l7_type", "l7_dns_cnames", "l7_dns_observation_source", "l7_http_code",
+			"l7_http_method", "l7_http_url", "l7_http_protocol", "l7_http_headers", "event_type_type", "event_type_sub_type",
+			"source_service_name", "source_service_namespace", "destination_service_name", "destination_service_namespace",
+			"traffic_direction", "trace_observation_point", "drop_reason_desc", "is_reply"},
+		resetColumns: []string{"ip_source", "l4_tcp_source_port", "l4_udp_source_port", "node_name"},
+		resetValues:  []string{"''", "0", "0", "''"},
+	},
+}
+
+// obsLogDialect the sql differences of the drivers for the observability logs
+type obsLogDialect struct {
+	idColumn string
+	divOp    string
+}
+
+func getObsLogDB(cfg types.ConfigDB) (*sql.DB, obsLogDialect, error) {
+	if cfg.DBDriver == "mysql" {
+		return connectObsDB(cfg), obsLogDialect{idColumn: "id", divOp: "DIV"}, nil
+	} else if cfg.DBDriver == "sqlite3" {
+		// sqlite does not auto increment the id column, the rowid is used instead
+		return connectObsDB(cfg), obsLogDialect{idColumn: "rowid", divOp: "/"}, nil
+	}
+	return nil, obsLogDialect{}, errors.New("unknown db driver")
+}
+
+func getObsLogTable(logType string) (obsLogTable, error) {
+	table, ok := obsLogTables[logType]
+	if !ok {
+		return obsLogTable{}, errors.New("unknown log type " + logType)
+	}
+	return table, nil
+}
+
+// =================== //
+// == Log Retention == //
+// =================== //
+
+// DeleteObsLogsBefore deletes the logs not updated since the given time
+func DeleteObsLogsBefore(cfg types.ConfigDB, logType string, before int64) (int64, error) {
+	table, err := getObsLogTable(logType)
+	if err != nil {
+		return 0, err
+	}
+
+	db, _, err := getObsLogDB(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := db.Exec("DELETE FROM "+table.name+" WHERE updated_time < ?", before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// CapObsLogs keeps the most recently updated maxRows logs of the table, or of the namespace if not empty
+func CapObsLogs(cfg types.ConfigDB, logType, namespace string, maxRows int) (int64, error) {
+	if maxRows <= 0 {
+		return 0, nil
+	}
+
+	table, err := getObsLogTable(logType)
+	if err != nil {
+		return 0, err
+	}
+
+	db, dialect, err := getObsLogDB(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	var whereClause string
+	var args []interface{}
+	if namespace != "" {
+		concatWhereClause(&whereClause, table.namespaceColumn)
+		args = append(args, namespace)
+	}
+
+	// the newest row to be deleted
+	query := "SELECT updated_time," + dialect.idColumn + " FROM " + table.name + whereClause +
+		" ORDER BY updated_time DESC, " + dialect.idColumn + " DESC LIMIT 1 OFFSET " + strconv.Itoa(maxRows)
+
+	var updatedTime, id int64
+	if err := db.QueryRow(query, args...).Scan(&updatedTime, &id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	concatWhereClauseExpr(&whereClause, "(updated_time < ? or (updated_time = ? and "+dialect.idColumn+" <= ?))")
+	args = append(args, updatedTime, updatedTime, id)
+
+	result, err := db.Exec("DELETE FROM "+table.name+whereClause, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// ==================== //
+// == Log Compaction == //
+// ==================== //
+
+// CompactObsLogs rolls up the logs updated in [from, to) into one row per bucket of seconds,
+// a rolled up row starts at the bucket start and is last updated at the bucket end
+func CompactObsLogs(cfg types.ConfigDB, logType string, from, to, bucket int64) (int64, error) {
+	if bucket <= 0 || to <= from {
+		return 0, nil
+	}
+
+	table, err := getObsLogTable(logType)
+	if err != nil {
+		return 0, err
+	}
+
+	db, dialect, err := getObsLogDB(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	bucketStr := strconv.FormatInt(bucket, 10)
+	bucketExpr := "(updated_time " + dialect.divOp + " " + bucketStr + ")"
+
+	// skip the rows already rolled up with the same bucket
+	whereClause := " WHERE updated_time >= ? and updated_time < ? and " + dialect.idColumn + " <= ?" +
+		" and not (start_time % " + bucketStr + " = 0 and updated_time = start_time + " + bucketStr + " - 1)"
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var maxID sql.NullInt64
+	if err := tx.QueryRow("SELECT MAX(" + dialect.idColumn + ") FROM " + table.name).Scan(&maxID); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if !maxID.Valid {
+		_ = tx.Rollback()
+		return 0, nil
+	}
+
+	groupColumns := strings.Join(table.groupColumns, ",")
+	insertQuery := "INSERT INTO " + table.name + " (" + groupColumns + "," + strings.Join(table.resetColumns, ",") +
+		",start_time,updated_time,total) SELECT " + groupColumns + "," + strings.Join(table.resetValues, ",") +
+		"," + bucketExpr + " * " + bucketStr + "," + bucketExpr + " * " + bucketStr + " + " + bucketStr + " - 1,SUM(total)" +
+		" FROM " + table.name + whereClause + " GROUP BY " + groupColumns + "," + bucketExpr
+
+	if _, err := tx.Exec(insertQuery, from, to, maxID.Int64); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	result, err := tx.Exec("DELETE FROM "+table.name+whereClause, from, to, maxID.Int64)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// VacuumObsLogDB reclaims the space of the deleted logs, only for sqlite
+func VacuumObsLogDB(cfg types.ConfigDB) error {
+	if cfg.DBDriver != "sqlite3" {
+		return nil
+	}
+
+	_, err := connectObsDB(cfg).Exec("VACUUM")
+	return err
+}
@@ -0,0 +1,52 @@
+package libs
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapObsLogs(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectQuery("^SELECT updated_time,id FROM system_logs WHERE namespace_name = \\? ORDER BY updated_time DESC, id DESC LIMIT 1 OFFSET 100").
+		WithArgs("kube-system").
+		WillReturnRows(sqlmock.NewRows([]string{"updated_time", "id"}).AddRow(50, 9))
+	mock.ExpectExec("^DELETE FROM system_logs WHERE namespace_name = \\? and \\(updated_time < \\? or \\(updated_time = \\? and id <= \\?\\)\\)").
+		WithArgs("kube-system", int64(50), int64(50), int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := CapObsLogs(types.ConfigDB{DBDriver: "mysql"}, ObsLogSystem, "kube-system", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestCompactObsLogs(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT MAX\\(id\\) FROM network_logs").
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(42))
+	mock.ExpectExec("^INSERT INTO network_logs \\((.+),ip_source,l4_tcp_source_port,l4_udp_source_port,node_name,start_time,updated_time,total\\) "+
+		"SELECT (.+),'',0,0,'',\\(updated_time DIV 3600\\) \\* 3600,(.+),SUM\\(total\\) FROM network_logs WHERE updated_time >= \\? and updated_time < \\? and id <= \\?(.+) GROUP BY (.+)").
+		WithArgs(int64(0), int64(7200), int64(42)).
+		WillReturnResult(sqlmock.NewResult(43, 1))
+	mock.ExpectExec("^DELETE FROM network_logs WHERE updated_time >= \\? and updated_time < \\? and id <= \\?").
+		WithArgs(int64(0), int64(7200), int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	compacted, err := CompactObsLogs(types.ConfigDB{DBDriver: "mysql"}, ObsLogNetwork, 0, 7200, 3600)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), compacted)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
@@ -14,10 +14,13 @@ import (
 )
 
 const WorkloadProcessFileSet_TableName = "workload_process_fileset"
+const WorkloadLearningState_TableName = "workload_learning_state"
 const TableNetworkPolicy_TableName = "network_policy"
 const TableSystemPolicy_TableName = "system_policy"
 const TableSystemLogs_TableName = "system_logs"
 const TableNetworkLogs_TableName = "network_logs"
+const PolicyChange_TableName = "policy_change"
+const RuleEvidence_TableName = "rule_evidence"
 
 // ================ //
 // == Connection == //
@@ -76,6 +79,11 @@ func connectMySQL(cfg types.ConfigDB) (db *sql.DB) {
 // ==================== //
 
 func GetNetworkPoliciesFromMySQL(cfg types.ConfigDB, cluster, namespace, status, nwtype, rule string) ([]types.KnoxNetworkPolicy, error) {
+	return GetNetworkPolicyPageFromMySQL(cfg, cluster, namespace, status, nwtype, rule, types.InsightQuery{})
+}
+
+// GetNetworkPolicyPageFromMySQL returns the network policies of the time range in the requested page
+func GetNetworkPolicyPageFromMySQL(cfg types.ConfigDB, cluster, namespace, status, nwtype, rule string, page types.InsightQuery) ([]types.KnoxNetworkPolicy, error) {
 	db := connectMySQL(cfg)
 	defer db.Close()
 
@@ -83,7 +91,7 @@ func GetNetworkPoliciesFromMySQL(cfg types.ConfigDB, cluster, namespace, status,
 	var results *sql.Rows
 	var err error
 
-	query := "SELECT apiVersion,kind,flow_ids,name,cluster_name,namespace,type,rule,status,outdated,spec,generatedTime,updatedTime FROM " + TableNetworkPolicy_TableName
+	query := "SELECT apiVersion,kind,flow_ids,name,cluster_name,namespace,type,rule,status,outdated,spec,generatedTime,updatedTime,review_state,rule_stats FROM " + TableNetworkPolicy_TableName
 
 	var whereClause string
 	var args []interface{}
@@ -109,7 +117,12 @@ func GetNetworkPoliciesFromMySQL(cfg types.ConfigDB, cluster, namespace, status,
 		args = append(args, rule)
 	}
 
-	results, err = db.Query(query+whereClause, args...)
+	orderClause, err := concatInsightQueryClause(&whereClause, &args, networkPolicyTimeColumn, networkPolicyKeyColumns, page)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err = db.Query(query+whereClause+orderClause, args...)
 	defer results.Close()
 
 	if err != nil {
@@ -121,6 +134,7 @@ func GetNetworkPoliciesFromMySQL(cfg types.ConfigDB, cluster, namespace, status,
 		policy := types.KnoxNetworkPolicy{}
 
 		var name, clusterName, namespace, policyType, rule, status string
+		var reviewState, ruleStats sql.NullString
 		specByte := []byte{}
 		spec := types.Spec{}
 
@@ -141,6 +155,8 @@ func GetNetworkPoliciesFromMySQL(cfg types.ConfigDB, cluster, namespace, status,
 			&specByte,
 			&policy.GeneratedTime,
 			&policy.UpdatedTime,
+			&reviewState,
+			&ruleStats,
 		); err != nil {
 			return nil, err
 		}
@@ -160,11 +176,16 @@ func GetNetworkPoliciesFromMySQL(cfg types.ConfigDB, cluster, namespace, status,
 			"type":         policyType,
 			"rule":         rule,
 			"status":       status,
+			"review_state": reviewState.String,
 		}
 
 		policy.FlowIDs = flowIDs
 		policy.Spec = spec
 
+		if policy.RuleStats, err = unmarshalRuleStats(ruleStats); err != nil {
+			return nil, err
+		}
+
 		policies = append(policies, policy)
 	}
 
@@ -177,7 +198,7 @@ func UpdateNetworkPolicyToMySQL(cfg types.ConfigDB, policy types.KnoxNetworkPoli
 
 	// set status -> outdated
 	stmt, err := db.Prepare("UPDATE " + TableNetworkPolicy_TableName +
-		" SET apiVersion=?,kind=?,cluster_name=?,namespace=?,type=?,status=?,outdated=?,spec=?,updatedTime=? WHERE name = ?")
+		" SET apiVersion=?,kind=?,cluster_name=?,namespace=?,type=?,status=?,outdated=?,spec=?,updatedTime=?,review_state=?,rule_stats=? WHERE name = ? and cluster_name = ?")
 	if err != nil {
 		return err
 	}
@@ -189,6 +210,11 @@ func UpdateNetworkPolicyToMySQL(cfg types.ConfigDB, policy types.KnoxNetworkPoli
 		return err
 	}
 
+	ruleStats, err := marshalRuleStats(policy.RuleStats)
+	if err != nil {
+		return err
+	}
+
 	_, err = stmt.Exec(
 		policy.APIVersion,
 		policy.Kind,
@@ -199,7 +225,10 @@ func UpdateNetworkPolicyToMySQL(cfg types.ConfigDB, policy types.KnoxNetworkPoli
 		policy.Outdated,
 		spec,
 		ConvertStrToUnixTime("now"),
-		policy.Metadata["name"])
+		GetReviewState(policy.Metadata),
+		ruleStats,
+		policy.Metadata["name"],
+		policy.Metadata["cluster_name"])
 	if err != nil {
 		return err
 	}
@@ -207,20 +236,20 @@ func UpdateNetworkPolicyToMySQL(cfg types.ConfigDB, policy types.KnoxNetworkPoli
 	return nil
 }
 
-func UpdateOutdatedNetworkPolicyFromMySQL(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string) error {
+func UpdateOutdatedNetworkPolicyFromMySQL(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string, reviewState string) error {
 	db := connectMySQL(cfg)
 	defer db.Close()
 
 	var err error
 
-	// set status -> outdated
-	stmt1, err := db.Prepare("UPDATE " + TableNetworkPolicy_TableName + " SET status=? WHERE name=?")
+	// set status -> outdated, review state -> superseded or kept
+	stmt1, err := db.Prepare("UPDATE " + TableNetworkPolicy_TableName + " SET status=?,review_state=? WHERE name=?")
 	if err != nil {
 		return err
 	}
 	defer stmt1.Close()
 
-	_, err = stmt1.Exec("outdated", outdatedPolicy)
+	_, err = stmt1.Exec("outdated", reviewState, outdatedPolicy)
 	if err != nil {
 		return err
 	}
@@ -241,7 +270,7 @@ func UpdateOutdatedNetworkPolicyFromMySQL(cfg types.ConfigDB, outdatedPolicy str
 }
 
 func insertNetworkPolicy(cfg types.ConfigDB, db *sql.DB, policy types.KnoxNetworkPolicy) error {
-	stmt, err := db.Prepare("INSERT INTO " + TableNetworkPolicy_TableName + "(apiVersion,kind,flow_ids,name,cluster_name,namespace,type,rule,status,outdated,spec,generatedTime,updatedTime) values(?,?,?,?,?,?,?,?,?,?,?,?,?)")
+	stmt, err := db.Prepare("INSERT INTO " + TableNetworkPolicy_TableName + "(apiVersion,kind,flow_ids,name,cluster_name,namespace,type,rule,status,outdated,spec,generatedTime,updatedTime,rule_stats) values(?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
 	if err != nil {
 		return err
 	}
@@ -259,6 +288,11 @@ func insertNetworkPolicy(cfg types.ConfigDB, db *sql.DB, policy types.KnoxNetwor
 		return err
 	}
 
+	ruleStats, err := marshalRuleStats(policy.RuleStats)
+	if err != nil {
+		return err
+	}
+
 	currTime := ConvertStrToUnixTime("now")
 
 	_, err = stmt.Exec(policy.APIVersion,
@@ -273,7 +307,8 @@ func insertNetworkPolicy(cfg types.ConfigDB, db *sql.DB, policy types.KnoxNetwor
 		policy.Outdated,
 		spec,
 		currTime,
-		currTime)
+		currTime,
+		ruleStats)
 	if err != nil {
 		return err
 	}
@@ -294,30 +329,50 @@ func InsertNetworkPoliciesToMySQL(cfg types.ConfigDB, policies []types.KnoxNetwo
 	return nil
 }
 
+func UpdatePolicyReviewToMySQL(cfg types.ConfigDB, review *types.PolicyReview) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	if review.PolicyType == "system" {
+		return updatePolicyReview(db, TableSystemPolicy_TableName, "clusterName", review)
+	}
+	return updatePolicyReview(db, TableNetworkPolicy_TableName, "cluster_name", review)
+}
+
+func UpdateRuleStatsToMySQL(cfg types.ConfigDB, policyType, clusterName, name string, stats map[string]types.RuleStat) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	if policyType == "system" {
+		return updateRuleStats(db, TableSystemPolicy_TableName, "clusterName", clusterName, name, stats)
+	}
+	return updateRuleStats(db, TableNetworkPolicy_TableName, "cluster_name", clusterName, name, stats)
+}
+
 // =================== //
 // == System Policy == //
 // =================== //
 
-func UpdateOutdatedSystemPolicyFromMySQL(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string) error {
+func UpdateOutdatedSystemPolicyFromMySQL(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string, reviewState string) error {
 	db := connectMySQL(cfg)
 	defer db.Close()
 
 	var err error
 
-	// set status -> outdated
-	stmt1, err := db.Prepare("UPDATE " + TableSystemPolicy_TableName + " SET status=? WHERE name=?")
+	// set status -> outdated, review state -> superseded or kept
+	stmt1, err := db.Prepare("UPDATE " + TableSystemPolicy_TableName + " SET status=?,review_state=? WHERE name=?")
 	if err != nil {
 		return err
 	}
 	defer stmt1.Close()
 
-	_, err = stmt1.Exec("outdated", outdatedPolicy)
+	_, err = stmt1.Exec("outdated", reviewState, outdatedPolicy)
 	if err != nil {
 		return err
 	}
 
 	// set outdated -> latest' name
-	stmt2, err := db.Prepare("UPDATE " + TableNetworkPolicy_TableName + " SET outdated=? WHERE name=?")
+	stmt2, err := db.Prepare("UPDATE " + TableSystemPolicy_TableName + " SET outdated=? WHERE name=?")
 	if err != nil {
 		return err
 	}
@@ -339,7 +394,7 @@ func GetSystemPoliciesFromMySQL(cfg types.ConfigDB, namespace, status string) ([
 	var results *sql.Rows
 	var err error
 
-	query := "SELECT apiVersion,kind,name,clusterName,namespace,type,status,outdated,spec,generatedTime,updatedTime,latest FROM " + TableSystemPolicy_TableName
+	query := "SELECT apiVersion,kind,name,clusterName,namespace,type,status,outdated,spec,generatedTime,updatedTime,latest,review_state,rule_stats FROM " + TableSystemPolicy_TableName
 
 	if namespace != "" && status != "" {
 		query = query + " WHERE namespace = ? and status = ? "
@@ -365,6 +420,7 @@ func GetSystemPoliciesFromMySQL(cfg types.ConfigDB, namespace, status string) ([
 		policy := types.KnoxSystemPolicy{}
 
 		var name, clusterName, namespace, policyType, status string
+		var reviewState, ruleStats sql.NullString
 		specByte := []byte{}
 		spec := types.KnoxSystemSpec{}
 
@@ -381,6 +437,8 @@ func GetSystemPoliciesFromMySQL(cfg types.ConfigDB, namespace, status string) ([
 			&policy.GeneratedTime,
 			&policy.UpdatedTime,
 			&policy.Latest,
+			&reviewState,
+			&ruleStats,
 		); err != nil {
 			return nil, err
 		}
@@ -390,15 +448,20 @@ func GetSystemPoliciesFromMySQL(cfg types.ConfigDB, namespace, status string) ([
 		}
 
 		policy.Metadata = map[string]string{
-			"name":        name,
-			"clusterName": clusterName,
-			"namespace":   namespace,
-			"type":        policyType,
-			"status":      status,
+			"name":         name,
+			"clusterName":  clusterName,
+			"namespace":    namespace,
+			"type":         policyType,
+			"status":       status,
+			"review_state": reviewState.String,
 		}
 
 		policy.Spec = spec
 
+		if policy.RuleStats, err = unmarshalRuleStats(ruleStats); err != nil {
+			return nil, err
+		}
+
 		policies = append(policies, policy)
 	}
 
@@ -406,7 +469,7 @@ func GetSystemPoliciesFromMySQL(cfg types.ConfigDB, namespace, status string) ([
 }
 
 func insertSystemPolicy(cfg types.ConfigDB, db *sql.DB, policy types.KnoxSystemPolicy) error {
-	stmt, err := db.Prepare("INSERT INTO " + TableSystemPolicy_TableName + "(apiVersion,kind,name,clusterName,namespace,type,status,outdated,spec,generatedTime,updatedTime,latest) values(?,?,?,?,?,?,?,?,?,?,?,?)")
+	stmt, err := db.Prepare("INSERT INTO " + TableSystemPolicy_TableName + "(apiVersion,kind,name,clusterName,namespace,type,status,outdated,spec,generatedTime,updatedTime,latest,rule_stats) values(?,?,?,?,?,?,?,?,?,?,?,?,?)")
 	if err != nil {
 		return err
 	}
@@ -418,6 +481,11 @@ func insertSystemPolicy(cfg types.ConfigDB, db *sql.DB, policy types.KnoxSystemP
 		return err
 	}
 
+	ruleStats, err := marshalRuleStats(policy.RuleStats)
+	if err != nil {
+		return err
+	}
+
 	_, err = stmt.Exec(
 		policy.APIVersion,
 		policy.Kind,
@@ -430,7 +498,8 @@ func insertSystemPolicy(cfg types.ConfigDB, db *sql.DB, policy types.KnoxSystemP
 		spec,
 		ConvertStrToUnixTime("now"),
 		ConvertStrToUnixTime("now"),
-		true)
+		true,
+		ruleStats)
 	if err != nil {
 		return err
 	}
@@ -457,7 +526,7 @@ func UpdateSystemPolicyToMySQL(cfg types.ConfigDB, policy types.KnoxSystemPolicy
 
 	// set status -> outdated
 	stmt, err := db.Prepare("UPDATE " + TableSystemPolicy_TableName +
-		" SET apiVersion=?,kind=?,clusterName=?,namespace=?,type=?,status=?,outdated=?,spec=?,updatedTime=?,latest=? WHERE name = ?")
+		" SET apiVersion=?,kind=?,clusterName=?,namespace=?,type=?,status=?,outdated=?,spec=?,updatedTime=?,latest=?,review_state=?,rule_stats=? WHERE name = ? and clusterName = ?")
 	if err != nil {
 		return err
 	}
@@ -469,6 +538,11 @@ func UpdateSystemPolicyToMySQL(cfg types.ConfigDB, policy types.KnoxSystemPolicy
 		return err
 	}
 
+	ruleStats, err := marshalRuleStats(policy.RuleStats)
+	if err != nil {
+		return err
+	}
+
 	_, err = stmt.Exec(
 		policy.APIVersion,
 		policy.Kind,
@@ -480,7 +554,10 @@ func UpdateSystemPolicyToMySQL(cfg types.ConfigDB, policy types.KnoxSystemPolicy
 		spec,
 		ConvertStrToUnixTime("now"),
 		true,
-		policy.Metadata["name"])
+		GetReviewState(policy.Metadata),
+		ruleStats,
+		policy.Metadata["name"],
+		policy.Metadata["clusterName"])
 	if err != nil {
 		return err
 	}
@@ -523,6 +600,21 @@ func ClearDBTablesMySQL(cfg types.ConfigDB) error {
 		return err
 	}
 
+	query = "DELETE FROM " + WorkloadLearningState_TableName
+	if _, err := db.Query(query); err != nil {
+		return err
+	}
+
+	query = "DELETE FROM " + PolicyChange_TableName
+	if _, err := db.Query(query); err != nil {
+		return err
+	}
+
+	query = "DELETE FROM " + RuleEvidence_TableName
+	if _, err := db.Query(query); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -615,10 +707,86 @@ func CreateTableWorkLoadProcessFileSetMySQL(cfg types.ConfigDB) error {
 	return err
 }
 
-func CreateTableSystemLogsMySQL(cfg types.ConfigDB) error {
+func CreateTableWorkloadLearningStateMySQL(cfg types.ConfigDB) error {
 	db := connectMySQL(cfg)
 	defer db.Close()
 
+	tableName := WorkloadLearningState_TableName
+
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + tableName + "` (" +
+			"	`id` int NOT NULL AUTO_INCREMENT," +
+			"	`clusterName` varchar(50) DEFAULT NULL," +
+			"	`namespace` varchar(50) DEFAULT NULL," +
+			"	`containerName` varchar(100) NOT NULL," +
+			"	`labels` varchar(1000) DEFAULT NULL," +
+			"	`state` varchar(20) DEFAULT NULL," + // state: "learning", "stable" or "enforced-candidate"
+			"	`stableIntervals` int NOT NULL," +
+			"	`firstSeenTime` bigint NOT NULL," +
+			"	`lastChangeTime` bigint NOT NULL," +
+			"	`updatedTime` bigint NOT NULL," +
+			"	PRIMARY KEY (`id`)" +
+			"  );"
+
+	_, err := db.Query(query)
+	return err
+}
+
+func CreateTablePolicyChangeMySQL(cfg types.ConfigDB) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	tableName := PolicyChange_TableName
+
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + tableName + "` (" +
+			"	`id` int NOT NULL AUTO_INCREMENT," +
+			"	`policy_type` varchar(10) DEFAULT NULL," + // policy_type: "network" or "system"
+			"	`cluster_name` varchar(50) DEFAULT NULL," +
+			"	`namespace` varchar(50) DEFAULT NULL," +
+			"	`name` varchar(128) DEFAULT NULL," +
+			"	`new_policy_name` varchar(128) DEFAULT NULL," +
+			"	`reason` varchar(20) DEFAULT NULL," +
+			"	`removed_rules` JSON DEFAULT NULL," +
+			"	`changed_time` bigint NOT NULL," +
+			"	PRIMARY KEY (`id`)" +
+			"  );"
+
+	_, err := db.Query(query)
+	return err
+}
+
+func CreateTableRuleEvidenceMySQL(cfg types.ConfigDB) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	tableName := RuleEvidence_TableName
+
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + tableName + "` (" +
+			"	`id` int NOT NULL AUTO_INCREMENT," +
+			"	`policy_type` varchar(10) DEFAULT NULL," + // policy_type: "network" or "system"
+			"	`cluster_name` varchar(50) DEFAULT NULL," +
+			"	`namespace` varchar(50) DEFAULT NULL," +
+			"	`labels` varchar(1000) DEFAULT NULL," +
+			"	`rule_key` varchar(1000) DEFAULT NULL," +
+			"	`rule` text DEFAULT NULL," +
+			"	`first_seen` bigint NOT NULL," +
+			"	`last_seen` bigint NOT NULL," +
+			"	`count` bigint NOT NULL," +
+			"	`src_pods` JSON DEFAULT NULL," +
+			"	`dst_pods` JSON DEFAULT NULL," +
+			"	`sample_log` text DEFAULT NULL," +
+			"	PRIMARY KEY (`id`)" +
+			"  );"
+
+	_, err := db.Query(query)
+	return err
+}
+
+func CreateTableSystemLogsMySQL(cfg types.ConfigDB) error {
+	db := connectObsDB(cfg)
+
 	tableName := TableSystemLogs_TableName
 
 	query :=
@@ -645,13 +813,12 @@ func CreateTableSystemLogsMySQL(cfg types.ConfigDB) error {
 			"	`total` INTEGER	" +
 			"  );"
 
-	_, err := db.Query(query)
+	_, err := db.Exec(query)
 	return err
 }
 
 func CreateTableNetworkLogsMySQL(cfg types.ConfigDB) error {
-	db := connectMySQL(cfg)
-	defer db.Close()
+	db := connectObsDB(cfg)
 
 	tableName := TableNetworkLogs_TableName
 
@@ -702,7 +869,7 @@ func CreateTableNetworkLogsMySQL(cfg types.ConfigDB) error {
 			"	`total` INTEGER" +
 			" 	);"
 
-	_, err := db.Query(query)
+	_, err := db.Exec(query)
 	return err
 }
 
@@ -725,7 +892,73 @@ func concatWhereClauseIntRange(whereClause *string, field string, start int64, e
 }
 
 // GetWorkloadProcessFileSetMySQL Handle File Sets in context to a given fromSource
+// GetWorkloadPageMySQL returns the workloads (cluster, namespace, labels and container) of the sets updated in the
+// time range in the requested page
+func GetWorkloadPageMySQL(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, page types.InsightQuery) ([]types.WorkloadProcessFileSet, error) {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	query := "SELECT DISTINCT clusterName,namespace,labels,containerName FROM " + WorkloadProcessFileSet_TableName
+
+	var whereClause string
+	var args []interface{}
+
+	if wpfs.ClusterName != "" {
+		concatWhereClause(&whereClause, "clusterName")
+		args = append(args, wpfs.ClusterName)
+	}
+	if wpfs.Namespace != "" {
+		concatWhereClause(&whereClause, "namespace")
+		args = append(args, wpfs.Namespace)
+	}
+	if wpfs.ContainerName != "" {
+		concatWhereClause(&whereClause, "containerName")
+		args = append(args, wpfs.ContainerName)
+	}
+	if wpfs.Labels != "" {
+		concatWhereClause(&whereClause, "labels")
+		args = append(args, wpfs.Labels)
+	}
+	if wpfs.FromSource != "" {
+		concatWhereClause(&whereClause, "fromSource")
+		args = append(args, wpfs.FromSource)
+	}
+
+	orderClause, err := concatInsightQueryClause(&whereClause, &args, wpfsTimeColumn, wpfsKeyColumns, page)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := db.Query(query+whereClause+orderClause, args...)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return nil, err
+	}
+	defer results.Close()
+
+	workloads := []types.WorkloadProcessFileSet{}
+	for results.Next() {
+		var workload types.WorkloadProcessFileSet
+		if err := results.Scan(
+			&workload.ClusterName,
+			&workload.Namespace,
+			&workload.Labels,
+			&workload.ContainerName,
+		); err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, workload)
+	}
+
+	return workloads, nil
+}
+
 func GetWorkloadProcessFileSetMySQL(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet) (map[types.WorkloadProcessFileSet][]string, types.PolicyNameMap, error) {
+	return GetWorkloadProcessFileSetInRangeMySQL(cfg, wpfs, 0, 0)
+}
+
+// GetWorkloadProcessFileSetInRangeMySQL returns the sets of the workloads updated in the time range
+func GetWorkloadProcessFileSetInRangeMySQL(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, since, until int64) (map[types.WorkloadProcessFileSet][]string, types.PolicyNameMap, error) {
 	db := connectMySQL(cfg)
 	defer db.Close()
 
@@ -762,7 +995,12 @@ func GetWorkloadProcessFileSetMySQL(cfg types.ConfigDB, wpfs types.WorkloadProce
 		args = append(args, wpfs.SetType)
 	}
 
-	results, err = db.Query(query+whereClause, args...)
+	orderClause, err := concatInsightQueryClause(&whereClause, &args, wpfsTimeColumn, wpfsKeyColumns, types.InsightQuery{Since: since, Until: until})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, err = db.Query(query+whereClause+orderClause, args...)
 
 	if err != nil {
 		log.Error().Msg(err.Error())
@@ -802,7 +1040,7 @@ func GetWorkloadProcessFileSetMySQL(cfg types.ConfigDB, wpfs types.WorkloadProce
 func InsertWorkloadProcessFileSetMySQL(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, fs []string) error {
 	db := connectMySQL(cfg)
 	defer db.Close()
-	policyName := "autopol-" + strings.ToLower(wpfs.SetType) + "-" + RandSeq(15)
+	policyName := getWPFSPolicyName(wpfs)
 	time := ConvertStrToUnixTime("now")
 
 	stmt, err := db.Prepare("INSERT INTO " + WorkloadProcessFileSet_TableName +
@@ -907,20 +1145,163 @@ func UpdateWorkloadProcessFileSetMySQL(cfg types.ConfigDB, wpfs types.WorkloadPr
 	return err
 }
 
-// UpdateOrInsertKubearmorLogsSQLite -- Update existing log or insert a new log into DB
-func UpdateOrInsertKubearmorLogsMySQL(cfg types.ConfigDB, kubearmorlogs []types.KubeArmorLog) error {
+// GetWorkloadLearningStatesMySQL fetch the learning states of the workloads matching the given filter
+func GetWorkloadLearningStatesMySQL(cfg types.ConfigDB, filter types.WorkloadLearningState) ([]types.WorkloadLearningState, error) {
 	db := connectMySQL(cfg)
 	defer db.Close()
 
+	query := "SELECT clusterName,namespace,containerName,labels,state,stableIntervals,firstSeenTime,lastChangeTime,updatedTime FROM " + WorkloadLearningState_TableName
+
+	var whereClause string
+	var args []interface{}
+
+	if filter.ClusterName != "" {
+		concatWhereClause(&whereClause, "clusterName")
+		args = append(args, filter.ClusterName)
+	}
+	if filter.Namespace != "" {
+		concatWhereClause(&whereClause, "namespace")
+		args = append(args, filter.Namespace)
+	}
+	if filter.ContainerName != "" {
+		concatWhereClause(&whereClause, "containerName")
+		args = append(args, filter.ContainerName)
+	}
+	if filter.Labels != "" {
+		concatWhereClause(&whereClause, "labels")
+		args = append(args, filter.Labels)
+	}
+	if filter.State != "" {
+		concatWhereClause(&whereClause, "state")
+		args = append(args, filter.State)
+	}
+
+	results, err := db.Query(query+whereClause, args...)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return nil, err
+	}
+	defer results.Close()
+
+	states := []types.WorkloadLearningState{}
+
+	for results.Next() {
+		var state types.WorkloadLearningState
+
+		if err := results.Scan(
+			&state.ClusterName,
+			&state.Namespace,
+			&state.ContainerName,
+			&state.Labels,
+			&state.State,
+			&state.StableIntervals,
+			&state.FirstSeenTime,
+			&state.LastChangeTime,
+			&state.UpdatedTime,
+		); err != nil {
+			return nil, err
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func InsertWorkloadLearningStateMySQL(cfg types.ConfigDB, state types.WorkloadLearningState) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	stmt, err := db.Prepare("INSERT INTO " + WorkloadLearningState_TableName +
+		"(clusterName,namespace,containerName,labels,state,stableIntervals,firstSeenTime,lastChangeTime,updatedTime) values(?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(
+		state.ClusterName,
+		state.Namespace,
+		state.ContainerName,
+		state.Labels,
+		state.State,
+		state.StableIntervals,
+		state.FirstSeenTime,
+		state.LastChangeTime,
+		state.UpdatedTime)
+	return err
+}
+
+func UpdateWorkloadLearningStateMySQL(cfg types.ConfigDB, state types.WorkloadLearningState) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	stmt, err := db.Prepare("UPDATE " + WorkloadLearningState_TableName +
+		" SET state=?,stableIntervals=?,lastChangeTime=?,updatedTime=? WHERE clusterName = ? and containerName = ? and namespace = ? and labels = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(
+		state.State,
+		state.StableIntervals,
+		state.LastChangeTime,
+		state.UpdatedTime,
+		state.ClusterName,
+		state.ContainerName,
+		state.Namespace,
+		state.Labels)
+	return err
+}
+
+func InsertPolicyChangeMySQL(cfg types.ConfigDB, change types.PolicyChange) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return insertPolicyChange(db, PolicyChange_TableName, change)
+}
+
+// GetPolicyChangesMySQL fetch the change records of the policies matching the given filter, the latest first
+func GetPolicyChangesMySQL(cfg types.ConfigDB, filter types.PolicyChange) ([]types.PolicyChange, error) {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return getPolicyChanges(db, PolicyChange_TableName, filter)
+}
+
+// UpsertRuleEvidencesMySQL merge the evidences of the observed rules into the stored ones
+func UpsertRuleEvidencesMySQL(cfg types.ConfigDB, evidences []types.RuleEvidence) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return upsertRuleEvidences(db, RuleEvidence_TableName, evidences)
+}
+
+// GetRuleEvidencesMySQL fetch the evidences of the rules matching the given filter
+func GetRuleEvidencesMySQL(cfg types.ConfigDB, filter types.RuleEvidence) ([]types.RuleEvidence, error) {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return getRuleEvidences(db, RuleEvidence_TableName, filter)
+}
+
+// UpdateOrInsertKubearmorLogsMySQL -- Update existing log or insert a new log into DB, returns the inserted logs
+func UpdateOrInsertKubearmorLogsMySQL(cfg types.ConfigDB, kubearmorlogs []types.KubeArmorLog) ([]types.KubeArmorLog, error) {
+	db := connectObsDB(cfg)
+
+	inserted := []types.KubeArmorLog{}
 	for _, kubearmorlog := range kubearmorlogs {
-		if err := updateOrInsertKubearmorLogMySQL(db, kubearmorlog); err != nil {
+		if isNew, err := updateOrInsertKubearmorLogMySQL(db, kubearmorlog); err != nil {
 			log.Error().Msg(err.Error())
+		} else if isNew {
+			inserted = append(inserted, kubearmorlog)
 		}
 	}
-	return nil
+	return inserted, nil
 }
 
-func updateOrInsertKubearmorLogMySQL(db *sql.DB, kubearmorlog types.KubeArmorLog) error {
+func updateOrInsertKubearmorLogMySQL(db *sql.DB, kubearmorlog types.KubeArmorLog) (bool, error) {
 	queryString := `cluster_name = ? and host_name = ? and namespace_name = ? and pod_name = ? and container_id = ? and 
 					container_name = ? and uid = ? and type = ? and source = ? and operation = ? and resource = ? and 
 					labels = ? and data = ? and category = ? and action = ? and result = ? `
@@ -929,7 +1310,7 @@ func updateOrInsertKubearmorLogMySQL(db *sql.DB, kubearmorlog types.KubeArmorLog
 
 	updateStmt, err := db.Prepare(query)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer updateStmt.Close()
 
@@ -954,7 +1335,7 @@ func updateOrInsertKubearmorLogMySQL(db *sql.DB, kubearmorlog types.KubeArmorLog
 	)
 	if err != nil {
 		log.Error().Msg(err.Error())
-		return err
+		return false, err
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -969,7 +1350,7 @@ func updateOrInsertKubearmorLogMySQL(db *sql.DB, kubearmorlog types.KubeArmorLog
 
 		insertStmt, err := db.Prepare(updateQuery)
 		if err != nil {
-			return err
+			return false, err
 		}
 		defer insertStmt.Close()
 
@@ -995,17 +1376,17 @@ func updateOrInsertKubearmorLogMySQL(db *sql.DB, kubearmorlog types.KubeArmorLog
 			1)
 		if err != nil {
 			log.Error().Msg(err.Error())
-			return err
+			return false, err
 		}
+		return true, nil
 	}
 
-	return nil
+	return false, nil
 }
 
 // GetSystemLogsMySQL
-func GetSystemLogsMySQL(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]types.KubeArmorLog, []uint32, error) {
-	db := connectMySQL(cfg)
-	defer db.Close()
+func GetSystemLogsMySQL(cfg types.ConfigDB, filterLog types.KubeArmorLog, logQuery types.LogQuery) ([]types.KubeArmorLog, []uint32, string, error) {
+	db := connectObsDB(cfg)
 
 	resLog := []types.KubeArmorLog{}
 	resTotal := []uint32{}
@@ -1016,7 +1397,7 @@ func GetSystemLogsMySQL(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]typ
 	queryString := `cluster_name,host_name,namespace_name,pod_name,container_id,container_name,
 		uid,type,source,operation,resource,labels,data,category,action,start_time,updated_time,result,total`
 
-	query := "SELECT " + queryString + " FROM " + TableSystemLogs_TableName + " "
+	query := "SELECT id," + queryString + " FROM " + TableSystemLogs_TableName + " "
 
 	var whereClause string
 	var args []interface{}
@@ -1094,18 +1475,25 @@ func GetSystemLogsMySQL(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]typ
 		args = append(args, filterLog.Result)
 	}
 
-	results, err = db.Query(query+whereClause, args...)
+	orderClause, err := concatLogQueryClause(&whereClause, &args, "id", logQuery)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	results, err = db.Query(query+whereClause+orderClause, args...)
 
 	if err != nil {
 		log.Error().Msg(err.Error())
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 	defer results.Close()
 
+	var loc_id int64
 	for results.Next() {
 		var loc_log types.KubeArmorLog
 		var loc_total uint32
 		if err := results.Scan(
+			&loc_id,
 			&loc_log.ClusterName,
 			&loc_log.HostName,
 			&loc_log.NamespaceName,
@@ -1126,18 +1514,22 @@ func GetSystemLogsMySQL(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]typ
 			&loc_log.Result,
 			&loc_total,
 		); err != nil {
-			return nil, nil, err
+			return nil, nil, "", err
 		}
 		resLog = append(resLog, loc_log)
 		resTotal = append(resTotal, loc_total)
 	}
 
-	return resLog, resTotal, err
+	nextPageToken := ""
+	if len(resLog) > 0 {
+		nextPageToken = getNextLogPageToken(logQuery, len(resLog), resLog[len(resLog)-1].UpdatedTime, int64(resTotal[len(resTotal)-1]), loc_id)
+	}
+
+	return resLog, resTotal, nextPageToken, err
 }
 
 func InsertCiliumLogsMySQL(cfg types.ConfigDB, log types.CiliumLog) error {
-	db := connectMySQL(cfg)
-	defer db.Close()
+	db := connectObsDB(cfg)
 
 	queryString := `(verdict,ip_source,ip_destination,ip_version,ip_encrypted,l4_tcp_source_port,l4_tcp_destination_port,
 		l4_udp_source_port,l4_udp_destination_port,l4_icmpv4_type,l4_icmpv4_code,l4_icmpv6_type,l4_icmpv6_code,
@@ -1202,9 +1594,8 @@ func InsertCiliumLogsMySQL(cfg types.ConfigDB, log types.CiliumLog) error {
 }
 
 // GetCiliumLogsMySQL
-func GetCiliumLogsMySQL(cfg types.ConfigDB, filterLog types.CiliumLog) ([]types.CiliumLog, []uint32, error) {
-	db := connectMySQL(cfg)
-	defer db.Close()
+func GetCiliumLogsMySQL(cfg types.ConfigDB, filterLog types.CiliumLog, logQuery types.LogQuery) ([]types.CiliumLog, []uint32, string, error) {
+	db := connectObsDB(cfg)
 
 	resLog := []types.CiliumLog{}
 	resTotal := []uint32{}
@@ -1219,7 +1610,7 @@ func GetCiliumLogsMySQL(cfg types.ConfigDB, filterLog types.CiliumLog) ([]types.
 	event_type_type,event_type_sub_type,source_service_name,source_service_namespace,destination_service_name,destination_service_namespace,
 	traffic_direction,trace_observation_point,drop_reason_desc,is_reply,start_time,updated_time,total`
 
-	query := "SELECT " + queryString + " FROM " + TableNetworkLogs_TableName + " "
+	query := "SELECT id," + queryString + " FROM " + TableNetworkLogs_TableName + " "
 
 	var whereClause string
 	var args []interface{}
@@ -1389,18 +1780,25 @@ func GetCiliumLogsMySQL(cfg types.ConfigDB, filterLog types.CiliumLog) ([]types.
 		args = append(args, filterLog.Total)
 	}
 
-	results, err = db.Query(query+whereClause, args...)
+	orderClause, err := concatLogQueryClause(&whereClause, &args, "id", logQuery)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	results, err = db.Query(query+whereClause+orderClause, args...)
 
 	if err != nil {
 		log.Error().Msg(err.Error())
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 	defer results.Close()
 
+	var loc_id int64
 	for results.Next() {
 		var loc_log types.CiliumLog
 		var loc_total uint32
 		if err := results.Scan(
+			&loc_id,
 			&loc_log.Verdict,
 			&loc_log.IpSource,
 			&loc_log.IpDestination,
@@ -1444,29 +1842,38 @@ func GetCiliumLogsMySQL(cfg types.ConfigDB, filterLog types.CiliumLog) ([]types.
 			&loc_log.UpdatedTime,
 			&loc_total,
 		); err != nil {
-			return nil, nil, err
+			return nil, nil, "", err
 		}
 		resLog = append(resLog, loc_log)
 		resTotal = append(resTotal, loc_total)
 	}
-	return resLog, resTotal, err
+	nextPageToken := ""
+	if len(resLog) > 0 {
+		nextPageToken = getNextLogPageToken(logQuery, len(resLog), resLog[len(resLog)-1].UpdatedTime, int64(resTotal[len(resTotal)-1]), loc_id)
+	}
+
+	return resLog, resTotal, nextPageToken, err
 }
 
-func UpdateOrInsertCiliumLogsMySQL(cfg types.ConfigDB, ciliumlogs []types.CiliumLog) error {
+// UpdateOrInsertCiliumLogsMySQL -- Update existing log or insert a new log into DB, returns the inserted logs
+func UpdateOrInsertCiliumLogsMySQL(cfg types.ConfigDB, ciliumlogs []types.CiliumLog) ([]types.CiliumLog, error) {
 	var err error = nil
-	db := connectMySQL(cfg)
-	defer db.Close()
+	db := connectObsDB(cfg)
 
-	for _, kubearmorLog := range ciliumlogs {
-		if err = updateOrInsertCiliumLogMySQL(db, kubearmorLog); err != nil {
+	inserted := []types.CiliumLog{}
+	for _, ciliumLog := range ciliumlogs {
+		var isNew bool
+		if isNew, err = updateOrInsertCiliumLogMySQL(db, ciliumLog); err != nil {
 			log.Error().Msg(err.Error())
+		} else if isNew {
+			inserted = append(inserted, ciliumLog)
 		}
 	}
-	return err
+	return inserted, err
 }
 
 // UpdateCiliumLogsMySQL -- Update existing log with time and count
-func updateOrInsertCiliumLogMySQL(db *sql.DB, ciliumlog types.CiliumLog) error {
+func updateOrInsertCiliumLogMySQL(db *sql.DB, ciliumlog types.CiliumLog) (bool, error) {
 	var err error
 	updateQueryString := `verdict = ? and ip_source = ? and ip_destination = ? and ip_version = ? and ip_encrypted = ? and l4_tcp_source_port = ? and 
 					l4_tcp_destination_port = ? and l4_udp_source_port = ? and l4_udp_destination_port = ? and l4_icmpv4_type = ? and 
@@ -1481,7 +1888,7 @@ func updateOrInsertCiliumLogMySQL(db *sql.DB, ciliumlog types.CiliumLog) error {
 
 	stmt, err := db.Prepare(query)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer stmt.Close()
 
@@ -1529,7 +1936,7 @@ func updateOrInsertCiliumLogMySQL(db *sql.DB, ciliumlog types.CiliumLog) error {
 	)
 
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -1547,7 +1954,7 @@ func updateOrInsertCiliumLogMySQL(db *sql.DB, ciliumlog types.CiliumLog) error {
 
 		stmt, err := db.Prepare(query)
 		if err != nil {
-			return err
+			return false, err
 		}
 		defer stmt.Close()
 
@@ -1594,7 +2001,8 @@ func updateOrInsertCiliumLogMySQL(db *sql.DB, ciliumlog types.CiliumLog) error {
 			ciliumlog.StartTime,
 			ciliumlog.UpdatedTime,
 			1)
+		return err == nil, err
 	}
 
-	return err
+	return false, err
 }
@@ -0,0 +1,121 @@
+package libs
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ============================== //
+// == Observability Connection == //
+// ============================== //
+
+// ObsDB long-lived pooled handle of the observability store, opened on first use
+var ObsDB *sql.DB = nil
+var obsDBCfg types.ConfigDB
+var obsDBMutex = &sync.Mutex{}
+
+// getObsMaxOpenConns returns the max open connections of the observability store, sqlite allows a single
+// writer and the concurrent ones fail with SQLITE_BUSY, so its pool is limited to one connection
+func getObsMaxOpenConns(cfg types.ConfigDB) int {
+	if cfg.DBDriver == "mysql" {
+		return cfg.MaxOpenConns
+	}
+
+	if cfg.MaxOpenConns != 0 && cfg.MaxOpenConns != 1 {
+		log.Warn().Msgf("observability db max-open-conns %d ignored, sqlite3 uses a single connection", cfg.MaxOpenConns)
+	}
+	return 1
+}
+
+func openObsDB(cfg types.ConfigDB) *sql.DB {
+	var db *sql.DB
+	if cfg.DBDriver == "mysql" {
+		db = connectMySQL(cfg)
+	} else {
+		db = connectSQLite(cfg, cfg.SQLiteDBPath)
+	}
+
+	db.SetMaxOpenConns(getObsMaxOpenConns(cfg))
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+
+	if cfg.ConnMaxLifetime != "" {
+		lifetime, err := time.ParseDuration(cfg.ConnMaxLifetime)
+		if err != nil {
+			log.Error().Msgf("invalid observability db conn-max-lifetime [%s] err=%s", cfg.ConnMaxLifetime, err.Error())
+		} else {
+			db.SetConnMaxLifetime(lifetime)
+		}
+	}
+
+	return db
+}
+
+// connectObsDB returns the pooled handle of the observability store, the handle is shared
+// and must not be closed by the caller
+func connectObsDB(cfg types.ConfigDB) *sql.DB {
+	if MockDB != nil {
+		return MockDB
+	}
+
+	obsDBMutex.Lock()
+	defer obsDBMutex.Unlock()
+
+	if ObsDB != nil && obsDBCfg == cfg {
+		return ObsDB
+	}
+
+	// the config was updated, reconnect
+	if ObsDB != nil {
+		if err := ObsDB.Close(); err != nil {
+			log.Error().Msg(err.Error())
+		}
+	}
+
+	ObsDB = openObsDB(cfg)
+	obsDBCfg = cfg
+
+	return ObsDB
+}
+
+// CloseObsDB closes the pooled handle of the observability store
+func CloseObsDB() {
+	obsDBMutex.Lock()
+	defer obsDBMutex.Unlock()
+
+	if ObsDB == nil {
+		return
+	}
+
+	if err := ObsDB.Close(); err != nil {
+		log.Error().Msg(err.Error())
+	}
+	ObsDB = nil
+}
+
+// ========================= //
+// == Observability Table == //
+// ========================= //
+
+// CreateObsTablesIfNotExist creates the log tables in the observability store
+func CreateObsTablesIfNotExist(cfg types.ConfigDB) {
+	if cfg.DBDriver == "mysql" {
+		if err := CreateTableSystemLogsMySQL(cfg); err != nil {
+			log.Error().Msg(err.Error())
+		}
+		if err := CreateTableNetworkLogsMySQL(cfg); err != nil {
+			log.Error().Msg(err.Error())
+		}
+	} else if cfg.DBDriver == "sqlite3" {
+		if err := CreateTableSystemLogsSQLite(cfg); err != nil {
+			log.Error().Msg(err.Error())
+		}
+		if err := CreateTableNetworkLogsSQLite(cfg); err != nil {
+			log.Error().Msg(err.Error())
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package libs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenObsDBSQLiteMaxOpenConns(t *testing.T) {
+	cfg := types.ConfigDB{
+		DBDriver:     "sqlite3",
+		SQLiteDBPath: filepath.Join(t.TempDir(), "obs.db"),
+		MaxOpenConns: 8,
+	}
+
+	// the concurrent sqlite writers would fail with SQLITE_BUSY
+	db := openObsDB(cfg)
+	defer db.Close()
+	assert.Equal(t, 1, db.Stats().MaxOpenConnections)
+
+	cfg.MaxOpenConns = 0
+	assert.Equal(t, 1, getObsMaxOpenConns(cfg))
+
+	cfg.DBDriver = "mysql"
+	assert.Equal(t, 0, getObsMaxOpenConns(cfg))
+	cfg.MaxOpenConns = 8
+	assert.Equal(t, 8, getObsMaxOpenConns(cfg))
+}
@@ -0,0 +1,126 @@
+package libs
+
+import (
+	"errors"
+	"io"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// ===================== //
+// == Parquet Writer  == //
+// ===================== //
+
+// a flat parquet file of required columns, written by the parquet-go writer and snappy compressed,
+// the rows are buffered and written as a row group every ParquetRowGroupSize bytes
+
+// ParquetRowGroupSize max size in bytes of a row group
+const ParquetRowGroupSize = 128 * 1024 * 1024
+
+// ParquetCreatedBy the writer recorded in the file metadata
+const ParquetCreatedBy = "knoxautopolicy"
+
+// parquet physical types
+const (
+	ParquetBoolean   = "BOOLEAN"
+	ParquetInt32     = "INT32"
+	ParquetInt64     = "INT64"
+	ParquetByteArray = "BYTE_ARRAY"
+)
+
+// ParquetColumn a column of a flat parquet schema
+type ParquetColumn struct {
+	Name string
+	Type string
+	// unsigned int32 column
+	Unsigned bool
+}
+
+// schemaMetadata returns the parquet-go schema metadata of the column
+func (column ParquetColumn) schemaMetadata() string {
+	metadata := "name=" + column.Name + ", type=" + column.Type + ", repetitiontype=REQUIRED"
+	if column.Type == ParquetByteArray {
+		metadata = metadata + ", convertedtype=UTF8"
+	} else if column.Unsigned {
+		metadata = metadata + ", convertedtype=UINT_32"
+	}
+	return metadata
+}
+
+// parquetValue returns the value of the column as its parquet-go type, the unsigned int32 values are stored
+// in the int32 physical type
+func (column ParquetColumn) parquetValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if column.Type == ParquetByteArray {
+			return v, nil
+		}
+	case bool:
+		if column.Type == ParquetBoolean {
+			return v, nil
+		}
+	case int32:
+		if column.Type == ParquetInt32 {
+			return v, nil
+		}
+	case uint32:
+		if column.Type == ParquetInt32 {
+			return int32(v), nil
+		}
+	case int64:
+		if column.Type == ParquetInt64 {
+			return v, nil
+		}
+	}
+	return nil, errors.New("invalid value of parquet column " + column.Name)
+}
+
+// ParquetWriter writes the rows of the columns as a parquet file
+type ParquetWriter struct {
+	columns []ParquetColumn
+	pw      *writer.CSVWriter
+}
+
+// NewParquetWriter writes the parquet header to w, the rows are written by WriteRow and the footer by Close
+func NewParquetWriter(w io.Writer, columns []ParquetColumn) (*ParquetWriter, error) {
+	metadata := []string{}
+	for _, column := range columns {
+		metadata = append(metadata, column.schemaMetadata())
+	}
+
+	pw, err := writer.NewCSVWriterFromWriter(metadata, w, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	createdBy := ParquetCreatedBy
+	pw.Footer.CreatedBy = &createdBy
+	pw.RowGroupSize = ParquetRowGroupSize
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	return &ParquetWriter{columns: columns, pw: pw}, nil
+}
+
+// WriteRow buffers the values of a row, in the order of the columns
+func (pw *ParquetWriter) WriteRow(values []interface{}) error {
+	if len(values) != len(pw.columns) {
+		return errors.New("the row does not match the parquet columns")
+	}
+
+	row := make([]interface{}, 0, len(values))
+	for i, value := range values {
+		v, err := pw.columns[i].parquetValue(value)
+		if err != nil {
+			return err
+		}
+		row = append(row, v)
+	}
+
+	return pw.pw.Write(row)
+}
+
+// Close writes the buffered rows and the footer of the file
+func (pw *ParquetWriter) Close() error {
+	return pw.pw.WriteStop()
+}
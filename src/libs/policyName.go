@@ -0,0 +1,151 @@
+package libs
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ================= //
+// == Policy Name == //
+// ================= //
+
+const (
+	// PolicyNamePrefix the prefix of the discovered policy names
+	PolicyNamePrefix = "autopol"
+
+	// PolicyNameMaxLen the names are valid k8s object names and label values
+	PolicyNameMaxLen = 63
+
+	policyNameHashLen = 8
+)
+
+// policyNameLabelKeys the label keys naming the workload, the most specific first
+var policyNameLabelKeys = []string{
+	"app.kubernetes.io/name",
+	"app",
+	"k8s-app",
+	"name",
+	"app.kubernetes.io/component",
+	"component",
+}
+
+// sanitizePolicyName lowercases the name and replaces the characters not allowed in a k8s name
+func sanitizePolicyName(name string) string {
+	var sb strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(name) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			sb.WriteRune(c)
+			dash = false
+		} else if !dash {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.Trim(sb.String(), "-")
+}
+
+// LabelStringToMap converts the comma separated key=value labels to a map
+func LabelStringToMap(labels string) map[string]string {
+	results := map[string]string{}
+	for _, label := range strings.Split(labels, ",") {
+		kv := strings.SplitN(strings.TrimSpace(label), "=", 2)
+		if len(kv) == 2 && kv[0] != "" {
+			results[kv[0]] = kv[1]
+		}
+	}
+	return results
+}
+
+// getPolicyNameWorkload returns the workload name of the selector labels, the value of the
+// first well-known app label, or the value of the first label in key order
+func getPolicyNameWorkload(labels map[string]string) string {
+	trimmed := map[string]string{}
+	keys := []string{}
+	for k, v := range labels {
+		k = strings.TrimPrefix(k, "k8s:")
+		trimmed[k] = v
+		keys = append(keys, k)
+	}
+
+	for _, key := range policyNameLabelKeys {
+		if v, ok := trimmed[key]; ok && sanitizePolicyName(v) != "" {
+			return v
+		}
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if sanitizePolicyName(trimmed[key]) != "" {
+			return trimmed[key]
+		}
+	}
+	return ""
+}
+
+// getPolicyNameHash returns a stable hash of the policy identity
+func getPolicyNameHash(policyType, namespace string, labels map[string]string, identity []string) string {
+	lbls := []string{}
+	for k, v := range labels {
+		lbls = append(lbls, strings.TrimPrefix(k, "k8s:")+"="+v)
+	}
+	sort.Strings(lbls)
+
+	fields := append([]string{policyType, namespace, strings.Join(lbls, ",")}, identity...)
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.Join(fields, types.RecordSeparator)))
+	return fmt.Sprintf("%0*x", policyNameHashLen, h.Sum32())
+}
+
+// GeneratePolicyName returns the deterministic name of a policy, derived from its type, namespace
+// and selector labels, e.g. autopol-egress-default-web-1a2b3c4d, the hash of the type, namespace,
+// labels and extra identity fields keeps the names of different workloads apart
+func GeneratePolicyName(policyType, namespace string, labels map[string]string, identity ...string) string {
+	hash := getPolicyNameHash(policyType, namespace, labels, identity)
+
+	parts := []string{PolicyNamePrefix}
+	for _, part := range []string{policyType, namespace, getPolicyNameWorkload(labels)} {
+		if part = sanitizePolicyName(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	readable := strings.Join(parts, "-")
+	if maxLen := PolicyNameMaxLen - policyNameHashLen - 1; len(readable) > maxLen {
+		readable = strings.TrimRight(readable[:maxLen], "-")
+	}
+
+	return readable + "-" + hash
+}
+
+// UniquePolicyName returns the name, or the name with the lowest -N suffix not in the names,
+// the versions of a policy share its generated name
+func UniquePolicyName(policyNamesMap map[string]bool, name string) string {
+	if !policyNamesMap[name] {
+		return name
+	}
+
+	for i := 2; ; i++ {
+		suffix := "-" + strconv.Itoa(i)
+		base := name
+		if len(base)+len(suffix) > PolicyNameMaxLen {
+			base = base[:PolicyNameMaxLen-len(suffix)]
+		}
+		if candidate := base + suffix; !policyNamesMap[candidate] {
+			return candidate
+		}
+	}
+}
+
+// getWPFSPolicyName returns the deterministic name of the policy of a WPFS record,
+// the container and source are part of the identity of the record
+func getWPFSPolicyName(wpfs types.WorkloadProcessFileSet) string {
+	return GeneratePolicyName(strings.ToLower(wpfs.SetType), wpfs.Namespace, LabelStringToMap(wpfs.Labels),
+		wpfs.ContainerName, wpfs.FromSource)
+}
@@ -0,0 +1,224 @@
+package libs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ============================ //
+// == Policy Name Migration  == //
+// ============================ //
+
+// policyNameRow a policy row to rename, with the policy it is outdated by
+type policyNameRow struct {
+	id        int64
+	cluster   string
+	name      string
+	namespace string
+	polType   string
+	outdated  sql.NullString
+	labels    map[string]string
+	// extra identity of the WPFS records
+	identity []string
+}
+
+// policyNameRenames the old to new names of each cluster
+type policyNameRenames map[string]map[string]string
+
+func (r policyNameRenames) rename(cluster, name string) string {
+	if renamed, ok := r[cluster][name]; ok {
+		return renamed
+	}
+	return name
+}
+
+// getPolicyIDColumn returns the row id column, sqlite does not auto increment the id column
+func getPolicyIDColumn(driver string) string {
+	if driver == "sqlite3" {
+		return "rowid"
+	}
+	return "id"
+}
+
+func selectPolicyNameRows(tx *sql.Tx, query string, scan func(*sql.Rows) (policyNameRow, error)) ([]policyNameRow, error) {
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []policyNameRow{}
+	for rows.Next() {
+		row, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+func scanPolicyRow(rows *sql.Rows) (policyNameRow, error) {
+	row := policyNameRow{}
+	specByte := []byte{}
+	if err := rows.Scan(&row.id, &row.cluster, &row.name, &row.namespace, &row.polType, &row.outdated, &specByte); err != nil {
+		return row, err
+	}
+
+	// the network and system specs share the selector
+	spec := struct {
+		Selector types.Selector `json:"selector"`
+	}{}
+	if len(specByte) > 0 {
+		if err := json.Unmarshal(specByte, &spec); err != nil {
+			return row, err
+		}
+	}
+	row.labels = spec.Selector.MatchLabels
+	row.polType = strings.ToLower(row.polType)
+
+	return row, nil
+}
+
+// isLatest reports whether the row is not outdated by another policy
+func (row policyNameRow) isLatest() bool {
+	return !row.outdated.Valid || row.outdated.String == ""
+}
+
+// assignPolicyNames renames the latest rows first and then the outdated rows, newest first, so the latest
+// version of a policy keeps the generated name the discovery looks it up by
+func assignPolicyNames(rows []policyNameRow, names map[string]map[string]bool, renames policyNameRenames) {
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		rowA, rowB := rows[order[a]], rows[order[b]]
+		if rowA.isLatest() != rowB.isLatest() {
+			return rowA.isLatest()
+		}
+		return rowA.id > rowB.id
+	})
+
+	for _, i := range order {
+		row := rows[i]
+		if names[row.cluster] == nil {
+			names[row.cluster] = map[string]bool{}
+			renames[row.cluster] = map[string]string{}
+		}
+
+		name := UniquePolicyName(names[row.cluster], GeneratePolicyName(row.polType, row.namespace, row.labels, row.identity...))
+		names[row.cluster][name] = true
+		renames[row.cluster][row.name] = name
+		rows[i].name = name
+	}
+}
+
+// setSystemPolicyIdentity names the system policies like the discovery, by the "system" type and the container
+// of the WPFS records of the workload, the container is left empty when the records of the workload do not
+// name a single container, as for the policies discovered without the WPFS records
+func setSystemPolicyIdentity(systemRows, wpfsRows []policyNameRow) {
+	containers := map[string][]string{}
+	for _, row := range wpfsRows {
+		key := row.cluster + "/" + row.namespace + "/" + LabelMapToString(row.labels)
+		if !ContainsElement(containers[key], row.identity[0]) {
+			containers[key] = append(containers[key], row.identity[0])
+		}
+	}
+
+	for i, row := range systemRows {
+		container := ""
+		if names := containers[row.cluster+"/"+row.namespace+"/"+LabelMapToString(row.labels)]; len(names) == 1 {
+			container = names[0]
+		}
+		systemRows[i].polType = "system"
+		systemRows[i].identity = []string{container}
+	}
+}
+
+func updatePolicyNameRows(tx *sql.Tx, query string, rows []policyNameRow, renames policyNameRenames, withOutdated bool) error {
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		args := []interface{}{row.name}
+		if withOutdated {
+			outdated := row.outdated
+			if outdated.Valid {
+				outdated.String = renames.rename(row.cluster, outdated.String)
+			}
+			args = append(args, outdated)
+		}
+		if _, err := stmt.Exec(append(args, row.id)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migratePolicyNames renames the discovered policies and WPFS records to their deterministic names,
+// the outdated references are renamed along with the policies of the same cluster
+func migratePolicyNames(tx *sql.Tx, driver string) error {
+	idColumn := getPolicyIDColumn(driver)
+
+	networkTable, systemTable, wpfsTable := TableNetworkPolicy_TableName, TableSystemPolicy_TableName, WorkloadProcessFileSet_TableName
+	if driver == "sqlite3" {
+		networkTable, systemTable, wpfsTable = TableNetworkPolicySQLite_TableName, TableSystemPolicySQLite_TableName, WorkloadProcessFileSetSQLite_TableName
+	}
+
+	// network policies
+	networkRows, err := selectPolicyNameRows(tx, "SELECT "+idColumn+",COALESCE(cluster_name,''),COALESCE(name,''),COALESCE(namespace,''),COALESCE(type,''),outdated,spec FROM "+
+		networkTable+" ORDER BY "+idColumn, scanPolicyRow)
+	if err != nil {
+		return err
+	}
+
+	networkRenames := policyNameRenames{}
+	assignPolicyNames(networkRows, map[string]map[string]bool{}, networkRenames)
+	if err := updatePolicyNameRows(tx, "UPDATE "+networkTable+" SET name=?,outdated=? WHERE "+idColumn+"=?",
+		networkRows, networkRenames, true); err != nil {
+		return err
+	}
+
+	// WPFS records and system policies, a system policy may be outdated by a WPFS policy
+	wpfsRows, err := selectPolicyNameRows(tx, "SELECT "+idColumn+",COALESCE(clusterName,''),COALESCE(policyName,''),COALESCE(namespace,''),COALESCE(labels,''),containerName,COALESCE(fromSource,''),COALESCE(settype,'') FROM "+
+		wpfsTable+" ORDER BY "+idColumn, func(rows *sql.Rows) (policyNameRow, error) {
+		row := policyNameRow{}
+		var labels, containerName, fromSource string
+		if err := rows.Scan(&row.id, &row.cluster, &row.name, &row.namespace, &labels, &containerName, &fromSource, &row.polType); err != nil {
+			return row, err
+		}
+		row.labels = LabelStringToMap(labels)
+		row.polType = strings.ToLower(row.polType)
+		row.identity = []string{containerName, fromSource}
+		return row, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	systemRows, err := selectPolicyNameRows(tx, "SELECT "+idColumn+",COALESCE(clusterName,''),COALESCE(name,''),COALESCE(namespace,''),type,outdated,spec FROM "+
+		systemTable+" ORDER BY "+idColumn, scanPolicyRow)
+	if err != nil {
+		return err
+	}
+	setSystemPolicyIdentity(systemRows, wpfsRows)
+
+	systemNames, systemRenames := map[string]map[string]bool{}, policyNameRenames{}
+	assignPolicyNames(wpfsRows, systemNames, systemRenames)
+	assignPolicyNames(systemRows, systemNames, systemRenames)
+
+	if err := updatePolicyNameRows(tx, "UPDATE "+wpfsTable+" SET policyName=? WHERE "+idColumn+"=?",
+		wpfsRows, systemRenames, false); err != nil {
+		return err
+	}
+	return updatePolicyNameRows(tx, "UPDATE "+systemTable+" SET name=?,outdated=? WHERE "+idColumn+"=?",
+		systemRows, systemRenames, true)
+}
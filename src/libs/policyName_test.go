@@ -0,0 +1,74 @@
+package libs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratePolicyName(t *testing.T) {
+	name := GeneratePolicyName("egress", "default", map[string]string{"app": "web", "tier": "front"})
+	assert.True(t, strings.HasPrefix(name, "autopol-egress-default-web-"), name)
+	assert.Equal(t, len("autopol-egress-default-web-")+policyNameHashLen, len(name))
+
+	// stable across runs and label order, and apart for other workloads
+	assert.Equal(t, name, GeneratePolicyName("egress", "default", map[string]string{"tier": "front", "app": "web"}))
+	assert.NotEqual(t, name, GeneratePolicyName("egress", "default", map[string]string{"app": "web"}))
+	assert.NotEqual(t, name, GeneratePolicyName("ingress", "default", map[string]string{"app": "web", "tier": "front"}))
+
+	name = GeneratePolicyName("process", "kube-system", map[string]string{"k8s:k8s-app": "Kube_DNS"})
+	assert.True(t, strings.HasPrefix(name, "autopol-process-kube-system-kube-dns-"), name)
+
+	name = GeneratePolicyName("file", strings.Repeat("namespace", 10), map[string]string{"app": "web"}, "nginx", "/bin/sh")
+	assert.Equal(t, PolicyNameMaxLen, len(name))
+	assert.NotEqual(t, name, GeneratePolicyName("file", strings.Repeat("namespace", 10), map[string]string{"app": "web"}, "nginx", "/bin/bash"))
+}
+
+func TestUniquePolicyName(t *testing.T) {
+	names := map[string]bool{"autopol-egress-default-web-1a2b3c4d": true, "autopol-egress-default-web-1a2b3c4d-2": true}
+
+	assert.Equal(t, "autopol-ingress-default-web-1a2b3c4d", UniquePolicyName(names, "autopol-ingress-default-web-1a2b3c4d"))
+	assert.Equal(t, "autopol-egress-default-web-1a2b3c4d-3", UniquePolicyName(names, "autopol-egress-default-web-1a2b3c4d"))
+}
+
+func TestMigratePolicyNames(t *testing.T) {
+	db, mock := NewMock()
+
+	webName := GeneratePolicyName("egress", "default", map[string]string{"app": "web"})
+	spec := `{"selector":{"matchLabels":{"app":"web"}}}`
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT rowid,(.+) FROM network_policy ORDER BY rowid").
+		WillReturnRows(sqlmock.NewRows([]string{"rowid", "cluster_name", "name", "namespace", "type", "outdated", "spec"}).
+			AddRow(1, "c1", "autopol-egress-aaaa", "default", "egress", "autopol-egress-bbbb", spec).
+			AddRow(2, "c1", "autopol-egress-bbbb", "default", "egress", nil, spec))
+	prep := mock.ExpectPrepare("^UPDATE network_policy SET name=\\?,outdated=\\? WHERE rowid=\\?")
+	// the latest version keeps the generated name
+	prep.ExpectExec().WithArgs(webName+"-2", webName, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	prep.ExpectExec().WithArgs(webName, nil, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("^SELECT rowid,(.+) FROM workload_process_fileset ORDER BY rowid").
+		WillReturnRows(sqlmock.NewRows([]string{"rowid", "clusterName", "policyName", "namespace", "labels", "containerName", "fromSource", "settype"}).
+			AddRow(1, "c1", "autopol-file-cccc", "default", "app=web", "nginx", "", "file"))
+	mock.ExpectQuery("^SELECT rowid,(.+) FROM system_policy ORDER BY rowid").
+		WillReturnRows(sqlmock.NewRows([]string{"rowid", "clusterName", "name", "namespace", "type", "outdated", "spec"}).
+			AddRow(1, "c1", "autopol-file-dddd", "default", "File", "autopol-file-cccc", spec))
+	mock.ExpectPrepare("^UPDATE workload_process_fileset SET policyName=\\? WHERE rowid=\\?").
+		ExpectExec().WithArgs(GeneratePolicyName("file", "default", map[string]string{"app": "web"}, "nginx", ""), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("^UPDATE system_policy SET name=\\?,outdated=\\? WHERE rowid=\\?").
+		ExpectExec().WithArgs(GeneratePolicyName("system", "default", map[string]string{"app": "web"}, "nginx"),
+		GeneratePolicyName("file", "default", map[string]string{"app": "web"}, "nginx", ""), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, migratePolicyNames(tx, "sqlite3"))
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
@@ -0,0 +1,96 @@
+package libs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// =================== //
+// == Policy Review == //
+// =================== //
+
+// review states of the discovered policies, discovered -> approved/rejected -> applied -> superseded
+const (
+	ReviewStateDiscovered = "discovered"
+	ReviewStateApproved   = "approved"
+	ReviewStateRejected   = "rejected"
+	ReviewStateApplied    = "applied"
+	// set when the policy is outdated by a newer version, not by a review
+	ReviewStateSuperseded = "superseded"
+)
+
+// reviewTransitions the states a reviewer can move a policy to from each state
+var reviewTransitions = map[string][]string{
+	ReviewStateDiscovered: {ReviewStateApproved, ReviewStateRejected},
+	ReviewStateApproved:   {ReviewStateApplied, ReviewStateRejected},
+	ReviewStateRejected:   {ReviewStateApproved},
+	ReviewStateApplied:    {ReviewStateRejected},
+}
+
+// GetReviewState returns the review state of the policy metadata, the policies not reviewed yet are discovered
+func GetReviewState(metadata map[string]string) string {
+	if state := metadata["review_state"]; state != "" {
+		return state
+	}
+	return ReviewStateDiscovered
+}
+
+// ValidateReviewTransition returns an error if a reviewer cannot move a policy from one state to the other
+func ValidateReviewTransition(from, to string) error {
+	if _, ok := reviewTransitions[to]; !ok {
+		return fmt.Errorf("invalid review state %s, choose approved/rejected/applied", to)
+	}
+
+	if !ContainsElement(reviewTransitions[from], to) {
+		return fmt.Errorf("cannot move a %s policy to %s", from, to)
+	}
+
+	return nil
+}
+
+// updatePolicyReview validates the transition from the current state of the latest policy and records
+// the reviewer and comment, the policies are identified by their name in the cluster, the review fails
+// if the state changed since it was read
+func updatePolicyReview(db *sql.DB, table, clusterColumn string, review *types.PolicyReview) error {
+	var state, status sql.NullString
+	if err := db.QueryRow("SELECT review_state,status FROM "+table+" WHERE name = ? and "+clusterColumn+" = ?",
+		review.Name, review.ClusterName).Scan(&state, &status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("no %s policy %s in cluster %s", review.PolicyType, review.Name, review.ClusterName)
+		}
+		return err
+	}
+
+	if status.String == "outdated" {
+		return fmt.Errorf("policy %s is outdated, review its latest version", review.Name)
+	}
+
+	review.PrevState = ReviewStateDiscovered
+	if state.Valid && state.String != "" {
+		review.PrevState = state.String
+	}
+
+	if err := ValidateReviewTransition(review.PrevState, review.State); err != nil {
+		return err
+	}
+
+	review.ReviewedTime = ConvertStrToUnixTime("now")
+
+	result, err := db.Exec("UPDATE "+table+" SET review_state=?,reviewer=?,review_comment=?,reviewed_time=? WHERE name = ? and "+clusterColumn+" = ? and COALESCE(review_state,'') = ?",
+		review.State, review.Reviewer, review.Comment, review.ReviewedTime, review.Name, review.ClusterName, state.String)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("policy %s was reviewed concurrently, review it again", review.Name)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package libs
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateReviewTransition(t *testing.T) {
+	assert.NoError(t, ValidateReviewTransition(ReviewStateDiscovered, ReviewStateApproved))
+	assert.NoError(t, ValidateReviewTransition(ReviewStateDiscovered, ReviewStateRejected))
+	assert.NoError(t, ValidateReviewTransition(ReviewStateApproved, ReviewStateApplied))
+	assert.NoError(t, ValidateReviewTransition(ReviewStateRejected, ReviewStateApproved))
+
+	assert.Error(t, ValidateReviewTransition(ReviewStateDiscovered, ReviewStateApplied))
+	assert.Error(t, ValidateReviewTransition(ReviewStateRejected, ReviewStateApplied))
+	assert.Error(t, ValidateReviewTransition(ReviewStateSuperseded, ReviewStateApproved))
+	// superseded is only set when the policy is outdated
+	assert.Error(t, ValidateReviewTransition(ReviewStateApplied, ReviewStateSuperseded))
+}
+
+func TestUpdatePolicyReview(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectQuery("^SELECT review_state,status FROM system_policy WHERE name = \\? and clusterName = \\?").
+		WithArgs("autopol-file-default-web-1a2b3c4d", "default").
+		WillReturnRows(sqlmock.NewRows([]string{"review_state", "status"}).AddRow(nil, "latest"))
+	mock.ExpectExec("^UPDATE system_policy SET review_state=\\?,reviewer=\\?,review_comment=\\?,reviewed_time=\\? WHERE name = \\? and clusterName = \\? and COALESCE\\(review_state,''\\) = \\?").
+		WithArgs(ReviewStateRejected, "alice", "too broad", sqlmock.AnyArg(), "autopol-file-default-web-1a2b3c4d", "default", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	review := types.PolicyReview{
+		PolicyType:  "system",
+		ClusterName: "default",
+		Name:        "autopol-file-default-web-1a2b3c4d",
+		State:       ReviewStateRejected,
+		Reviewer:    "alice",
+		Comment:     "too broad",
+	}
+	err := UpdatePolicyReview(types.ConfigDB{DBDriver: "mysql"}, &review)
+	assert.NoError(t, err)
+	assert.Equal(t, ReviewStateDiscovered, review.PrevState)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestUpdatePolicyReviewConcurrent(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectQuery("^SELECT review_state,status FROM system_policy WHERE name = \\? and clusterName = \\?").
+		WithArgs("autopol-file-default-web-1a2b3c4d", "default").
+		WillReturnRows(sqlmock.NewRows([]string{"review_state", "status"}).AddRow(ReviewStateApproved, "latest"))
+	// another reviewer rejected the policy in between
+	mock.ExpectExec("^UPDATE system_policy SET (.+) and COALESCE\\(review_state,''\\) = \\?").
+		WithArgs(ReviewStateApplied, "bob", "", sqlmock.AnyArg(), "autopol-file-default-web-1a2b3c4d", "default", ReviewStateApproved).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	review := types.PolicyReview{
+		PolicyType:  "system",
+		ClusterName: "default",
+		Name:        "autopol-file-default-web-1a2b3c4d",
+		State:       ReviewStateApplied,
+		Reviewer:    "bob",
+	}
+	err := UpdatePolicyReview(types.ConfigDB{DBDriver: "mysql"}, &review)
+	assert.Error(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestUpdatePolicyReviewOutdated(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectQuery("^SELECT review_state,status FROM network_policy WHERE name = \\? and cluster_name = \\?").
+		WithArgs("autopol-egress-default-web-1a2b3c4d", "default").
+		WillReturnRows(sqlmock.NewRows([]string{"review_state", "status"}).AddRow(ReviewStateSuperseded, "outdated"))
+
+	review := types.PolicyReview{
+		PolicyType:  "network",
+		ClusterName: "default",
+		Name:        "autopol-egress-default-web-1a2b3c4d",
+		State:       ReviewStateApproved,
+	}
+	err := UpdatePolicyReview(types.ConfigDB{DBDriver: "sqlite3"}, &review)
+	assert.Error(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
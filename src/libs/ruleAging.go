@@ -0,0 +1,224 @@
+package libs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ================ //
+// == Rule Aging == //
+// ================ //
+
+// PolicyChangeReasonAged the reason of a policy version without the rules not observed for the aging period
+const PolicyChangeReasonAged = "aged"
+
+func marshalRuleStats(stats map[string]types.RuleStat) (interface{}, error) {
+	if len(stats) == 0 {
+		return nil, nil
+	}
+
+	ruleStats, err := json.Marshal(stats)
+	if err != nil {
+		return nil, err
+	}
+
+	return ruleStats, nil
+}
+
+// unmarshalRuleStats returns nil for the policies stored before the rule statistics
+func unmarshalRuleStats(ruleStats sql.NullString) (map[string]types.RuleStat, error) {
+	if !ruleStats.Valid || ruleStats.String == "" {
+		return nil, nil
+	}
+
+	stats := map[string]types.RuleStat{}
+	if err := json.Unmarshal([]byte(ruleStats.String), &stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// MarkRulesSeen returns a copy of the rule statistics with the rules observed at the given time
+func MarkRulesSeen(stats map[string]types.RuleStat, keys []string, seenTime int64) map[string]types.RuleStat {
+	marked := map[string]types.RuleStat{}
+	for key, stat := range stats {
+		marked[key] = stat
+	}
+
+	for _, key := range keys {
+		stat := marked[key]
+		if stat.FirstSeen == 0 {
+			stat.FirstSeen = seenTime
+		}
+		if seenTime > stat.LastSeen {
+			stat.LastSeen = seenTime
+		}
+		stat.Hits++
+
+		marked[key] = stat
+	}
+
+	return marked
+}
+
+// GetAgedRuleKeys returns the rules last seen before the deadline, the rules without statistics
+// are considered seen at the given default time, e.g. the generation time of the policy
+func GetAgedRuleKeys(stats map[string]types.RuleStat, keys []string, defaultLastSeen int64, deadline int64) []string {
+	aged := []string{}
+
+	for _, key := range keys {
+		lastSeen := defaultLastSeen
+		if stat, ok := stats[key]; ok && stat.LastSeen > 0 {
+			lastSeen = stat.LastSeen
+		}
+
+		if lastSeen < deadline {
+			aged = append(aged, key)
+		}
+	}
+
+	return aged
+}
+
+// updateRuleStats stores the rule statistics of a policy without touching its updated time,
+// so observing the same rules again does not look like a policy change
+func updateRuleStats(db *sql.DB, table, clusterColumn, clusterName, name string, stats map[string]types.RuleStat) error {
+	ruleStats, err := marshalRuleStats(stats)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec("UPDATE "+table+" SET rule_stats=? WHERE name = ? and "+clusterColumn+" = ?",
+		ruleStats, name, clusterName)
+	return err
+}
+
+func insertPolicyChange(db *sql.DB, table string, change types.PolicyChange) error {
+	removedRules, err := json.Marshal(change.RemovedRules)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare("INSERT INTO " + table +
+		"(policy_type,cluster_name,namespace,name,new_policy_name,reason,removed_rules,changed_time) values(?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(
+		change.PolicyType,
+		change.ClusterName,
+		change.Namespace,
+		change.Name,
+		change.NewPolicyName,
+		change.Reason,
+		removedRules,
+		change.ChangedTime)
+	return err
+}
+
+func getPolicyChanges(db *sql.DB, table string, filter types.PolicyChange) ([]types.PolicyChange, error) {
+	query := "SELECT policy_type,cluster_name,namespace,name,new_policy_name,reason,removed_rules,changed_time FROM " + table
+
+	var whereClause string
+	var args []interface{}
+
+	if filter.PolicyType != "" {
+		concatWhereClause(&whereClause, "policy_type")
+		args = append(args, filter.PolicyType)
+	}
+	if filter.ClusterName != "" {
+		concatWhereClause(&whereClause, "cluster_name")
+		args = append(args, filter.ClusterName)
+	}
+	if filter.Namespace != "" {
+		concatWhereClause(&whereClause, "namespace")
+		args = append(args, filter.Namespace)
+	}
+	if filter.Name != "" {
+		concatWhereClause(&whereClause, "name")
+		args = append(args, filter.Name)
+	}
+
+	results, err := db.Query(query+whereClause+" ORDER BY changed_time DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+
+	changes := []types.PolicyChange{}
+
+	for results.Next() {
+		var change types.PolicyChange
+		var removedRules []byte
+
+		if err := results.Scan(
+			&change.PolicyType,
+			&change.ClusterName,
+			&change.Namespace,
+			&change.Name,
+			&change.NewPolicyName,
+			&change.Reason,
+			&removedRules,
+			&change.ChangedTime,
+		); err != nil {
+			return nil, err
+		}
+
+		if len(removedRules) > 0 {
+			if err := json.Unmarshal(removedRules, &change.RemovedRules); err != nil {
+				return nil, err
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// UpdateRuleStats stores the rule statistics of a network or system policy
+func UpdateRuleStats(cfg types.ConfigDB, policyType, clusterName, name string, stats map[string]types.RuleStat) error {
+	if policyType != "network" && policyType != "system" {
+		return errors.New("no policy type, choose network or system, not [" + policyType + "]")
+	}
+
+	if cfg.DBDriver == "mysql" {
+		return UpdateRuleStatsToMySQL(cfg, policyType, clusterName, name, stats)
+	} else if cfg.DBDriver == "sqlite3" {
+		return UpdateRuleStatsToSQLite(cfg, policyType, clusterName, name, stats)
+	}
+
+	return errors.New("no db driver")
+}
+
+func InsertPolicyChange(cfg types.ConfigDB, change types.PolicyChange) error {
+	if cfg.DBDriver == "mysql" {
+		return InsertPolicyChangeMySQL(cfg, change)
+	} else if cfg.DBDriver == "sqlite3" {
+		return InsertPolicyChangeSQLite(cfg, change)
+	}
+	return errors.New("no db driver")
+}
+
+func GetPolicyChanges(cfg types.ConfigDB, filter types.PolicyChange) ([]types.PolicyChange, error) {
+	if cfg.DBDriver == "mysql" {
+		res, err := GetPolicyChangesMySQL(cfg, filter)
+		if err != nil {
+			log.Error().Msg(err.Error())
+		}
+		return res, err
+	} else if cfg.DBDriver == "sqlite3" {
+		res, err := GetPolicyChangesSQLite(cfg, filter)
+		if err != nil {
+			log.Error().Msg(err.Error())
+		}
+		return res, err
+	}
+	return nil, errors.New("no db driver")
+}
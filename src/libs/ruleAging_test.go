@@ -0,0 +1,80 @@
+package libs
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkRulesSeen(t *testing.T) {
+	stats := map[string]types.RuleStat{
+		"a": {FirstSeen: 100, LastSeen: 200, Hits: 2},
+	}
+
+	marked := MarkRulesSeen(stats, []string{"a", "b"}, 300)
+	assert.Equal(t, types.RuleStat{FirstSeen: 100, LastSeen: 300, Hits: 3}, marked["a"])
+	assert.Equal(t, types.RuleStat{FirstSeen: 300, LastSeen: 300, Hits: 1}, marked["b"])
+	// the given statistics are not modified
+	assert.Equal(t, int64(200), stats["a"].LastSeen)
+	assert.NotContains(t, stats, "b")
+}
+
+func TestGetAgedRuleKeys(t *testing.T) {
+	stats := map[string]types.RuleStat{
+		"seen":  {FirstSeen: 100, LastSeen: 500, Hits: 5},
+		"stale": {FirstSeen: 100, LastSeen: 200, Hits: 1},
+	}
+
+	// the rules without statistics fall back to the generation time of the policy
+	aged := GetAgedRuleKeys(stats, []string{"seen", "stale", "unknown"}, 100, 300)
+	assert.Equal(t, []string{"stale", "unknown"}, aged)
+
+	aged = GetAgedRuleKeys(stats, []string{"seen", "unknown"}, 400, 300)
+	assert.Empty(t, aged)
+}
+
+func TestUpdateRuleStats(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectExec("^UPDATE network_policy SET rule_stats=\\? WHERE name = \\? and cluster_name = \\?").
+		WithArgs([]byte(`{"a":{"first_seen":100,"last_seen":200,"hits":2}}`), "autopol-egress-1a2b3c4d", "default").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	stats := map[string]types.RuleStat{
+		"a": {FirstSeen: 100, LastSeen: 200, Hits: 2},
+	}
+	err := UpdateRuleStats(types.ConfigDB{DBDriver: "mysql"}, "network", "default", "autopol-egress-1a2b3c4d", stats)
+	assert.NoError(t, err)
+
+	assert.Error(t, UpdateRuleStats(types.ConfigDB{DBDriver: "mysql"}, "kubearmor", "default", "", stats))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestGetPolicyChanges(t *testing.T) {
+	_, mock := NewMock()
+
+	rows := mock.NewRows([]string{
+		"policy_type", "cluster_name", "namespace", "name", "new_policy_name", "reason", "removed_rules", "changed_time",
+	}).
+		AddRow("system", "default", "wordpress", "autopol-process-1a2b3c4d", "autopol-process-5e6f7a8b", PolicyChangeReasonAged,
+			[]byte(`["process path /bin/sh"]`), 300)
+
+	mock.ExpectQuery("^SELECT (.+) FROM policy_change WHERE policy_type = \\? and namespace = \\? ORDER BY changed_time DESC").
+		WithArgs("system", "wordpress").
+		WillReturnRows(rows)
+
+	changes, err := GetPolicyChanges(types.ConfigDB{DBDriver: "mysql"}, types.PolicyChange{PolicyType: "system", Namespace: "wordpress"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, "autopol-process-5e6f7a8b", changes[0].NewPolicyName)
+	assert.Equal(t, []string{"process path /bin/sh"}, changes[0].RemovedRules)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
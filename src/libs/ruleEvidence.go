@@ -0,0 +1,280 @@
+package libs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// =================== //
+// == Rule Evidence == //
+// =================== //
+
+// RuleEvidenceSampleLimit the max number of the sample pods kept for a rule
+const RuleEvidenceSampleLimit = 5
+
+func appendEvidenceSamples(samples []string, values []string) []string {
+	for _, value := range values {
+		if len(samples) >= RuleEvidenceSampleLimit {
+			break
+		}
+		if value != "" && !ContainsElement(samples, value) {
+			samples = append(samples, value)
+		}
+	}
+
+	return samples
+}
+
+// MergeRuleEvidence returns the evidence of a rule observed again, the sample log is the latest one
+func MergeRuleEvidence(exist, evidence types.RuleEvidence) types.RuleEvidence {
+	merged := exist
+
+	if merged.FirstSeen == 0 || (evidence.FirstSeen > 0 && evidence.FirstSeen < merged.FirstSeen) {
+		merged.FirstSeen = evidence.FirstSeen
+	}
+	if evidence.LastSeen > merged.LastSeen {
+		merged.LastSeen = evidence.LastSeen
+		if evidence.SampleLog != "" {
+			merged.SampleLog = evidence.SampleLog
+		}
+	}
+	if merged.SampleLog == "" {
+		merged.SampleLog = evidence.SampleLog
+	}
+	if merged.Rule == "" {
+		merged.Rule = evidence.Rule
+	}
+	merged.Count += evidence.Count
+
+	merged.SrcPods = appendEvidenceSamples(append([]string{}, exist.SrcPods...), evidence.SrcPods)
+	merged.DstPods = appendEvidenceSamples(append([]string{}, exist.DstPods...), evidence.DstPods)
+
+	return merged
+}
+
+// GetRuleEvidenceSeenTime returns the time a log was observed, the logs without their own time were
+// observed at the time of the discovery run
+func GetRuleEvidenceSeenTime(logTime, runTime int64) int64 {
+	if logTime > 0 {
+		return logTime
+	}
+	return runTime
+}
+
+func getRuleEvidenceKey(evidence types.RuleEvidence) string {
+	return evidence.PolicyType + types.RecordSeparator + evidence.ClusterName + types.RecordSeparator +
+		evidence.Namespace + types.RecordSeparator + evidence.Labels + types.RecordSeparator + evidence.RuleKey
+}
+
+// AddRuleEvidence records a log observed for the rule in the evidence map of a discovery run
+func AddRuleEvidence(evidences map[string]types.RuleEvidence, evidence types.RuleEvidence) {
+	key := getRuleEvidenceKey(evidence)
+
+	if exist, ok := evidences[key]; ok {
+		evidences[key] = MergeRuleEvidence(exist, evidence)
+	} else {
+		evidences[key] = evidence
+	}
+}
+
+func getRuleEvidences(db *sql.DB, table string, filter types.RuleEvidence) ([]types.RuleEvidence, error) {
+	query := "SELECT policy_type,cluster_name,namespace,labels,rule_key,rule,first_seen,last_seen,count,src_pods,dst_pods,sample_log FROM " + table
+
+	var whereClause string
+	var args []interface{}
+
+	if filter.PolicyType != "" {
+		concatWhereClause(&whereClause, "policy_type")
+		args = append(args, filter.PolicyType)
+	}
+	if filter.ClusterName != "" {
+		concatWhereClause(&whereClause, "cluster_name")
+		args = append(args, filter.ClusterName)
+	}
+	if filter.Namespace != "" {
+		concatWhereClause(&whereClause, "namespace")
+		args = append(args, filter.Namespace)
+	}
+	if filter.Labels != "" {
+		concatWhereClause(&whereClause, "labels")
+		args = append(args, filter.Labels)
+	}
+	if filter.RuleKey != "" {
+		concatWhereClause(&whereClause, "rule_key")
+		args = append(args, filter.RuleKey)
+	}
+
+	results, err := db.Query(query+whereClause, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+
+	evidences := []types.RuleEvidence{}
+
+	for results.Next() {
+		var evidence types.RuleEvidence
+		var srcPods, dstPods []byte
+
+		if err := results.Scan(
+			&evidence.PolicyType,
+			&evidence.ClusterName,
+			&evidence.Namespace,
+			&evidence.Labels,
+			&evidence.RuleKey,
+			&evidence.Rule,
+			&evidence.FirstSeen,
+			&evidence.LastSeen,
+			&evidence.Count,
+			&srcPods,
+			&dstPods,
+			&evidence.SampleLog,
+		); err != nil {
+			return nil, err
+		}
+
+		if len(srcPods) > 0 {
+			if err := json.Unmarshal(srcPods, &evidence.SrcPods); err != nil {
+				return nil, err
+			}
+		}
+		if len(dstPods) > 0 {
+			if err := json.Unmarshal(dstPods, &evidence.DstPods); err != nil {
+				return nil, err
+			}
+		}
+
+		evidences = append(evidences, evidence)
+	}
+
+	return evidences, nil
+}
+
+// upsertRuleEvidences merges the evidences of a discovery run into the evidences already stored,
+// the stored evidences are read once per namespace of the run
+func upsertRuleEvidences(db *sql.DB, table string, evidences []types.RuleEvidence) error {
+	groups := []types.RuleEvidence{}
+	grouped := map[string][]types.RuleEvidence{}
+	for _, evidence := range evidences {
+		group := types.RuleEvidence{
+			PolicyType:  evidence.PolicyType,
+			ClusterName: evidence.ClusterName,
+			Namespace:   evidence.Namespace,
+		}
+		key := getRuleEvidenceKey(group)
+		if _, ok := grouped[key]; !ok {
+			groups = append(groups, group)
+		}
+		grouped[key] = append(grouped[key], evidence)
+	}
+
+	updateStmt, err := db.Prepare("UPDATE " + table + " SET first_seen=?,last_seen=?,count=?,src_pods=?,dst_pods=?,sample_log=? " +
+		"WHERE policy_type = ? and cluster_name = ? and namespace = ? and labels = ? and rule_key = ?")
+	if err != nil {
+		return err
+	}
+	defer updateStmt.Close()
+
+	insertStmt, err := db.Prepare("INSERT INTO " + table +
+		"(policy_type,cluster_name,namespace,labels,rule_key,rule,first_seen,last_seen,count,src_pods,dst_pods,sample_log) values(?,?,?,?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+	defer insertStmt.Close()
+
+	for _, group := range groups {
+		exists, err := getRuleEvidences(db, table, group)
+		if err != nil {
+			return err
+		}
+
+		stored := map[string]types.RuleEvidence{}
+		for _, exist := range exists {
+			stored[getRuleEvidenceKey(exist)] = exist
+		}
+
+		for _, evidence := range grouped[getRuleEvidenceKey(group)] {
+			exist, ok := stored[getRuleEvidenceKey(evidence)]
+			if ok {
+				evidence = MergeRuleEvidence(exist, evidence)
+			}
+
+			srcPods, err := json.Marshal(evidence.SrcPods)
+			if err != nil {
+				return err
+			}
+			dstPods, err := json.Marshal(evidence.DstPods)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				_, err = updateStmt.Exec(
+					evidence.FirstSeen,
+					evidence.LastSeen,
+					evidence.Count,
+					srcPods,
+					dstPods,
+					evidence.SampleLog,
+					evidence.PolicyType,
+					evidence.ClusterName,
+					evidence.Namespace,
+					evidence.Labels,
+					evidence.RuleKey)
+			} else {
+				_, err = insertStmt.Exec(
+					evidence.PolicyType,
+					evidence.ClusterName,
+					evidence.Namespace,
+					evidence.Labels,
+					evidence.RuleKey,
+					evidence.Rule,
+					evidence.FirstSeen,
+					evidence.LastSeen,
+					evidence.Count,
+					srcPods,
+					dstPods,
+					evidence.SampleLog)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// UpsertRuleEvidences stores the evidences of the rules observed in a discovery run
+func UpsertRuleEvidences(cfg types.ConfigDB, evidences []types.RuleEvidence) error {
+	if len(evidences) == 0 {
+		return nil
+	}
+
+	if cfg.DBDriver == "mysql" {
+		return UpsertRuleEvidencesMySQL(cfg, evidences)
+	} else if cfg.DBDriver == "sqlite3" {
+		return UpsertRuleEvidencesSQLite(cfg, evidences)
+	}
+	return errors.New("no db driver")
+}
+
+func GetRuleEvidences(cfg types.ConfigDB, filter types.RuleEvidence) ([]types.RuleEvidence, error) {
+	if cfg.DBDriver == "mysql" {
+		res, err := GetRuleEvidencesMySQL(cfg, filter)
+		if err != nil {
+			log.Error().Msg(err.Error())
+		}
+		return res, err
+	} else if cfg.DBDriver == "sqlite3" {
+		res, err := GetRuleEvidencesSQLite(cfg, filter)
+		if err != nil {
+			log.Error().Msg(err.Error())
+		}
+		return res, err
+	}
+	return nil, errors.New("no db driver")
+}
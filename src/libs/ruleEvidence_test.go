@@ -0,0 +1,103 @@
+package libs
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeRuleEvidence(t *testing.T) {
+	exist := types.RuleEvidence{
+		RuleKey:   "egress-1a2b3c4d",
+		FirstSeen: 100,
+		LastSeen:  200,
+		Count:     2,
+		SrcPods:   []string{"web-1", "web-2", "web-3", "web-4"},
+		SampleLog: "old",
+	}
+	evidence := types.RuleEvidence{
+		RuleKey:   "egress-1a2b3c4d",
+		FirstSeen: 300,
+		LastSeen:  300,
+		Count:     1,
+		SrcPods:   []string{"web-1", "web-5", "web-6"},
+		DstPods:   []string{"redis-1"},
+		SampleLog: "new",
+	}
+
+	merged := MergeRuleEvidence(exist, evidence)
+	assert.Equal(t, int64(100), merged.FirstSeen)
+	assert.Equal(t, int64(300), merged.LastSeen)
+	assert.Equal(t, int64(3), merged.Count)
+	assert.Equal(t, "new", merged.SampleLog)
+	// the samples are capped
+	assert.Equal(t, []string{"web-1", "web-2", "web-3", "web-4", "web-5"}, merged.SrcPods)
+	assert.Equal(t, []string{"redis-1"}, merged.DstPods)
+	assert.Equal(t, 4, len(exist.SrcPods))
+}
+
+func TestAddRuleEvidence(t *testing.T) {
+	evidences := map[string]types.RuleEvidence{}
+
+	evidence := types.RuleEvidence{PolicyType: "network", Namespace: "default", Labels: "app=web", RuleKey: "egress-1a2b3c4d", Count: 1}
+	AddRuleEvidence(evidences, evidence)
+	AddRuleEvidence(evidences, evidence)
+
+	evidence.Labels = "app=api"
+	AddRuleEvidence(evidences, evidence)
+
+	assert.Equal(t, 2, len(evidences))
+	for _, e := range evidences {
+		if e.Labels == "app=web" {
+			assert.Equal(t, int64(2), e.Count)
+		}
+	}
+}
+
+func TestUpsertRuleEvidences(t *testing.T) {
+	_, mock := NewMock()
+
+	columns := []string{
+		"policy_type", "cluster_name", "namespace", "labels", "rule_key", "rule",
+		"first_seen", "last_seen", "count", "src_pods", "dst_pods", "sample_log",
+	}
+	updateQuery := "^UPDATE rule_evidence SET first_seen=\\?,last_seen=\\?,count=\\?,src_pods=\\?,dst_pods=\\?,sample_log=\\?"
+
+	updatePrep := mock.ExpectPrepare(updateQuery)
+	insertPrep := mock.ExpectPrepare("^INSERT INTO rule_evidence")
+
+	// the stored evidences of the namespace are read at once
+	mock.ExpectQuery("^SELECT (.+) FROM rule_evidence WHERE policy_type = \\? and cluster_name = \\? and namespace = \\?$").
+		WithArgs("network", "default", "default").
+		WillReturnRows(mock.NewRows(columns).
+			AddRow("network", "default", "default", "app=web", "egress-1a2b3c4d", "egress {}", 100, 200, 2,
+				[]byte(`["web-1"]`), []byte(`["redis-1"]`), "old").
+			AddRow("network", "default", "default", "app=api", "egress-5e6f7a8b", "egress {}", 100, 200, 2,
+				[]byte(`["api-1"]`), []byte(`["redis-1"]`), "old"))
+
+	// stored evidence, merged
+	updatePrep.ExpectExec().
+		WithArgs(100, 300, 3, []byte(`["web-1","web-2"]`), []byte(`["redis-1"]`), "new",
+			"network", "default", "default", "app=web", "egress-1a2b3c4d").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// new evidence, inserted
+	insertPrep.ExpectExec().
+		WithArgs("network", "default", "default", "app=web", "egress-5e6f7a8b", "egress {}", 300, 300, 1,
+			[]byte(`["web-2"]`), []byte(`null`), "new").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := UpsertRuleEvidences(types.ConfigDB{DBDriver: "mysql"}, []types.RuleEvidence{
+		{PolicyType: "network", ClusterName: "default", Namespace: "default", Labels: "app=web", RuleKey: "egress-1a2b3c4d",
+			Rule: "egress {}", FirstSeen: 300, LastSeen: 300, Count: 1, SrcPods: []string{"web-2"}, SampleLog: "new"},
+		{PolicyType: "network", ClusterName: "default", Namespace: "default", Labels: "app=web", RuleKey: "egress-5e6f7a8b",
+			Rule: "egress {}", FirstSeen: 300, LastSeen: 300, Count: 1, SrcPods: []string{"web-2"}, SampleLog: "new"},
+	})
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
@@ -0,0 +1,406 @@
+package libs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	SchemaPolicy        = "policy"
+	SchemaObservability = "observability"
+
+	SchemaVersion_TableName = "schema_version"
+)
+
+const (
+	SchemaCommandStatus = "status"
+	SchemaCommandDryRun = "dry-run"
+)
+
+// SchemaCommand set by the command line to print the migrations instead of applying them
+var SchemaCommand string = ""
+
+// schemaMigration an up migration of a schema, with the statements of each driver
+type schemaMigration struct {
+	version     int
+	description string
+	mysql       []string
+	sqlite      []string
+	// data migration run after the statements, in the same transaction
+	migrate func(tx *sql.Tx, driver string) error
+}
+
+// schemaMigrations the ordered migrations applied on top of the tables created by
+// CreateTablesIfNotExist and CreateObsTablesIfNotExist, append only
+var schemaMigrations = map[string][]schemaMigration{
+	SchemaPolicy: {
+		{
+			version:     1,
+			description: "index network policies by cluster, namespace and status",
+			mysql: []string{"CREATE INDEX idx_network_policy_namespace ON " + TableNetworkPolicy_TableName +
+				" (cluster_name, namespace, status)"},
+			sqlite: []string{"CREATE INDEX IF NOT EXISTS idx_network_policy_namespace ON " + TableNetworkPolicySQLite_TableName +
+				" (cluster_name, namespace, status)"},
+		},
+		{
+			version:     2,
+			description: "index system policies by cluster, namespace and status",
+			mysql: []string{"CREATE INDEX idx_system_policy_namespace ON " + TableSystemPolicy_TableName +
+				" (clusterName, namespace, status)"},
+			sqlite: []string{"CREATE INDEX IF NOT EXISTS idx_system_policy_namespace ON " + TableSystemPolicySQLite_TableName +
+				" (clusterName, namespace, status)"},
+		},
+		{
+			version:     3,
+			description: "index WPFS by cluster and namespace",
+			mysql: []string{"CREATE INDEX idx_wpfs_namespace ON " + WorkloadProcessFileSet_TableName +
+				" (clusterName, namespace)"},
+			sqlite: []string{"CREATE INDEX IF NOT EXISTS idx_wpfs_namespace ON " + WorkloadProcessFileSetSQLite_TableName +
+				" (clusterName, namespace)"},
+		},
+		{
+			version:     4,
+			description: "widen network policy names",
+			mysql:       []string{"ALTER TABLE " + TableNetworkPolicy_TableName + " MODIFY `name` varchar(128) DEFAULT NULL"},
+			// sqlite does not enforce the varchar length
+		},
+		{
+			version:     5,
+			description: "rename the policies to deterministic names",
+			mysql: []string{
+				"ALTER TABLE " + TableNetworkPolicy_TableName + " MODIFY `outdated` varchar(128) DEFAULT NULL",
+				"ALTER TABLE " + TableSystemPolicy_TableName + " MODIFY `outdated` varchar(128) DEFAULT NULL",
+			},
+			migrate: migratePolicyNames,
+		},
+		{
+			version:     6,
+			description: "add the review state of the policies",
+			mysql: []string{
+				"ALTER TABLE " + TableNetworkPolicy_TableName + " ADD COLUMN `review_state` varchar(20) DEFAULT '" + ReviewStateDiscovered + "'," +
+					" ADD COLUMN `reviewer` varchar(100) DEFAULT NULL, ADD COLUMN `review_comment` varchar(500) DEFAULT NULL," +
+					" ADD COLUMN `reviewed_time` bigint NOT NULL DEFAULT 0",
+				"ALTER TABLE " + TableSystemPolicy_TableName + " ADD COLUMN `review_state` varchar(20) DEFAULT '" + ReviewStateDiscovered + "'," +
+					" ADD COLUMN `reviewer` varchar(100) DEFAULT NULL, ADD COLUMN `review_comment` varchar(500) DEFAULT NULL," +
+					" ADD COLUMN `reviewed_time` bigint NOT NULL DEFAULT 0",
+				"UPDATE " + TableNetworkPolicy_TableName + " SET review_state='" + ReviewStateSuperseded + "' WHERE status='outdated'",
+				"UPDATE " + TableSystemPolicy_TableName + " SET review_state='" + ReviewStateSuperseded + "' WHERE status='outdated'",
+			},
+			// sqlite adds a single column per statement
+			sqlite: []string{
+				"ALTER TABLE " + TableNetworkPolicySQLite_TableName + " ADD COLUMN `review_state` varchar(20) DEFAULT '" + ReviewStateDiscovered + "'",
+				"ALTER TABLE " + TableNetworkPolicySQLite_TableName + " ADD COLUMN `reviewer` varchar(100) DEFAULT NULL",
+				"ALTER TABLE " + TableNetworkPolicySQLite_TableName + " ADD COLUMN `review_comment` varchar(500) DEFAULT NULL",
+				"ALTER TABLE " + TableNetworkPolicySQLite_TableName + " ADD COLUMN `reviewed_time` bigint NOT NULL DEFAULT 0",
+				"ALTER TABLE " + TableSystemPolicySQLite_TableName + " ADD COLUMN `review_state` varchar(20) DEFAULT '" + ReviewStateDiscovered + "'",
+				"ALTER TABLE " + TableSystemPolicySQLite_TableName + " ADD COLUMN `reviewer` varchar(100) DEFAULT NULL",
+				"ALTER TABLE " + TableSystemPolicySQLite_TableName + " ADD COLUMN `review_comment` varchar(500) DEFAULT NULL",
+				"ALTER TABLE " + TableSystemPolicySQLite_TableName + " ADD COLUMN `reviewed_time` bigint NOT NULL DEFAULT 0",
+				"UPDATE " + TableNetworkPolicySQLite_TableName + " SET review_state='" + ReviewStateSuperseded + "' WHERE status='outdated'",
+				"UPDATE " + TableSystemPolicySQLite_TableName + " SET review_state='" + ReviewStateSuperseded + "' WHERE status='outdated'",
+			},
+		},
+		{
+			version:     7,
+			description: "add the rule statistics of the policies",
+			mysql: []string{
+				"ALTER TABLE " + TableNetworkPolicy_TableName + " ADD COLUMN `rule_stats` JSON DEFAULT NULL",
+				"ALTER TABLE " + TableSystemPolicy_TableName + " ADD COLUMN `rule_stats` JSON DEFAULT NULL",
+			},
+			sqlite: []string{
+				"ALTER TABLE " + TableNetworkPolicySQLite_TableName + " ADD COLUMN `rule_stats` JSON DEFAULT NULL",
+				"ALTER TABLE " + TableSystemPolicySQLite_TableName + " ADD COLUMN `rule_stats` JSON DEFAULT NULL",
+			},
+		},
+	},
+	SchemaObservability: {
+		{
+			version:     1,
+			description: "index system logs by update time and namespace",
+			mysql: []string{
+				"CREATE INDEX idx_system_logs_updated_time ON " + TableSystemLogs_TableName + " (updated_time)",
+				"CREATE INDEX idx_system_logs_namespace ON " + TableSystemLogs_TableName + " (namespace_name, updated_time)",
+			},
+			sqlite: []string{
+				"CREATE INDEX IF NOT EXISTS idx_system_logs_updated_time ON " + TableSystemLogsSQLite_TableName + " (updated_time)",
+				"CREATE INDEX IF NOT EXISTS idx_system_logs_namespace ON " + TableSystemLogsSQLite_TableName + " (namespace_name, updated_time)",
+			},
+		},
+		{
+			version:     2,
+			description: "index network logs by update time and namespace",
+			mysql: []string{
+				"CREATE INDEX idx_network_logs_updated_time ON " + TableNetworkLogs_TableName + " (updated_time)",
+				"CREATE INDEX idx_network_logs_namespace ON " + TableNetworkLogs_TableName + " (source_namespace, updated_time)",
+			},
+			sqlite: []string{
+				"CREATE INDEX IF NOT EXISTS idx_network_logs_updated_time ON " + TableNetworkLogsSQLite_TableName + " (updated_time)",
+				"CREATE INDEX IF NOT EXISTS idx_network_logs_namespace ON " + TableNetworkLogsSQLite_TableName + " (source_namespace, updated_time)",
+			},
+		},
+	},
+}
+
+// ======================= //
+// == Schema Connection == //
+// ======================= //
+
+// connectSchemaDB returns the db of the schema and the func releasing it
+func connectSchemaDB(cfg types.ConfigDB, schema string) (*sql.DB, func(), error) {
+	if schema == SchemaObservability {
+		// the pooled handle is kept open
+		return connectObsDB(cfg), func() {}, nil
+	}
+
+	var db *sql.DB
+	if cfg.DBDriver == "mysql" {
+		db = connectMySQL(cfg)
+	} else if cfg.DBDriver == "sqlite3" {
+		db = connectSQLite(cfg, cfg.SQLiteDBPath)
+	} else {
+		return nil, nil, errors.New("unknown db driver")
+	}
+
+	return db, func() { _ = db.Close() }, nil
+}
+
+func createTableSchemaVersion(db *sql.DB) error {
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + SchemaVersion_TableName + "` (" +
+			"	`schemaName` varchar(50) NOT NULL," + // the policy and observability schemas may share the db
+			"	`version` int NOT NULL," +
+			"	`description` varchar(250) DEFAULT NULL," +
+			"	`appliedTime` bigint NOT NULL," +
+			"	PRIMARY KEY (`schemaName`, `version`)" +
+			"  );"
+
+	_, err := db.Exec(query)
+	return err
+}
+
+// getAppliedSchemaVersions returns the applied time of the applied versions of the schema
+func getAppliedSchemaVersions(db *sql.DB, schema string) (map[int]int64, error) {
+	rows, err := db.Query("SELECT version,appliedTime FROM "+SchemaVersion_TableName+" WHERE schemaName = ?", schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]int64{}
+	for rows.Next() {
+		var version int
+		var appliedTime int64
+		if err := rows.Scan(&version, &appliedTime); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedTime
+	}
+
+	return applied, rows.Err()
+}
+
+func getSchemaMigrationStatements(migration schemaMigration, driver string) []string {
+	if driver == "mysql" {
+		return migration.mysql
+	}
+	return migration.sqlite
+}
+
+func getSchemaMigrations(cfg types.ConfigDB, schema string, applied map[int]int64) []types.SchemaMigration {
+	results := []types.SchemaMigration{}
+
+	for _, migration := range schemaMigrations[schema] {
+		appliedTime, ok := applied[migration.version]
+		results = append(results, types.SchemaMigration{
+			Schema:      schema,
+			Version:     migration.version,
+			Description: migration.description,
+			Statements:  getSchemaMigrationStatements(migration, cfg.DBDriver),
+			Data:        migration.migrate != nil,
+			Applied:     ok,
+			AppliedTime: appliedTime,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Version < results[j].Version
+	})
+
+	return results
+}
+
+func getSchemaDataMigration(schema string, version int) func(tx *sql.Tx, driver string) error {
+	for _, migration := range schemaMigrations[schema] {
+		if migration.version == version {
+			return migration.migrate
+		}
+	}
+	return nil
+}
+
+// ======================= //
+// == Schema Migrations == //
+// ======================= //
+
+// GetSchemaMigrationStatus returns the applied and pending migrations of the schema, nothing is written
+func GetSchemaMigrationStatus(cfg types.ConfigDB, schema string) ([]types.SchemaMigration, error) {
+	db, release, err := connectSchemaDB(cfg, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	applied, err := getAppliedSchemaVersions(db, schema)
+	if err != nil {
+		// the version table is created by the first migration run
+		log.Warn().Msgf("could not read the %s schema version, assuming none applied err=%s", schema, err.Error())
+		applied = map[int]int64{}
+	}
+
+	return getSchemaMigrations(cfg, schema, applied), nil
+}
+
+// applySchemaMigration runs the statements and records the version in one transaction,
+// mysql commits DDL statements implicitly, so a failed mysql migration may be partially applied
+func applySchemaMigration(db *sql.DB, driver string, migration types.SchemaMigration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range migration.Statements {
+		if _, err := tx.Exec(statement); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if migrate := getSchemaDataMigration(migration.Schema, migration.Version); migrate != nil {
+		if err := migrate(tx, driver); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO "+SchemaVersion_TableName+" (schemaName,version,description,appliedTime) VALUES (?,?,?,?)",
+		migration.Schema, migration.Version, migration.Description, ConvertStrToUnixTime("now")); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MigrateSchema applies the pending migrations of the schema in order and returns them,
+// with dryRun the pending migrations are only returned
+func MigrateSchema(cfg types.ConfigDB, schema string, dryRun bool) ([]types.SchemaMigration, error) {
+	if dryRun {
+		migrations, err := GetSchemaMigrationStatus(cfg, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		pending := []types.SchemaMigration{}
+		for _, migration := range migrations {
+			if !migration.Applied {
+				pending = append(pending, migration)
+			}
+		}
+		return pending, nil
+	}
+
+	db, release, err := connectSchemaDB(cfg, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if err := createTableSchemaVersion(db); err != nil {
+		return nil, err
+	}
+
+	applied, err := getAppliedSchemaVersions(db, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []types.SchemaMigration{}
+	for _, migration := range getSchemaMigrations(cfg, schema, applied) {
+		if migration.Applied {
+			continue
+		}
+
+		// stop at the first failure, the later migrations may depend on it
+		if err := applySchemaMigration(db, cfg.DBDriver, migration); err != nil {
+			return results, fmt.Errorf("%s schema migration %d (%s) failed: %s",
+				schema, migration.Version, migration.Description, err.Error())
+		}
+
+		migration.Applied = true
+		results = append(results, migration)
+	}
+
+	return results, nil
+}
+
+func getSchemaConfig(schema string, policyDB, obsDB types.ConfigDB) types.ConfigDB {
+	if schema == SchemaObservability {
+		return obsDB
+	}
+	return policyDB
+}
+
+// MigrateSchemas applies the pending migrations of the policy and observability schemas
+func MigrateSchemas(policyDB, obsDB types.ConfigDB) {
+	for _, schema := range []string{SchemaPolicy, SchemaObservability} {
+		migrations, err := MigrateSchema(getSchemaConfig(schema, policyDB, obsDB), schema, false)
+		for _, migration := range migrations {
+			log.Info().Msgf("applied %s schema migration %d (%s)", schema, migration.Version, migration.Description)
+		}
+		if err != nil {
+			log.Error().Msg(err.Error())
+		}
+	}
+}
+
+// RunSchemaCommand prints the migration status or the pending migrations of the schemas
+func RunSchemaCommand(policyDB, obsDB types.ConfigDB) {
+	for _, schema := range []string{SchemaPolicy, SchemaObservability} {
+		cfg := getSchemaConfig(schema, policyDB, obsDB)
+
+		var migrations []types.SchemaMigration
+		var err error
+		if SchemaCommand == SchemaCommandStatus {
+			migrations, err = GetSchemaMigrationStatus(cfg, schema)
+		} else {
+			migrations, err = MigrateSchema(cfg, schema, true)
+		}
+		if err != nil {
+			fmt.Printf("%s schema: %s\n", schema, err.Error())
+			continue
+		}
+
+		fmt.Printf("%s schema (%s):\n", schema, cfg.DBDriver)
+		for _, migration := range migrations {
+			status := "pending"
+			if migration.Applied {
+				status = "applied at " + time.Unix(migration.AppliedTime, 0).UTC().Format(time.RFC3339)
+			}
+			fmt.Printf("  %d\t%s\t%s\n", migration.Version, migration.Description, status)
+
+			if SchemaCommand == SchemaCommandDryRun {
+				for _, statement := range migration.Statements {
+					fmt.Printf("    %s;\n", statement)
+				}
+				if migration.Data {
+					fmt.Printf("    -- data migration: %s\n", migration.Description)
+				}
+			}
+		}
+	}
+}
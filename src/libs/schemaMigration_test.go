@@ -0,0 +1,148 @@
+package libs
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateSchemaMySQL(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectExec("^CREATE TABLE IF NOT EXISTS `schema_version`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("^SELECT version,appliedTime FROM schema_version WHERE schemaName = \\?").
+		WithArgs(SchemaPolicy).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "appliedTime"}).AddRow(1, 100).AddRow(2, 100).AddRow(3, 100))
+	mock.ExpectBegin()
+	mock.ExpectExec("^ALTER TABLE network_policy MODIFY `name` varchar\\(128\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^INSERT INTO schema_version \\(schemaName,version,description,appliedTime\\) VALUES").
+		WithArgs(SchemaPolicy, 4, "widen network policy names", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("^ALTER TABLE network_policy MODIFY `outdated` varchar\\(128\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^ALTER TABLE system_policy MODIFY `outdated` varchar\\(128\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("^SELECT id,(.+) FROM network_policy ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "cluster_name", "name", "namespace", "type", "outdated", "spec"}))
+	mock.ExpectPrepare("^UPDATE network_policy SET name=\\?,outdated=\\? WHERE id=\\?")
+	mock.ExpectQuery("^SELECT id,(.+) FROM workload_process_fileset ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "clusterName", "policyName", "namespace", "labels", "containerName", "fromSource", "settype"}))
+	mock.ExpectQuery("^SELECT id,(.+) FROM system_policy ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "clusterName", "name", "namespace", "type", "outdated", "spec"}))
+	mock.ExpectPrepare("^UPDATE workload_process_fileset SET policyName=\\? WHERE id=\\?")
+	mock.ExpectPrepare("^UPDATE system_policy SET name=\\?,outdated=\\? WHERE id=\\?")
+	mock.ExpectExec("^INSERT INTO schema_version \\(schemaName,version,description,appliedTime\\) VALUES").
+		WithArgs(SchemaPolicy, 5, "rename the policies to deterministic names", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("^ALTER TABLE network_policy ADD COLUMN `review_state`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^ALTER TABLE system_policy ADD COLUMN `review_state`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^UPDATE network_policy SET review_state='superseded' WHERE status='outdated'").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("^UPDATE system_policy SET review_state='superseded' WHERE status='outdated'").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^INSERT INTO schema_version \\(schemaName,version,description,appliedTime\\) VALUES").
+		WithArgs(SchemaPolicy, 6, "add the review state of the policies", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("^ALTER TABLE network_policy ADD COLUMN `rule_stats` JSON").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^ALTER TABLE system_policy ADD COLUMN `rule_stats` JSON").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^INSERT INTO schema_version \\(schemaName,version,description,appliedTime\\) VALUES").
+		WithArgs(SchemaPolicy, 7, "add the rule statistics of the policies", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	migrations, err := MigrateSchema(types.ConfigDB{DBDriver: "mysql"}, SchemaPolicy, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(migrations))
+	assert.Equal(t, 4, migrations[0].Version)
+	assert.True(t, migrations[0].Applied)
+	assert.True(t, migrations[1].Data)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestMigrateSchemaSQLite(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectExec("^CREATE TABLE IF NOT EXISTS `schema_version`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("^SELECT version,appliedTime FROM schema_version WHERE schemaName = \\?").
+		WithArgs(SchemaObservability).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "appliedTime"}).AddRow(1, 100))
+	mock.ExpectBegin()
+	mock.ExpectExec("^CREATE INDEX IF NOT EXISTS idx_network_logs_updated_time ON network_logs").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^CREATE INDEX IF NOT EXISTS idx_network_logs_namespace ON network_logs").
+		WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	migrations, err := MigrateSchema(types.ConfigDB{DBDriver: "sqlite3"}, SchemaObservability, false)
+	assert.Error(t, err)
+	assert.Empty(t, migrations)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestMigrateSchemaDryRun(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectQuery("^SELECT version,appliedTime FROM schema_version WHERE schemaName = \\?").
+		WithArgs(SchemaPolicy).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "appliedTime"}).AddRow(1, 100).AddRow(2, 100))
+
+	migrations, err := MigrateSchema(types.ConfigDB{DBDriver: "sqlite3"}, SchemaPolicy, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(migrations))
+	assert.Equal(t, 3, migrations[0].Version)
+	assert.False(t, migrations[0].Applied)
+	// sqlite has nothing to run for the widened names, only the version is recorded
+	assert.Empty(t, migrations[1].Statements)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestGetSchemaMigrationStatus(t *testing.T) {
+	_, mock := NewMock()
+
+	mock.ExpectQuery("^SELECT version,appliedTime FROM schema_version WHERE schemaName = \\?").
+		WithArgs(SchemaObservability).
+		WillReturnError(assert.AnError)
+
+	migrations, err := GetSchemaMigrationStatus(types.ConfigDB{DBDriver: "mysql"}, SchemaObservability)
+	assert.NoError(t, err)
+	assert.Equal(t, len(schemaMigrations[SchemaObservability]), len(migrations))
+	for _, migration := range migrations {
+		assert.False(t, migration.Applied)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestSchemaMigrationVersions(t *testing.T) {
+	for schema, migrations := range schemaMigrations {
+		for i, migration := range migrations {
+			assert.Equal(t, i+1, migration.version, "%s schema migrations should be ordered without gaps", schema)
+		}
+	}
+}
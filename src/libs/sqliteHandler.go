@@ -8,17 +8,19 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/accuknox/auto-policy-discovery/src/config"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
 const WorkloadProcessFileSetSQLite_TableName = "workload_process_fileset"
+const WorkloadLearningStateSQLite_TableName = "workload_learning_state"
 const TableNetworkPolicySQLite_TableName = "network_policy"
 const TableSystemPolicySQLite_TableName = "system_policy"
 const TableSystemLogsSQLite_TableName = "system_logs"
 const TableNetworkLogsSQLite_TableName = "network_logs"
+const PolicyChangeSQLite_TableName = "policy_change"
+const RuleEvidenceSQLite_TableName = "rule_evidence"
 
 // ================ //
 // == Connection == //
@@ -73,7 +75,12 @@ func connectSQLite(cfg types.ConfigDB, dbpath string) (db *sql.DB) {
 // == Network Policy == //
 // ==================== //
 
-func GetNetworkPoliciesFromSQLite(cfg types.ConfigDB, cluster, namespace, status string) ([]types.KnoxNetworkPolicy, error) {
+func GetNetworkPoliciesFromSQLite(cfg types.ConfigDB, cluster, namespace, status, nwtype, rule string) ([]types.KnoxNetworkPolicy, error) {
+	return GetNetworkPolicyPageFromSQLite(cfg, cluster, namespace, status, nwtype, rule, types.InsightQuery{})
+}
+
+// GetNetworkPolicyPageFromSQLite returns the network policies of the time range in the requested page
+func GetNetworkPolicyPageFromSQLite(cfg types.ConfigDB, cluster, namespace, status, nwtype, rule string, page types.InsightQuery) ([]types.KnoxNetworkPolicy, error) {
 	db := connectSQLite(cfg, cfg.SQLiteDBPath)
 	defer db.Close()
 
@@ -81,22 +88,38 @@ func GetNetworkPoliciesFromSQLite(cfg types.ConfigDB, cluster, namespace, status
 	var results *sql.Rows
 	var err error
 
-	query := "SELECT apiVersion,kind,flow_ids,name,cluster_name,namespace,type,rule,status,outdated,spec,generatedTime,updatedTime FROM " + TableNetworkPolicySQLite_TableName
-	if cluster != "" && namespace != "" && status != "" {
-		query = query + " WHERE cluster_name = ? and namespace = ? and status = ? "
-		results, err = db.Query(query, cluster, namespace, status)
-	} else if cluster != "" && status != "" {
-		query = query + " WHERE cluster_name = ? and status = ? "
-		results, err = db.Query(query, cluster, status)
-	} else if namespace != "" && status != "" {
-		query = query + " WHERE namespace = ? and status = ? "
-		results, err = db.Query(query, namespace, status)
-	} else if status != "" {
-		query = query + " WHERE status = ? "
-		results, err = db.Query(query, status)
-	} else {
-		results, err = db.Query(query)
+	query := "SELECT apiVersion,kind,flow_ids,name,cluster_name,namespace,type,rule,status,outdated,spec,generatedTime,updatedTime,review_state,rule_stats FROM " + TableNetworkPolicySQLite_TableName
+
+	var whereClause string
+	var args []interface{}
+
+	if cluster != "" {
+		concatWhereClauseSQLite(&whereClause, "cluster_name")
+		args = append(args, cluster)
+	}
+	if namespace != "" {
+		concatWhereClauseSQLite(&whereClause, "namespace")
+		args = append(args, namespace)
 	}
+	if status != "" {
+		concatWhereClauseSQLite(&whereClause, "status")
+		args = append(args, status)
+	}
+	if nwtype != "" {
+		concatWhereClauseSQLite(&whereClause, "type")
+		args = append(args, nwtype)
+	}
+	if rule != "" {
+		concatWhereClauseSQLite(&whereClause, "rule")
+		args = append(args, rule)
+	}
+
+	orderClause, err := concatInsightQueryClause(&whereClause, &args, networkPolicyTimeColumn, networkPolicyKeyColumns, page)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err = db.Query(query+whereClause+orderClause, args...)
 	defer results.Close()
 
 	if err != nil {
@@ -108,6 +131,7 @@ func GetNetworkPoliciesFromSQLite(cfg types.ConfigDB, cluster, namespace, status
 		policy := types.KnoxNetworkPolicy{}
 
 		var name, clusterName, namespace, policyType, rule, status string
+		var reviewState, ruleStats sql.NullString
 		specByte := []byte{}
 		spec := types.Spec{}
 
@@ -128,6 +152,8 @@ func GetNetworkPoliciesFromSQLite(cfg types.ConfigDB, cluster, namespace, status
 			&specByte,
 			&policy.GeneratedTime,
 			&policy.UpdatedTime,
+			&reviewState,
+			&ruleStats,
 		); err != nil {
 			return nil, err
 		}
@@ -147,11 +173,16 @@ func GetNetworkPoliciesFromSQLite(cfg types.ConfigDB, cluster, namespace, status
 			"type":         policyType,
 			"rule":         rule,
 			"status":       status,
+			"review_state": reviewState.String,
 		}
 
 		policy.FlowIDs = flowIDs
 		policy.Spec = spec
 
+		if policy.RuleStats, err = unmarshalRuleStats(ruleStats); err != nil {
+			return nil, err
+		}
+
 		policies = append(policies, policy)
 	}
 
@@ -163,7 +194,7 @@ func UpdateNetworkPolicyToSQLite(cfg types.ConfigDB, policy types.KnoxNetworkPol
 	defer db.Close()
 
 	stmt, err := db.Prepare("UPDATE " + TableNetworkPolicySQLite_TableName +
-		" SET apiVersion=?,kind=?,cluster_name=?,namespace=?,type=?,status=?,outdated=?,spec=?,updatedTime=? WHERE name = ?")
+		" SET apiVersion=?,kind=?,cluster_name=?,namespace=?,type=?,status=?,outdated=?,spec=?,updatedTime=?,review_state=?,rule_stats=? WHERE name = ? and cluster_name = ?")
 	if err != nil {
 		return err
 	}
@@ -175,6 +206,11 @@ func UpdateNetworkPolicyToSQLite(cfg types.ConfigDB, policy types.KnoxNetworkPol
 		return err
 	}
 
+	ruleStats, err := marshalRuleStats(policy.RuleStats)
+	if err != nil {
+		return err
+	}
+
 	_, err = stmt.Exec(
 		policy.APIVersion,
 		policy.Kind,
@@ -185,7 +221,10 @@ func UpdateNetworkPolicyToSQLite(cfg types.ConfigDB, policy types.KnoxNetworkPol
 		policy.Outdated,
 		spec,
 		ConvertStrToUnixTime("now"),
-		policy.Metadata["name"])
+		GetReviewState(policy.Metadata),
+		ruleStats,
+		policy.Metadata["name"],
+		policy.Metadata["cluster_name"])
 	if err != nil {
 		return err
 	}
@@ -193,20 +232,20 @@ func UpdateNetworkPolicyToSQLite(cfg types.ConfigDB, policy types.KnoxNetworkPol
 	return nil
 }
 
-func UpdateOutdatedNetworkPolicyFromSQLite(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string) error {
+func UpdateOutdatedNetworkPolicyFromSQLite(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string, reviewState string) error {
 	db := connectSQLite(cfg, cfg.SQLiteDBPath)
 	defer db.Close()
 
 	var err error
 
-	// set status -> outdated
-	stmt1, err := db.Prepare("UPDATE " + TableNetworkPolicySQLite_TableName + " SET status=? WHERE name=?")
+	// set status -> outdated, review state -> superseded or kept
+	stmt1, err := db.Prepare("UPDATE " + TableNetworkPolicySQLite_TableName + " SET status=?,review_state=? WHERE name=?")
 	if err != nil {
 		return err
 	}
 	defer stmt1.Close()
 
-	_, err = stmt1.Exec("outdated", outdatedPolicy)
+	_, err = stmt1.Exec("outdated", reviewState, outdatedPolicy)
 	if err != nil {
 		return err
 	}
@@ -227,7 +266,7 @@ func UpdateOutdatedNetworkPolicyFromSQLite(cfg types.ConfigDB, outdatedPolicy st
 }
 
 func insertNetworkPolicySQLite(cfg types.ConfigDB, db *sql.DB, policy types.KnoxNetworkPolicy) error {
-	stmt, err := db.Prepare("INSERT INTO " + TableNetworkPolicySQLite_TableName + "(apiVersion,kind,flow_ids,name,cluster_name,namespace,type,rule,status,outdated,spec,generatedTime,updatedTime) values(?,?,?,?,?,?,?,?,?,?,?,?,?)")
+	stmt, err := db.Prepare("INSERT INTO " + TableNetworkPolicySQLite_TableName + "(apiVersion,kind,flow_ids,name,cluster_name,namespace,type,rule,status,outdated,spec,generatedTime,updatedTime,rule_stats) values(?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
 	if err != nil {
 		return err
 	}
@@ -245,6 +284,11 @@ func insertNetworkPolicySQLite(cfg types.ConfigDB, db *sql.DB, policy types.Knox
 		return err
 	}
 
+	ruleStats, err := marshalRuleStats(policy.RuleStats)
+	if err != nil {
+		return err
+	}
+
 	currTime := ConvertStrToUnixTime("now")
 
 	_, err = stmt.Exec(policy.APIVersion,
@@ -259,7 +303,8 @@ func insertNetworkPolicySQLite(cfg types.ConfigDB, db *sql.DB, policy types.Knox
 		policy.Outdated,
 		spec,
 		currTime,
-		currTime)
+		currTime,
+		ruleStats)
 	if err != nil {
 		return err
 	}
@@ -280,30 +325,50 @@ func InsertNetworkPoliciesToSQLite(cfg types.ConfigDB, policies []types.KnoxNetw
 	return nil
 }
 
+func UpdatePolicyReviewToSQLite(cfg types.ConfigDB, review *types.PolicyReview) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	if review.PolicyType == "system" {
+		return updatePolicyReview(db, TableSystemPolicySQLite_TableName, "clusterName", review)
+	}
+	return updatePolicyReview(db, TableNetworkPolicySQLite_TableName, "cluster_name", review)
+}
+
+func UpdateRuleStatsToSQLite(cfg types.ConfigDB, policyType, clusterName, name string, stats map[string]types.RuleStat) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	if policyType == "system" {
+		return updateRuleStats(db, TableSystemPolicySQLite_TableName, "clusterName", clusterName, name, stats)
+	}
+	return updateRuleStats(db, TableNetworkPolicySQLite_TableName, "cluster_name", clusterName, name, stats)
+}
+
 // =================== //
 // == System Policy == //
 // =================== //
 
-func UpdateOutdatedSystemPolicyFromSQLite(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string) error {
+func UpdateOutdatedSystemPolicyFromSQLite(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string, reviewState string) error {
 	db := connectSQLite(cfg, cfg.SQLiteDBPath)
 	defer db.Close()
 
 	var err error
 
-	// set status -> outdated
-	stmt1, err := db.Prepare("UPDATE " + TableSystemPolicySQLite_TableName + " SET status=? WHERE name=?")
+	// set status -> outdated, review state -> superseded or kept
+	stmt1, err := db.Prepare("UPDATE " + TableSystemPolicySQLite_TableName + " SET status=?,review_state=? WHERE name=?")
 	if err != nil {
 		return err
 	}
 	defer stmt1.Close()
 
-	_, err = stmt1.Exec("outdated", outdatedPolicy)
+	_, err = stmt1.Exec("outdated", reviewState, outdatedPolicy)
 	if err != nil {
 		return err
 	}
 
 	// set outdated -> latest' name
-	stmt2, err := db.Prepare("UPDATE " + TableNetworkPolicySQLite_TableName + " SET outdated=? WHERE name=?")
+	stmt2, err := db.Prepare("UPDATE " + TableSystemPolicySQLite_TableName + " SET outdated=? WHERE name=?")
 	if err != nil {
 		return err
 	}
@@ -325,7 +390,7 @@ func GetSystemPoliciesFromSQLite(cfg types.ConfigDB, namespace, status string) (
 	var results *sql.Rows
 	var err error
 
-	query := "SELECT apiVersion,kind,name,clusterName,namespace,type,status,outdated,spec,generatedTime,updatedTime,latest FROM " + TableSystemPolicySQLite_TableName
+	query := "SELECT apiVersion,kind,name,clusterName,namespace,type,status,outdated,spec,generatedTime,updatedTime,latest,review_state,rule_stats FROM " + TableSystemPolicySQLite_TableName
 
 	if namespace != "" && status != "" {
 		query = query + " WHERE namespace = ? and status = ? "
@@ -350,6 +415,7 @@ func GetSystemPoliciesFromSQLite(cfg types.ConfigDB, namespace, status string) (
 		policy := types.KnoxSystemPolicy{}
 
 		var name, clusterName, namespace, policyType, status string
+		var reviewState, ruleStats sql.NullString
 		specByte := []byte{}
 		spec := types.KnoxSystemSpec{}
 
@@ -366,6 +432,8 @@ func GetSystemPoliciesFromSQLite(cfg types.ConfigDB, namespace, status string) (
 			&policy.GeneratedTime,
 			&policy.UpdatedTime,
 			&policy.Latest,
+			&reviewState,
+			&ruleStats,
 		); err != nil {
 			return nil, err
 		}
@@ -375,15 +443,20 @@ func GetSystemPoliciesFromSQLite(cfg types.ConfigDB, namespace, status string) (
 		}
 
 		policy.Metadata = map[string]string{
-			"name":        name,
-			"clusterName": clusterName,
-			"namespace":   namespace,
-			"type":        policyType,
-			"status":      status,
+			"name":         name,
+			"clusterName":  clusterName,
+			"namespace":    namespace,
+			"type":         policyType,
+			"status":       status,
+			"review_state": reviewState.String,
 		}
 
 		policy.Spec = spec
 
+		if policy.RuleStats, err = unmarshalRuleStats(ruleStats); err != nil {
+			return nil, err
+		}
+
 		policies = append(policies, policy)
 	}
 
@@ -391,7 +464,7 @@ func GetSystemPoliciesFromSQLite(cfg types.ConfigDB, namespace, status string) (
 }
 
 func insertSystemPolicySQLite(cfg types.ConfigDB, db *sql.DB, policy types.KnoxSystemPolicy) error {
-	stmt, err := db.Prepare("INSERT INTO " + TableSystemPolicySQLite_TableName + "(apiVersion,kind,name,clusterName,namespace,type,status,outdated,spec,generatedTime,updatedTime,latest) values(?,?,?,?,?,?,?,?,?,?,?,?)")
+	stmt, err := db.Prepare("INSERT INTO " + TableSystemPolicySQLite_TableName + "(apiVersion,kind,name,clusterName,namespace,type,status,outdated,spec,generatedTime,updatedTime,latest,rule_stats) values(?,?,?,?,?,?,?,?,?,?,?,?,?)")
 	if err != nil {
 		return err
 	}
@@ -403,6 +476,11 @@ func insertSystemPolicySQLite(cfg types.ConfigDB, db *sql.DB, policy types.KnoxS
 		return err
 	}
 
+	ruleStats, err := marshalRuleStats(policy.RuleStats)
+	if err != nil {
+		return err
+	}
+
 	_, err = stmt.Exec(
 		policy.APIVersion,
 		policy.Kind,
@@ -415,7 +493,8 @@ func insertSystemPolicySQLite(cfg types.ConfigDB, db *sql.DB, policy types.KnoxS
 		spec,
 		ConvertStrToUnixTime("now"),
 		ConvertStrToUnixTime("now"),
-		true)
+		true,
+		ruleStats)
 	if err != nil {
 		return err
 	}
@@ -442,7 +521,7 @@ func UpdateSystemPolicyToSQLite(cfg types.ConfigDB, policy types.KnoxSystemPolic
 
 	// set status -> outdated
 	stmt, err := db.Prepare("UPDATE " + TableSystemPolicySQLite_TableName +
-		" SET apiVersion=?,kind=?,clusterName=?,namespace=?,type=?,status=?,outdated=?,spec=?,updatedTime=?,latest=? WHERE name = ?")
+		" SET apiVersion=?,kind=?,clusterName=?,namespace=?,type=?,status=?,outdated=?,spec=?,updatedTime=?,latest=?,review_state=?,rule_stats=? WHERE name = ? and clusterName = ?")
 	if err != nil {
 		return err
 	}
@@ -454,6 +533,11 @@ func UpdateSystemPolicyToSQLite(cfg types.ConfigDB, policy types.KnoxSystemPolic
 		return err
 	}
 
+	ruleStats, err := marshalRuleStats(policy.RuleStats)
+	if err != nil {
+		return err
+	}
+
 	_, err = stmt.Exec(
 		policy.APIVersion,
 		policy.Kind,
@@ -465,7 +549,10 @@ func UpdateSystemPolicyToSQLite(cfg types.ConfigDB, policy types.KnoxSystemPolic
 		spec,
 		ConvertStrToUnixTime("now"),
 		true,
-		policy.Metadata["name"])
+		GetReviewState(policy.Metadata),
+		ruleStats,
+		policy.Metadata["name"],
+		policy.Metadata["clusterName"])
 	if err != nil {
 		return err
 	}
@@ -496,6 +583,21 @@ func ClearDBTablesSQLite(cfg types.ConfigDB) error {
 		return err
 	}
 
+	query = "DELETE FROM " + WorkloadLearningStateSQLite_TableName
+	if _, err := db.Query(query); err != nil {
+		return err
+	}
+
+	query = "DELETE FROM " + PolicyChangeSQLite_TableName
+	if _, err := db.Query(query); err != nil {
+		return err
+	}
+
+	query = "DELETE FROM " + RuleEvidenceSQLite_TableName
+	if _, err := db.Query(query); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -588,10 +690,86 @@ func CreateTableWorkLoadProcessFileSetSQLite(cfg types.ConfigDB) error {
 	return err
 }
 
-func CreateTableSystemLogsSQLite(cfg types.ConfigDB) error {
-	db := connectSQLite(cfg, config.GetCfgObservabilityDBName())
+func CreateTableWorkloadLearningStateSQLite(cfg types.ConfigDB) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
 	defer db.Close()
 
+	tableName := WorkloadLearningStateSQLite_TableName
+
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + tableName + "` (" +
+			"	`id` INTEGER AUTO_INCREMENT," +
+			"	`clusterName` varchar(50) DEFAULT NULL," +
+			"	`namespace` varchar(50) DEFAULT NULL," +
+			"	`containerName` varchar(100) NOT NULL," +
+			"	`labels` varchar(1000) DEFAULT NULL," +
+			"	`state` varchar(20) DEFAULT NULL," + // state: "learning", "stable" or "enforced-candidate"
+			"	`stableIntervals` int NOT NULL," +
+			"	`firstSeenTime` bigint NOT NULL," +
+			"	`lastChangeTime` bigint NOT NULL," +
+			"	`updatedTime` bigint NOT NULL," +
+			"	PRIMARY KEY (`id`)" +
+			"  );"
+
+	_, err := db.Exec(query)
+	return err
+}
+
+func CreateTablePolicyChangeSQLite(cfg types.ConfigDB) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	tableName := PolicyChangeSQLite_TableName
+
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + tableName + "` (" +
+			"	`id` INTEGER AUTO_INCREMENT," +
+			"	`policy_type` varchar(10) DEFAULT NULL," + // policy_type: "network" or "system"
+			"	`cluster_name` varchar(50) DEFAULT NULL," +
+			"	`namespace` varchar(50) DEFAULT NULL," +
+			"	`name` varchar(128) DEFAULT NULL," +
+			"	`new_policy_name` varchar(128) DEFAULT NULL," +
+			"	`reason` varchar(20) DEFAULT NULL," +
+			"	`removed_rules` JSON DEFAULT NULL," +
+			"	`changed_time` bigint NOT NULL," +
+			"	PRIMARY KEY (`id`)" +
+			"  );"
+
+	_, err := db.Exec(query)
+	return err
+}
+
+func CreateTableRuleEvidenceSQLite(cfg types.ConfigDB) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	tableName := RuleEvidenceSQLite_TableName
+
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + tableName + "` (" +
+			"	`id` INTEGER AUTO_INCREMENT," +
+			"	`policy_type` varchar(10) DEFAULT NULL," + // policy_type: "network" or "system"
+			"	`cluster_name` varchar(50) DEFAULT NULL," +
+			"	`namespace` varchar(50) DEFAULT NULL," +
+			"	`labels` varchar(1000) DEFAULT NULL," +
+			"	`rule_key` varchar(1000) DEFAULT NULL," +
+			"	`rule` text DEFAULT NULL," +
+			"	`first_seen` bigint NOT NULL," +
+			"	`last_seen` bigint NOT NULL," +
+			"	`count` bigint NOT NULL," +
+			"	`src_pods` JSON DEFAULT NULL," +
+			"	`dst_pods` JSON DEFAULT NULL," +
+			"	`sample_log` text DEFAULT NULL," +
+			"	PRIMARY KEY (`id`)" +
+			"  );"
+
+	_, err := db.Exec(query)
+	return err
+}
+
+func CreateTableSystemLogsSQLite(cfg types.ConfigDB) error {
+	db := connectObsDB(cfg)
+
 	tableName := TableSystemLogsSQLite_TableName
 
 	query :=
@@ -624,8 +802,7 @@ func CreateTableSystemLogsSQLite(cfg types.ConfigDB) error {
 }
 
 func CreateTableNetworkLogsSQLite(cfg types.ConfigDB) error {
-	db := connectSQLite(cfg, config.GetCfgObservabilityDBName())
-	defer db.Close()
+	db := connectObsDB(cfg)
 
 	tableName := TableNetworkLogsSQLite_TableName
 
@@ -700,7 +877,73 @@ func concatWhereClauseIntRangeSQLite(whereClause *string, field string, start in
 }
 
 // GetWorkloadProcessFileSetMySQL Handle File Sets in context to a given fromSource
+// GetWorkloadPageSQLite returns the workloads (cluster, namespace, labels and container) of the sets updated in the
+// time range in the requested page
+func GetWorkloadPageSQLite(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, page types.InsightQuery) ([]types.WorkloadProcessFileSet, error) {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	query := "SELECT DISTINCT clusterName,namespace,labels,containerName FROM " + WorkloadProcessFileSetSQLite_TableName
+
+	var whereClause string
+	var args []interface{}
+
+	if wpfs.ClusterName != "" {
+		concatWhereClauseSQLite(&whereClause, "clusterName")
+		args = append(args, wpfs.ClusterName)
+	}
+	if wpfs.Namespace != "" {
+		concatWhereClauseSQLite(&whereClause, "namespace")
+		args = append(args, wpfs.Namespace)
+	}
+	if wpfs.ContainerName != "" {
+		concatWhereClauseSQLite(&whereClause, "containerName")
+		args = append(args, wpfs.ContainerName)
+	}
+	if wpfs.Labels != "" {
+		concatWhereClauseSQLite(&whereClause, "labels")
+		args = append(args, wpfs.Labels)
+	}
+	if wpfs.FromSource != "" {
+		concatWhereClauseSQLite(&whereClause, "fromSource")
+		args = append(args, wpfs.FromSource)
+	}
+
+	orderClause, err := concatInsightQueryClause(&whereClause, &args, wpfsTimeColumn, wpfsKeyColumns, page)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := db.Query(query+whereClause+orderClause, args...)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return nil, err
+	}
+	defer results.Close()
+
+	workloads := []types.WorkloadProcessFileSet{}
+	for results.Next() {
+		var workload types.WorkloadProcessFileSet
+		if err := results.Scan(
+			&workload.ClusterName,
+			&workload.Namespace,
+			&workload.Labels,
+			&workload.ContainerName,
+		); err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, workload)
+	}
+
+	return workloads, nil
+}
+
 func GetWorkloadProcessFileSetSQLite(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet) (map[types.WorkloadProcessFileSet][]string, types.PolicyNameMap, error) {
+	return GetWorkloadProcessFileSetInRangeSQLite(cfg, wpfs, 0, 0)
+}
+
+// GetWorkloadProcessFileSetInRangeSQLite returns the sets of the workloads updated in the time range
+func GetWorkloadProcessFileSetInRangeSQLite(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, since, until int64) (map[types.WorkloadProcessFileSet][]string, types.PolicyNameMap, error) {
 	db := connectSQLite(cfg, cfg.SQLiteDBPath)
 	defer db.Close()
 
@@ -737,7 +980,12 @@ func GetWorkloadProcessFileSetSQLite(cfg types.ConfigDB, wpfs types.WorkloadProc
 		args = append(args, wpfs.SetType)
 	}
 
-	results, err = db.Query(query+whereClause, args...)
+	orderClause, err := concatInsightQueryClause(&whereClause, &args, wpfsTimeColumn, wpfsKeyColumns, types.InsightQuery{Since: since, Until: until})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, err = db.Query(query+whereClause+orderClause, args...)
 
 	if err != nil {
 		log.Error().Msg(err.Error())
@@ -777,7 +1025,7 @@ func GetWorkloadProcessFileSetSQLite(cfg types.ConfigDB, wpfs types.WorkloadProc
 func InsertWorkloadProcessFileSetSQLite(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, fs []string) error {
 	db := connectSQLite(cfg, cfg.SQLiteDBPath)
 	defer db.Close()
-	policyName := "autopol-" + strings.ToLower(wpfs.SetType) + "-" + RandSeq(15)
+	policyName := getWPFSPolicyName(wpfs)
 	time := ConvertStrToUnixTime("now")
 
 	stmt, err := db.Prepare("INSERT INTO " + WorkloadProcessFileSetSQLite_TableName +
@@ -882,20 +1130,163 @@ func UpdateWorkloadProcessFileSetSQLite(cfg types.ConfigDB, wpfs types.WorkloadP
 	return err
 }
 
-// UpdateOrInsertKubearmorLogsSQLite -- Update existing log or insert a new log into DB
-func UpdateOrInsertKubearmorLogsSQLite(cfg types.ConfigDB, kubearmorlogs []types.KubeArmorLog) error {
-	db := connectSQLite(cfg, config.GetCfgObservabilityDBName())
+// GetWorkloadLearningStatesSQLite fetch the learning states of the workloads matching the given filter
+func GetWorkloadLearningStatesSQLite(cfg types.ConfigDB, filter types.WorkloadLearningState) ([]types.WorkloadLearningState, error) {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	query := "SELECT clusterName,namespace,containerName,labels,state,stableIntervals,firstSeenTime,lastChangeTime,updatedTime FROM " + WorkloadLearningStateSQLite_TableName
+
+	var whereClause string
+	var args []interface{}
+
+	if filter.ClusterName != "" {
+		concatWhereClauseSQLite(&whereClause, "clusterName")
+		args = append(args, filter.ClusterName)
+	}
+	if filter.Namespace != "" {
+		concatWhereClauseSQLite(&whereClause, "namespace")
+		args = append(args, filter.Namespace)
+	}
+	if filter.ContainerName != "" {
+		concatWhereClauseSQLite(&whereClause, "containerName")
+		args = append(args, filter.ContainerName)
+	}
+	if filter.Labels != "" {
+		concatWhereClauseSQLite(&whereClause, "labels")
+		args = append(args, filter.Labels)
+	}
+	if filter.State != "" {
+		concatWhereClauseSQLite(&whereClause, "state")
+		args = append(args, filter.State)
+	}
+
+	results, err := db.Query(query+whereClause, args...)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return nil, err
+	}
+	defer results.Close()
+
+	states := []types.WorkloadLearningState{}
+
+	for results.Next() {
+		var state types.WorkloadLearningState
+
+		if err := results.Scan(
+			&state.ClusterName,
+			&state.Namespace,
+			&state.ContainerName,
+			&state.Labels,
+			&state.State,
+			&state.StableIntervals,
+			&state.FirstSeenTime,
+			&state.LastChangeTime,
+			&state.UpdatedTime,
+		); err != nil {
+			return nil, err
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func InsertWorkloadLearningStateSQLite(cfg types.ConfigDB, state types.WorkloadLearningState) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	stmt, err := db.Prepare("INSERT INTO " + WorkloadLearningStateSQLite_TableName +
+		"(clusterName,namespace,containerName,labels,state,stableIntervals,firstSeenTime,lastChangeTime,updatedTime) values(?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(
+		state.ClusterName,
+		state.Namespace,
+		state.ContainerName,
+		state.Labels,
+		state.State,
+		state.StableIntervals,
+		state.FirstSeenTime,
+		state.LastChangeTime,
+		state.UpdatedTime)
+	return err
+}
+
+func UpdateWorkloadLearningStateSQLite(cfg types.ConfigDB, state types.WorkloadLearningState) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	stmt, err := db.Prepare("UPDATE " + WorkloadLearningStateSQLite_TableName +
+		" SET state=?,stableIntervals=?,lastChangeTime=?,updatedTime=? WHERE clusterName = ? and containerName = ? and namespace = ? and labels = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(
+		state.State,
+		state.StableIntervals,
+		state.LastChangeTime,
+		state.UpdatedTime,
+		state.ClusterName,
+		state.ContainerName,
+		state.Namespace,
+		state.Labels)
+	return err
+}
+
+func InsertPolicyChangeSQLite(cfg types.ConfigDB, change types.PolicyChange) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return insertPolicyChange(db, PolicyChangeSQLite_TableName, change)
+}
+
+// GetPolicyChangesSQLite fetch the change records of the policies matching the given filter, the latest first
+func GetPolicyChangesSQLite(cfg types.ConfigDB, filter types.PolicyChange) ([]types.PolicyChange, error) {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return getPolicyChanges(db, PolicyChangeSQLite_TableName, filter)
+}
+
+// UpsertRuleEvidencesSQLite merge the evidences of the observed rules into the stored ones
+func UpsertRuleEvidencesSQLite(cfg types.ConfigDB, evidences []types.RuleEvidence) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
 	defer db.Close()
 
+	return upsertRuleEvidences(db, RuleEvidenceSQLite_TableName, evidences)
+}
+
+// GetRuleEvidencesSQLite fetch the evidences of the rules matching the given filter
+func GetRuleEvidencesSQLite(cfg types.ConfigDB, filter types.RuleEvidence) ([]types.RuleEvidence, error) {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return getRuleEvidences(db, RuleEvidenceSQLite_TableName, filter)
+}
+
+// UpdateOrInsertKubearmorLogsSQLite -- Update existing log or insert a new log into DB, returns the inserted logs
+func UpdateOrInsertKubearmorLogsSQLite(cfg types.ConfigDB, kubearmorlogs []types.KubeArmorLog) ([]types.KubeArmorLog, error) {
+	db := connectObsDB(cfg)
+
+	inserted := []types.KubeArmorLog{}
 	for _, kubearmorlog := range kubearmorlogs {
-		if err := updateOrInsertKubearmorLogsSQLite(db, kubearmorlog); err != nil {
+		if isNew, err := updateOrInsertKubearmorLogsSQLite(db, kubearmorlog); err != nil {
 			log.Error().Msg(err.Error())
+		} else if isNew {
+			inserted = append(inserted, kubearmorlog)
 		}
 	}
-	return nil
+	return inserted, nil
 }
 
-func updateOrInsertKubearmorLogsSQLite(db *sql.DB, kubearmorlog types.KubeArmorLog) error {
+func updateOrInsertKubearmorLogsSQLite(db *sql.DB, kubearmorlog types.KubeArmorLog) (bool, error) {
 	queryString := `cluster_name = ? and host_name = ? and namespace_name = ? and pod_name = ? and container_id = ? and 
 					container_name = ? and uid = ? and type = ? and source = ? and operation = ? and resource = ? and 
 					labels = ? and data = ? and category = ? and action = ? and result = ? `
@@ -904,7 +1295,7 @@ func updateOrInsertKubearmorLogsSQLite(db *sql.DB, kubearmorlog types.KubeArmorL
 
 	updateStmt, err := db.Prepare(query)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer updateStmt.Close()
 
@@ -929,7 +1320,7 @@ func updateOrInsertKubearmorLogsSQLite(db *sql.DB, kubearmorlog types.KubeArmorL
 	)
 	if err != nil {
 		log.Error().Msg(err.Error())
-		return err
+		return false, err
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -944,7 +1335,7 @@ func updateOrInsertKubearmorLogsSQLite(db *sql.DB, kubearmorlog types.KubeArmorL
 
 		insertStmt, err := db.Prepare(updateQuery)
 		if err != nil {
-			return err
+			return false, err
 		}
 		defer insertStmt.Close()
 
@@ -970,17 +1361,17 @@ func updateOrInsertKubearmorLogsSQLite(db *sql.DB, kubearmorlog types.KubeArmorL
 			1)
 		if err != nil {
 			log.Error().Msg(err.Error())
-			return err
+			return false, err
 		}
+		return true, nil
 	}
 
-	return nil
+	return false, nil
 }
 
 // GetSystemLogsMySQL
-func GetSystemLogsSQLite(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]types.KubeArmorLog, []uint32, error) {
-	db := connectSQLite(cfg, config.GetCfgObservabilityDBName())
-	defer db.Close()
+func GetSystemLogsSQLite(cfg types.ConfigDB, filterLog types.KubeArmorLog, logQuery types.LogQuery) ([]types.KubeArmorLog, []uint32, string, error) {
+	db := connectObsDB(cfg)
 
 	resLog := []types.KubeArmorLog{}
 	resTotal := []uint32{}
@@ -991,7 +1382,7 @@ func GetSystemLogsSQLite(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]ty
 	queryString := `cluster_name,host_name,namespace_name,pod_name,container_id,container_name,
 		uid,type,source,operation,resource,labels,data,category,action,start_time,updated_time,result,total`
 
-	query := "SELECT " + queryString + " FROM " + TableSystemLogsSQLite_TableName + " "
+	query := "SELECT rowid," + queryString + " FROM " + TableSystemLogsSQLite_TableName + " "
 
 	var whereClause string
 	var args []interface{}
@@ -1069,17 +1460,24 @@ func GetSystemLogsSQLite(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]ty
 		args = append(args, filterLog.Result)
 	}
 
-	results, err = db.Query(query+whereClause, args...)
+	orderClause, err := concatLogQueryClause(&whereClause, &args, "rowid", logQuery)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	results, err = db.Query(query+whereClause+orderClause, args...)
 	if err != nil {
 		log.Error().Msg(err.Error())
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 	defer results.Close()
 
+	var loc_id int64
 	for results.Next() {
 		var loc_log types.KubeArmorLog
 		var loc_total uint32
 		if err := results.Scan(
+			&loc_id,
 			&loc_log.ClusterName,
 			&loc_log.HostName,
 			&loc_log.NamespaceName,
@@ -1100,19 +1498,23 @@ func GetSystemLogsSQLite(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]ty
 			&loc_log.Result,
 			&loc_total,
 		); err != nil {
-			return nil, nil, err
+			return nil, nil, "", err
 		}
 		resLog = append(resLog, loc_log)
 		resTotal = append(resTotal, loc_total)
 	}
 
-	return resLog, resTotal, err
+	nextPageToken := ""
+	if len(resLog) > 0 {
+		nextPageToken = getNextLogPageToken(logQuery, len(resLog), resLog[len(resLog)-1].UpdatedTime, int64(resTotal[len(resTotal)-1]), loc_id)
+	}
+
+	return resLog, resTotal, nextPageToken, err
 }
 
 // GetNetworkLogsMySQL
-func GetCiliumLogsSQLite(cfg types.ConfigDB, filterLog types.CiliumLog) ([]types.CiliumLog, []uint32, error) {
-	db := connectSQLite(cfg, config.GetCfgObservabilityDBName())
-	defer db.Close()
+func GetCiliumLogsSQLite(cfg types.ConfigDB, filterLog types.CiliumLog, logQuery types.LogQuery) ([]types.CiliumLog, []uint32, string, error) {
+	db := connectObsDB(cfg)
 
 	resLog := []types.CiliumLog{}
 	resTotal := []uint32{}
@@ -1127,7 +1529,7 @@ func GetCiliumLogsSQLite(cfg types.ConfigDB, filterLog types.CiliumLog) ([]types
 	event_type_type,event_type_sub_type,source_service_name,source_service_namespace,destination_service_name,destination_service_namespace,
 	traffic_direction,trace_observation_point,drop_reason_desc,is_reply,start_time,updated_time,total`
 
-	query := "SELECT " + queryString + " FROM " + TableNetworkLogsSQLite_TableName + " "
+	query := "SELECT rowid," + queryString + " FROM " + TableNetworkLogsSQLite_TableName + " "
 
 	var whereClause string
 	var args []interface{}
@@ -1297,18 +1699,25 @@ func GetCiliumLogsSQLite(cfg types.ConfigDB, filterLog types.CiliumLog) ([]types
 		args = append(args, filterLog.Total)
 	}
 
-	results, err = db.Query(query+whereClause, args...)
+	orderClause, err := concatLogQueryClause(&whereClause, &args, "rowid", logQuery)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	results, err = db.Query(query+whereClause+orderClause, args...)
 
 	if err != nil {
 		log.Error().Msg(err.Error())
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 	defer results.Close()
 
+	var loc_id int64
 	for results.Next() {
 		var loc_log types.CiliumLog
 		var loc_total uint32
 		if err := results.Scan(
+			&loc_id,
 			&loc_log.Verdict,
 			&loc_log.IpSource,
 			&loc_log.IpDestination,
@@ -1352,29 +1761,36 @@ func GetCiliumLogsSQLite(cfg types.ConfigDB, filterLog types.CiliumLog) ([]types
 			&loc_log.UpdatedTime,
 			&loc_total,
 		); err != nil {
-			return nil, nil, err
+			return nil, nil, "", err
 		}
 		resLog = append(resLog, loc_log)
 		resTotal = append(resTotal, loc_total)
 	}
-	return resLog, resTotal, err
+	nextPageToken := ""
+	if len(resLog) > 0 {
+		nextPageToken = getNextLogPageToken(logQuery, len(resLog), resLog[len(resLog)-1].UpdatedTime, int64(resTotal[len(resTotal)-1]), loc_id)
+	}
+
+	return resLog, resTotal, nextPageToken, err
 }
 
-// UpdateCiliumLogsMySQL -- Update existing log with time and count
-func UpdateOrInsertCiliumLogsSQLite(cfg types.ConfigDB, ciliumlogs []types.CiliumLog) error {
+// UpdateOrInsertCiliumLogsSQLite -- Update existing log with time and count or insert a new log, returns the inserted logs
+func UpdateOrInsertCiliumLogsSQLite(cfg types.ConfigDB, ciliumlogs []types.CiliumLog) ([]types.CiliumLog, error) {
 	var err error = nil
-	db := connectSQLite(cfg, config.GetCfgObservabilityDBName())
-	defer db.Close()
+	db := connectObsDB(cfg)
 
+	inserted := []types.CiliumLog{}
 	for _, ciliumLog := range ciliumlogs {
-		if err := updateOrInsertCiliumLogSQLite(db, ciliumLog); err != nil {
+		if isNew, err := updateOrInsertCiliumLogSQLite(db, ciliumLog); err != nil {
 			log.Error().Msg(err.Error())
+		} else if isNew {
+			inserted = append(inserted, ciliumLog)
 		}
 	}
-	return err
+	return inserted, err
 }
 
-func updateOrInsertCiliumLogSQLite(db *sql.DB, ciliumlog types.CiliumLog) error {
+func updateOrInsertCiliumLogSQLite(db *sql.DB, ciliumlog types.CiliumLog) (bool, error) {
 	var err error
 	queryString := `verdict = ? and ip_source = ? and ip_destination = ? and ip_version = ? and ip_encrypted = ? and l4_tcp_source_port = ? and 
 					l4_tcp_destination_port = ? and l4_udp_source_port = ? and l4_udp_destination_port = ? and l4_icmpv4_type = ? and 
@@ -1389,7 +1805,7 @@ func updateOrInsertCiliumLogSQLite(db *sql.DB, ciliumlog types.CiliumLog) error
 
 	updateStmt, err := db.Prepare(query)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer updateStmt.Close()
 
@@ -1437,7 +1853,7 @@ func updateOrInsertCiliumLogSQLite(db *sql.DB, ciliumlog types.CiliumLog) error
 	)
 	if err != nil {
 		log.Error().Msg(err.Error())
-		return err
+		return false, err
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -1455,7 +1871,7 @@ func updateOrInsertCiliumLogSQLite(db *sql.DB, ciliumlog types.CiliumLog) error
 
 		insertStmt, err := db.Prepare(query)
 		if err != nil {
-			return err
+			return false, err
 		}
 		defer insertStmt.Close()
 
@@ -1505,7 +1921,8 @@ func updateOrInsertCiliumLogSQLite(db *sql.DB, ciliumlog types.CiliumLog) error
 		if err != nil {
 			log.Error().Msg(err.Error())
 		}
+		return err == nil, err
 	}
 
-	return err
+	return false, err
 }
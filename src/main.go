@@ -4,11 +4,14 @@ import (
 	"math/rand"
 	"net"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/accuknox/auto-policy-discovery/src/config"
 	libs "github.com/accuknox/auto-policy-discovery/src/libs"
 	logger "github.com/accuknox/auto-policy-discovery/src/logging"
+	obs "github.com/accuknox/auto-policy-discovery/src/observability"
 	grpcserver "github.com/accuknox/auto-policy-discovery/src/server"
 
 	"github.com/rs/zerolog"
@@ -35,8 +38,21 @@ func init() {
 	log.Info().Msgf("SYSTEM-POLICY: %+v", config.GetCfgSys())
 	log.Info().Msgf("KUBEARMOR: %+v", config.GetCfgKubeArmor())
 
-	// 3. setup the tables in db
+	// 3. setup the tables in db and migrate the schemas
+	if libs.SchemaCommand != "" {
+		libs.RunSchemaCommand(config.GetCfgDB(), config.GetCfgObservabilityDB())
+		os.Exit(0)
+	}
+	if libs.LogExportFormat != "" {
+		if err := libs.RunLogExportCommand(config.GetCfgObservabilityDB()); err != nil {
+			log.Error().Msgf("Failed to export the logs: %v", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	libs.CreateTablesIfNotExist(config.GetCfgDB())
+	libs.CreateObsTablesIfNotExist(config.GetCfgObservabilityDB())
+	libs.MigrateSchemas(config.GetCfgDB(), config.GetCfgObservabilityDB())
 
 	// 4. Seed random number generator
 	rand.Seed(time.Now().UnixNano())
@@ -55,6 +71,15 @@ func main() {
 	}
 	server := grpcserver.GetNewServer()
 
+	// stop serving on a termination signal, the observability is stopped once the server returns
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		server.Stop()
+	}()
+	defer obs.StopObservability()
+
 	// start autopolicy service
 	log.Info().Msgf("gRPC server on %s port started", grpcserver.PortNumber)
 	if err := server.Serve(lis); err != nil {
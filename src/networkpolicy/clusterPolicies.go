@@ -0,0 +1,383 @@
+package networkpolicy
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/cluster"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/plugin"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ===================================== //
+// == Policies Applied in the Cluster == //
+// ===================================== //
+
+const namespaceLabel = "io.kubernetes.pod.namespace"
+
+// GetClusterNetworkPolicies returns the cilium policies in the cluster not managed by the auto policy discovery,
+// e.g. the hand-written policies, as knox policies
+func GetClusterNetworkPolicies() []types.KnoxNetworkPolicy {
+	ciliumPolicies, err := cluster.NewPolicyApplier(cluster.ConnectDynamicClient()).GetCiliumPolicies()
+	if err != nil {
+		log.Warn().Msgf("failed to get the cilium policies in the cluster: %s", err.Error())
+		return nil
+	}
+
+	policies := []types.KnoxNetworkPolicy{}
+	for _, ciliumPolicy := range ciliumPolicies {
+		policies = append(policies, plugin.ConvertCiliumPolicyToKnoxNetworkPolicies(ciliumPolicy)...)
+	}
+
+	return policies
+}
+
+// normalizeLabels drops the k8s source prefix of the cilium labels and adds the namespace
+// of the endpoint if the labels do not select one
+func normalizeLabels(labels map[string]string, namespace string) map[string]string {
+	normalized := map[string]string{}
+	for k, v := range labels {
+		normalized[strings.TrimPrefix(k, "k8s:")] = v
+	}
+
+	if _, ok := normalized[namespaceLabel]; !ok && namespace != "" {
+		normalized[namespaceLabel] = namespace
+	}
+
+	return normalized
+}
+
+// isEndpointCovered checks if the labels of the cluster rule select the endpoint of the discovered rule
+func isEndpointCovered(labels map[string]string, namespace string, clusterLabels map[string]string, clusterNamespace string) bool {
+	endpoint := normalizeLabels(labels, namespace)
+
+	for k, v := range normalizeLabels(clusterLabels, clusterNamespace) {
+		if endpoint[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isCIDRsCovered(cidrs []types.SpecCIDR, clusterCIDRs []types.SpecCIDR) bool {
+	clusterNets := []*net.IPNet{}
+	for _, clusterCIDR := range clusterCIDRs {
+		for _, cidr := range clusterCIDR.CIDRs {
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+				clusterNets = append(clusterNets, ipNet)
+			}
+		}
+	}
+
+	for _, specCIDR := range cidrs {
+		for _, cidr := range specCIDR.CIDRs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return false
+			}
+
+			covered := false
+			for _, clusterNet := range clusterNets {
+				ones, _ := ipNet.Mask.Size()
+				clusterOnes, _ := clusterNet.Mask.Size()
+				if clusterNet.Contains(ipNet.IP) && clusterOnes <= ones {
+					covered = true
+					break
+				}
+			}
+
+			if !covered {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func isEntitiesCovered(entities []string, clusterEntities []string) bool {
+	if libs.ContainsElement(clusterEntities, "all") {
+		return true
+	}
+
+	for _, entity := range entities {
+		if !libs.ContainsElement(clusterEntities, entity) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isServicesCovered(services []types.SpecService, clusterServices []types.SpecService) bool {
+	for _, service := range services {
+		covered := false
+		for _, clusterService := range clusterServices {
+			if service == clusterService {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isFQDNsCovered checks the names against the cluster names and patterns, a pattern is only covered by itself
+func isFQDNsCovered(fqdns []types.SpecFQDN, clusterFQDNs []types.SpecFQDN) bool {
+	clusterNames := []string{}
+	clusterPatterns := []string{}
+	for _, clusterFQDN := range clusterFQDNs {
+		clusterNames = append(clusterNames, clusterFQDN.MatchNames...)
+		clusterPatterns = append(clusterPatterns, clusterFQDN.MatchPatterns...)
+	}
+
+	for _, fqdn := range fqdns {
+		for _, name := range fqdn.MatchNames {
+			if !libs.ContainsElement(clusterNames, name) && !matchFQDNPatterns(clusterPatterns, name) {
+				return false
+			}
+		}
+		for _, pattern := range fqdn.MatchPatterns {
+			if !libs.ContainsElement(clusterPatterns, pattern) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func isPortCovered(port types.SpecPort, clusterPorts []types.SpecPort) bool {
+	for _, clusterPort := range clusterPorts {
+		if (clusterPort.Port == "" || clusterPort.Port == "0" || clusterPort.Port == port.Port || isPortInRange(port, clusterPort)) &&
+			(clusterPort.Protocol == "" || clusterPort.Protocol == "any" || strings.EqualFold(clusterPort.Protocol, port.Protocol)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isHTTPCovered checks the http rule against the cluster rules, the cilium paths are regular expressions
+func isHTTPCovered(http types.SpecHTTP, clusterHTTPs []types.SpecHTTP) bool {
+	for _, clusterHTTP := range clusterHTTPs {
+		if clusterHTTP.Method != "" && !strings.EqualFold(clusterHTTP.Method, http.Method) {
+			continue
+		}
+
+		if clusterHTTP.Path == "" || clusterHTTP.Path == http.Path {
+			return true
+		}
+		if matched, err := regexp.MatchString("^"+clusterHTTP.Path+"$", http.Path); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isL47Covered checks the ports, icmps and http rules, a cluster rule without any allows all of them
+func isL47Covered(rule types.L47Rule, clusterRule types.L47Rule) bool {
+	if len(clusterRule.GetPortRules()) == 0 && len(clusterRule.GetICMPRules()) == 0 {
+		return true
+	}
+
+	// the discovered rule allows all the ports the cluster rule restricts
+	if len(rule.GetPortRules()) == 0 && len(rule.GetICMPRules()) == 0 {
+		return false
+	}
+
+	for _, port := range rule.GetPortRules() {
+		if !isPortCovered(port, clusterRule.GetPortRules()) {
+			return false
+		}
+	}
+
+	for _, icmp := range rule.GetICMPRules() {
+		covered := false
+		for _, clusterICMP := range clusterRule.GetICMPRules() {
+			if icmp.Equal(clusterICMP) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			return false
+		}
+	}
+
+	if len(clusterRule.GetHTTPRules()) > 0 {
+		for _, http := range rule.GetHTTPRules() {
+			if !isHTTPCovered(http, clusterRule.GetHTTPRules()) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func isEgressCovered(rule types.Egress, namespace string, clusterRule types.Egress, clusterNamespace string) bool {
+	switch {
+	case clusterRule.MatchLabels != nil:
+		if rule.MatchLabels == nil || !isEndpointCovered(rule.MatchLabels, namespace, clusterRule.MatchLabels, clusterNamespace) {
+			return false
+		}
+	case len(clusterRule.ToCIDRs) > 0:
+		if len(rule.ToCIDRs) == 0 || !isCIDRsCovered(rule.ToCIDRs, clusterRule.ToCIDRs) {
+			return false
+		}
+	case len(clusterRule.ToEntities) > 0:
+		if len(rule.ToEntities) == 0 || !isEntitiesCovered(rule.ToEntities, clusterRule.ToEntities) {
+			return false
+		}
+	case len(clusterRule.ToServices) > 0:
+		if len(rule.ToServices) == 0 || !isServicesCovered(rule.ToServices, clusterRule.ToServices) {
+			return false
+		}
+	case len(clusterRule.ToFQDNs) > 0:
+		if len(rule.ToFQDNs) == 0 || !isFQDNsCovered(rule.ToFQDNs, clusterRule.ToFQDNs) {
+			return false
+		}
+	}
+
+	return isL47Covered(rule, clusterRule)
+}
+
+func isIngressCovered(rule types.Ingress, namespace string, clusterRule types.Ingress, clusterNamespace string) bool {
+	switch {
+	case clusterRule.MatchLabels != nil:
+		if rule.MatchLabels == nil || !isEndpointCovered(rule.MatchLabels, namespace, clusterRule.MatchLabels, clusterNamespace) {
+			return false
+		}
+	case len(clusterRule.FromCIDRs) > 0:
+		if len(rule.FromCIDRs) == 0 || !isCIDRsCovered(rule.FromCIDRs, clusterRule.FromCIDRs) {
+			return false
+		}
+	case len(clusterRule.FromEntities) > 0:
+		if len(rule.FromEntities) == 0 || !isEntitiesCovered(rule.FromEntities, clusterRule.FromEntities) {
+			return false
+		}
+	}
+
+	return isL47Covered(rule, clusterRule)
+}
+
+// isClusterPolicySelecting checks if the cluster policy selects the endpoints of the discovered policy,
+// a clusterwide policy selects the endpoints of all the namespaces, or the ones of its match expressions
+func isClusterPolicySelecting(clusterPolicy types.KnoxNetworkPolicy, policy types.KnoxNetworkPolicy) bool {
+	kind := clusterPolicy.Kind
+	if kind == types.KindKnoxClusterNetworkPolicy {
+		kind = types.KindKnoxNetworkPolicy
+	}
+	if kind != policy.Kind || clusterPolicy.Metadata["type"] != policy.Metadata["type"] {
+		return false
+	}
+
+	clusterNamespace := getClusterPolicyNamespace(clusterPolicy)
+	if clusterNamespace != "" && clusterNamespace != policy.Metadata["namespace"] {
+		return false
+	}
+
+	if !matchSelectorExpressions(clusterPolicy.Spec.Selector.MatchExpressions, policy.Spec.Selector.MatchLabels, policy.Metadata["namespace"]) {
+		return false
+	}
+
+	return isEndpointCovered(policy.Spec.Selector.MatchLabels, policy.Metadata["namespace"],
+		clusterPolicy.Spec.Selector.MatchLabels, clusterNamespace)
+}
+
+// removeClusterCoveredRules removes the rules of the policy already allowed by the policies in the cluster,
+// returns false if no rule is left to propose
+func removeClusterCoveredRules(policy types.KnoxNetworkPolicy, clusterPolicies []types.KnoxNetworkPolicy) (types.KnoxNetworkPolicy, bool) {
+	namespace := policy.Metadata["namespace"]
+
+	selecting := []types.KnoxNetworkPolicy{}
+	for _, clusterPolicy := range clusterPolicies {
+		if isClusterPolicySelecting(clusterPolicy, policy) {
+			selecting = append(selecting, clusterPolicy)
+		}
+	}
+	if len(selecting) == 0 {
+		return policy, true
+	}
+
+	ingress := []types.Ingress{}
+	for _, rule := range policy.Spec.Ingress {
+		covered := false
+		for _, clusterPolicy := range selecting {
+			for _, clusterRule := range clusterPolicy.Spec.Ingress {
+				if isIngressCovered(rule, namespace, clusterRule, getClusterPolicyNamespace(clusterPolicy)) {
+					covered = true
+					break
+				}
+			}
+		}
+
+		if !covered {
+			ingress = append(ingress, rule)
+		}
+	}
+
+	egress := []types.Egress{}
+	for _, rule := range policy.Spec.Egress {
+		covered := false
+		for _, clusterPolicy := range selecting {
+			for _, clusterRule := range clusterPolicy.Spec.Egress {
+				if isEgressCovered(rule, namespace, clusterRule, getClusterPolicyNamespace(clusterPolicy)) {
+					covered = true
+					break
+				}
+			}
+		}
+
+		if !covered {
+			egress = append(egress, rule)
+		}
+	}
+
+	if len(ingress) == 0 && len(egress) == 0 {
+		return policy, false
+	}
+
+	policy.Spec.Ingress = nil
+	if len(ingress) > 0 {
+		policy.Spec.Ingress = ingress
+	}
+	policy.Spec.Egress = nil
+	if len(egress) > 0 {
+		policy.Spec.Egress = egress
+	}
+
+	return policy, true
+}
+
+// RemoveClusterCoveredPolicies removes the discovered rules the policies in the cluster already allow,
+// so the deduplication does not propose them again
+func RemoveClusterCoveredPolicies(discoveredPolicies []types.KnoxNetworkPolicy, clusterPolicies []types.KnoxNetworkPolicy) []types.KnoxNetworkPolicy {
+	if len(clusterPolicies) == 0 {
+		return discoveredPolicies
+	}
+
+	policies := []types.KnoxNetworkPolicy{}
+	for _, policy := range discoveredPolicies {
+		if policy, ok := removeClusterCoveredRules(policy, clusterPolicies); ok {
+			policies = append(policies, policy)
+		}
+	}
+
+	if skipped := len(discoveredPolicies) - len(policies); skipped > 0 {
+		log.Info().Msgf("%d discovered policies are covered by the policies in the cluster", skipped)
+	}
+
+	return policies
+}
@@ -0,0 +1,105 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveClusterCoveredPolicies(t *testing.T) {
+	// hand-written policy of the team: web may reach redis on 6379 and any cidr of 10.0.0.0/8
+	clusterPolicy := types.KnoxNetworkPolicy{
+		Kind: types.KindKnoxNetworkPolicy,
+		Metadata: map[string]string{
+			"name":      "allow-redis",
+			"namespace": "default",
+			"type":      PolicyTypeEgress,
+		},
+		Spec: types.Spec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "web"}},
+			Egress: []types.Egress{
+				{
+					MatchLabels: map[string]string{"k8s:app": "redis"},
+					ToPorts:     []types.SpecPort{{Port: "6379", Protocol: "tcp"}},
+				},
+				{
+					ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"10.0.0.0/8"}}},
+				},
+			},
+		},
+	}
+
+	discovered := types.KnoxNetworkPolicy{
+		Kind: types.KindKnoxNetworkPolicy,
+		Metadata: map[string]string{
+			"namespace": "default",
+			"type":      PolicyTypeEgress,
+		},
+		Spec: types.Spec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "web", "tier": "frontend"}},
+			Egress: []types.Egress{
+				{
+					MatchLabels: map[string]string{"app": "redis"},
+					ToPorts:     []types.SpecPort{{Port: "6379", Protocol: "tcp"}},
+				},
+				{
+					ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"10.1.2.3/32"}}},
+					ToPorts: []types.SpecPort{{Port: "443", Protocol: "tcp"}},
+				},
+				{
+					// another port of redis is not covered
+					MatchLabels: map[string]string{"app": "redis"},
+					ToPorts:     []types.SpecPort{{Port: "6380", Protocol: "tcp"}},
+				},
+			},
+		},
+	}
+
+	// the same rules in another namespace are not covered
+	otherNamespace := types.KnoxNetworkPolicy{}
+	otherNamespace.Kind = discovered.Kind
+	otherNamespace.Metadata = map[string]string{"namespace": "staging", "type": PolicyTypeEgress}
+	otherNamespace.Spec = discovered.Spec
+
+	// all the rules covered, not proposed
+	covered := types.KnoxNetworkPolicy{}
+	covered.Kind = discovered.Kind
+	covered.Metadata = discovered.Metadata
+	covered.Spec.Selector = discovered.Spec.Selector
+	covered.Spec.Egress = discovered.Spec.Egress[:2]
+
+	policies := RemoveClusterCoveredPolicies([]types.KnoxNetworkPolicy{discovered, otherNamespace, covered}, []types.KnoxNetworkPolicy{clusterPolicy})
+	assert.Equal(t, 2, len(policies))
+	assert.Equal(t, []types.Egress{discovered.Spec.Egress[2]}, policies[0].Spec.Egress)
+	assert.Equal(t, 3, len(policies[1].Spec.Egress))
+}
+
+func TestIsL47Covered(t *testing.T) {
+	allPorts := types.Egress{}
+	port80 := types.Egress{ToPorts: []types.SpecPort{{Port: "80", Protocol: "tcp"}}}
+	http := types.Egress{
+		ToPorts: []types.SpecPort{{Port: "80", Protocol: "tcp"}},
+		ToHTTPs: []types.SpecHTTP{{Method: "GET", Path: "/api/v1/items"}},
+	}
+	httpPattern := types.Egress{
+		ToPorts: []types.SpecPort{{Port: "80", Protocol: "tcp"}},
+		ToHTTPs: []types.SpecHTTP{{Method: "GET", Path: "/api/.*"}},
+	}
+
+	assert.True(t, isL47Covered(port80, allPorts))
+	assert.False(t, isL47Covered(allPorts, port80))
+	assert.True(t, isL47Covered(http, port80))
+	assert.True(t, isL47Covered(http, httpPattern))
+	assert.False(t, isL47Covered(port80, types.Egress{ToPorts: []types.SpecPort{{Port: "443", Protocol: "tcp"}}}))
+}
+
+func TestIsFQDNsCovered(t *testing.T) {
+	clusterFQDNs := []types.SpecFQDN{{MatchNames: []string{"api.github.com"}, MatchPatterns: []string{"*.s3.amazonaws.com"}}}
+
+	assert.True(t, isFQDNsCovered([]types.SpecFQDN{{MatchNames: []string{"api.github.com"}}}, clusterFQDNs))
+	assert.True(t, isFQDNsCovered([]types.SpecFQDN{{MatchNames: []string{"bucket.s3.amazonaws.com"}}}, clusterFQDNs))
+	assert.True(t, isFQDNsCovered([]types.SpecFQDN{{MatchPatterns: []string{"*.s3.amazonaws.com"}}}, clusterFQDNs))
+	assert.False(t, isFQDNsCovered([]types.SpecFQDN{{MatchPatterns: []string{"*.github.com"}}}, clusterFQDNs))
+	assert.False(t, isFQDNsCovered([]types.SpecFQDN{{MatchNames: []string{"example.com"}}}, clusterFQDNs))
+}
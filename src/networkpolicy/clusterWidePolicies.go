@@ -0,0 +1,301 @@
+package networkpolicy
+
+import (
+	"sort"
+	"strings"
+
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ========================== //
+// == Clusterwide Policies == //
+// ========================== //
+
+// ClusterWideNamespace the namespace the discovered clusterwide policies are stored in
+const ClusterWideNamespace = "reserved:clusterwide"
+
+// matchExpression returns true if the labels satisfy the match expression, the k8s source prefix is ignored
+func matchExpression(expr types.MatchExpression, labels map[string]string) bool {
+	value, ok := labels[strings.TrimPrefix(expr.Key, "k8s:")]
+
+	switch expr.Operator {
+	case "In":
+		return ok && libs.ContainsElement(expr.Values, value)
+	case "NotIn":
+		return !ok || !libs.ContainsElement(expr.Values, value)
+	case "Exists":
+		return ok
+	case "DoesNotExist":
+		return !ok
+	}
+
+	return false
+}
+
+// matchSelectorExpressions checks if the endpoint of the labels in the namespace satisfies all the match expressions
+func matchSelectorExpressions(exprs []types.MatchExpression, labels map[string]string, namespace string) bool {
+	endpoint := normalizeLabels(labels, namespace)
+
+	for _, expr := range exprs {
+		if !matchExpression(expr, endpoint) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getSelectorKey returns the labels and the match expressions of the selector, the policies of the same
+// selector share the key
+func getSelectorKey(selector types.Selector) string {
+	key := strings.Join(getLabelArrayFromMap(selector.MatchLabels), ",")
+
+	for _, expr := range selector.MatchExpressions {
+		key = key + ";" + expr.Key + " " + expr.Operator + " " + strings.Join(expr.Values, ",")
+	}
+
+	return key
+}
+
+// getClusterPolicyNamespace returns the namespace of the policy in the cluster, a clusterwide policy has none
+func getClusterPolicyNamespace(policy types.KnoxNetworkPolicy) string {
+	if policy.Kind == types.KindKnoxClusterNetworkPolicy {
+		return ""
+	}
+	return policy.Metadata["namespace"]
+}
+
+func hasNamespaceLabel(labels map[string]string) bool {
+	_, ok := normalizeLabels(labels, "")[namespaceLabel]
+	return ok
+}
+
+// isClusterWideIngressRule returns true if the peer of the rule does not depend on the namespace of the policy,
+// a peer of the same namespace does not specify the namespace
+func isClusterWideIngressRule(rule types.Ingress) bool {
+	if len(rule.ToHTTPs) > 0 {
+		return false
+	}
+
+	if rule.MatchLabels != nil {
+		return hasNamespaceLabel(rule.MatchLabels)
+	}
+
+	return len(rule.FromCIDRs) > 0 || len(rule.FromEntities) > 0
+}
+
+// isClusterWideEgressRule returns true if the peer of the rule does not depend on the namespace of the policy
+func isClusterWideEgressRule(rule types.Egress) bool {
+	if len(rule.ToHTTPs) > 0 {
+		return false
+	}
+
+	if rule.MatchLabels != nil {
+		return hasNamespaceLabel(rule.MatchLabels)
+	}
+
+	if len(rule.ToServices) > 0 {
+		for _, service := range rule.ToServices {
+			if service.Namespace == "" {
+				return false
+			}
+		}
+		return true
+	}
+
+	return len(rule.ToCIDRs) > 0 || len(rule.ToEntities) > 0 || len(rule.ToFQDNs) > 0
+}
+
+// sharedRule is a rule of the namespaced policies of the same endpoint labels in its namespaces
+type sharedRule struct {
+	policyType string
+	rule       interface{}
+	labels     map[string]string
+	namespaces []string
+}
+
+// getSharedRules returns the rules of the namespaced policies shared by the workloads of the same endpoint labels in at
+// least minWorkloads namespaces, a clusterwide policy selects the endpoints of its labels, so the workloads of the
+// other labels do not count for it
+func getSharedRules(discoveredPolicies map[string][]types.KnoxNetworkPolicy, minWorkloads int) []sharedRule {
+	namespaces := []string{}
+	for namespace := range discoveredPolicies {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	keys := []string{}
+	rules := map[string]*sharedRule{}
+
+	addRule := func(policyType, namespace string, labels map[string]string, rule interface{}) {
+		key := getRuleKey(policyType, rule) + "/" + strings.Join(getLabelArrayFromMap(labels), ",")
+
+		if _, ok := rules[key]; !ok {
+			keys = append(keys, key)
+			rules[key] = &sharedRule{policyType: policyType, rule: rule, labels: labels}
+		}
+		if !libs.ContainsElement(rules[key].namespaces, namespace) {
+			rules[key].namespaces = append(rules[key].namespaces, namespace)
+		}
+	}
+
+	for _, namespace := range namespaces {
+		for _, policy := range discoveredPolicies[namespace] {
+			if policy.Kind != types.KindKnoxNetworkPolicy {
+				continue
+			}
+
+			for _, rule := range policy.Spec.Ingress {
+				if isClusterWideIngressRule(rule) {
+					addRule(PolicyTypeIngress, namespace, policy.Spec.Selector.MatchLabels, rule)
+				}
+			}
+			for _, rule := range policy.Spec.Egress {
+				if isClusterWideEgressRule(rule) {
+					addRule(PolicyTypeEgress, namespace, policy.Spec.Selector.MatchLabels, rule)
+				}
+			}
+		}
+	}
+
+	shared := []sharedRule{}
+	for _, key := range keys {
+		if len(rules[key].namespaces) >= minWorkloads {
+			shared = append(shared, *rules[key])
+		}
+	}
+
+	return shared
+}
+
+// getClusterWidePolicyKey returns the endpoint labels and the rules of the clusterwide policy, the key does not
+// change when a namespace joins the policy
+func getClusterWidePolicyKey(policy types.KnoxNetworkPolicy) string {
+	return strings.Join(getLabelArrayFromMap(policy.Spec.Selector.MatchLabels), ",") + ";" +
+		strings.Join(getPolicyRuleKeys(policy), ",")
+}
+
+// getPolicySelector returns the selector the policy is deduplicated by, a clusterwide policy by its key
+func getPolicySelector(policy types.KnoxNetworkPolicy) Selector {
+	if policy.Kind == types.KindKnoxClusterNetworkPolicy {
+		return Selector{policy.Kind, getClusterWidePolicyKey(policy)}
+	}
+	return Selector{policy.Kind, getSelectorKey(policy.Spec.Selector)}
+}
+
+// getSelectorNamespaces returns the namespaces of the namespace expression of the selector
+func getSelectorNamespaces(selector types.Selector) []string {
+	for _, expr := range selector.MatchExpressions {
+		if strings.TrimPrefix(expr.Key, "k8s:") == namespaceLabel && expr.Operator == "In" {
+			return expr.Values
+		}
+	}
+	return nil
+}
+
+// mergeClusterWideSelector sets the selector of the existing clusterwide policy to the discovered one,
+// returns true if the namespaces of the selector changed
+func mergeClusterWideSelector(existPolicy *types.KnoxNetworkPolicy, newPolicy types.KnoxNetworkPolicy) bool {
+	if existPolicy.Kind != types.KindKnoxClusterNetworkPolicy ||
+		getSelectorKey(existPolicy.Spec.Selector) == getSelectorKey(newPolicy.Spec.Selector) {
+		return false
+	}
+
+	existPolicy.Spec.Selector = newPolicy.Spec.Selector
+	return true
+}
+
+// buildClusterWidePolicies builds a clusterwide policy of each shared rule and endpoint labels, the policy selects
+// the endpoints of the labels in its namespaces and the namespaces of the existing policy of the same key
+func buildClusterWidePolicies(rules []sharedRule, existingPolicies []types.KnoxNetworkPolicy) []types.KnoxNetworkPolicy {
+	existingNamespaces := map[string][]string{}
+	for _, policy := range existingPolicies {
+		if policy.Kind == types.KindKnoxClusterNetworkPolicy {
+			key := getClusterWidePolicyKey(policy)
+			existingNamespaces[key] = append(existingNamespaces[key], getSelectorNamespaces(policy.Spec.Selector)...)
+		}
+	}
+
+	results := []types.KnoxNetworkPolicy{}
+	for _, shared := range rules {
+		var policy types.KnoxNetworkPolicy
+		if shared.policyType == PolicyTypeIngress {
+			policy = buildNewKnoxIngressPolicy()
+		} else {
+			policy = buildNewKnoxEgressPolicy()
+		}
+		policy.Kind = types.KindKnoxClusterNetworkPolicy
+		policy.Metadata["namespace"] = ClusterWideNamespace
+
+		if len(shared.labels) > 0 {
+			policy.Spec.Selector.MatchLabels = map[string]string{}
+			for k, v := range shared.labels {
+				policy.Spec.Selector.MatchLabels[k] = v
+			}
+		}
+
+		switch rule := shared.rule.(type) {
+		case types.Ingress:
+			policy.Spec.Ingress = []types.Ingress{rule}
+		case types.Egress:
+			policy.Spec.Egress = []types.Egress{rule}
+		}
+
+		namespaces := append([]string{}, shared.namespaces...)
+		for _, namespace := range existingNamespaces[getClusterWidePolicyKey(policy)] {
+			if !libs.ContainsElement(namespaces, namespace) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+		sort.Strings(namespaces)
+
+		policy.Spec.Selector.MatchExpressions = []types.MatchExpression{{
+			Key:      "k8s:" + namespaceLabel,
+			Operator: "In",
+			Values:   namespaces,
+		}}
+
+		results = append(results, policy)
+	}
+
+	return results
+}
+
+// HoistClusterWidePolicies proposes clusterwide policies, stored under the clusterwide namespace, for the rules shared
+// by the configured number of workloads of the discovered namespaced policies, the rules are removed from the
+// namespaced policies once a clusterwide policy allowing them is approved or applied
+func HoistClusterWidePolicies(discoveredPolicies map[string][]types.KnoxNetworkPolicy, existingPolicies []types.KnoxNetworkPolicy) map[string][]types.KnoxNetworkPolicy {
+	minWorkloads := cfg.GetCfgNetworkClusterWideMinWorkloads()
+	if minWorkloads <= 0 {
+		return discoveredPolicies
+	}
+
+	clusterWidePolicies := buildClusterWidePolicies(getSharedRules(discoveredPolicies, minWorkloads), existingPolicies)
+
+	// the pending clusterwide policies do not allow their rules yet
+	coveringPolicies := []types.KnoxNetworkPolicy{}
+	for _, policy := range existingPolicies {
+		if policy.Kind != types.KindKnoxClusterNetworkPolicy {
+			continue
+		}
+		if state := libs.GetReviewState(policy.Metadata); state == libs.ReviewStateApproved || state == libs.ReviewStateApplied {
+			coveringPolicies = append(coveringPolicies, policy)
+		}
+	}
+
+	if len(coveringPolicies) > 0 {
+		for namespace, policies := range discoveredPolicies {
+			discoveredPolicies[namespace] = RemoveClusterCoveredPolicies(policies, coveringPolicies)
+		}
+	}
+
+	if len(clusterWidePolicies) > 0 {
+		log.Info().Msgf("%d clusterwide policies are discovered for the rules shared by the workloads of the same labels in at least %d namespaces",
+			len(clusterWidePolicies), minWorkloads)
+		discoveredPolicies[ClusterWideNamespace] = append(discoveredPolicies[ClusterWideNamespace], clusterWidePolicies...)
+	}
+
+	return discoveredPolicies
+}
@@ -0,0 +1,181 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchSelectorExpressions(t *testing.T) {
+	exprs := []types.MatchExpression{{Key: "k8s:io.kubernetes.pod.namespace", Operator: "In", Values: []string{"default", "shop"}}}
+
+	assert.True(t, matchSelectorExpressions(exprs, map[string]string{"app": "web"}, "shop"))
+	assert.False(t, matchSelectorExpressions(exprs, map[string]string{"app": "web"}, "staging"))
+	assert.True(t, matchSelectorExpressions(nil, map[string]string{"app": "web"}, "staging"))
+
+	notIn := []types.MatchExpression{{Key: "io.kubernetes.pod.namespace", Operator: "NotIn", Values: []string{"kube-system"}}}
+	assert.True(t, matchSelectorExpressions(notIn, map[string]string{"app": "web"}, "default"))
+	assert.False(t, matchSelectorExpressions(notIn, map[string]string{"app": "web"}, "kube-system"))
+}
+
+func TestHoistClusterWidePolicies(t *testing.T) {
+	cfgNetPolicy := cfg.CurrentCfg.ConfigNetPolicy
+	defer func() { cfg.CurrentCfg.ConfigNetPolicy = cfgNetPolicy }()
+
+	kubeDNS := types.Egress{
+		MatchLabels: map[string]string{"k8s-app": "kube-dns", "k8s:io.kubernetes.pod.namespace": "kube-system"},
+		ToPorts:     []types.SpecPort{{Port: "53", Protocol: "UDP"}},
+	}
+	redis := types.Egress{
+		// a peer of the same namespace is not shared, although the rules are equal
+		MatchLabels: map[string]string{"app": "redis"},
+		ToPorts:     []types.SpecPort{{Port: "6379", Protocol: "TCP"}},
+	}
+	github := types.Egress{
+		ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"api.github.com"}}},
+		ToPorts: []types.SpecPort{{Port: "443", Protocol: "TCP"}},
+	}
+
+	egressPolicy := func(namespace, app string, rules ...types.Egress) types.KnoxNetworkPolicy {
+		policy := buildNewKnoxEgressPolicy()
+		policy.Metadata["namespace"] = namespace
+		policy.Spec.Selector.MatchLabels = map[string]string{"app": app}
+		policy.Spec.Egress = rules
+		return policy
+	}
+
+	discovered := func() map[string][]types.KnoxNetworkPolicy {
+		return map[string][]types.KnoxNetworkPolicy{
+			"default": {egressPolicy("default", "web", kubeDNS, redis)},
+			"shop":    {egressPolicy("shop", "web", kubeDNS, redis, github)},
+			"staging": {egressPolicy("staging", "web", kubeDNS, github), egressPolicy("staging", "api", github)},
+		}
+	}
+
+	// disabled
+	assert.Equal(t, discovered(), HoistClusterWidePolicies(discovered(), nil))
+
+	cfg.CurrentCfg.ConfigNetPolicy.NetClusterWideMinWorkloads = 2
+
+	// the rule to github is shared by 3 workloads, the workload of the api labels does not count for the web labels
+	hoisted := HoistClusterWidePolicies(discovered(), nil)
+	assert.Equal(t, 2, len(hoisted[ClusterWideNamespace]))
+
+	clusterWide := hoisted[ClusterWideNamespace][0]
+	assert.Equal(t, types.KindKnoxClusterNetworkPolicy, clusterWide.Kind)
+	assert.Equal(t, map[string]string{"app": "web"}, clusterWide.Spec.Selector.MatchLabels)
+	assert.Equal(t, []types.MatchExpression{{
+		Key:      "k8s:io.kubernetes.pod.namespace",
+		Operator: "In",
+		Values:   []string{"default", "shop", "staging"},
+	}}, clusterWide.Spec.Selector.MatchExpressions)
+	assert.Equal(t, []types.Egress{kubeDNS}, clusterWide.Spec.Egress)
+
+	assert.Equal(t, map[string]string{"app": "web"}, hoisted[ClusterWideNamespace][1].Spec.Selector.MatchLabels)
+	assert.Equal(t, []string{"shop", "staging"}, getSelectorNamespaces(hoisted[ClusterWideNamespace][1].Spec.Selector))
+	assert.Equal(t, []types.Egress{github}, hoisted[ClusterWideNamespace][1].Spec.Egress)
+
+	// the proposed clusterwide policies do not remove the rules from the namespaced policies
+	assert.Equal(t, []types.Egress{kubeDNS, redis}, hoisted["default"][0].Spec.Egress)
+	assert.Equal(t, []types.Egress{kubeDNS, github}, hoisted["staging"][0].Spec.Egress)
+
+	// the pending clusterwide policy does not remove the rules either
+	cfg.CurrentCfg.ConfigNetPolicy.NetClusterWideMinWorkloads = 5
+
+	hoisted = HoistClusterWidePolicies(discovered(), []types.KnoxNetworkPolicy{clusterWide})
+	assert.Equal(t, 0, len(hoisted[ClusterWideNamespace]))
+	assert.Equal(t, []types.Egress{kubeDNS, redis}, hoisted["default"][0].Spec.Egress)
+
+	// the rules of the approved clusterwide policy are not proposed again in its namespaces
+	approved := clusterWide
+	approved.Metadata = map[string]string{}
+	for k, v := range clusterWide.Metadata {
+		approved.Metadata[k] = v
+	}
+	approved.Metadata["review_state"] = libs.ReviewStateApproved
+
+	hoisted = HoistClusterWidePolicies(discovered(), []types.KnoxNetworkPolicy{approved})
+	assert.Equal(t, []types.Egress{redis}, hoisted["default"][0].Spec.Egress)
+	assert.Equal(t, []types.Egress{redis, github}, hoisted["shop"][0].Spec.Egress)
+	assert.Equal(t, []types.Egress{github}, hoisted["staging"][0].Spec.Egress)
+
+	// the namespaces of the existing clusterwide policy are kept, and the key does not change when a namespace joins
+	existing := clusterWide
+	existing.Spec.Selector = types.Selector{
+		MatchLabels:      clusterWide.Spec.Selector.MatchLabels,
+		MatchExpressions: []types.MatchExpression{{Key: "k8s:io.kubernetes.pod.namespace", Operator: "In", Values: []string{"prod"}}},
+	}
+	cfg.CurrentCfg.ConfigNetPolicy.NetClusterWideMinWorkloads = 2
+
+	hoisted = HoistClusterWidePolicies(discovered(), []types.KnoxNetworkPolicy{existing})
+	assert.Equal(t, []string{"default", "prod", "shop", "staging"}, getSelectorNamespaces(hoisted[ClusterWideNamespace][0].Spec.Selector))
+	assert.Equal(t, getPolicySelector(existing), getPolicySelector(hoisted[ClusterWideNamespace][0]))
+	assert.NotEqual(t, getPolicySelector(existing), getPolicySelector(hoisted[ClusterWideNamespace][1]))
+
+	merged := existing
+	assert.True(t, mergeClusterWideSelector(&merged, hoisted[ClusterWideNamespace][0]))
+	assert.Equal(t, hoisted[ClusterWideNamespace][0].Spec.Selector, merged.Spec.Selector)
+	assert.False(t, mergeClusterWideSelector(&merged, hoisted[ClusterWideNamespace][0]))
+}
+
+func TestHoistClusterWidePoliciesDistinctLabels(t *testing.T) {
+	cfgNetPolicy := cfg.CurrentCfg.ConfigNetPolicy
+	defer func() { cfg.CurrentCfg.ConfigNetPolicy = cfgNetPolicy }()
+
+	kubeDNS := types.Egress{
+		MatchLabels: map[string]string{"k8s-app": "kube-dns", "k8s:io.kubernetes.pod.namespace": "kube-system"},
+		ToPorts:     []types.SpecPort{{Port: "53", Protocol: "UDP"}},
+	}
+
+	egressPolicy := func(namespace, app string) types.KnoxNetworkPolicy {
+		policy := buildNewKnoxEgressPolicy()
+		policy.Metadata["namespace"] = namespace
+		policy.Spec.Selector.MatchLabels = map[string]string{"app": app}
+		policy.Spec.Egress = []types.Egress{kubeDNS}
+		return policy
+	}
+
+	// each namespace runs a workload of its own labels and a web workload, all of them resolving by kube-dns
+	discovered := func() map[string][]types.KnoxNetworkPolicy {
+		return map[string][]types.KnoxNetworkPolicy{
+			"billing": {egressPolicy("billing", "billing"), egressPolicy("billing", "web")},
+			"shop":    {egressPolicy("shop", "cart"), egressPolicy("shop", "web")},
+			"staging": {egressPolicy("staging", "loadgen"), egressPolicy("staging", "web")},
+		}
+	}
+
+	countPolicies := func(policies map[string][]types.KnoxNetworkPolicy) int {
+		count := 0
+		for _, namespaced := range policies {
+			count += len(namespaced)
+		}
+		return count
+	}
+
+	cfg.CurrentCfg.ConfigNetPolicy.NetClusterWideMinWorkloads = 2
+
+	// a clusterwide policy per workload of distinct labels would select a single namespace, only the web one is proposed
+	hoisted := HoistClusterWidePolicies(discovered(), nil)
+	assert.Equal(t, 1, len(hoisted[ClusterWideNamespace]))
+	assert.Equal(t, map[string]string{"app": "web"}, hoisted[ClusterWideNamespace][0].Spec.Selector.MatchLabels)
+	assert.Equal(t, []string{"billing", "shop", "staging"}, getSelectorNamespaces(hoisted[ClusterWideNamespace][0].Spec.Selector))
+
+	// once approved, the clusterwide policy replaces the namespaced web policies
+	approved := hoisted[ClusterWideNamespace][0]
+	approved.Metadata = map[string]string{}
+	for k, v := range hoisted[ClusterWideNamespace][0].Metadata {
+		approved.Metadata[k] = v
+	}
+	approved.Metadata["review_state"] = libs.ReviewStateApproved
+
+	hoisted = HoistClusterWidePolicies(discovered(), []types.KnoxNetworkPolicy{approved})
+	assert.Equal(t, 6, countPolicies(discovered()))
+	assert.Equal(t, 4, countPolicies(hoisted))
+	for _, namespace := range []string{"billing", "shop", "staging"} {
+		assert.Equal(t, 1, len(hoisted[namespace]))
+		assert.NotEqual(t, "web", hoisted[namespace][0].Spec.Selector.MatchLabels["app"])
+	}
+}
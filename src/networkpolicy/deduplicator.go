@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/telemetry"
 	types "github.com/accuknox/auto-policy-discovery/src/types"
 
 	"github.com/google/go-cmp/cmp"
@@ -316,6 +317,8 @@ func updateOutdatedPolicy(outdatedPolicy types.KnoxNetworkPolicy, newPolicy *typ
 	}
 
 	libs.UpdateOutdatedNetworkPolicy(CfgDB, outdatedPolicy.Metadata["name"], newPolicy.Metadata["name"])
+	telemetry.PolicyEvent(telemetry.EventPolicyOutdated, telemetry.PolicyTypeNetwork,
+		outdatedPolicy.Metadata["cluster_name"], outdatedPolicy.Metadata["namespace"], outdatedPolicy.Metadata["name"])
 }
 
 func includedHTTPPath(httpRules []types.SpecHTTP, targetRule types.SpecHTTP) bool {
@@ -748,32 +751,13 @@ func UpdateService(newPolicy types.KnoxNetworkPolicy, existingPolicies []types.K
 	return newPolicy, false
 }
 
-// ======================= //
-// == Policy Name Check == //
-// ======================= //
-
-func existPolicyName(policyNamesMap map[string]bool, name string) bool {
-	if _, ok := policyNamesMap[name]; ok {
-		return true
-	}
-
-	return false
-}
+// ================= //
+// == Policy Name == //
+// ================= //
 
 func GeneratePolicyName(policyNamesMap map[string]bool, policy types.KnoxNetworkPolicy, clusterName string) types.KnoxNetworkPolicy {
-	egressPrefix := "autopol-egress-"
-	ingressPrefix := "autopol-ingress-"
-
-	polType := policy.Metadata["type"]
-	name := "autopol-" + polType + "-" + libs.RandSeq(15)
-
-	for existPolicyName(policyNamesMap, name) {
-		if polType == "egress" {
-			name = egressPrefix + libs.RandSeq(15)
-		} else {
-			name = ingressPrefix + libs.RandSeq(15)
-		}
-	}
+	name := libs.GeneratePolicyName(policy.Metadata["type"], policy.Metadata["namespace"], policy.Spec.Selector.MatchLabels)
+	name = libs.UniquePolicyName(policyNamesMap, name)
 
 	policyNamesMap[name] = true
 
@@ -892,6 +876,8 @@ func updateExistCIDRtoNewFQDN(existingPolicies []types.KnoxNetworkPolicy, newPol
 						}
 
 						libs.UpdateOutdatedNetworkPolicy(CfgDB, existCIDR.Metadata["name"], fqdnPolicy.Metadata["name"])
+						telemetry.PolicyEvent(telemetry.EventPolicyOutdated, telemetry.PolicyTypeNetwork,
+							existCIDR.Metadata["cluster_name"], existCIDR.Metadata["namespace"], existCIDR.Metadata["name"])
 					}
 				}
 			}
@@ -913,6 +899,66 @@ func IsExistingPolicySpec(existingPolicies []types.KnoxNetworkPolicy, newPolicy
 	return false
 }
 
+// ======================= //
+// == Rejected Policies == //
+// ======================= //
+
+// removeRejectedRules removes the rules of the new policy a reviewer already rejected in a policy
+// of the same selector and direction, returns false if no rule is left to propose
+func removeRejectedRules(newPolicy types.KnoxNetworkPolicy, rejectedPolicies []types.KnoxNetworkPolicy) (types.KnoxNetworkPolicy, bool) {
+	if len(rejectedPolicies) == 0 {
+		return newPolicy, true
+	}
+
+	ingress := []types.Ingress{}
+	for _, rule := range newPolicy.Spec.Ingress {
+		rejected := false
+		for _, rejectedPolicy := range rejectedPolicies {
+			for _, rejectedRule := range rejectedPolicy.Spec.Ingress {
+				if cmp.Equal(&rule, &rejectedRule) {
+					rejected = true
+					break
+				}
+			}
+		}
+
+		if !rejected {
+			ingress = append(ingress, rule)
+		}
+	}
+
+	egress := []types.Egress{}
+	for _, rule := range newPolicy.Spec.Egress {
+		rejected := false
+		for _, rejectedPolicy := range rejectedPolicies {
+			for _, rejectedRule := range rejectedPolicy.Spec.Egress {
+				if cmp.Equal(&rule, &rejectedRule) {
+					rejected = true
+					break
+				}
+			}
+		}
+
+		if !rejected {
+			egress = append(egress, rule)
+		}
+	}
+
+	if len(ingress) == 0 && len(egress) == 0 {
+		return newPolicy, false
+	}
+
+	// keep the nil rules of the direction the policy does not cover
+	if len(newPolicy.Spec.Ingress) > 0 {
+		newPolicy.Spec.Ingress = ingress
+	}
+	if len(newPolicy.Spec.Egress) > 0 {
+		newPolicy.Spec.Egress = egress
+	}
+
+	return newPolicy, true
+}
+
 // ====================================== //
 // == Update Duplicated Network Policy == //
 // ====================================== //
@@ -923,12 +969,27 @@ func UpdateDuplicatedPolicy(existingPolicies []types.KnoxNetworkPolicy, discover
 	existIngressPolicies := map[Selector]types.KnoxNetworkPolicy{}
 	existEgressPolicies := map[Selector]types.KnoxNetworkPolicy{}
 
+	// the rejected policies are not merged into, their rules are not proposed again
+	rejectedIngressPolicies := map[Selector][]types.KnoxNetworkPolicy{}
+	rejectedEgressPolicies := map[Selector][]types.KnoxNetworkPolicy{}
+
+	// the rules of the discovered policies are observed now
+	seenTime := libs.ConvertStrToUnixTime("now")
+
 	policyNamesMap := map[string]bool{}
 	for _, existPolicy := range existingPolicies {
 		policyNamesMap[existPolicy.Metadata["name"]] = true
 
-		lblArr := getLabelArrayFromMap(existPolicy.Spec.Selector.MatchLabels)
-		selector := Selector{existPolicy.Kind, strings.Join(lblArr, ",")}
+		selector := getPolicySelector(existPolicy)
+		if libs.GetReviewState(existPolicy.Metadata) == libs.ReviewStateRejected {
+			if existPolicy.Metadata["type"] == PolicyTypeIngress {
+				rejectedIngressPolicies[selector] = append(rejectedIngressPolicies[selector], existPolicy)
+			} else {
+				rejectedEgressPolicies[selector] = append(rejectedEgressPolicies[selector], existPolicy)
+			}
+			continue
+		}
+
 		if existPolicy.Metadata["type"] == PolicyTypeIngress {
 			existIngressPolicies[selector] = existPolicy
 		} else {
@@ -937,34 +998,67 @@ func UpdateDuplicatedPolicy(existingPolicies []types.KnoxNetworkPolicy, discover
 	}
 
 	for _, newPolicy := range discoveredPolicies {
-		lblArr := getLabelArrayFromMap(newPolicy.Spec.Selector.MatchLabels)
-		selector := Selector{newPolicy.Kind, strings.Join(lblArr, ",")}
+		selector := getPolicySelector(newPolicy)
 		if newPolicy.Metadata["type"] == PolicyTypeIngress {
+			newPolicy, ok := removeRejectedRules(newPolicy, rejectedIngressPolicies[selector])
+			if !ok {
+				continue
+			}
+
 			existPolicy, ok := existIngressPolicies[selector]
 			if ok {
 				// Ingress policy for this endpoint exists already
 				mergedPolicy, updated := mergeIngressPolicies(existPolicy, []types.KnoxNetworkPolicy{newPolicy})
+				updated = mergeClusterWideSelector(&mergedPolicy, newPolicy) || updated
 				if updated {
-					existIngressPolicies[selector] = mergedPolicy
+					mergedPolicy = collapsePolicyPortRanges(aggregatePolicyFQDNs(mergedPolicy))
+				}
+				markPolicyRulesSeen(&mergedPolicy, newPolicy, seenTime)
+				existIngressPolicies[selector] = mergedPolicy
+				if updated {
+					// the merged rules are reviewed again
+					mergedPolicy.Metadata["review_state"] = libs.ReviewStateDiscovered
 					libs.UpdateNetworkPolicy(CfgDB, mergedPolicy)
+					telemetry.PolicyEvent(telemetry.EventPolicyUpdated, telemetry.PolicyTypeNetwork,
+						clusterName, mergedPolicy.Metadata["namespace"], mergedPolicy.Metadata["name"])
+				} else if err := libs.UpdateRuleStats(CfgDB, "network", clusterName, mergedPolicy.Metadata["name"], mergedPolicy.RuleStats); err != nil {
+					log.Error().Msg(err.Error())
 				}
 			} else {
 				// Ingress policy for this endpoint does not exists previously
 				namedPolicy := GeneratePolicyName(policyNamesMap, newPolicy, clusterName)
+				markPolicyRulesSeen(&namedPolicy, newPolicy, seenTime)
 				newPolicies = append(newPolicies, namedPolicy)
 			}
 		} else {
+			newPolicy, ok := removeRejectedRules(newPolicy, rejectedEgressPolicies[selector])
+			if !ok {
+				continue
+			}
+
 			existPolicy, ok := existEgressPolicies[selector]
 			if ok {
 				// Egress policy for this endpoint exists already
 				mergedPolicy, updated := mergeEgressPolicies(existPolicy, []types.KnoxNetworkPolicy{newPolicy})
+				updated = mergeClusterWideSelector(&mergedPolicy, newPolicy) || updated
+				if updated {
+					mergedPolicy = collapsePolicyPortRanges(aggregatePolicyFQDNs(mergedPolicy))
+				}
+				markPolicyRulesSeen(&mergedPolicy, newPolicy, seenTime)
+				existEgressPolicies[selector] = mergedPolicy
 				if updated {
-					existEgressPolicies[selector] = mergedPolicy
+					// the merged rules are reviewed again
+					mergedPolicy.Metadata["review_state"] = libs.ReviewStateDiscovered
 					libs.UpdateNetworkPolicy(CfgDB, mergedPolicy)
+					telemetry.PolicyEvent(telemetry.EventPolicyUpdated, telemetry.PolicyTypeNetwork,
+						clusterName, mergedPolicy.Metadata["namespace"], mergedPolicy.Metadata["name"])
+				} else if err := libs.UpdateRuleStats(CfgDB, "network", clusterName, mergedPolicy.Metadata["name"], mergedPolicy.RuleStats); err != nil {
+					log.Error().Msg(err.Error())
 				}
 			} else {
 				// Egress policy for this endpoint does not exists previously
 				namedPolicy := GeneratePolicyName(policyNamesMap, newPolicy, clusterName)
+				markPolicyRulesSeen(&namedPolicy, newPolicy, seenTime)
 				newPolicies = append(newPolicies, namedPolicy)
 			}
 		}
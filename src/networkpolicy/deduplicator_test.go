@@ -502,3 +502,57 @@ func TestUpdateService(t *testing.T) {
 
 	assert.Equal(t, result, expected, ShouldBeEqual)
 }
+
+func TestUpdateDuplicatedPolicyRejected(t *testing.T) {
+	rejected := types.KnoxNetworkPolicy{
+		Kind: "CiliumNetworkPolicy",
+		Metadata: map[string]string{
+			"name":         "autopol-egress-default-test1-1a2b3c4d",
+			"namespace":    "default",
+			"status":       "latest",
+			"type":         PolicyTypeEgress,
+			"review_state": libs.ReviewStateRejected,
+		},
+
+		Spec: types.Spec{
+			Selector: types.Selector{
+				MatchLabels: map[string]string{
+					"app": "test1",
+				},
+			},
+
+			Egress: []types.Egress{
+				types.Egress{
+					ToCIDRs: []types.SpecCIDR{
+						types.SpecCIDR{
+							CIDRs: []string{"10.0.0.1/32"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// the rejected rule only, not proposed again
+	sameRule := types.KnoxNetworkPolicy{}
+	libs.DeepCopy(&sameRule, &rejected)
+	sameRule.Metadata = map[string]string{"namespace": "default", "type": PolicyTypeEgress}
+
+	// the rejected rule and a new one, the new one is proposed
+	newRule := types.KnoxNetworkPolicy{}
+	libs.DeepCopy(&newRule, &sameRule)
+	newRule.Metadata = map[string]string{"namespace": "default", "type": PolicyTypeEgress}
+	newRule.Spec.Egress = append(newRule.Spec.Egress, types.Egress{
+		ToCIDRs: []types.SpecCIDR{
+			types.SpecCIDR{
+				CIDRs: []string{"10.0.0.2/32"},
+			},
+		},
+	})
+
+	newPolicies := UpdateDuplicatedPolicy([]types.KnoxNetworkPolicy{rejected}, []types.KnoxNetworkPolicy{sameRule, newRule}, map[string][]string{}, "default")
+	assert.Equal(t, 1, len(newPolicies))
+	assert.Equal(t, 1, len(newPolicies[0].Spec.Egress))
+	assert.Equal(t, []string{"10.0.0.2/32"}, newPolicies[0].Spec.Egress[0].ToCIDRs[0].CIDRs)
+	assert.NotEqual(t, rejected.Metadata["name"], newPolicies[0].Metadata["name"])
+}
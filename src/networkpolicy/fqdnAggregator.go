@@ -0,0 +1,193 @@
+package networkpolicy
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"golang.org/x/net/publicsuffix"
+)
+
+// ====================== //
+// == FQDN aggregation == //
+// ====================== //
+
+// WildFQDNLabel the characters a '*' of a cilium fqdn pattern matches, within a single label
+var WildFQDNLabel string = "[-a-zA-Z0-9_]*"
+
+// matchFQDNPattern returns true if the domain name matches the cilium matchPattern
+func matchFQDNPattern(pattern, name string) bool {
+	pattern = strings.TrimSuffix(strings.ToLower(pattern), ".")
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+
+	if pattern == "*" {
+		return true
+	}
+
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, WildFQDNLabel) + "$"
+	matched, err := regexp.MatchString(expr, name)
+	return err == nil && matched
+}
+
+func matchFQDNPatterns(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matchFQDNPattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MinFQDNPatternThreshold the min siblings of a wildcard pattern, a single name is never widened to a pattern
+const MinFQDNPatternThreshold = 2
+
+// getFQDNPattern returns the wildcard pattern of the siblings of the domain name, e.g., a.cdn.example.com
+// -> *.cdn.example.com, the names directly under a public suffix (e.g., bbc.co.uk, foo.herokuapp.com) are
+// owned by unrelated parties and not aggregated
+func getFQDNPattern(name string) string {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(name, ".")), ".")
+	if len(labels) < 3 {
+		return ""
+	}
+
+	base := strings.Join(labels[1:], ".")
+	if _, err := publicsuffix.EffectiveTLDPlusOne(base); err != nil {
+		return ""
+	}
+
+	return "*." + base
+}
+
+// aggregateFQDNs returns the names not covered by the patterns, and the patterns with the wildcard patterns of
+// the names of at least threshold siblings, false if no name is covered by the patterns
+func aggregateFQDNs(names, patterns []string, threshold int) ([]string, []string, bool) {
+	siblings := map[string][]string{}
+	uncovered := []string{}
+	aggregated := false
+
+	for _, name := range names {
+		if matchFQDNPatterns(patterns, name) {
+			aggregated = true
+			continue
+		}
+		if libs.ContainsElement(uncovered, name) {
+			continue
+		}
+		uncovered = append(uncovered, name)
+
+		if pattern := getFQDNPattern(name); pattern != "" {
+			siblings[pattern] = append(siblings[pattern], name)
+		}
+	}
+
+	aggregatedPatterns := []string{}
+	for _, pattern := range patterns {
+		if !libs.ContainsElement(aggregatedPatterns, pattern) {
+			aggregatedPatterns = append(aggregatedPatterns, pattern)
+		}
+	}
+	for pattern, siblingNames := range siblings {
+		if len(siblingNames) >= threshold && !libs.ContainsElement(aggregatedPatterns, pattern) {
+			aggregatedPatterns = append(aggregatedPatterns, pattern)
+		}
+	}
+
+	aggregatedNames := []string{}
+	for _, name := range uncovered {
+		if matchFQDNPatterns(aggregatedPatterns, name) {
+			aggregated = true
+			continue
+		}
+		aggregatedNames = append(aggregatedNames, name)
+	}
+
+	sort.Strings(aggregatedNames)
+	sort.Strings(aggregatedPatterns)
+
+	return aggregatedNames, aggregatedPatterns, aggregated
+}
+
+// getFQDNKey returns the domain name or the pattern of the fqdn rule
+func getFQDNKey(fqdn types.SpecFQDN) string {
+	if len(fqdn.MatchNames) > 0 {
+		return fqdn.MatchNames[0]
+	}
+	if len(fqdn.MatchPatterns) > 0 {
+		return fqdn.MatchPatterns[0]
+	}
+	return ""
+}
+
+func aggregateEgressFQDNs(rules []types.Egress, threshold int) []types.Egress {
+	results := []types.Egress{}
+
+	groupKeys := []string{}
+	groupRules := map[string][]types.Egress{}
+
+	for _, rule := range rules {
+		if len(rule.ToFQDNs) == 0 || len(rule.ToHTTPs) > 0 {
+			results = append(results, rule)
+			continue
+		}
+
+		// the fqdn rules of the same ports are aggregated together
+		peer := rule
+		peer.ToFQDNs = nil
+		b, _ := json.Marshal(peer)
+		key := string(b)
+
+		if _, ok := groupRules[key]; !ok {
+			groupKeys = append(groupKeys, key)
+		}
+		groupRules[key] = append(groupRules[key], rule)
+	}
+
+	for _, key := range groupKeys {
+		names := []string{}
+		patterns := []string{}
+		for _, rule := range groupRules[key] {
+			for _, fqdn := range rule.ToFQDNs {
+				names = append(names, fqdn.MatchNames...)
+				patterns = append(patterns, fqdn.MatchPatterns...)
+			}
+		}
+
+		aggregatedNames, aggregatedPatterns, aggregated := aggregateFQDNs(names, patterns, threshold)
+
+		// no name is covered by a pattern, the rules are kept as they are
+		if !aggregated {
+			results = append(results, groupRules[key]...)
+			continue
+		}
+
+		for _, pattern := range aggregatedPatterns {
+			rule := groupRules[key][0]
+			rule.ToFQDNs = []types.SpecFQDN{{MatchPatterns: []string{pattern}}}
+			results = append(results, rule)
+		}
+		for _, name := range aggregatedNames {
+			rule := groupRules[key][0]
+			rule.ToFQDNs = []types.SpecFQDN{{MatchNames: []string{name}}}
+			results = append(results, rule)
+		}
+	}
+
+	return results
+}
+
+// aggregatePolicyFQDNs replaces the fqdn rules of the sibling domain names with a rule of their wildcard
+// pattern, once the configured number of siblings are observed
+func aggregatePolicyFQDNs(policy types.KnoxNetworkPolicy) types.KnoxNetworkPolicy {
+	threshold := cfg.GetCfgNetworkFQDNPatternThreshold()
+	if threshold < MinFQDNPatternThreshold || len(policy.Spec.Egress) == 0 {
+		return policy
+	}
+
+	policy.Spec.Egress = aggregateEgressFQDNs(policy.Spec.Egress, threshold)
+
+	return policy
+}
@@ -0,0 +1,108 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchFQDNPattern(t *testing.T) {
+	assert.True(t, matchFQDNPattern("*.s3.amazonaws.com", "bucket-1.s3.amazonaws.com"))
+	assert.True(t, matchFQDNPattern("*.s3.amazonaws.com", "Bucket.s3.amazonaws.com."))
+	assert.True(t, matchFQDNPattern("*", "example.com"))
+
+	// a '*' does not match across the labels
+	assert.False(t, matchFQDNPattern("*.s3.amazonaws.com", "a.b.s3.amazonaws.com"))
+	assert.False(t, matchFQDNPattern("*.s3.amazonaws.com", "s3.amazonaws.com"))
+	assert.False(t, matchFQDNPattern("*.s3.amazonaws.com", "bucket.s3xamazonaws.com"))
+}
+
+func TestGetFQDNPattern(t *testing.T) {
+	assert.Equal(t, "*.cdn.example.com", getFQDNPattern("a.cdn.example.com"))
+	assert.Equal(t, "*.bbc.co.uk", getFQDNPattern("www.bbc.co.uk"))
+	assert.Equal(t, "", getFQDNPattern("example.com"))
+
+	// the wildcards directly under a public suffix match the names of unrelated owners
+	assert.Equal(t, "", getFQDNPattern("bbc.co.uk"))
+	assert.Equal(t, "", getFQDNPattern("foo.herokuapp.com"))
+	assert.Equal(t, "", getFQDNPattern("bucket.s3.amazonaws.com"))
+}
+
+func TestAggregateFQDNsPublicSuffix(t *testing.T) {
+	names := []string{"bbc.co.uk", "amazon.co.uk", "foo.herokuapp.com", "bar.herokuapp.com"}
+
+	aggregatedNames, patterns, aggregated := aggregateFQDNs(names, nil, 2)
+	assert.False(t, aggregated)
+	assert.Equal(t, []string{"amazon.co.uk", "bar.herokuapp.com", "bbc.co.uk", "foo.herokuapp.com"}, aggregatedNames)
+	assert.Equal(t, []string{}, patterns)
+}
+
+func TestAggregateFQDNs(t *testing.T) {
+	names := []string{"a.cdn.example.com", "b.cdn.example.com", "c.cdn.example.com", "api.github.com", "example.com"}
+
+	aggregatedNames, patterns, aggregated := aggregateFQDNs(names, nil, 3)
+	assert.True(t, aggregated)
+	assert.Equal(t, []string{"api.github.com", "example.com"}, aggregatedNames)
+	assert.Equal(t, []string{"*.cdn.example.com"}, patterns)
+
+	// below the threshold
+	_, patterns, aggregated = aggregateFQDNs(names, nil, 4)
+	assert.False(t, aggregated)
+	assert.Equal(t, []string{}, patterns)
+
+	// a name observed again is covered by the pattern
+	aggregatedNames, patterns, aggregated = aggregateFQDNs([]string{"d.cdn.example.com"}, []string{"*.cdn.example.com"}, 3)
+	assert.True(t, aggregated)
+	assert.Equal(t, []string{}, aggregatedNames)
+	assert.Equal(t, []string{"*.cdn.example.com"}, patterns)
+}
+
+func TestAggregatePolicyFQDNs(t *testing.T) {
+	cfgNetPolicy := cfg.CurrentCfg.ConfigNetPolicy
+	defer func() { cfg.CurrentCfg.ConfigNetPolicy = cfgNetPolicy }()
+
+	https := []types.SpecPort{{Port: "443", Protocol: "TCP"}}
+	fqdnRule := func(name string) types.Egress {
+		return types.Egress{ToPorts: https, ToFQDNs: []types.SpecFQDN{{MatchNames: []string{name}}}}
+	}
+
+	policy := types.KnoxNetworkPolicy{
+		Metadata: map[string]string{"type": PolicyTypeEgress},
+		Spec: types.Spec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "uploader"}},
+			Egress: []types.Egress{
+				fqdnRule("a.cdn.example.com"),
+				fqdnRule("b.cdn.example.com"),
+				fqdnRule("api.github.com"),
+				{ToPorts: []types.SpecPort{{Port: "80", Protocol: "TCP"}}, ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"c.cdn.example.com"}}}},
+			},
+		},
+	}
+
+	// disabled
+	assert.Equal(t, policy, aggregatePolicyFQDNs(policy))
+
+	// a single name is not widened to a pattern
+	cfg.CurrentCfg.ConfigNetPolicy.NetFQDNPatternThreshold = 1
+	assert.Equal(t, policy, aggregatePolicyFQDNs(policy))
+
+	cfg.CurrentCfg.ConfigNetPolicy.NetFQDNPatternThreshold = 2
+
+	aggregated := aggregatePolicyFQDNs(policy)
+	assert.Equal(t, []types.Egress{
+		{ToPorts: https, ToFQDNs: []types.SpecFQDN{{MatchPatterns: []string{"*.cdn.example.com"}}}},
+		fqdnRule("api.github.com"),
+		policy.Spec.Egress[3],
+	}, aggregated.Spec.Egress)
+
+	// the name matching the pattern is allowed already, and observed as the pattern rule
+	discovered := policy
+	discovered.Spec.Egress = []types.Egress{fqdnRule("d.cdn.example.com")}
+
+	merged, updated := mergeEgressPolicies(aggregated, []types.KnoxNetworkPolicy{discovered})
+	assert.False(t, updated)
+	assert.Equal(t, 3, len(merged.Spec.Egress))
+	assert.Equal(t, []string{getRuleKey(PolicyTypeEgress, aggregated.Spec.Egress[0])}, getCoveringRuleKeys(merged, discovered))
+}
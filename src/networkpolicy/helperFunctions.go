@@ -225,7 +225,7 @@ func getNetworkLogs() []types.KnoxNetworkLog {
 			return nil
 		}
 
-		// convert hubble flows -> network logs (but, in this case, no flow id)
+		// convert hubble flows -> network logs (the flow ids are numbered below)
 		for _, flow := range flows {
 			if log, valid := plugin.ConvertCiliumFlowToKnoxNetworkLog(flow); valid {
 				networkLogs = append(networkLogs, log)
@@ -243,7 +243,7 @@ func getNetworkLogs() []types.KnoxNetworkLog {
 			return nil
 		}
 
-		// convert hubble flows -> network logs (but, in this case, no flow id)
+		// convert hubble flows -> network logs (the flow ids are numbered below)
 		for _, flow := range flows {
 			networkLogs = append(networkLogs, *flow)
 		}
@@ -278,7 +278,7 @@ func getNetworkLogs() []types.KnoxNetworkLog {
 		pods := cluster.GetPodsFromK8sClient()
 		ReplaceMultiubuntuPodName(flows, pods)
 
-		// convert file flows -> network logs (but, in this case, no flow id..)
+		// convert file flows -> network logs (the flow ids are numbered below)
 		for _, flow := range flows {
 			if log, valid := plugin.ConvertCiliumFlowToKnoxNetworkLog(flow); valid {
 				networkLogs = append(networkLogs, log)
@@ -297,6 +297,12 @@ func getNetworkLogs() []types.KnoxNetworkLog {
 		if log.ClusterName == "" {
 			networkLogs[i].ClusterName = "Default"
 		}
+
+		// number the flows without id, so the samples of the rule evidences can be told apart
+		if log.FlowID == 0 {
+			libs.LastFlowID++
+			networkLogs[i].FlowID = int(libs.LastFlowID)
+		}
 	}
 
 	return networkLogs
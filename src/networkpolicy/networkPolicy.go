@@ -13,6 +13,7 @@ import (
 	"github.com/accuknox/auto-policy-discovery/src/libs"
 	logger "github.com/accuknox/auto-policy-discovery/src/logging"
 	"github.com/accuknox/auto-policy-discovery/src/plugin"
+	"github.com/accuknox/auto-policy-discovery/src/telemetry"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/google/go-cmp/cmp"
 
@@ -126,6 +127,11 @@ func InitNetPolicyDiscoveryConfiguration() {
 
 	NetworkLogFilters = cfg.GetCfgNetworkLogFilters()
 	NamespaceFilters = cfg.GetCfgNetworkSkipNamespaces()
+
+	if threshold := cfg.GetCfgNetworkFQDNPatternThreshold(); threshold > 0 && threshold < MinFQDNPatternThreshold {
+		log.Error().Msgf("fqdn-pattern-threshold %d rejected, at least %d sibling names are required, fqdn patterns disabled",
+			threshold, MinFQDNPatternThreshold)
+	}
 }
 
 // ============================= //
@@ -805,10 +811,13 @@ func mergeProtocolPorts(src string, dsts []Dst) []MergedPortDst {
 					Type:   uint8(dst.ICMPType),
 				}}
 			} else {
-				l4MergedDst.ToPorts = []types.SpecPort{{
+				port := types.SpecPort{
 					Protocol: libs.GetProtocol(dst.Protocol),
 					Port:     strconv.Itoa(dst.DstPort),
-				}}
+				}
+				if !libs.ContainsElement(l4MergedDst.ToPorts, port) {
+					l4MergedDst.ToPorts = append(l4MergedDst.ToPorts, port)
+				}
 			}
 
 			flowIDs := getFlowIDFromTrackMap2(src, dst)
@@ -852,6 +861,7 @@ func mergeProtocolPorts(src string, dsts []Dst) []MergedPortDst {
 
 	l47Dsts := []MergedPortDst{}
 	if l4DstExists {
+		l4MergedDst.ToPorts = collapseProtocolPorts(l4MergedDst.ToPorts)
 		l47Dsts = append(l47Dsts, l4MergedDst)
 	}
 	if l7DstExists {
@@ -1541,6 +1551,12 @@ func DiscoverNetworkPolicy(namespace string,
 	for _, p := range egressPolicies {
 		networkPolicies = append(networkPolicies, p...)
 	}
+
+	// the sibling domain names are allowed as a fqdn pattern, and the dense ports of a peer as a port range
+	for i := range networkPolicies {
+		networkPolicies[i] = collapsePolicyPortRanges(aggregatePolicyFQDNs(networkPolicies[i]))
+	}
+
 	return networkPolicies
 }
 
@@ -1650,15 +1666,17 @@ func mergeEgressPolicies(existPolicy types.KnoxNetworkPolicy, policies []types.K
 					}
 				}
 			} else if len(newEgress.ToFQDNs) > 0 {
-				newFQDN := newEgress.ToFQDNs[0].MatchNames[0]
+				newFQDN := getFQDNKey(newEgress.ToFQDNs[0])
 
 				for i, existEgress := range mergedPolicy.Spec.Egress {
 					if len(existEgress.ToFQDNs) == 0 {
 						continue
 					}
-					existFQDN := existEgress.ToFQDNs[0].MatchNames[0]
+					existFQDN := getFQDNKey(existEgress.ToFQDNs[0])
 
-					if newFQDN == existFQDN {
+					// a name matching the pattern of the existing L4 rule is allowed as well
+					if newFQDN == existFQDN || (len(newEgress.ToHTTPs) == 0 && len(existEgress.ToHTTPs) == 0 &&
+						isFQDNsCovered(newEgress.ToFQDNs, existEgress.ToFQDNs)) {
 						egressMatched, updated, mergedPolicy.Spec.Egress[i].ToHTTPs = mergeHttpRules(existEgress, newEgress)
 						if egressMatched {
 							break
@@ -1711,6 +1729,12 @@ func mergeHttpRules(existRule types.L47Rule, newRule types.L47Rule) (bool, bool,
 	// Handling Ingress/Egress with L4 and HTTP rules
 	mergedHttpRule := existHttpRule
 	updated := false
+
+	// a L4 rule of a port within the port range of the existing rule is allowed already
+	if len(existHttpRule) == 0 && len(newHttpRule) == 0 && isPortInRange(newPortRule[0], existPortRule[0]) {
+		return true, false, mergedHttpRule
+	}
+
 	if existPortRule[0].Equal(newPortRule[0]) {
 		for _, h := range newHttpRule {
 			if !libs.ContainsElement(existHttpRule, h) {
@@ -1749,9 +1773,10 @@ func convertKnoxNetworkLogToKnoxNetworkPolicy(log *types.KnoxNetworkLog, pods []
 			ingress.MatchLabels["io.kubernetes.pod.namespace"] = log.SrcNamespace
 		}
 
-		// 1.3 Set the dst port/protocol
+		// 1.3 Set the dst port/protocol, or the container port name of the dst
 		if !libs.IsICMP(log.Protocol) {
-			egress.ToPorts = []types.SpecPort{{Port: strconv.Itoa(log.DstPort), Protocol: libs.GetProtocol(log.Protocol)}}
+			port := types.SpecPort{Port: strconv.Itoa(log.DstPort), Protocol: libs.GetProtocol(log.Protocol)}
+			egress.ToPorts = []types.SpecPort{getNamedPort(log.DstPodName, pods, port)}
 			ingress.ToPorts = append(ingress.ToPorts, egress.ToPorts...)
 		} else {
 			// 1.4 Set the icmp code/type
@@ -1792,9 +1817,10 @@ func convertKnoxNetworkLogToKnoxNetworkPolicy(log *types.KnoxNetworkLog, pods []
 		if srcEntity != "" {
 			ingress.FromEntities = append(ingress.FromEntities, srcEntity)
 
-			// 2.3 Set the dst port/protocol
+			// 2.3 Set the dst port/protocol, or the container port name of the dst
 			if !libs.IsICMP(log.Protocol) {
-				ingress.ToPorts = []types.SpecPort{{Port: strconv.Itoa(log.DstPort), Protocol: libs.GetProtocol(log.Protocol)}}
+				port := types.SpecPort{Port: strconv.Itoa(log.DstPort), Protocol: libs.GetProtocol(log.Protocol)}
+				ingress.ToPorts = []types.SpecPort{getNamedPort(log.DstPodName, pods, port)}
 			} else {
 				// 2.4 Set the icmp code/type
 				family := "IPv4"
@@ -1828,7 +1854,7 @@ func convertKnoxNetworkLogToKnoxNetworkPolicy(log *types.KnoxNetworkLog, pods []
 		dstEntity := getEntityFromReservedLabels(log.DstReservedLabels)
 		if dstEntity != "" {
 			if dstEntity == "world" && log.DNSQuery != "" {
-				fqdn := types.SpecFQDN{[]string{log.DNSQuery}}
+				fqdn := types.SpecFQDN{MatchNames: []string{log.DNSQuery}}
 				egress.ToFQDNs = append(egress.ToFQDNs, fqdn)
 			} else {
 				egress.ToEntities = append(egress.ToEntities, dstEntity)
@@ -2034,6 +2060,7 @@ func applyPolicyFilter(discoveredPolicies map[string][]types.KnoxNetworkPolicy)
 func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog) map[string][]types.KnoxNetworkPolicy {
 
 	discoveredNetworkPolicies := map[string][]types.KnoxNetworkPolicy{}
+	startTime, totalNewPolicies := time.Now(), 0
 
 	// get cluster names, iterate each cluster
 	clusteredLogs := clusteringNetworkLogs(networkLogs)
@@ -2064,6 +2091,10 @@ func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog)
 		// filter ignoring network logs from configuration
 		filteredLogs := FilterNetworkLogsByConfig(networkLogs, pods)
 
+		// the flows observed for the discovered rules
+		evidences := map[string]types.RuleEvidence{}
+		seenTime := libs.ConvertStrToUnixTime("now")
+
 		// iterate each namespace
 		for _, namespace := range namespaces {
 			// get network logs by target namespace
@@ -2079,6 +2110,7 @@ func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog)
 			log.Info().Msgf("DiscoverNetworkPolicy for cluster [%s] namespace [%s]", clusterName, namespace)
 			// discover network policies based on the network logs
 			discoveredNetPolicies := DiscoverNetworkPolicy(namespace, logsPerNamespace, services, pods)
+			addNetworkRuleEvidences(evidences, clusterName, logsPerNamespace, pods, seenTime)
 
 			// Segregate policies based on policy namespace
 			// Context:
@@ -2096,6 +2128,19 @@ func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog)
 		// filter discovered policies
 		discoveredNetworkPolicies = applyPolicyFilter(discoveredNetworkPolicies)
 
+		// propose clusterwide policies for the rules shared by many workloads
+		if cfg.GetCfgNetworkClusterWideMinWorkloads() > 0 {
+			existingClusterWidePolicies := libs.GetNetworkPolicies(CfgDB, clusterName, ClusterWideNamespace, "latest", "", "")
+			discoveredNetworkPolicies = HoistClusterWidePolicies(discoveredNetworkPolicies, existingClusterWidePolicies)
+			namespaces = append(namespaces, ClusterWideNamespace)
+		}
+
+		// the policies already in the cluster seed the deduplication
+		clusterNetPolicies := []types.KnoxNetworkPolicy{}
+		if cfg.GetCfgNetworkClusterPolicySeed() {
+			clusterNetPolicies = GetClusterNetworkPolicies()
+		}
+
 		// iterate each namespace
 		for _, namespace := range namespaces {
 			discoveredPolicies := discoveredNetworkPolicies[namespace]
@@ -2107,6 +2152,9 @@ func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog)
 			// get existing network policies in db
 			existingNetPolicies := libs.GetNetworkPolicies(CfgDB, clusterName, namespace, "latest", "", "")
 
+			// skip the rules the policies in the cluster already allow
+			discoveredPolicies = RemoveClusterCoveredPolicies(discoveredPolicies, clusterNetPolicies)
+
 			log.Info().Msgf("UpdateDuplicatedPolicy for cluster [%s] namespace [%s]", clusterName, namespace)
 			// update duplicated policy
 			newNetPolicies := UpdateDuplicatedPolicy(existingNetPolicies, discoveredPolicies, DomainToIPs, clusterName)
@@ -2117,6 +2165,11 @@ func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog)
 					libs.InsertNetworkPolicies(CfgDB, newNetPolicies)
 				}
 
+				for _, policy := range newNetPolicies {
+					telemetry.PolicyEvent(telemetry.EventPolicyCreated, telemetry.PolicyTypeNetwork, clusterName, namespace, policy.Metadata["name"])
+				}
+				totalNewPolicies += len(newNetPolicies)
+
 				// write discovered policies to file
 				if strings.Contains(NetworkPolicyTo, "file") {
 					WriteNetworkPoliciesToFile(clusterName, namespace)
@@ -2126,10 +2179,20 @@ func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog)
 			}
 		}
 
+		// store the evidences of the discovered rules
+		if strings.Contains(NetworkPolicyTo, "db") {
+			storeRuleEvidences(evidences)
+		}
+
+		// propose the policies without the rules not observed for the aging period
+		AgeNetworkPolicyRules(clusterName)
+
 		// update cluster global variables
 		updateMultiClusterVariables(clusterName)
 	}
 
+	telemetry.WorkerRunEvent(telemetry.PolicyTypeNetwork, len(networkLogs), totalNewPolicies, time.Since(startTime))
+
 	return discoveredNetworkPolicies
 }
 
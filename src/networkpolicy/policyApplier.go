@@ -0,0 +1,98 @@
+package networkpolicy
+
+import (
+	"fmt"
+
+	"github.com/accuknox/auto-policy-discovery/src/cluster"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/plugin"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ========================== //
+// == Apply Network Policy == //
+// ========================== //
+
+// getLatestNetworkPolicy returns the latest version of the network policy in the cluster
+func getLatestNetworkPolicy(clusterName, name string) (types.KnoxNetworkPolicy, error) {
+	for _, policy := range libs.GetNetworkPolicies(CfgDB, clusterName, "", "latest", "", "") {
+		if policy.Metadata["name"] == name {
+			return policy, nil
+		}
+	}
+
+	return types.KnoxNetworkPolicy{}, fmt.Errorf("no latest network policy %s in cluster %s", name, clusterName)
+}
+
+// getPreviousNetworkPolicyNames returns the names of the versions the network policy outdated, from the nearest one
+func getPreviousNetworkPolicyNames(clusterName, name string) []string {
+	outdatedPolicies := libs.GetNetworkPolicies(CfgDB, clusterName, "", "outdated", "", "")
+
+	names := []string{}
+	seen := map[string]bool{name: true}
+	for next := []string{name}; len(next) > 0; {
+		current := next
+		next = []string{}
+		for _, policy := range outdatedPolicies {
+			prev := policy.Metadata["name"]
+			if libs.ContainsElement(current, policy.Outdated) && !seen[prev] {
+				seen[prev] = true
+				names = append(names, prev)
+				next = append(next, prev)
+			}
+		}
+	}
+
+	return names
+}
+
+// ApplyNetworkPolicy creates or updates the cilium policy of an approved network policy in the cluster, the cilium
+// policies of its previous versions are replaced, the policy is marked as applied unless it is a dry run
+func ApplyNetworkPolicy(applier *cluster.PolicyApplier, clusterName, name, reviewer string, dryRun bool) (types.PolicyApplyResult, error) {
+	policy, err := getLatestNetworkPolicy(clusterName, name)
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	state := libs.GetReviewState(policy.Metadata)
+	if state != libs.ReviewStateApproved && state != libs.ReviewStateApplied {
+		return types.PolicyApplyResult{}, fmt.Errorf("network policy %s is %s, approve it before applying", name, state)
+	}
+
+	result, err := applier.ApplyReplacing(plugin.ConvertKnoxNetworkPolicyToCiliumPolicy(policy),
+		getPreviousNetworkPolicyNames(clusterName, name), dryRun)
+	if err != nil {
+		return result, err
+	}
+	for _, replaced := range result.Replaced {
+		log.Info().Msgf("network policy %s replaced the %s %s", name, result.Kind, replaced)
+	}
+
+	if !dryRun && state == libs.ReviewStateApproved {
+		err = libs.UpdatePolicyReview(CfgDB, &types.PolicyReview{
+			PolicyType:  "network",
+			ClusterName: clusterName,
+			Name:        name,
+			State:       libs.ReviewStateApplied,
+			Reviewer:    reviewer,
+			Comment:     result.Kind + " " + result.Action,
+		})
+	}
+
+	return result, err
+}
+
+// RollbackNetworkPolicy restores the previous version of the cilium policy of the network policy in the cluster
+func RollbackNetworkPolicy(applier *cluster.PolicyApplier, clusterName, name string, dryRun bool) (types.PolicyApplyResult, error) {
+	policy, err := getLatestNetworkPolicy(clusterName, name)
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	kind, namespace, objName, err := cluster.GetPolicyObjectKey(plugin.ConvertKnoxNetworkPolicyToCiliumPolicy(policy))
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	return applier.Rollback(kind, namespace, objName, dryRun)
+}
@@ -0,0 +1,364 @@
+package networkpolicy
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ================================== //
+// == Port Ranges and Named Ports  == //
+// ================================== //
+
+// getPortBounds returns the first and the last port number of the port, false if it is a named port
+func getPortBounds(port types.SpecPort) (int, int, bool) {
+	start, err := strconv.Atoi(port.Port)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	end := start
+	if port.EndPort > start {
+		end = port.EndPort
+	}
+
+	return start, end, true
+}
+
+// isPortInRange returns true if the port, or all the ports of its range, are within the port range
+func isPortInRange(port, portRange types.SpecPort) bool {
+	if port.Equal(portRange) {
+		return true
+	}
+	if !strings.EqualFold(port.Protocol, portRange.Protocol) {
+		return false
+	}
+
+	start, end, ok := getPortBounds(port)
+	if !ok {
+		return false
+	}
+	rangeStart, rangeEnd, ok := getPortBounds(portRange)
+	if !ok {
+		return false
+	}
+
+	return rangeStart <= start && end <= rangeEnd
+}
+
+type portRun struct {
+	start   int
+	end     int
+	count   int
+	members []types.SpecPort
+}
+
+// collapsePorts returns the ports with the runs of at least minPorts ports, each no farther than maxGap from
+// the previous one, collapsed into a port range, the ports of a protocol are expected
+func collapsePorts(ports []types.SpecPort, minPorts, maxGap int) []types.SpecPort {
+	if minPorts <= 0 || len(ports) < 2 {
+		return ports
+	}
+
+	results := []types.SpecPort{}
+	numbered := []types.SpecPort{}
+	for _, port := range ports {
+		if _, _, ok := getPortBounds(port); !ok {
+			results = append(results, port)
+			continue
+		}
+		numbered = append(numbered, port)
+	}
+
+	sort.Slice(numbered, func(i, j int) bool {
+		si, ei, _ := getPortBounds(numbered[i])
+		sj, ej, _ := getPortBounds(numbered[j])
+		if si == sj {
+			return ei < ej
+		}
+		return si < sj
+	})
+
+	runs := []portRun{}
+	for _, port := range numbered {
+		start, end, _ := getPortBounds(port)
+
+		if len(runs) > 0 && start-runs[len(runs)-1].end <= maxGap {
+			run := &runs[len(runs)-1]
+
+			// the ports already in the run are not counted again
+			if start > run.end {
+				run.count += end - start + 1
+			} else if end > run.end {
+				run.count += end - run.end
+			}
+			if end > run.end {
+				run.end = end
+			}
+
+			dup := false
+			for _, member := range run.members {
+				if member.Equal(port) {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				run.members = append(run.members, port)
+			}
+			continue
+		}
+
+		runs = append(runs, portRun{start: start, end: end, count: end - start + 1, members: []types.SpecPort{port}})
+	}
+
+	for _, run := range runs {
+		if len(run.members) > 1 && run.count >= minPorts {
+			results = append(results, types.SpecPort{
+				Port:     strconv.Itoa(run.start),
+				Protocol: run.members[0].Protocol,
+				EndPort:  run.end,
+			})
+		} else {
+			results = append(results, run.members...)
+		}
+	}
+
+	return results
+}
+
+// collapseProtocolPorts collapses the dense ports of each protocol into port ranges
+func collapseProtocolPorts(ports []types.SpecPort) []types.SpecPort {
+	minPorts := cfg.GetCfgNetworkPortRangeMinPorts()
+	if minPorts <= 0 {
+		return ports
+	}
+	maxGap := cfg.GetCfgNetworkPortRangeMaxGap()
+
+	protocols := []string{}
+	protocolPorts := map[string][]types.SpecPort{}
+	for _, port := range ports {
+		if _, ok := protocolPorts[port.Protocol]; !ok {
+			protocols = append(protocols, port.Protocol)
+		}
+		protocolPorts[port.Protocol] = append(protocolPorts[port.Protocol], port)
+	}
+
+	results := []types.SpecPort{}
+	for _, protocol := range protocols {
+		results = append(results, collapsePorts(protocolPorts[protocol], minPorts, maxGap)...)
+	}
+
+	return results
+}
+
+// getIngressPeerKey returns the ingress rule without its ports, the rules of the same peer share the key
+func getIngressPeerKey(rule types.Ingress) string {
+	rule.ToPorts = nil
+	rule.ToHTTPs = nil
+	b, _ := json.Marshal(rule)
+	return string(b)
+}
+
+// getEgressPeerKey returns the egress rule without its ports, the rules of the same peer share the key
+func getEgressPeerKey(rule types.Egress) string {
+	rule.ToPorts = nil
+	rule.ToHTTPs = nil
+	b, _ := json.Marshal(rule)
+	return string(b)
+}
+
+// isPortRangeRule returns true if the rule is a L4 rule of a single port, which can be in a port range
+func isPortRangeRule(rule types.L47Rule) bool {
+	ports := rule.GetPortRules()
+	if len(rule.GetHTTPRules()) > 0 || len(ports) != 1 {
+		return false
+	}
+
+	_, _, ok := getPortBounds(ports[0])
+	return ok
+}
+
+func collapseIngressPortRanges(rules []types.Ingress, minPorts, maxGap int) []types.Ingress {
+	results := []types.Ingress{}
+
+	groupKeys := []string{}
+	groupRules := map[string]types.Ingress{}
+	groupPorts := map[string][]types.SpecPort{}
+
+	for _, rule := range rules {
+		if !isPortRangeRule(rule) {
+			results = append(results, rule)
+			continue
+		}
+
+		key := getIngressPeerKey(rule) + rule.ToPorts[0].Protocol
+		if _, ok := groupRules[key]; !ok {
+			groupKeys = append(groupKeys, key)
+			groupRules[key] = rule
+		}
+		groupPorts[key] = append(groupPorts[key], rule.ToPorts[0])
+	}
+
+	for _, key := range groupKeys {
+		for _, port := range collapsePorts(groupPorts[key], minPorts, maxGap) {
+			rule := groupRules[key]
+			rule.ToPorts = []types.SpecPort{port}
+			results = append(results, rule)
+		}
+	}
+
+	return results
+}
+
+func collapseEgressPortRanges(rules []types.Egress, minPorts, maxGap int) []types.Egress {
+	results := []types.Egress{}
+
+	groupKeys := []string{}
+	groupRules := map[string]types.Egress{}
+	groupPorts := map[string][]types.SpecPort{}
+
+	for _, rule := range rules {
+		if !isPortRangeRule(rule) {
+			results = append(results, rule)
+			continue
+		}
+
+		key := getEgressPeerKey(rule) + rule.ToPorts[0].Protocol
+		if _, ok := groupRules[key]; !ok {
+			groupKeys = append(groupKeys, key)
+			groupRules[key] = rule
+		}
+		groupPorts[key] = append(groupPorts[key], rule.ToPorts[0])
+	}
+
+	for _, key := range groupKeys {
+		for _, port := range collapsePorts(groupPorts[key], minPorts, maxGap) {
+			rule := groupRules[key]
+			rule.ToPorts = []types.SpecPort{port}
+			results = append(results, rule)
+		}
+	}
+
+	return results
+}
+
+// collapsePolicyPortRanges collapses the dense ports of the rules of the same peer into port ranges,
+// a rule keeps a single port or port range as the rules of the discovered policies do
+func collapsePolicyPortRanges(policy types.KnoxNetworkPolicy) types.KnoxNetworkPolicy {
+	minPorts := cfg.GetCfgNetworkPortRangeMinPorts()
+	if minPorts <= 0 {
+		return policy
+	}
+	maxGap := cfg.GetCfgNetworkPortRangeMaxGap()
+
+	if len(policy.Spec.Ingress) > 0 {
+		policy.Spec.Ingress = collapseIngressPortRanges(policy.Spec.Ingress, minPorts, maxGap)
+	}
+	if len(policy.Spec.Egress) > 0 {
+		policy.Spec.Egress = collapseEgressPortRanges(policy.Spec.Egress, minPorts, maxGap)
+	}
+
+	return policy
+}
+
+// isIngressRuleCovered returns true if the L4 rule is allowed by the covering rule, a port within its port range
+func isIngressRuleCovered(rule, covering types.Ingress) bool {
+	if len(rule.ToHTTPs) > 0 || len(covering.ToHTTPs) > 0 || len(rule.ToPorts) != len(covering.ToPorts) {
+		return false
+	}
+
+	for i := range rule.ToPorts {
+		if !isPortInRange(rule.ToPorts[i], covering.ToPorts[i]) {
+			return false
+		}
+	}
+
+	return getIngressPeerKey(rule) == getIngressPeerKey(covering)
+}
+
+// isEgressRuleCovered returns true if the L4 rule is allowed by the covering rule, a port within its port range
+// and a domain name matching its fqdn pattern
+func isEgressRuleCovered(rule, covering types.Egress) bool {
+	if len(rule.ToHTTPs) > 0 || len(covering.ToHTTPs) > 0 || len(rule.ToPorts) != len(covering.ToPorts) {
+		return false
+	}
+
+	for i := range rule.ToPorts {
+		if !isPortInRange(rule.ToPorts[i], covering.ToPorts[i]) {
+			return false
+		}
+	}
+
+	if len(rule.ToFQDNs) > 0 || len(covering.ToFQDNs) > 0 {
+		if len(rule.ToFQDNs) == 0 || len(covering.ToFQDNs) == 0 || !isFQDNsCovered(rule.ToFQDNs, covering.ToFQDNs) {
+			return false
+		}
+		rule.ToFQDNs, covering.ToFQDNs = nil, nil
+	}
+
+	return getEgressPeerKey(rule) == getEgressPeerKey(covering)
+}
+
+// getCoveringRuleKeys returns the keys of the rules of the policy the rules of the discovered policy are
+// merged into, a rule covered by a port range or a fqdn pattern is observed as the covering rule
+func getCoveringRuleKeys(policy types.KnoxNetworkPolicy, discoveredPolicy types.KnoxNetworkPolicy) []string {
+	keys := []string{}
+	existKeys := getPolicyRuleKeys(policy)
+
+	for _, discovered := range discoveredPolicy.Spec.Ingress {
+		key := getRuleKey(PolicyTypeIngress, discovered)
+		if !libs.ContainsElement(existKeys, key) {
+			for _, rule := range policy.Spec.Ingress {
+				if isIngressRuleCovered(discovered, rule) {
+					key = getRuleKey(PolicyTypeIngress, rule)
+					break
+				}
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	for _, discovered := range discoveredPolicy.Spec.Egress {
+		key := getRuleKey(PolicyTypeEgress, discovered)
+		if !libs.ContainsElement(existKeys, key) {
+			for _, rule := range policy.Spec.Egress {
+				if isEgressRuleCovered(discovered, rule) {
+					key = getRuleKey(PolicyTypeEgress, rule)
+					break
+				}
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// getNamedPort returns the container port name of the pod for the port if the named ports are preferred
+func getNamedPort(podName string, pods []types.Pod, port types.SpecPort) types.SpecPort {
+	if !cfg.GetCfgNetworkNamedPorts() {
+		return port
+	}
+
+	for _, pod := range pods {
+		if pod.PodName != podName {
+			continue
+		}
+
+		for _, namedPort := range pod.NamedPorts {
+			if strconv.Itoa(namedPort.Port) == port.Port && strings.EqualFold(namedPort.Protocol, port.Protocol) {
+				port.Port = namedPort.Name
+				return port
+			}
+		}
+		break
+	}
+
+	return port
+}
@@ -0,0 +1,121 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPortInRange(t *testing.T) {
+	portRange := types.SpecPort{Port: "30000", Protocol: "TCP", EndPort: 30100}
+
+	assert.True(t, isPortInRange(types.SpecPort{Port: "30050", Protocol: "TCP"}, portRange))
+	assert.True(t, isPortInRange(types.SpecPort{Port: "30000", Protocol: "TCP", EndPort: 30100}, portRange))
+	assert.False(t, isPortInRange(types.SpecPort{Port: "30050", Protocol: "UDP"}, portRange))
+	assert.False(t, isPortInRange(types.SpecPort{Port: "30090", Protocol: "TCP", EndPort: 30200}, portRange))
+	assert.False(t, isPortInRange(types.SpecPort{Port: "http", Protocol: "TCP"}, portRange))
+	assert.True(t, isPortInRange(types.SpecPort{Port: "http", Protocol: "TCP"}, types.SpecPort{Port: "http", Protocol: "TCP"}))
+}
+
+func TestCollapsePorts(t *testing.T) {
+	ports := []types.SpecPort{
+		{Port: "30010", Protocol: "TCP"},
+		{Port: "30001", Protocol: "TCP"},
+		{Port: "30005", Protocol: "TCP"},
+		{Port: "80", Protocol: "TCP"},
+		{Port: "http", Protocol: "TCP"},
+	}
+
+	// the ports below the threshold are kept
+	assert.Equal(t, ports, collapsePorts(ports, 0, 10))
+
+	collapsed := collapsePorts(ports, 3, 10)
+	assert.Equal(t, []types.SpecPort{
+		{Port: "http", Protocol: "TCP"},
+		{Port: "80", Protocol: "TCP"},
+		{Port: "30001", Protocol: "TCP", EndPort: 30010},
+	}, collapsed)
+
+	// a port observed again is merged into the range
+	collapsed = collapsePorts([]types.SpecPort{
+		{Port: "30001", Protocol: "TCP", EndPort: 30010},
+		{Port: "30020", Protocol: "TCP"},
+	}, 3, 10)
+	assert.Equal(t, []types.SpecPort{{Port: "30001", Protocol: "TCP", EndPort: 30020}}, collapsed)
+
+	// the ports too far apart are not a range
+	collapsed = collapsePorts([]types.SpecPort{
+		{Port: "30001", Protocol: "TCP"},
+		{Port: "30100", Protocol: "TCP"},
+		{Port: "30200", Protocol: "TCP"},
+	}, 3, 10)
+	assert.Equal(t, 3, len(collapsed))
+}
+
+func TestCollapsePolicyPortRanges(t *testing.T) {
+	cfgNetPolicy := cfg.CurrentCfg.ConfigNetPolicy
+	defer func() { cfg.CurrentCfg.ConfigNetPolicy = cfgNetPolicy }()
+
+	cfg.CurrentCfg.ConfigNetPolicy.NetPortRangeMinPorts = 3
+	cfg.CurrentCfg.ConfigNetPolicy.NetPortRangeMaxGap = 10
+
+	ftp := map[string]string{"app": "ftp"}
+	policy := types.KnoxNetworkPolicy{
+		Metadata: map[string]string{"type": PolicyTypeEgress},
+		Spec: types.Spec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "web"}},
+			Egress: []types.Egress{
+				{MatchLabels: ftp, ToPorts: []types.SpecPort{{Port: "21", Protocol: "TCP"}}},
+				{MatchLabels: ftp, ToPorts: []types.SpecPort{{Port: "30001", Protocol: "TCP"}}},
+				{MatchLabels: ftp, ToPorts: []types.SpecPort{{Port: "30004", Protocol: "TCP"}}},
+				{MatchLabels: ftp, ToPorts: []types.SpecPort{{Port: "30008", Protocol: "TCP"}}},
+				{MatchLabels: ftp, ToPorts: []types.SpecPort{{Port: "30002", Protocol: "TCP"}},
+					ToHTTPs: []types.SpecHTTP{{Method: "GET", Path: "/"}}},
+			},
+		},
+	}
+
+	collapsed := collapsePolicyPortRanges(policy)
+	assert.Equal(t, []types.Egress{
+		{MatchLabels: ftp, ToPorts: []types.SpecPort{{Port: "30002", Protocol: "TCP"}},
+			ToHTTPs: []types.SpecHTTP{{Method: "GET", Path: "/"}}},
+		{MatchLabels: ftp, ToPorts: []types.SpecPort{{Port: "21", Protocol: "TCP"}}},
+		{MatchLabels: ftp, ToPorts: []types.SpecPort{{Port: "30001", Protocol: "TCP", EndPort: 30008}}},
+	}, collapsed.Spec.Egress)
+
+	// the port within the range is allowed already, and observed as the range rule
+	discovered := policy
+	discovered.Spec.Egress = []types.Egress{{MatchLabels: ftp, ToPorts: []types.SpecPort{{Port: "30006", Protocol: "TCP"}}}}
+
+	merged, updated := mergeEgressPolicies(collapsed, []types.KnoxNetworkPolicy{discovered})
+	assert.False(t, updated)
+	assert.Equal(t, 3, len(merged.Spec.Egress))
+	assert.Equal(t, []string{getRuleKey(PolicyTypeEgress, collapsed.Spec.Egress[2])}, getCoveringRuleKeys(merged, discovered))
+
+	// the rules of the single ports are covered by the range rule, not the other way around
+	assert.True(t, isEgressRuleCovered(discovered.Spec.Egress[0], collapsed.Spec.Egress[2]))
+	assert.False(t, isEgressRuleCovered(collapsed.Spec.Egress[2], discovered.Spec.Egress[0]))
+	assert.False(t, isEgressRuleCovered(discovered.Spec.Egress[0], collapsed.Spec.Egress[1]))
+}
+
+func TestGetNamedPort(t *testing.T) {
+	cfgNetPolicy := cfg.CurrentCfg.ConfigNetPolicy
+	defer func() { cfg.CurrentCfg.ConfigNetPolicy = cfgNetPolicy }()
+
+	pods := []types.Pod{{
+		Namespace:  "default",
+		PodName:    "web-1",
+		NamedPorts: []types.ContainerPort{{Name: "http", Port: 8080, Protocol: "TCP"}},
+	}}
+	port := types.SpecPort{Port: "8080", Protocol: "TCP"}
+
+	cfg.CurrentCfg.ConfigNetPolicy.NetNamedPorts = false
+	assert.Equal(t, port, getNamedPort("web-1", pods, port))
+
+	cfg.CurrentCfg.ConfigNetPolicy.NetNamedPorts = true
+	assert.Equal(t, types.SpecPort{Port: "http", Protocol: "TCP"}, getNamedPort("web-1", pods, port))
+	assert.Equal(t, port, getNamedPort("web-2", pods, port))
+	assert.Equal(t, types.SpecPort{Port: "8080", Protocol: "UDP"}, getNamedPort("web-1", pods, types.SpecPort{Port: "8080", Protocol: "UDP"}))
+}
@@ -0,0 +1,192 @@
+package networkpolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/telemetry"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ================ //
+// == Rule Aging == //
+// ================ //
+
+// getRuleKey returns the key of the rule statistics, the http rules are merged into the rule
+// of the same peer and ports, so they are not part of the key
+func getRuleKey(ruleType string, rule interface{}) string {
+	switch r := rule.(type) {
+	case types.Ingress:
+		r.ToHTTPs = nil
+		rule = r
+	case types.Egress:
+		r.ToHTTPs = nil
+		rule = r
+	}
+
+	b, _ := json.Marshal(rule)
+
+	h := fnv.New32a()
+	_, _ = h.Write(b)
+	return fmt.Sprintf("%s-%08x", ruleType, h.Sum32())
+}
+
+func getPolicyRuleKeys(policy types.KnoxNetworkPolicy) []string {
+	keys := []string{}
+
+	for _, ingress := range policy.Spec.Ingress {
+		keys = append(keys, getRuleKey(PolicyTypeIngress, ingress))
+	}
+	for _, egress := range policy.Spec.Egress {
+		keys = append(keys, getRuleKey(PolicyTypeEgress, egress))
+	}
+
+	return keys
+}
+
+// markPolicyRulesSeen records the rules of the discovered policy as observed in the existing policy,
+// a rule within the port range of an existing rule is observed as that rule
+func markPolicyRulesSeen(policy *types.KnoxNetworkPolicy, discoveredPolicy types.KnoxNetworkPolicy, seenTime int64) {
+	policy.RuleStats = libs.MarkRulesSeen(policy.RuleStats, getCoveringRuleKeys(*policy, discoveredPolicy), seenTime)
+}
+
+// removeAgedRules returns the policy without the rules last seen before the deadline and the removed rules,
+// returns false if no rule is aged, or all of them are since an empty policy is not a tightened one
+func removeAgedRules(policy types.KnoxNetworkPolicy, deadline int64) (types.KnoxNetworkPolicy, []string, bool) {
+	defaultLastSeen := policy.UpdatedTime
+	if defaultLastSeen == 0 {
+		defaultLastSeen = policy.GeneratedTime
+	}
+
+	agedKeys := libs.GetAgedRuleKeys(policy.RuleStats, getPolicyRuleKeys(policy), defaultLastSeen, deadline)
+	if len(agedKeys) == 0 {
+		return policy, nil, false
+	}
+
+	removedRules := []string{}
+	ruleStats := map[string]types.RuleStat{}
+
+	ingress := []types.Ingress{}
+	for _, rule := range policy.Spec.Ingress {
+		key := getRuleKey(PolicyTypeIngress, rule)
+		if libs.ContainsElement(agedKeys, key) {
+			b, _ := json.Marshal(rule)
+			removedRules = append(removedRules, PolicyTypeIngress+" "+string(b))
+			continue
+		}
+
+		ingress = append(ingress, rule)
+		if stat, ok := policy.RuleStats[key]; ok {
+			ruleStats[key] = stat
+		}
+	}
+
+	egress := []types.Egress{}
+	for _, rule := range policy.Spec.Egress {
+		key := getRuleKey(PolicyTypeEgress, rule)
+		if libs.ContainsElement(agedKeys, key) {
+			b, _ := json.Marshal(rule)
+			removedRules = append(removedRules, PolicyTypeEgress+" "+string(b))
+			continue
+		}
+
+		egress = append(egress, rule)
+		if stat, ok := policy.RuleStats[key]; ok {
+			ruleStats[key] = stat
+		}
+	}
+
+	if len(ingress) == 0 && len(egress) == 0 {
+		return policy, nil, false
+	}
+
+	metadata := map[string]string{}
+	for k, v := range policy.Metadata {
+		metadata[k] = v
+	}
+	// the tightened version is reviewed again
+	metadata["review_state"] = libs.ReviewStateDiscovered
+
+	policy.Metadata = metadata
+	policy.Outdated = ""
+	policy.RuleStats = ruleStats
+
+	policy.Spec.Ingress = nil
+	if len(ingress) > 0 {
+		policy.Spec.Ingress = ingress
+	}
+	policy.Spec.Egress = nil
+	if len(egress) > 0 {
+		policy.Spec.Egress = egress
+	}
+
+	return policy, removedRules, true
+}
+
+// AgeNetworkPolicyRules proposes a tightened version of the latest policies of the cluster without the rules
+// not observed for the configured number of days, the tightened version outdates the policy and is reviewed
+// again, the removed rules are listed in the change record
+func AgeNetworkPolicyRules(clusterName string) {
+	agingDays := cfg.GetCfgNetworkRuleAgingDays()
+	if agingDays <= 0 {
+		return
+	}
+
+	now := libs.ConvertStrToUnixTime("now")
+	deadline := now - int64(agingDays)*24*60*60
+
+	// the names of the outdated versions are taken as well
+	policies := libs.GetNetworkPolicies(CfgDB, clusterName, "", "", "", "")
+
+	policyNamesMap := map[string]bool{}
+	for _, policy := range policies {
+		policyNamesMap[policy.Metadata["name"]] = true
+	}
+
+	for _, policy := range policies {
+		if policy.Metadata["status"] != "latest" || libs.GetReviewState(policy.Metadata) == libs.ReviewStateRejected {
+			continue
+		}
+
+		agedPolicy, removedRules, ok := removeAgedRules(policy, deadline)
+		if !ok {
+			continue
+		}
+
+		agedPolicy = GeneratePolicyName(policyNamesMap, agedPolicy, clusterName)
+
+		libs.InsertNetworkPolicies(CfgDB, []types.KnoxNetworkPolicy{agedPolicy})
+		telemetry.PolicyEvent(telemetry.EventPolicyCreated, telemetry.PolicyTypeNetwork,
+			clusterName, agedPolicy.Metadata["namespace"], agedPolicy.Metadata["name"])
+
+		// the approved or applied version keeps its spec and state to roll back to, the apply of
+		// the tightened version replaces it in the cluster
+		reviewState := libs.GetReviewState(policy.Metadata)
+		if reviewState != libs.ReviewStateApproved && reviewState != libs.ReviewStateApplied {
+			reviewState = libs.ReviewStateSuperseded
+		}
+		libs.UpdateOutdatedNetworkPolicyReview(CfgDB, policy.Metadata["name"], agedPolicy.Metadata["name"], reviewState)
+		telemetry.PolicyEvent(telemetry.EventPolicyOutdated, telemetry.PolicyTypeNetwork,
+			clusterName, policy.Metadata["namespace"], policy.Metadata["name"])
+
+		change := types.PolicyChange{
+			PolicyType:    "network",
+			ClusterName:   clusterName,
+			Namespace:     policy.Metadata["namespace"],
+			Name:          policy.Metadata["name"],
+			NewPolicyName: agedPolicy.Metadata["name"],
+			Reason:        libs.PolicyChangeReasonAged,
+			RemovedRules:  removedRules,
+			ChangedTime:   now,
+		}
+		if err := libs.InsertPolicyChange(CfgDB, change); err != nil {
+			log.Error().Msg(err.Error())
+		}
+
+		log.Info().Msgf("Network policy [%s] tightened to [%s], %d rules not seen for %d days",
+			policy.Metadata["name"], agedPolicy.Metadata["name"], len(removedRules), agingDays)
+	}
+}
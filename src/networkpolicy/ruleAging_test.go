@@ -0,0 +1,104 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRuleKey(t *testing.T) {
+	rule := types.Egress{
+		MatchLabels: map[string]string{"app": "redis"},
+		ToPorts:     []types.SpecPort{{Port: "6379", Protocol: "tcp"}},
+	}
+
+	// the http rules merged into the rule keep its key
+	merged := rule
+	merged.ToHTTPs = []types.SpecHTTP{{Method: "GET", Path: "/"}}
+	assert.Equal(t, getRuleKey(PolicyTypeEgress, rule), getRuleKey(PolicyTypeEgress, merged))
+
+	other := rule
+	other.ToPorts = []types.SpecPort{{Port: "6380", Protocol: "tcp"}}
+	assert.NotEqual(t, getRuleKey(PolicyTypeEgress, rule), getRuleKey(PolicyTypeEgress, other))
+}
+
+func TestRemoveAgedRules(t *testing.T) {
+	redis := types.Egress{
+		MatchLabels: map[string]string{"app": "redis"},
+		ToPorts:     []types.SpecPort{{Port: "6379", Protocol: "tcp"}},
+	}
+	dns := types.Egress{
+		MatchLabels: map[string]string{"k8s-app": "kube-dns"},
+		ToPorts:     []types.SpecPort{{Port: "53", Protocol: "udp"}},
+	}
+
+	policy := types.KnoxNetworkPolicy{
+		Metadata: map[string]string{
+			"name":         "autopol-egress-default-web-1a2b3c4d",
+			"namespace":    "default",
+			"type":         PolicyTypeEgress,
+			"status":       "latest",
+			"review_state": libs.ReviewStateApplied,
+		},
+		Spec: types.Spec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "web"}},
+			Egress:   []types.Egress{redis, dns},
+		},
+		GeneratedTime: 100,
+		UpdatedTime:   100,
+		RuleStats: map[string]types.RuleStat{
+			getRuleKey(PolicyTypeEgress, redis): {FirstSeen: 100, LastSeen: 100, Hits: 1},
+			getRuleKey(PolicyTypeEgress, dns):   {FirstSeen: 100, LastSeen: 1000, Hits: 9},
+		},
+	}
+
+	aged, removed, ok := removeAgedRules(policy, 500)
+	assert.True(t, ok)
+	assert.Equal(t, []types.Egress{dns}, aged.Spec.Egress)
+	assert.Equal(t, 1, len(removed))
+	assert.Contains(t, removed[0], `"6379"`)
+	assert.Equal(t, libs.ReviewStateDiscovered, aged.Metadata["review_state"])
+	assert.Equal(t, 1, len(aged.RuleStats))
+	// the tightened version is named when it is inserted as the new version
+	assert.Equal(t, "autopol-egress-default-web-1a2b3c4d", aged.Metadata["name"])
+	assert.Equal(t, "latest", aged.Metadata["status"])
+	// the latest policy is left as is
+	assert.Equal(t, libs.ReviewStateApplied, policy.Metadata["review_state"])
+
+	// nothing to tighten
+	_, _, ok = removeAgedRules(policy, 50)
+	assert.False(t, ok)
+
+	// all the rules are aged, an empty policy is not proposed
+	_, _, ok = removeAgedRules(policy, 5000)
+	assert.False(t, ok)
+}
+
+func TestUpdateDuplicatedPolicyRuleStats(t *testing.T) {
+	redis := types.Egress{
+		MatchLabels: map[string]string{"app": "redis"},
+		ToPorts:     []types.SpecPort{{Port: "6379", Protocol: "tcp"}},
+	}
+
+	discovered := types.KnoxNetworkPolicy{
+		Kind: types.KindKnoxNetworkPolicy,
+		Metadata: map[string]string{
+			"namespace": "default",
+			"type":      PolicyTypeEgress,
+		},
+		Spec: types.Spec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "web"}},
+			Egress:   []types.Egress{redis},
+		},
+	}
+
+	newPolicies := UpdateDuplicatedPolicy(nil, []types.KnoxNetworkPolicy{discovered}, nil, "default")
+	assert.Equal(t, 1, len(newPolicies))
+
+	stat, ok := newPolicies[0].RuleStats[getRuleKey(PolicyTypeEgress, redis)]
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), stat.Hits)
+	assert.Equal(t, stat.FirstSeen, stat.LastSeen)
+}
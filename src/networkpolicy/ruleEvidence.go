@@ -0,0 +1,169 @@
+package networkpolicy
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// =================== //
+// == Rule Evidence == //
+// =================== //
+
+func getEvidenceEndpoint(podName, ip string) string {
+	if podName != "" {
+		return podName
+	}
+	return ip
+}
+
+// getPolicyRules returns the ingress and egress rules of the policy by rule key
+func getPolicyRules(policy types.KnoxNetworkPolicy) ([]string, map[string]string) {
+	keys := []string{}
+	rules := map[string]string{}
+
+	for _, ingress := range policy.Spec.Ingress {
+		key := getRuleKey(PolicyTypeIngress, ingress)
+		ingress.ToHTTPs = nil
+		b, _ := json.Marshal(ingress)
+
+		keys = append(keys, key)
+		rules[key] = PolicyTypeIngress + " " + string(b)
+	}
+	for _, egress := range policy.Spec.Egress {
+		key := getRuleKey(PolicyTypeEgress, egress)
+		egress.ToHTTPs = nil
+		b, _ := json.Marshal(egress)
+
+		keys = append(keys, key)
+		rules[key] = PolicyTypeEgress + " " + string(b)
+	}
+
+	return keys, rules
+}
+
+// addNetworkRuleEvidences records the flows as the evidences of the rules they are discovered into,
+// a flow turns into a rule of the same peer and port as the merged rule, so it shares the rule key,
+// the flows without their own time are seen at the time of the run
+func addNetworkRuleEvidences(evidences map[string]types.RuleEvidence, clusterName string, networkLogs []types.KnoxNetworkLog, pods []types.Pod, seenTime int64) {
+	for _, networkLog := range networkLogs {
+		ingress, egress := convertKnoxNetworkLogToKnoxNetworkPolicy(&networkLog, pods)
+
+		sampleLog, err := json.Marshal(networkLog)
+		if err != nil {
+			log.Error().Msg(err.Error())
+			continue
+		}
+		logTime := libs.GetRuleEvidenceSeenTime(networkLog.Timestamp, seenTime)
+
+		for _, policy := range []*types.KnoxNetworkPolicy{ingress, egress} {
+			if policy == nil {
+				continue
+			}
+
+			keys, rules := getPolicyRules(*policy)
+			for _, key := range keys {
+				libs.AddRuleEvidence(evidences, types.RuleEvidence{
+					PolicyType:  "network",
+					ClusterName: clusterName,
+					Namespace:   policy.Metadata["namespace"],
+					Labels:      strings.Join(getLabelArrayFromMap(policy.Spec.Selector.MatchLabels), ","),
+					RuleKey:     key,
+					Rule:        rules[key],
+					FirstSeen:   logTime,
+					LastSeen:    logTime,
+					Count:       1,
+					SrcPods:     []string{getEvidenceEndpoint(networkLog.SrcPodName, networkLog.SrcIP)},
+					DstPods:     []string{getEvidenceEndpoint(networkLog.DstPodName, networkLog.DstIP)},
+					SampleLog:   string(sampleLog),
+				})
+			}
+		}
+	}
+}
+
+func storeRuleEvidences(evidences map[string]types.RuleEvidence) {
+	records := []types.RuleEvidence{}
+	for _, evidence := range evidences {
+		records = append(records, evidence)
+	}
+
+	if err := libs.UpsertRuleEvidences(CfgDB, records); err != nil {
+		log.Error().Msg(err.Error())
+	}
+}
+
+// isEvidenceCovered returns true if the rule recorded by the evidence is covered by the rule of the policy
+func isEvidenceCovered(evidence types.RuleEvidence, rule interface{}) bool {
+	switch r := rule.(type) {
+	case types.Ingress:
+		observed := types.Ingress{}
+		if !strings.HasPrefix(evidence.Rule, PolicyTypeIngress+" ") ||
+			json.Unmarshal([]byte(strings.TrimPrefix(evidence.Rule, PolicyTypeIngress+" ")), &observed) != nil {
+			return false
+		}
+		r.ToHTTPs = nil
+		return isIngressRuleCovered(observed, r)
+	case types.Egress:
+		observed := types.Egress{}
+		if !strings.HasPrefix(evidence.Rule, PolicyTypeEgress+" ") ||
+			json.Unmarshal([]byte(strings.TrimPrefix(evidence.Rule, PolicyTypeEgress+" ")), &observed) != nil {
+			return false
+		}
+		r.ToHTTPs = nil
+		return isEgressRuleCovered(observed, r)
+	}
+
+	return false
+}
+
+// GetNetworkPolicyRuleEvidences returns the evidences of the rules of the policy, a rule without
+// any evidence, e.g. seeded by the policies in the cluster, has no observations
+func GetNetworkPolicyRuleEvidences(policy types.KnoxNetworkPolicy) ([]types.RuleEvidence, error) {
+	labels := strings.Join(getLabelArrayFromMap(policy.Spec.Selector.MatchLabels), ",")
+
+	stored, err := libs.GetRuleEvidences(CfgDB, types.RuleEvidence{
+		PolicyType:  "network",
+		ClusterName: policy.Metadata["cluster_name"],
+		Namespace:   policy.Metadata["namespace"],
+		Labels:      labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	evidences := []types.RuleEvidence{}
+
+	// the flows are recorded by the rules of a single port and domain name, they are merged
+	// into the rule of their port range or fqdn pattern
+	policyRules := map[string]interface{}{}
+	for _, ingress := range policy.Spec.Ingress {
+		policyRules[getRuleKey(PolicyTypeIngress, ingress)] = ingress
+	}
+	for _, egress := range policy.Spec.Egress {
+		policyRules[getRuleKey(PolicyTypeEgress, egress)] = egress
+	}
+
+	keys, rules := getPolicyRules(policy)
+	for _, key := range keys {
+		evidence := types.RuleEvidence{
+			PolicyType:  "network",
+			ClusterName: policy.Metadata["cluster_name"],
+			Namespace:   policy.Metadata["namespace"],
+			Labels:      labels,
+			RuleKey:     key,
+		}
+		for _, record := range stored {
+			if record.RuleKey == key || isEvidenceCovered(record, policyRules[key]) {
+				evidence = libs.MergeRuleEvidence(evidence, record)
+			}
+		}
+		evidence.Rule = rules[key]
+
+		evidences = append(evidences, evidence)
+	}
+
+	return evidences, nil
+}
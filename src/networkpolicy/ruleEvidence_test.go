@@ -0,0 +1,54 @@
+package networkpolicy
+
+import (
+	"strings"
+	"testing"
+
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddNetworkRuleEvidences(t *testing.T) {
+	pods := []types.Pod{
+		{Namespace: "default", PodName: "web-1", Labels: []string{"app=web"}},
+		{Namespace: "default", PodName: "web-2", Labels: []string{"app=web"}},
+		{Namespace: "default", PodName: "redis-1", Labels: []string{"app=redis"}},
+	}
+
+	logs := []types.KnoxNetworkLog{
+		{FlowID: 1, SrcNamespace: "default", SrcPodName: "web-1", DstNamespace: "default", DstPodName: "redis-1",
+			Protocol: 6, DstPort: 6379},
+		{FlowID: 2, SrcNamespace: "default", SrcPodName: "web-2", DstNamespace: "default", DstPodName: "redis-1",
+			Protocol: 6, DstPort: 6379, Timestamp: 250},
+	}
+
+	evidences := map[string]types.RuleEvidence{}
+	addNetworkRuleEvidences(evidences, "default", logs, pods, 300)
+
+	// the egress rule of the web pods and the ingress rule of the redis pod
+	assert.Equal(t, 2, len(evidences))
+
+	policies := DiscoverNetworkPolicy("default", logs, nil, pods)
+	for _, policy := range policies {
+		keys, _ := getPolicyRules(policy)
+		assert.Equal(t, 1, len(keys))
+
+		labels := strings.Join(getLabelArrayFromMap(policy.Spec.Selector.MatchLabels), ",")
+
+		found := false
+		for _, evidence := range evidences {
+			if evidence.Labels == labels && evidence.RuleKey == keys[0] {
+				found = true
+				assert.Equal(t, int64(2), evidence.Count)
+				// the flow time, or the run time of a flow without one
+				assert.Equal(t, int64(250), evidence.FirstSeen)
+				assert.Equal(t, int64(300), evidence.LastSeen)
+				assert.Equal(t, []string{"web-1", "web-2"}, evidence.SrcPods)
+				assert.Equal(t, []string{"redis-1"}, evidence.DstPods)
+				// the first flow of the run is the sample
+				assert.Contains(t, evidence.SampleLog, `"flow_id":1`)
+			}
+		}
+		assert.True(t, found)
+	}
+}
@@ -0,0 +1,510 @@
+package observability
+
+import (
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	opb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/observability"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	AnomalyNewBinary        = "new-binary"
+	AnomalyNewEgress        = "new-egress"
+	AnomalyNewListeningPort = "new-listening-port"
+)
+
+const (
+	// OpProcess the kubearmor operation of the executed binaries, also the set type of their WPFS sets
+	OpProcess = "Process"
+
+	// AnomalySubscriberBuffer alerts buffered per gRPC subscriber, the alerts of a slow subscriber are dropped
+	AnomalySubscriberBuffer = 100
+
+	// AnomalyAlertedLimit max number of alerted anomalies remembered to not alert them again
+	AnomalyAlertedLimit = 10000
+)
+
+var (
+	AnomalySubscribers      map[chan types.AnomalyAlert]struct{}
+	AnomalySubscribersMutex *sync.Mutex
+
+	// AnomalyAlerted key: type + workload + resource
+	AnomalyAlerted      map[string]bool
+	AnomalyAlertedMutex *sync.Mutex
+
+	AnomalyPublisher      anomalyPublisher
+	AnomalyPublisherMutex *sync.Mutex
+)
+
+func init() {
+	AnomalySubscribers = map[chan types.AnomalyAlert]struct{}{}
+	AnomalySubscribersMutex = &sync.Mutex{}
+	AnomalyAlerted = map[string]bool{}
+	AnomalyAlertedMutex = &sync.Mutex{}
+	AnomalyPublisherMutex = &sync.Mutex{}
+}
+
+func initAnomaly() {
+	anomalyCfg := config.GetCfgObservabilityAnomaly()
+	if !anomalyCfg.Enable || anomalyCfg.PublisherDriver == "" {
+		return
+	}
+
+	publisher, err := newAnomalyPublisher(anomalyCfg)
+	if err != nil {
+		log.Error().Msgf("failed creating anomaly publisher err=%s", err.Error())
+		return
+	}
+	AnomalyPublisherMutex.Lock()
+	AnomalyPublisher = publisher
+	AnomalyPublisherMutex.Unlock()
+	log.Info().Msgf("Anomaly alerts published to %s topic %s", anomalyCfg.PublisherDriver, anomalyCfg.PublisherTopic)
+}
+
+// closeAnomalyPublisher flushes and closes the anomaly publisher, the later alerts are not published
+func closeAnomalyPublisher() {
+	AnomalyPublisherMutex.Lock()
+	defer AnomalyPublisherMutex.Unlock()
+
+	if AnomalyPublisher != nil {
+		AnomalyPublisher.Close()
+		AnomalyPublisher = nil
+	}
+}
+
+// =================== //
+// == Label Helpers == //
+// =================== //
+
+func isLabelSubset(subset, labels []string) bool {
+	for _, label := range subset {
+		if !libs.ContainsElement(labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+func getPeerNamespace(matchLabels map[string]string, namespace string) string {
+	if ns, ok := matchLabels["k8s:io.kubernetes.pod.namespace"]; ok {
+		return ns
+	} else if ns, ok := matchLabels["io.kubernetes.pod.namespace"]; ok {
+		return ns
+	}
+	return namespace
+}
+
+// ======================= //
+// == System Anomalies  == //
+// ======================= //
+
+// learnedProcessSet the binaries executed by a workload, of one WPFS set
+type learnedProcessSet struct {
+	selector libs.LabelSelector
+	paths    []string
+}
+
+// getLearnedProcessSets returns the learned process sets, key: namespace + container name
+func getLearnedProcessSets() map[string][]learnedProcessSet {
+	results := map[string][]learnedProcessSet{}
+
+	res, _, err := libs.GetWorkloadProcessFileSet(config.GetCfgDB(), types.WorkloadProcessFileSet{SetType: OpProcess})
+	if err != nil {
+		log.Error().Msgf("could not fetch WPFS err=%s", err.Error())
+		return results
+	}
+
+	for wpfs, paths := range res {
+		selector, err := libs.ParseLabelSelector(wpfs.Labels)
+		if err != nil {
+			log.Error().Msgf("invalid WPFS labels %s err=%s", wpfs.Labels, err.Error())
+			continue
+		}
+
+		key := wpfs.Namespace + types.RecordSeparator + wpfs.ContainerName
+		results[key] = append(results[key], learnedProcessSet{selector: selector, paths: paths})
+	}
+
+	return results
+}
+
+// isPathLearned checks the path against the learned paths, a learned dir covers the paths under it
+func isPathLearned(path string, paths []string) bool {
+	for _, learned := range paths {
+		if learned == path || (strings.HasSuffix(learned, "/") && strings.HasPrefix(path, learned)) {
+			return true
+		}
+	}
+	return false
+}
+
+// findSystemAnomalies returns the binaries not in the learned process sets of their workloads,
+// the workloads without a learned process set are skipped
+func findSystemAnomalies(kubearmorLogs []types.KubeArmorLog, processSets map[string][]learnedProcessSet) []types.AnomalyAlert {
+	alerts := []types.AnomalyAlert{}
+
+	for _, kubearmorLog := range kubearmorLogs {
+		if kubearmorLog.Operation != OpProcess || kubearmorLog.NamespaceName == "" || kubearmorLog.PodName == "" {
+			continue
+		}
+
+		fields := strings.Fields(kubearmorLog.Resource)
+		if len(fields) == 0 || !filepath.IsAbs(fields[0]) {
+			continue
+		}
+		binary := fields[0]
+
+		labels := strings.Split(kubearmorLog.Labels, ",")
+		learned, known := false, false
+		for _, set := range processSets[kubearmorLog.NamespaceName+types.RecordSeparator+kubearmorLog.ContainerName] {
+			if !set.selector.Matches(labels) {
+				continue
+			}
+			learned = true
+			if isPathLearned(binary, set.paths) {
+				known = true
+				break
+			}
+		}
+		if !learned || known {
+			continue
+		}
+
+		alerts = append(alerts, types.AnomalyAlert{
+			Type:          AnomalyNewBinary,
+			ClusterName:   kubearmorLog.ClusterName,
+			Namespace:     kubearmorLog.NamespaceName,
+			PodName:       kubearmorLog.PodName,
+			ContainerName: kubearmorLog.ContainerName,
+			Labels:        kubearmorLog.Labels,
+			Source:        kubearmorLog.Source,
+			Resource:      binary,
+			Message:       "new binary " + binary + " executed by " + kubearmorLog.Source,
+			Time:          kubearmorLog.UpdatedTime,
+		})
+	}
+
+	return alerts
+}
+
+// DetectSystemAnomalies alerts on the new binaries of the system logs seen for the first time
+func DetectSystemAnomalies(kubearmorLogs []types.KubeArmorLog) {
+	if !config.GetCfgObservabilityAnomaly().Enable || len(kubearmorLogs) == 0 {
+		return
+	}
+
+	emitAnomalyAlerts(findSystemAnomalies(kubearmorLogs, getLearnedProcessSets()))
+}
+
+// ======================== //
+// == Network Anomalies  == //
+// ======================== //
+
+func getFlowPort(ciliumLog types.CiliumLog) (string, uint32) {
+	if ciliumLog.L4TCPDestinationPort != 0 {
+		return "TCP", ciliumLog.L4TCPDestinationPort
+	} else if ciliumLog.L4UDPDestinationPort != 0 {
+		return "UDP", ciliumLog.L4UDPDestinationPort
+	}
+	return "", 0
+}
+
+func isPortAllowed(toPorts []types.SpecPort, protocol string, port uint32) bool {
+	if len(toPorts) == 0 {
+		return true
+	}
+
+	for _, toPort := range toPorts {
+		if toPort.Protocol != "" && !strings.EqualFold(toPort.Protocol, protocol) {
+			continue
+		}
+		if toPort.Port == "" || toPort.Port == strconv.Itoa(int(port)) {
+			return true
+		}
+	}
+	return false
+}
+
+// getSelectingPolicies returns the policies selecting the workload
+func getSelectingPolicies(policies []types.KnoxNetworkPolicy, namespace string, labels []string) []types.KnoxNetworkPolicy {
+	results := []types.KnoxNetworkPolicy{}
+	for _, policy := range policies {
+		if policy.Metadata["namespace"] == namespace && libs.LabelSelectorFromMap(policy.Spec.Selector.MatchLabels).Matches(labels) {
+			results = append(results, policy)
+		}
+	}
+	return results
+}
+
+func isIPInCIDRs(ip string, toCIDRs []types.SpecCIDR) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, toCIDR := range toCIDRs {
+		for _, cidr := range toCIDR.CIDRs {
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.Contains(addr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isEgressCovered checks if an egress rule of the policies allows the flow
+func isEgressCovered(policies []types.KnoxNetworkPolicy, ciliumLog types.CiliumLog) bool {
+	protocol, port := getFlowPort(ciliumLog)
+	dstLabels := strings.Split(ciliumLog.DestinationLabels, ",")
+
+	for _, policy := range policies {
+		namespace := policy.Metadata["namespace"]
+
+		for _, egress := range policy.Spec.Egress {
+			if !isPortAllowed(egress.ToPorts, protocol, port) {
+				continue
+			}
+
+			for _, toService := range egress.ToServices {
+				if toService.ServiceName == ciliumLog.DestinationServiceName && toService.Namespace == ciliumLog.DestinationServiceNamespace {
+					return true
+				}
+			}
+
+			if ciliumLog.DestinationNamespace != "" {
+				if len(egress.MatchLabels) > 0 && getPeerNamespace(egress.MatchLabels, namespace) == ciliumLog.DestinationNamespace &&
+					libs.LabelSelectorFromMap(egress.MatchLabels).Matches(dstLabels) {
+					return true
+				}
+				continue
+			}
+
+			for _, entity := range egress.ToEntities {
+				if entity == "all" || libs.ContainsElement(dstLabels, "reserved:"+entity) {
+					return true
+				}
+			}
+
+			if isIPInCIDRs(ciliumLog.IpDestination, egress.ToCIDRs) {
+				return true
+			}
+
+			// the flows do not carry the fqdn, any world destination may be one of the fqdns
+			if len(egress.ToFQDNs) > 0 && libs.ContainsElement(dstLabels, "reserved:world") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isListeningPortCovered checks if an ingress rule of the policies allows the port
+func isListeningPortCovered(policies []types.KnoxNetworkPolicy, protocol string, port uint32) bool {
+	for _, policy := range policies {
+		for _, ingress := range policy.Spec.Ingress {
+			if isPortAllowed(ingress.ToPorts, protocol, port) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getEgressDestination(ciliumLog types.CiliumLog) string {
+	if ciliumLog.DestinationServiceName != "" {
+		return ciliumLog.DestinationServiceNamespace + "/" + ciliumLog.DestinationServiceName
+	}
+
+	if ciliumLog.DestinationNamespace != "" {
+		return ciliumLog.DestinationNamespace + "/" + ciliumLog.DestinationLabels
+	}
+
+	for _, label := range strings.Split(ciliumLog.DestinationLabels, ",") {
+		if strings.HasPrefix(label, "reserved:") && label != "reserved:world" {
+			return strings.TrimPrefix(label, "reserved:")
+		}
+	}
+
+	return ciliumLog.IpDestination
+}
+
+// findNetworkAnomalies returns the egress destinations and listening ports not allowed by the policies
+// of their workloads, the workloads without a discovered policy are skipped
+func findNetworkAnomalies(ciliumLogs []types.CiliumLog, policies []types.KnoxNetworkPolicy) []types.AnomalyAlert {
+	alerts := []types.AnomalyAlert{}
+
+	for _, ciliumLog := range ciliumLogs {
+		if ciliumLog.IsReply {
+			continue
+		}
+
+		protocol, port := getFlowPort(ciliumLog)
+
+		if ciliumLog.TrafficDirection == "EGRESS" && ciliumLog.SourceNamespace != "" {
+			selecting := getSelectingPolicies(policies, ciliumLog.SourceNamespace, strings.Split(ciliumLog.SourceLabels, ","))
+			if len(selecting) == 0 || isEgressCovered(selecting, ciliumLog) {
+				continue
+			}
+
+			destination := getEgressDestination(ciliumLog)
+			alerts = append(alerts, types.AnomalyAlert{
+				Type:      AnomalyNewEgress,
+				Namespace: ciliumLog.SourceNamespace,
+				PodName:   ciliumLog.SourcePodName,
+				Labels:    ciliumLog.SourceLabels,
+				Resource:  destination,
+				Protocol:  protocol,
+				Port:      port,
+				Message:   "new egress destination " + destination + " " + protocol + "/" + strconv.Itoa(int(port)),
+				Time:      ciliumLog.UpdatedTime,
+			})
+		} else if ciliumLog.TrafficDirection == "INGRESS" && ciliumLog.DestinationNamespace != "" && port != 0 {
+			selecting := getSelectingPolicies(policies, ciliumLog.DestinationNamespace, strings.Split(ciliumLog.DestinationLabels, ","))
+			if len(selecting) == 0 || isListeningPortCovered(selecting, protocol, port) {
+				continue
+			}
+
+			listening := protocol + "/" + strconv.Itoa(int(port))
+			alerts = append(alerts, types.AnomalyAlert{
+				Type:      AnomalyNewListeningPort,
+				Namespace: ciliumLog.DestinationNamespace,
+				PodName:   ciliumLog.DestinationPodName,
+				Labels:    ciliumLog.DestinationLabels,
+				Resource:  listening,
+				Protocol:  protocol,
+				Port:      port,
+				Message:   "new listening port " + listening,
+				Time:      ciliumLog.UpdatedTime,
+			})
+		}
+	}
+
+	return alerts
+}
+
+// DetectNetworkAnomalies alerts on the new egress destinations and listening ports of the network logs seen for the first time
+func DetectNetworkAnomalies(ciliumLogs []types.CiliumLog) {
+	if !config.GetCfgObservabilityAnomaly().Enable || len(ciliumLogs) == 0 {
+		return
+	}
+
+	policies := libs.GetNetworkPolicies(config.GetCfgDB(), "", "", "latest", "", "")
+	emitAnomalyAlerts(findNetworkAnomalies(ciliumLogs, policies))
+}
+
+// =================== //
+// == Anomaly Alert == //
+// =================== //
+
+// isAnomalyAlerted returns true if the anomaly was already alerted, and remembers it otherwise
+func isAnomalyAlerted(alert types.AnomalyAlert) bool {
+	AnomalyAlertedMutex.Lock()
+	defer AnomalyAlertedMutex.Unlock()
+
+	key := strings.Join([]string{alert.Type, alert.Namespace, alert.ContainerName, alert.Labels,
+		alert.Resource, alert.Protocol, strconv.Itoa(int(alert.Port))}, types.RecordSeparator)
+	if AnomalyAlerted[key] {
+		return true
+	}
+
+	if len(AnomalyAlerted) >= AnomalyAlertedLimit {
+		AnomalyAlerted = map[string]bool{}
+	}
+	AnomalyAlerted[key] = true
+
+	return false
+}
+
+func emitAnomalyAlerts(alerts []types.AnomalyAlert) {
+	for _, alert := range alerts {
+		if isAnomalyAlerted(alert) {
+			continue
+		}
+
+		log.Warn().Msgf("anomaly [%s] namespace=%s pod=%s: %s", alert.Type, alert.Namespace, alert.PodName, alert.Message)
+
+		AnomalySubscribersMutex.Lock()
+		for subscriber := range AnomalySubscribers {
+			select {
+			case subscriber <- alert:
+			default:
+				log.Warn().Msg("anomaly alert subscriber is full, alert dropped")
+			}
+		}
+		AnomalySubscribersMutex.Unlock()
+
+		publishAnomalyAlert(alert)
+	}
+}
+
+func publishAnomalyAlert(alert types.AnomalyAlert) {
+	AnomalyPublisherMutex.Lock()
+	defer AnomalyPublisherMutex.Unlock()
+
+	if AnomalyPublisher == nil {
+		return
+	}
+
+	data, err := json.Marshal(alert)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		return
+	}
+	if err := AnomalyPublisher.Publish(data); err != nil {
+		log.Error().Msgf("failed publishing anomaly alert err=%s", err.Error())
+	}
+}
+
+// WatchAnomalyAlerts streams the anomaly alerts of the type and namespace until the client leaves
+func WatchAnomalyAlerts(pbRequest *opb.AnomalyRequest, stream opb.Anomaly_WatchAlertsServer) error {
+	subscriber := make(chan types.AnomalyAlert, AnomalySubscriberBuffer)
+
+	AnomalySubscribersMutex.Lock()
+	AnomalySubscribers[subscriber] = struct{}{}
+	AnomalySubscribersMutex.Unlock()
+
+	defer func() {
+		AnomalySubscribersMutex.Lock()
+		delete(AnomalySubscribers, subscriber)
+		AnomalySubscribersMutex.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case alert := <-subscriber:
+			if pbRequest.Type != "" && pbRequest.Type != alert.Type {
+				continue
+			}
+			if pbRequest.Namespace != "" && pbRequest.Namespace != alert.Namespace {
+				continue
+			}
+
+			if err := stream.Send(&opb.AnomalyAlert{
+				Type:          alert.Type,
+				ClusterName:   alert.ClusterName,
+				Namespace:     alert.Namespace,
+				PodName:       alert.PodName,
+				ContainerName: alert.ContainerName,
+				Labels:        alert.Labels,
+				Source:        alert.Source,
+				Resource:      alert.Resource,
+				Protocol:      alert.Protocol,
+				Port:          alert.Port,
+				Message:       alert.Message,
+				Time:          alert.Time,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
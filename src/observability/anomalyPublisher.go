@@ -0,0 +1,123 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	PublisherKafka  = "kafka"
+	PublisherPulsar = "pulsar"
+)
+
+// anomalyPublisher publishes the anomaly alerts to a message broker topic
+type anomalyPublisher interface {
+	Publish(data []byte) error
+	Close()
+}
+
+// ======================== //
+// == Kafka Publisher    == //
+// ======================== //
+
+type kafkaAnomalyPublisher struct {
+	producer *kafka.Producer
+	topic    string
+}
+
+func newKafkaAnomalyPublisher(anomalyCfg types.ConfigObsAnomaly) (*kafkaAnomalyPublisher, error) {
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": strings.Join(anomalyCfg.PublisherServers, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publisher := &kafkaAnomalyPublisher{producer: producer, topic: anomalyCfg.PublisherTopic}
+	go publisher.drainEvents()
+
+	return publisher, nil
+}
+
+// drainEvents logs the failed deliveries of the alerts, until the producer is closed
+func (p *kafkaAnomalyPublisher) drainEvents() {
+	for event := range p.producer.Events() {
+		switch ev := event.(type) {
+		case *kafka.Message:
+			if ev.TopicPartition.Error != nil {
+				log.Error().Msgf("failed delivering anomaly alert err=%s", ev.TopicPartition.Error.Error())
+			}
+		case kafka.Error:
+			log.Error().Msgf("anomaly publisher err=%s", ev.Error())
+		}
+	}
+}
+
+func (p *kafkaAnomalyPublisher) Publish(data []byte) error {
+	return p.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &p.topic, Partition: kafka.PartitionAny},
+		Value:          data,
+	}, nil)
+}
+
+func (p *kafkaAnomalyPublisher) Close() {
+	p.producer.Flush(1000)
+	p.producer.Close()
+}
+
+// ======================== //
+// == Pulsar Publisher   == //
+// ======================== //
+
+type pulsarAnomalyPublisher struct {
+	client   pulsar.Client
+	producer pulsar.Producer
+}
+
+func newPulsarAnomalyPublisher(anomalyCfg types.ConfigObsAnomaly) (*pulsarAnomalyPublisher, error) {
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL: "pulsar://" + strings.Join(anomalyCfg.PublisherServers, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := client.CreateProducer(pulsar.ProducerOptions{Topic: anomalyCfg.PublisherTopic})
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &pulsarAnomalyPublisher{client: client, producer: producer}, nil
+}
+
+func (p *pulsarAnomalyPublisher) Publish(data []byte) error {
+	p.producer.SendAsync(context.Background(), &pulsar.ProducerMessage{Payload: data},
+		func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
+			if err != nil {
+				log.Error().Msgf("failed publishing anomaly alert err=%s", err.Error())
+			}
+		})
+	return nil
+}
+
+func (p *pulsarAnomalyPublisher) Close() {
+	p.producer.Close()
+	p.client.Close()
+}
+
+func newAnomalyPublisher(anomalyCfg types.ConfigObsAnomaly) (anomalyPublisher, error) {
+	switch anomalyCfg.PublisherDriver {
+	case PublisherKafka:
+		return newKafkaAnomalyPublisher(anomalyCfg)
+	case PublisherPulsar:
+		return newPulsarAnomalyPublisher(anomalyCfg)
+	}
+	return nil, errors.New("unknown anomaly publisher driver " + anomalyCfg.PublisherDriver)
+}
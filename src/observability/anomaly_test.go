@@ -0,0 +1,113 @@
+package observability
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindSystemAnomalies(t *testing.T) {
+	selector, err := libs.ParseLabelSelector("app=web")
+	assert.NoError(t, err)
+
+	processSets := map[string][]learnedProcessSet{
+		"default" + types.RecordSeparator + "web": {{selector: selector, paths: []string{"/usr/sbin/nginx", "/bin/"}}},
+	}
+
+	kubearmorLog := func(labels, resource string) types.KubeArmorLog {
+		return types.KubeArmorLog{
+			NamespaceName: "default",
+			PodName:       "web-1",
+			ContainerName: "web",
+			Labels:        labels,
+			Operation:     OpProcess,
+			Source:        "/bin/sh",
+			Resource:      resource,
+		}
+	}
+
+	alerts := findSystemAnomalies([]types.KubeArmorLog{
+		// learned path and learned dir
+		kubearmorLog("app=web,pod-template-hash=5ff5974cd4", "/usr/sbin/nginx -g daemon off;"),
+		kubearmorLog("app=web,pod-template-hash=5ff5974cd4", "/bin/ls -l"),
+		// a new binary
+		kubearmorLog("app=web,pod-template-hash=5ff5974cd4", "/usr/bin/curl http://example.com"),
+		// a workload without a learned process set
+		kubearmorLog("app=db", "/usr/bin/curl"),
+	}, processSets)
+
+	assert.Equal(t, 1, len(alerts))
+	assert.Equal(t, AnomalyNewBinary, alerts[0].Type)
+	assert.Equal(t, "/usr/bin/curl", alerts[0].Resource)
+}
+
+func TestFindNetworkAnomalies(t *testing.T) {
+	policy := types.KnoxNetworkPolicy{
+		Metadata: map[string]string{"namespace": "default"},
+		Spec: types.Spec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "web"}},
+			Egress: []types.Egress{{
+				MatchLabels: map[string]string{"app": "db", "k8s:io.kubernetes.pod.namespace": "default"},
+				ToPorts:     []types.SpecPort{{Port: "3306", Protocol: "TCP"}},
+			}},
+			Ingress: []types.Ingress{{
+				ToPorts: []types.SpecPort{{Port: "80", Protocol: "TCP"}},
+			}},
+		},
+	}
+
+	egress := func(dstLabels string, port uint32) types.CiliumLog {
+		return types.CiliumLog{
+			TrafficDirection:     "EGRESS",
+			SourceNamespace:      "default",
+			SourceLabels:         "app=web,pod-template-hash=5ff5974cd4",
+			DestinationNamespace: "default",
+			DestinationLabels:    dstLabels,
+			L4TCPDestinationPort: port,
+		}
+	}
+	ingress := func(port uint32) types.CiliumLog {
+		return types.CiliumLog{
+			TrafficDirection:     "INGRESS",
+			DestinationNamespace: "default",
+			DestinationLabels:    "app=web",
+			L4TCPDestinationPort: port,
+		}
+	}
+
+	alerts := findNetworkAnomalies([]types.CiliumLog{
+		egress("app=db,statefulset.kubernetes.io/pod-name=db-0", 3306),
+		egress("app=db", 5432),
+		egress("app=cache", 6379),
+		ingress(80),
+		ingress(8080),
+	}, []types.KnoxNetworkPolicy{policy})
+
+	assert.Equal(t, 3, len(alerts))
+	assert.Equal(t, AnomalyNewEgress, alerts[0].Type)
+	assert.Equal(t, "default/app=db", alerts[0].Resource)
+	assert.Equal(t, uint32(5432), alerts[0].Port)
+	assert.Equal(t, "default/app=cache", alerts[1].Resource)
+	assert.Equal(t, AnomalyNewListeningPort, alerts[2].Type)
+	assert.Equal(t, "TCP/8080", alerts[2].Resource)
+
+	// the workloads without a discovered policy are skipped
+	assert.Empty(t, findNetworkAnomalies([]types.CiliumLog{egress("app=cache", 6379)}, nil))
+}
+
+func TestIsAnomalyAlerted(t *testing.T) {
+	defer func() { AnomalyAlerted = map[string]bool{} }()
+
+	alert := types.AnomalyAlert{Type: AnomalyNewBinary, Namespace: "default", ContainerName: "web", Resource: "/usr/bin/curl"}
+	assert.False(t, isAnomalyAlerted(alert))
+	assert.True(t, isAnomalyAlerted(alert))
+
+	// another pod of the same workload is not alerted again
+	alert.PodName = "web-2"
+	assert.True(t, isAnomalyAlerted(alert))
+
+	alert.Resource = "/usr/bin/wget"
+	assert.False(t, isAnomalyAlerted(alert))
+}
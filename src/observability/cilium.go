@@ -182,10 +182,14 @@ func ProcessNetworkLogs() {
 			}
 			res = append(res, netLog)
 		}
-		if err := libs.UpdateOrInsertCiliumLogs(CfgDB, res); err != nil {
+		inserted, err := libs.UpdateOrInsertCiliumLogs(CfgDB, res)
+		if err != nil {
 			log.Error().Msg(err.Error())
 		}
 		ObsMutex.Unlock()
+
+		DetectNetworkAnomalies(inserted)
+		exportNetworkSummary(res, inserted)
 	}
 }
 
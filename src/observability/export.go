@@ -0,0 +1,41 @@
+package observability
+
+import (
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	opb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/observability"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ExportLogs writes the cilium and kubearmor logs of the request to csv or parquet files on the server,
+// under the configured export directory
+func ExportLogs(pbRequest *opb.ExportRequest) (*opb.ExportResponse, error) {
+	dir, err := libs.ResolveLogExportDir(config.GetCfgObservabilityExportDir(), pbRequest.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := libs.ExportLogs(CfgDB, types.LogExportRequest{
+		Format:    pbRequest.Format,
+		Type:      pbRequest.Type,
+		Namespace: pbRequest.Namespace,
+		Labels:    pbRequest.Label,
+		Since:     pbRequest.Since,
+		Until:     pbRequest.Until,
+		Dir:       dir,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &opb.ExportResponse{}
+	for _, file := range files {
+		log.Info().Msgf("exported %d %s logs to %s", file.Rows, file.Type, file.Path)
+		response.Files = append(response.Files, &opb.ExportedFile{
+			Type: file.Type,
+			Path: file.Path,
+			Rows: file.Rows,
+		})
+	}
+	return response, nil
+}
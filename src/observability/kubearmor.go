@@ -69,10 +69,14 @@ func ProcessSystemLogs() {
 
 			res = append(res, locLog)
 		}
-		if err := libs.UpdateOrInsertKubearmorLogs(CfgDB, res); err != nil {
+		inserted, err := libs.UpdateOrInsertKubearmorLogs(CfgDB, res)
+		if err != nil {
 			log.Error().Msg(err.Error())
 		}
 		ObsMutex.Unlock()
+
+		DetectSystemAnomalies(inserted)
+		exportSystemSummary(res, inserted)
 	}
 }
 
@@ -46,7 +46,7 @@ func initMutex() {
 
 func InitObservability() {
 	log = logger.GetInstance()
-	CfgDB = cfg.GetCfgDB()
+	CfgDB = cfg.GetCfgObservabilityDB()
 
 	if cfg.GetCfgObservabilityEnable() {
 		// Init mutex
@@ -58,11 +58,22 @@ func InitObservability() {
 			log.Error().Msg(err.Error())
 			return
 		}
+		initRetention()
+		initAnomaly()
 		ObsCronJob.Start()
 		log.Info().Msg("Observability cron job started")
 	}
 }
 
+// StopObservability stops the observability cron job and closes the anomaly publisher
+func StopObservability() {
+	if ObsCronJob != nil {
+		ObsCronJob.Stop()
+		ObsCronJob = nil
+	}
+	closeAnomalyPublisher()
+}
+
 func ObservabilityCronJob() {
 	if config.GetCfgObservabilitySysObsStatus() {
 		ProcessSystemLogs()
@@ -0,0 +1,118 @@
+package observability
+
+import (
+	"time"
+
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+)
+
+const (
+	hourSeconds = int64(time.Hour / time.Second)
+	daySeconds  = 24 * hourSeconds
+)
+
+// ============================ //
+// == Log Retention & Rollup == //
+// ============================ //
+
+func parseRetentionDuration(name, durationStr string) int64 {
+	if durationStr == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil {
+		log.Error().Msgf("invalid observability retention %s [%s] err=%s", name, durationStr, err.Error())
+		return 0
+	}
+
+	return int64(duration.Seconds())
+}
+
+func initRetention() {
+	retention := config.GetCfgObservabilityRetention()
+	if !retention.Enable {
+		return
+	}
+
+	if err := ObsCronJob.AddFunc(retention.CronJobTimeInterval, LogRetentionCronJob); err != nil {
+		log.Error().Msg(err.Error())
+		return
+	}
+	log.Info().Msg("Observability retention cron job added")
+}
+
+// getRollupRanges returns the hourly and daily rollup time ranges [from, to)
+func getRollupRanges(now, hourlyAfter, dailyAfter int64) ([2]int64, [2]int64) {
+	var hourly, daily [2]int64
+
+	if dailyAfter > 0 {
+		daily = [2]int64{0, now - dailyAfter}
+	}
+
+	if hourlyAfter > 0 {
+		hourly = [2]int64{daily[1], now - hourlyAfter}
+	}
+
+	return hourly, daily
+}
+
+func applyLogRetention(logType string, now int64) {
+	retention := config.GetCfgObservabilityRetention()
+
+	if maxAge := parseRetentionDuration("max-age", retention.MaxAge); maxAge > 0 {
+		if deleted, err := libs.DeleteObsLogsBefore(CfgDB, logType, now-maxAge); err != nil {
+			log.Error().Msgf("failed deleting old %s logs err=%s", logType, err.Error())
+		} else if deleted > 0 {
+			log.Info().Msgf("deleted %d %s logs older than %s", deleted, logType, retention.MaxAge)
+		}
+	}
+
+	for _, nsMaxRows := range retention.NamespaceMaxRows {
+		if deleted, err := libs.CapObsLogs(CfgDB, logType, nsMaxRows.Namespace, nsMaxRows.MaxRows); err != nil {
+			log.Error().Msgf("failed capping %s logs of namespace [%s] err=%s", logType, nsMaxRows.Namespace, err.Error())
+		} else if deleted > 0 {
+			log.Info().Msgf("deleted %d %s logs over the cap of namespace [%s]", deleted, logType, nsMaxRows.Namespace)
+		}
+	}
+
+	if deleted, err := libs.CapObsLogs(CfgDB, logType, "", retention.MaxRows); err != nil {
+		log.Error().Msgf("failed capping %s logs err=%s", logType, err.Error())
+	} else if deleted > 0 {
+		log.Info().Msgf("deleted %d %s logs over the cap", deleted, logType)
+	}
+
+	hourly, daily := getRollupRanges(now,
+		parseRetentionDuration("hourly-after", retention.HourlyRollupAfter),
+		parseRetentionDuration("daily-after", retention.DailyRollupAfter))
+
+	if compacted, err := libs.CompactObsLogs(CfgDB, logType, daily[0], daily[1], daySeconds); err != nil {
+		log.Error().Msgf("failed daily rollup of %s logs err=%s", logType, err.Error())
+	} else if compacted > 0 {
+		log.Info().Msgf("rolled up %d %s logs into daily aggregates", compacted, logType)
+	}
+
+	if compacted, err := libs.CompactObsLogs(CfgDB, logType, hourly[0], hourly[1], hourSeconds); err != nil {
+		log.Error().Msgf("failed hourly rollup of %s logs err=%s", logType, err.Error())
+	} else if compacted > 0 {
+		log.Info().Msgf("rolled up %d %s logs into hourly aggregates", compacted, logType)
+	}
+}
+
+// LogRetentionCronJob prunes and rolls up the observability logs
+func LogRetentionCronJob() {
+	ObsMutex.Lock()
+	defer ObsMutex.Unlock()
+
+	now := time.Now().Unix()
+
+	applyLogRetention(libs.ObsLogSystem, now)
+	applyLogRetention(libs.ObsLogNetwork, now)
+
+	if config.GetCfgObservabilityRetention().Vacuum {
+		if err := libs.VacuumObsLogDB(CfgDB); err != nil {
+			log.Error().Msgf("failed vacuum of observability db err=%s", err.Error())
+		}
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/accuknox/auto-policy-discovery/src/types"
 )
 
+// SummaryPageTokenSeparator separates the system and network log cursors of a summary page token
+const SummaryPageTokenSeparator = "."
+
 func deDuplicateServerInOutConn(connList []types.SysNwConnDetail) []types.SysNwConnDetail {
 	occurred := map[types.SysNwConnDetail]bool{}
 	result := []types.SysNwConnDetail{}
@@ -66,25 +69,40 @@ func fetchSysServerConnDetail(data string, res string) (types.SysNwConnDetail, e
 	return conn, nil
 }
 
-//GetSummaryLogs - Give Summary logs of Pod based on Label and Namespace Input
+//GetSummaryLogs - Give Summary logs of Pod or Workload based on Label and Namespace Input
 func GetSummaryLogs(pbRequest *opb.LogsRequest, stream opb.Summary_FetchLogsServer) error {
 	log.Info().Msg("Get Summary Log Called")
 	systemPods := make(map[string][]types.SystemSummary)
 	networkPods := make(map[string][]types.NetworkSummary)
-	// Thsi type is used for comparison
-	syserverconn := []types.SysNwConnDetail{}
 
-	//Fetch network Logs
-	networkLogs, networkTotal, err := libs.GetCiliumLogs(CfgDB, types.CiliumLog{
-		SourceLabels:    pbRequest.Label,
-		SourceNamespace: pbRequest.Namespace,
-	})
+	sysQuery, netQuery, fetchSys, fetchNet, err := getSummaryLogQueries(pbRequest)
 	if err != nil {
 		return err
 	}
 
+	grouper, err := newSummaryGrouper(pbRequest)
+	if err != nil {
+		return err
+	}
+
+	//Fetch network Logs
+	networkLogs, networkTotal, netNextPageToken := []types.CiliumLog{}, []uint32{}, ""
+	if fetchNet {
+		networkLogs, networkTotal, netNextPageToken, err = libs.GetCiliumLogsByQuery(CfgDB, types.CiliumLog{
+			SourceLabels:    pbRequest.Label,
+			SourceNamespace: pbRequest.Namespace,
+		}, netQuery)
+		if err != nil {
+			return err
+		}
+	}
+
 	for nwindex, locNetLog := range networkLogs {
-		networkPods[locNetLog.SourcePodName] = append(networkPods[locNetLog.SourcePodName], types.NetworkSummary{
+		groupKey := grouper.getGroupKey(locNetLog.SourceNamespace, locNetLog.SourcePodName, locNetLog.SourceLabels)
+		if groupKey == "" {
+			continue
+		}
+		networkPods[groupKey] = append(networkPods[groupKey], types.NetworkSummary{
 			Verdict:              locNetLog.Verdict,
 			DestinationLabels:    locNetLog.DestinationLabels,
 			DestinationNamespace: locNetLog.DestinationNamespace,
@@ -104,19 +122,26 @@ func GetSummaryLogs(pbRequest *opb.LogsRequest, stream opb.Summary_FetchLogsServ
 	}
 
 	//Fetch System Logs
-	systemLogs, systemTotal, err := libs.GetKubearmorLogs(CfgDB, types.KubeArmorLog{
-		Labels:        pbRequest.Label,
-		NamespaceName: pbRequest.Namespace,
-	})
-	if err != nil {
-		return err
+	systemLogs, systemTotal, sysNextPageToken := []types.KubeArmorLog{}, []uint32{}, ""
+	if fetchSys {
+		systemLogs, systemTotal, sysNextPageToken, err = libs.GetKubearmorLogsByQuery(CfgDB, types.KubeArmorLog{
+			Labels:        pbRequest.Label,
+			NamespaceName: pbRequest.Namespace,
+		}, sysQuery)
+		if err != nil {
+			return err
+		}
 	}
 	for sysindex, locSysLog := range systemLogs {
+		groupKey := grouper.getGroupKey(locSysLog.NamespaceName, locSysLog.PodName, locSysLog.Labels)
+		if groupKey == "" {
+			continue
+		}
 		nwConnDetail := types.SysNwConnDetail{}
 		if locSysLog.Operation == "Network" {
 			nwConnDetail, err = fetchSysServerConnDetail(locSysLog.Data, locSysLog.Resource)
 		}
-		systemPods[locSysLog.PodName] = append(systemPods[locSysLog.PodName], types.SystemSummary{
+		systemPods[groupKey] = append(systemPods[groupKey], types.SystemSummary{
 			Operation:   locSysLog.Operation,
 			Source:      locSysLog.Source,
 			Resource:    locSysLog.Resource,
@@ -128,7 +153,17 @@ func GetSummaryLogs(pbRequest *opb.LogsRequest, stream opb.Summary_FetchLogsServ
 
 	}
 
-	for podName, sysLogs := range systemPods {
+	nextPageToken := getSummaryNextPageToken(sysNextPageToken, netNextPageToken)
+
+	// a page of network logs can have pods without system logs
+	for groupKey := range networkPods {
+		if _, ok := systemPods[groupKey]; !ok {
+			systemPods[groupKey] = []types.SystemSummary{}
+		}
+	}
+
+	for groupKey, sysLogs := range systemPods {
+		group := grouper.groups[groupKey]
 
 		var listOfFile, listOfProcess, listOfNetwork []*opb.ListOfSource
 		var inServerConn, outServerConn []*opb.ServerConnections
@@ -173,7 +208,7 @@ func GetSummaryLogs(pbRequest *opb.LogsRequest, stream opb.Summary_FetchLogsServ
 		}
 		var networkIngress, networkEgress []*opb.ListOfConnection
 		//Network Block
-		for _, netLog := range networkPods[podName] {
+		for _, netLog := range networkPods[groupKey] {
 			//Check Traffic Direction is Ingress or Egress
 			switch netLog.TrafficDirection {
 			case "INGRESS":
@@ -183,6 +218,7 @@ func GetSummaryLogs(pbRequest *opb.LogsRequest, stream opb.Summary_FetchLogsServ
 			}
 		}
 
+		syserverconn := []types.SysNwConnDetail{}
 		for _, syslog := range sysLogs {
 			syserverconn = append(syserverconn, syslog.ServerConn)
 		}
@@ -206,8 +242,8 @@ func GetSummaryLogs(pbRequest *opb.LogsRequest, stream opb.Summary_FetchLogsServ
 
 		//Stream Block
 		if err := stream.Send(&opb.LogsResponse{
-			PodDetail:     podName,
-			Namespace:     pbRequest.Namespace,
+			PodDetail:     grouper.getPodDetail(groupKey),
+			Namespace:     group.Namespace,
 			ListOfFile:    listOfFile,
 			ListOfProcess: listOfProcess,
 			ListOfNetwork: listOfNetwork,
@@ -215,6 +251,10 @@ func GetSummaryLogs(pbRequest *opb.LogsRequest, stream opb.Summary_FetchLogsServ
 			Egress:        networkEgress,
 			InServerConn:  inServerConn,
 			OutServerConn: outServerConn,
+			NextPageToken: nextPageToken,
+			WorkloadKind:  group.WorkloadKind,
+			WorkloadName:  group.WorkloadName,
+			Pods:          group.Pods,
 		}); err != nil {
 			log.Error().Msg("Error in Streaming Summary Logs : " + err.Error())
 		}
@@ -223,6 +263,40 @@ func GetSummaryLogs(pbRequest *opb.LogsRequest, stream opb.Summary_FetchLogsServ
 	return nil
 }
 
+// getSummaryLogQueries - Get the system and network log queries of the request, a page token holds both
+// the system and the network cursors, an empty cursor means there is no more logs of that type
+func getSummaryLogQueries(pbRequest *opb.LogsRequest) (types.LogQuery, types.LogQuery, bool, bool, error) {
+	sysQuery := types.LogQuery{
+		Since:     pbRequest.Since,
+		Until:     pbRequest.Until,
+		Limit:     int(pbRequest.Limit),
+		SortBy:    pbRequest.SortBy,
+		SortOrder: pbRequest.SortOrder,
+	}
+	netQuery := sysQuery
+
+	if pbRequest.PageToken == "" {
+		return sysQuery, netQuery, true, true, nil
+	}
+
+	tokens := strings.Split(pbRequest.PageToken, SummaryPageTokenSeparator)
+	if len(tokens) != 2 {
+		return sysQuery, netQuery, false, false, errors.New("invalid page token")
+	}
+
+	sysQuery.PageToken, netQuery.PageToken = tokens[0], tokens[1]
+
+	return sysQuery, netQuery, tokens[0] != "", tokens[1] != "", nil
+}
+
+// getSummaryNextPageToken - Get the page token of the next page, empty for the last page
+func getSummaryNextPageToken(sysNextPageToken, netNextPageToken string) string {
+	if sysNextPageToken == "" && netNextPageToken == "" {
+		return ""
+	}
+	return sysNextPageToken + SummaryPageTokenSeparator + netNextPageToken
+}
+
 //networkRegex - To Get the Protocol using Regex
 func networkRegex(str string) (string, error) {
 	var retcp, reudp, reicmp, reraw *regexp.Regexp
@@ -272,7 +346,9 @@ func convertListofDestination(arr []*opb.ListOfDestination, sysLog types.SystemS
 	for _, value := range arr {
 		if value.Destination == destination && value.Status == sysLog.Action {
 			value.Count += sysLog.Count
-			value.LastUpdatedTime = sysLog.UpdatedTime
+			if sysLog.UpdatedTime > value.LastUpdatedTime {
+				value.LastUpdatedTime = sysLog.UpdatedTime
+			}
 			return arr
 		}
 	}
@@ -362,7 +438,9 @@ func convertNetworkConnection(netLog types.NetworkSummary, list []*opb.ListOfCon
 		if value.DestinationLabels == listOfConn.DestinationLabels && value.DestinationNamespace == listOfConn.DestinationNamespace &&
 			value.Protocol == listOfConn.Protocol && value.Port == listOfConn.Port && value.Status == listOfConn.Status {
 			value.Count += netLog.Count
-			value.LastUpdatedTime = netLog.UpdatedTime
+			if netLog.UpdatedTime > value.LastUpdatedTime {
+				value.LastUpdatedTime = netLog.UpdatedTime
+			}
 			return list
 		}
 	}
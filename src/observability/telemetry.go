@@ -0,0 +1,49 @@
+package observability
+
+import (
+	"github.com/accuknox/auto-policy-discovery/src/telemetry"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	LogTypeSystem  = "system"
+	LogTypeNetwork = "network"
+)
+
+// exportObservabilitySummary exports the number of the logs and the new logs per namespace
+func exportObservabilitySummary(logType string, logs, newLogs map[string]int) {
+	for namespace, count := range logs {
+		telemetry.ObservabilitySummary(logType, namespace, count, newLogs[namespace])
+	}
+}
+
+func exportSystemSummary(kubearmorLogs, inserted []types.KubeArmorLog) {
+	logs, newLogs := map[string]int{}, map[string]int{}
+	for _, kubearmorLog := range kubearmorLogs {
+		logs[kubearmorLog.NamespaceName]++
+	}
+	for _, kubearmorLog := range inserted {
+		newLogs[kubearmorLog.NamespaceName]++
+	}
+
+	exportObservabilitySummary(LogTypeSystem, logs, newLogs)
+}
+
+func getFlowNamespace(ciliumLog types.CiliumLog) string {
+	if ciliumLog.SourceNamespace != "" {
+		return ciliumLog.SourceNamespace
+	}
+	return ciliumLog.DestinationNamespace
+}
+
+func exportNetworkSummary(ciliumLogs, inserted []types.CiliumLog) {
+	logs, newLogs := map[string]int{}, map[string]int{}
+	for _, ciliumLog := range ciliumLogs {
+		logs[getFlowNamespace(ciliumLog)]++
+	}
+	for _, ciliumLog := range inserted {
+		newLogs[getFlowNamespace(ciliumLog)]++
+	}
+
+	exportObservabilitySummary(LogTypeNetwork, logs, newLogs)
+}
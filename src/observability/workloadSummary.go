@@ -0,0 +1,150 @@
+package observability
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/cluster"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	opb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/observability"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	SummaryGroupByPod      = "pod"
+	SummaryGroupByWorkload = "workload"
+)
+
+// summaryGroup - The pods of a summary response, a single pod or the replicas of a workload
+type summaryGroup struct {
+	Namespace    string
+	WorkloadKind string
+	WorkloadName string
+	Pods         []string
+}
+
+// summaryGrouper - Map the logs of the pods to their summary groups
+type summaryGrouper struct {
+	groupBy  string
+	workload string
+
+	// key: namespace/pod name
+	pods map[string]types.Pod
+	// the distinct workloads of the live pods per namespace
+	workloads map[string][]types.Pod
+
+	groups map[string]*summaryGroup
+}
+
+func newSummaryGrouper(pbRequest *opb.LogsRequest) (*summaryGrouper, error) {
+	grouper := &summaryGrouper{
+		groupBy:   pbRequest.GroupBy,
+		workload:  pbRequest.Workload,
+		pods:      map[string]types.Pod{},
+		workloads: map[string][]types.Pod{},
+		groups:    map[string]*summaryGroup{},
+	}
+
+	if grouper.groupBy == "" {
+		grouper.groupBy = SummaryGroupByPod
+	} else if grouper.groupBy != SummaryGroupByPod && grouper.groupBy != SummaryGroupByWorkload {
+		return nil, errors.New("invalid group by " + pbRequest.GroupBy)
+	}
+
+	if grouper.groupBy == SummaryGroupByWorkload || grouper.workload != "" {
+		grouper.setPods(cluster.GetPodsFromK8sClient())
+	}
+
+	return grouper, nil
+}
+
+func (g *summaryGrouper) setPods(pods []types.Pod) {
+	added := map[string]bool{}
+
+	for _, pod := range pods {
+		g.pods[pod.Namespace+"/"+pod.PodName] = pod
+
+		if pod.WorkloadName == "" || len(pod.WorkloadLabels) == 0 {
+			continue
+		}
+
+		key := pod.Namespace + "/" + pod.WorkloadKind + "/" + pod.WorkloadName
+		if added[key] {
+			continue
+		}
+		added[key] = true
+
+		g.workloads[pod.Namespace] = append(g.workloads[pod.Namespace], pod)
+	}
+}
+
+// resolveWorkload - Get the owning workload of the pod, the pods not running anymore are matched
+// to the most specific workload selecting their labels, a pod without a workload is its own workload
+func (g *summaryGrouper) resolveWorkload(namespace, podName, labels string) (string, string) {
+	if pod, ok := g.pods[namespace+"/"+podName]; ok && pod.WorkloadName != "" {
+		return pod.WorkloadKind, pod.WorkloadName
+	}
+
+	podLabels := []string{}
+	if labels != "" {
+		podLabels = cluster.NormalizeWorkloadLabels(namespace, strings.Split(labels, ","))
+	}
+
+	var selected *types.Pod
+	for i, workload := range g.workloads[namespace] {
+		if isLabelSubset(workload.WorkloadLabels, podLabels) &&
+			(selected == nil || len(workload.WorkloadLabels) > len(selected.WorkloadLabels)) {
+			selected = &g.workloads[namespace][i]
+		}
+	}
+	if selected != nil {
+		return selected.WorkloadKind, selected.WorkloadName
+	}
+
+	return "Pod", podName
+}
+
+func (g *summaryGrouper) isWorkloadSelected(kind, name string) bool {
+	return g.workload == "" || g.workload == name || g.workload == kind+"/"+name
+}
+
+// getGroupKey - Get the summary group key of the log of the pod, empty if the log is filtered out
+func (g *summaryGrouper) getGroupKey(namespace, podName, labels string) string {
+	kind, name := "", ""
+	if g.groupBy == SummaryGroupByWorkload || g.workload != "" {
+		kind, name = g.resolveWorkload(namespace, podName, labels)
+		if !g.isWorkloadSelected(kind, name) {
+			return ""
+		}
+	}
+
+	key := podName
+	if g.groupBy == SummaryGroupByWorkload {
+		key = namespace + "/" + kind + "/" + name
+	}
+
+	group, ok := g.groups[key]
+	if !ok {
+		group = &summaryGroup{
+			Namespace:    namespace,
+			WorkloadKind: kind,
+			WorkloadName: name,
+		}
+		g.groups[key] = group
+	}
+	if !libs.ContainsElement(group.Pods, podName) {
+		group.Pods = append(group.Pods, podName)
+		sort.Strings(group.Pods)
+	}
+
+	return key
+}
+
+// getPodDetail - Get the pod detail of the summary response of the group
+func (g *summaryGrouper) getPodDetail(key string) string {
+	if g.groupBy == SummaryGroupByWorkload {
+		return g.groups[key].WorkloadName
+	}
+	return key
+}
@@ -0,0 +1,48 @@
+package observability
+
+import (
+	"testing"
+
+	opb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/observability"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSummaryGrouper(groupBy, workload string) *summaryGrouper {
+	grouper, _ := newSummaryGrouper(&opb.LogsRequest{})
+	grouper.groupBy, grouper.workload = groupBy, workload
+	grouper.setPods([]types.Pod{
+		{Namespace: "default", PodName: "web-6d4b75cb6d-abcde", Labels: []string{"app=web"},
+			WorkloadKind: "Deployment", WorkloadName: "web", WorkloadLabels: []string{"app=web"}},
+		{Namespace: "default", PodName: "web-6d4b75cb6d-fghij", Labels: []string{"app=web"},
+			WorkloadKind: "Deployment", WorkloadName: "web", WorkloadLabels: []string{"app=web"}},
+		{Namespace: "default", PodName: "db-0", Labels: []string{"app=db"},
+			WorkloadKind: "StatefulSet", WorkloadName: "db", WorkloadLabels: []string{"app=db"}},
+	})
+	return grouper
+}
+
+func TestGroupSummaryByWorkload(t *testing.T) {
+	grouper := newTestSummaryGrouper(SummaryGroupByWorkload, "")
+
+	first := grouper.getGroupKey("default", "web-6d4b75cb6d-abcde", "app=web,pod-template-hash=6d4b75cb6d")
+	second := grouper.getGroupKey("default", "web-6d4b75cb6d-fghij", "app=web,pod-template-hash=6d4b75cb6d")
+	// a pod of an old replicaset, not running anymore
+	restarted := grouper.getGroupKey("default", "web-5f9c8d7b4-klmno", "app=web,pod-template-hash=5f9c8d7b4")
+	db := grouper.getGroupKey("default", "db-0", "app=db")
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, first, restarted)
+	assert.NotEqual(t, first, db)
+	assert.Equal(t, "web", grouper.getPodDetail(first))
+	assert.Equal(t, "Deployment", grouper.groups[first].WorkloadKind)
+	assert.Equal(t, []string{"web-5f9c8d7b4-klmno", "web-6d4b75cb6d-abcde", "web-6d4b75cb6d-fghij"}, grouper.groups[first].Pods)
+}
+
+func TestGroupSummaryDrillDown(t *testing.T) {
+	grouper := newTestSummaryGrouper(SummaryGroupByPod, "Deployment/web")
+
+	assert.Equal(t, "web-6d4b75cb6d-abcde", grouper.getGroupKey("default", "web-6d4b75cb6d-abcde", "app=web"))
+	assert.Equal(t, "", grouper.getGroupKey("default", "db-0", "app=db"))
+	assert.Equal(t, "standalone", newTestSummaryGrouper(SummaryGroupByPod, "").getGroupKey("default", "standalone", "run=standalone"))
+}
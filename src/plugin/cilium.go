@@ -194,6 +194,7 @@ func ConvertCiliumFlowToKnoxNetworkLog(ciliumFlow *cilium.Flow) (types.KnoxNetwo
 	log.SrcReservedLabels = getReservedLabelsIfExist(ciliumFlow.Source.Labels)
 
 	log.IsReply = ciliumFlow.GetIsReply().GetValue()
+	log.Timestamp = ciliumFlow.GetTime().GetSeconds()
 
 	// get L3
 	if ciliumFlow.IP != nil {
@@ -533,6 +534,11 @@ func buildNewCiliumNetworkPolicy(inPolicy types.KnoxNetworkPolicy) types.CiliumN
 	if inPolicy.Kind == types.KindKnoxHostNetworkPolicy {
 		ciliumPolicy.Kind = cu.ResourceTypeCiliumClusterwideNetworkPolicy
 		ciliumPolicy.Spec.NodeSelector.MatchLabels = inPolicy.Spec.Selector.MatchLabels
+	} else if inPolicy.Kind == types.KindKnoxClusterNetworkPolicy {
+		// a clusterwide policy selects the endpoints of its labels in its namespaces by the namespace label
+		ciliumPolicy.Kind = cu.ResourceTypeCiliumClusterwideNetworkPolicy
+		ciliumPolicy.Spec.EndpointSelector = inPolicy.Spec.Selector
+		delete(ciliumPolicy.Metadata, "namespace")
 	} else {
 		ciliumPolicy.Kind = cu.ResourceTypeCiliumNetworkPolicy
 		ciliumPolicy.Spec.EndpointSelector.MatchLabels = inPolicy.Spec.Selector.MatchLabels
@@ -590,6 +596,9 @@ func ConvertKnoxNetworkPolicyToCiliumPolicy(inPolicy types.KnoxNetworkPolicy) ty
 					for _, matchName := range fqdn.MatchNames {
 						ciliumEgress.ToFQDNs = append(ciliumEgress.ToFQDNs, map[string]string{"matchName": matchName})
 					}
+					for _, matchPattern := range fqdn.MatchPatterns {
+						ciliumEgress.ToFQDNs = append(ciliumEgress.ToFQDNs, map[string]string{"matchPattern": matchPattern})
+					}
 				}
 			} else if len(knoxEgress.ToServices) > 0 {
 				// ================== //
@@ -638,7 +647,7 @@ func ConvertKnoxNetworkPolicyToCiliumPolicy(inPolicy types.KnoxNetworkPolicy) ty
 					ciliumEgress.ToPorts[0].Rules = map[string][]types.SubRule{"http": httpRules}
 				}
 
-				port := types.CiliumPort{Port: toPort.Port, Protocol: strings.ToUpper(toPort.Protocol)}
+				port := types.CiliumPort{Port: toPort.Port, EndPort: toPort.EndPort, Protocol: strings.ToUpper(toPort.Protocol)}
 				ciliumEgress.ToPorts[0].Ports = append(ciliumEgress.ToPorts[0].Ports, port)
 			}
 
@@ -716,7 +725,7 @@ func ConvertKnoxNetworkPolicyToCiliumPolicy(inPolicy types.KnoxNetworkPolicy) ty
 					ciliumIngress.ToPorts[0].Rules = map[string][]types.SubRule{"http": httpRules}
 				}
 
-				port := types.CiliumPort{Port: toPort.Port, Protocol: strings.ToUpper(toPort.Protocol)}
+				port := types.CiliumPort{Port: toPort.Port, EndPort: toPort.EndPort, Protocol: strings.ToUpper(toPort.Protocol)}
 				ciliumIngress.ToPorts[0].Ports = append(ciliumIngress.ToPorts[0].Ports, port)
 			}
 
@@ -739,6 +748,10 @@ func ConvertKnoxNetworkPolicyToCiliumPolicy(inPolicy types.KnoxNetworkPolicy) ty
 
 	}
 
+	if template, ok := GetPolicyTemplate(inPolicy.Metadata["namespace"], inPolicy.Spec.Selector.MatchLabels); ok {
+		applyTemplateToCiliumPolicy(&ciliumPolicy, template)
+	}
+
 	return ciliumPolicy
 }
 
@@ -753,6 +766,171 @@ func ConvertKnoxPoliciesToCiliumPolicies(policies []types.KnoxNetworkPolicy) []t
 	return ciliumPolicies
 }
 
+// ========================================== //
+// == Cilium Policy to Knox Network Policy == //
+// ========================================== //
+
+// convertCiliumPortsToKnox returns the ports and the http rules of the cilium port lists
+func convertCiliumPortsToKnox(portLists []types.CiliumPortList) ([]types.SpecPort, []types.SpecHTTP) {
+	var ports []types.SpecPort
+	var https []types.SpecHTTP
+
+	for _, portList := range portLists {
+		for _, port := range portList.Ports {
+			ports = append(ports, types.SpecPort{Port: port.Port, Protocol: strings.ToLower(port.Protocol), EndPort: port.EndPort})
+		}
+
+		for _, http := range portList.Rules["http"] {
+			https = append(https, types.SpecHTTP{Method: http["method"], Path: http["path"]})
+		}
+	}
+
+	return ports, https
+}
+
+func convertCiliumICMPsToKnox(icmps []types.CiliumICMP) []types.SpecICMP {
+	var knoxICMPs []types.SpecICMP
+
+	for _, icmp := range icmps {
+		for _, field := range icmp.Fields {
+			knoxICMPs = append(knoxICMPs, types.SpecICMP{Family: field.Family, Type: field.Type})
+		}
+	}
+
+	return knoxICMPs
+}
+
+func convertCiliumEgressToKnox(ciliumEgress types.CiliumEgress) []types.Egress {
+	ports, https := convertCiliumPortsToKnox(ciliumEgress.ToPorts)
+	l4 := types.Egress{ToPorts: ports, ToHTTPs: https, ICMPs: convertCiliumICMPsToKnox(ciliumEgress.ICMPs)}
+
+	egress := []types.Egress{}
+
+	for _, endpoint := range ciliumEgress.ToEndpoints {
+		rule := l4
+		rule.MatchLabels = endpoint.MatchLabels
+		egress = append(egress, rule)
+	}
+
+	if len(ciliumEgress.ToCIDRs) > 0 {
+		rule := l4
+		rule.ToCIDRs = []types.SpecCIDR{{CIDRs: ciliumEgress.ToCIDRs}}
+		egress = append(egress, rule)
+	}
+
+	if len(ciliumEgress.ToEntities) > 0 {
+		rule := l4
+		rule.ToEntities = ciliumEgress.ToEntities
+		egress = append(egress, rule)
+	}
+
+	for _, service := range ciliumEgress.ToServices {
+		rule := l4
+		rule.ToServices = []types.SpecService{{ServiceName: service.K8sService.ServiceName, Namespace: service.K8sService.Namespace}}
+		egress = append(egress, rule)
+	}
+
+	fqdn := types.SpecFQDN{}
+	for _, ciliumFQDN := range ciliumEgress.ToFQDNs {
+		if matchName, ok := ciliumFQDN["matchName"]; ok {
+			fqdn.MatchNames = append(fqdn.MatchNames, matchName)
+		}
+		if matchPattern, ok := ciliumFQDN["matchPattern"]; ok {
+			fqdn.MatchPatterns = append(fqdn.MatchPatterns, matchPattern)
+		}
+	}
+	if len(fqdn.MatchNames) > 0 || len(fqdn.MatchPatterns) > 0 {
+		rule := l4
+		rule.ToFQDNs = []types.SpecFQDN{fqdn}
+		egress = append(egress, rule)
+	}
+
+	// a rule of the ports only allows any peer
+	if len(ciliumEgress.ToEndpoints) == 0 && len(ciliumEgress.ToCIDRs) == 0 && len(ciliumEgress.ToEntities) == 0 &&
+		len(ciliumEgress.ToServices) == 0 && len(ciliumEgress.ToFQDNs) == 0 {
+		egress = append(egress, l4)
+	}
+
+	return egress
+}
+
+func convertCiliumIngressToKnox(ciliumIngress types.CiliumIngress) []types.Ingress {
+	ports, https := convertCiliumPortsToKnox(ciliumIngress.ToPorts)
+	l4 := types.Ingress{ToPorts: ports, ToHTTPs: https, ICMPs: convertCiliumICMPsToKnox(ciliumIngress.ICMPs)}
+
+	ingress := []types.Ingress{}
+
+	for _, endpoint := range ciliumIngress.FromEndpoints {
+		rule := l4
+		rule.MatchLabels = endpoint.MatchLabels
+		ingress = append(ingress, rule)
+	}
+
+	if len(ciliumIngress.FromCIDRs) > 0 {
+		rule := l4
+		rule.FromCIDRs = []types.SpecCIDR{{CIDRs: ciliumIngress.FromCIDRs}}
+		ingress = append(ingress, rule)
+	}
+
+	if len(ciliumIngress.FromEntities) > 0 {
+		rule := l4
+		rule.FromEntities = ciliumIngress.FromEntities
+		ingress = append(ingress, rule)
+	}
+
+	// a rule of the ports only allows any peer
+	if len(ciliumIngress.FromEndpoints) == 0 && len(ciliumIngress.FromCIDRs) == 0 && len(ciliumIngress.FromEntities) == 0 {
+		ingress = append(ingress, l4)
+	}
+
+	return ingress
+}
+
+// ConvertCiliumPolicyToKnoxNetworkPolicies converts a cilium policy to a knox ingress policy and a knox egress policy,
+// a cilium rule of several peers becomes a knox rule per peer, the deny rules are not converted
+func ConvertCiliumPolicyToKnoxNetworkPolicies(ciliumPolicy types.CiliumNetworkPolicy) []types.KnoxNetworkPolicy {
+	policies := []types.KnoxNetworkPolicy{}
+
+	newPolicy := func(policyType string) types.KnoxNetworkPolicy {
+		policy := types.KnoxNetworkPolicy{
+			APIVersion: "v1",
+			Kind:       types.KindKnoxNetworkPolicy,
+			Metadata: map[string]string{
+				"name":      ciliumPolicy.Metadata["name"],
+				"namespace": ciliumPolicy.Metadata["namespace"],
+				"type":      policyType,
+			},
+		}
+
+		if len(ciliumPolicy.Spec.NodeSelector.MatchLabels) > 0 {
+			policy.Kind = types.KindKnoxHostNetworkPolicy
+			policy.Spec.Selector.MatchLabels = ciliumPolicy.Spec.NodeSelector.MatchLabels
+		} else {
+			policy.Spec.Selector = ciliumPolicy.Spec.EndpointSelector
+		}
+
+		return policy
+	}
+
+	if len(ciliumPolicy.Spec.Ingress) > 0 {
+		ingressPolicy := newPolicy("ingress")
+		for _, ciliumIngress := range ciliumPolicy.Spec.Ingress {
+			ingressPolicy.Spec.Ingress = append(ingressPolicy.Spec.Ingress, convertCiliumIngressToKnox(ciliumIngress)...)
+		}
+		policies = append(policies, ingressPolicy)
+	}
+
+	if len(ciliumPolicy.Spec.Egress) > 0 {
+		egressPolicy := newPolicy("egress")
+		for _, ciliumEgress := range ciliumPolicy.Spec.Egress {
+			egressPolicy.Spec.Egress = append(egressPolicy.Spec.Egress, convertCiliumEgressToKnox(ciliumEgress)...)
+		}
+		policies = append(policies, egressPolicy)
+	}
+
+	return policies
+}
+
 // ========================= //
 // == Cilium Hubble Relay == //
 // ========================= //
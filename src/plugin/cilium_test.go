@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/accuknox/auto-policy-discovery/src/config"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 	flow "github.com/cilium/cilium/api/v1/flow"
 	"github.com/google/go-cmp/cmp"
@@ -78,10 +79,11 @@ func TestConvertCiliumFlowToKnoxLog(t *testing.T) {
 			"src_port": 6379,
 			"dst_port": 60416,
 			"direction": "INGRESS",
-			"action": "allow"
+			"action": "allow",
+			"timestamp": 1605679254
 		}
 	*/
-	logBytes := []byte("{\"src_namespace\":\"default\",\"src_pod_name\":\"redis-cart-74594bd569-gw2xb\",\"dst_reserved_labels\":[\"reserved:host\"],\"protocol\":6,\"src_ip\":\"10.0.1.31\",\"dst_ip\":\"10.0.1.144\",\"src_port\":6379,\"dst_port\":60416,\"direction\":\"INGRESS\",\"action\":\"allow\"}")
+	logBytes := []byte("{\"src_namespace\":\"default\",\"src_pod_name\":\"redis-cart-74594bd569-gw2xb\",\"dst_reserved_labels\":[\"reserved:host\"],\"protocol\":6,\"src_ip\":\"10.0.1.31\",\"dst_ip\":\"10.0.1.144\",\"src_port\":6379,\"dst_port\":60416,\"direction\":\"INGRESS\",\"action\":\"allow\",\"timestamp\":1605679254}")
 	flow := &flow.Flow{}
 	json.Unmarshal(flowBytes, flow)
 
@@ -182,3 +184,118 @@ func TestConvertKnoxPolicyToCiliumPolicy(t *testing.T) {
 		t.Errorf("they should be equal %v %v", expected, actual)
 	}
 }
+
+func TestConvertKnoxFQDNPolicyToCiliumPolicy(t *testing.T) {
+	knoxPolicy := types.KnoxNetworkPolicy{
+		Metadata: map[string]string{"name": "autopol-egress-uploader", "namespace": "default"},
+		Spec: types.Spec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "uploader"}},
+			Egress: []types.Egress{{
+				ToPorts: []types.SpecPort{{Port: "443", Protocol: "tcp"}},
+				ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"api.github.com"}, MatchPatterns: []string{"*.s3.amazonaws.com"}}},
+			}},
+		},
+	}
+
+	// no egress to kube-dns is added for the dns proxy
+	actual := ConvertKnoxNetworkPolicyToCiliumPolicy(knoxPolicy)
+	if len(actual.Spec.Egress) != 1 {
+		t.Fatalf("there should be a fqdn egress rule only %v", actual.Spec.Egress)
+	}
+	if !cmp.Equal([]types.CiliumFQDN{{"matchName": "api.github.com"}, {"matchPattern": "*.s3.amazonaws.com"}}, actual.Spec.Egress[0].ToFQDNs) {
+		t.Errorf("unexpected fqdn rule %v", actual.Spec.Egress[0].ToFQDNs)
+	}
+
+	knoxPolicy.Spec.Egress = append(knoxPolicy.Spec.Egress, types.Egress{
+		MatchLabels: map[string]string{"k8s-app": "kube-dns", "io.kubernetes.pod.namespace": "kube-system"},
+		ToPorts:     []types.SpecPort{{Port: "53", Protocol: "udp"}},
+	})
+
+	// the egress to kube-dns is kept as discovered
+	actual = ConvertKnoxNetworkPolicyToCiliumPolicy(knoxPolicy)
+	if len(actual.Spec.Egress) != 2 {
+		t.Fatalf("there should be a fqdn and a kube-dns egress rule %v", actual.Spec.Egress)
+	}
+	if actual.Spec.Egress[1].ToPorts[0].Rules != nil {
+		t.Errorf("unexpected dns rules %v", actual.Spec.Egress[1].ToPorts)
+	}
+
+	// the dns lookups are not restricted to the fqdn names and patterns, the other names are resolved still
+	config.CurrentCfg.ConfigNetPolicy.NetFQDNPatternThreshold = 2
+	defer func() { config.CurrentCfg.ConfigNetPolicy.NetFQDNPatternThreshold = 0 }()
+
+	actual = ConvertKnoxNetworkPolicyToCiliumPolicy(knoxPolicy)
+	if len(actual.Spec.Egress) != 2 {
+		t.Fatalf("there should be a fqdn and a kube-dns egress rule %v", actual.Spec.Egress)
+	}
+	if actual.Spec.Egress[1].ToPorts[0].Rules != nil {
+		t.Errorf("unexpected dns rules %v", actual.Spec.Egress[1].ToPorts)
+	}
+}
+
+func TestConvertKnoxClusterNetworkPolicyToCiliumPolicy(t *testing.T) {
+	selector := types.Selector{MatchExpressions: []types.MatchExpression{{
+		Key:      "k8s:io.kubernetes.pod.namespace",
+		Operator: "In",
+		Values:   []string{"default", "shop"},
+	}}}
+
+	knoxPolicy := types.KnoxNetworkPolicy{
+		Kind:     types.KindKnoxClusterNetworkPolicy,
+		Metadata: map[string]string{"name": "autopol-egress-clusterwide", "namespace": "reserved:clusterwide"},
+		Spec: types.Spec{
+			Selector: selector,
+			Egress: []types.Egress{{
+				MatchLabels: map[string]string{"app": "metrics-server", "io.kubernetes.pod.namespace": "kube-system"},
+				ToPorts:     []types.SpecPort{{Port: "443", Protocol: "tcp"}},
+			}},
+		},
+	}
+
+	actual := ConvertKnoxNetworkPolicyToCiliumPolicy(knoxPolicy)
+	if actual.Kind != "CiliumClusterwideNetworkPolicy" {
+		t.Errorf("the kind should be a clusterwide policy %v", actual.Kind)
+	}
+	if _, ok := actual.Metadata["namespace"]; ok {
+		t.Errorf("a clusterwide policy should not have a namespace %v", actual.Metadata)
+	}
+	if !cmp.Equal(selector, actual.Spec.EndpointSelector) {
+		t.Errorf("they should be equal %v %v", selector, actual.Spec.EndpointSelector)
+	}
+
+	// the namespace expressions are kept in the knox selector
+	policies := ConvertCiliumPolicyToKnoxNetworkPolicies(actual)
+	if len(policies) != 1 || !cmp.Equal(selector, policies[0].Spec.Selector) {
+		t.Errorf("the selector should be kept %v", policies)
+	}
+}
+
+func TestConvertCiliumPolicyToKnoxNetworkPolicies(t *testing.T) {
+	ciliumBytes := []byte("{\"apiVersion\":\"cilium.io/v2\",\"kind\":\"CiliumNetworkPolicy\",\"metadata\":{\"name\":\"allow-cart\",\"namespace\":\"default\"},\"spec\":{\"endpointSelector\":{\"matchLabels\":{\"app\":\"cartservice\"}},\"egress\":[{\"toEndpoints\":[{\"matchLabels\":{\"app\":\"redis-cart\"}},{\"matchLabels\":{\"app\":\"redis-backup\"}}],\"toPorts\":[{\"ports\":[{\"port\":\"6379\",\"protocol\":\"TCP\"}]}]}],\"ingress\":[{\"toPorts\":[{\"ports\":[{\"port\":\"7070\",\"protocol\":\"TCP\"}]}]}]}}")
+
+	ciliumPolicy := types.CiliumNetworkPolicy{}
+	json.Unmarshal(ciliumBytes, &ciliumPolicy)
+
+	policies := ConvertCiliumPolicyToKnoxNetworkPolicies(ciliumPolicy)
+	if len(policies) != 2 {
+		t.Fatalf("there should be an ingress and an egress policy %v", policies)
+	}
+
+	// the ingress rule of the ports only allows any peer
+	expectedIngress := []types.Ingress{{ToPorts: []types.SpecPort{{Port: "7070", Protocol: "tcp"}}}}
+	if policies[0].Metadata["type"] != "ingress" || !cmp.Equal(expectedIngress, policies[0].Spec.Ingress) {
+		t.Errorf("they should be equal %v %v", expectedIngress, policies[0].Spec.Ingress)
+	}
+
+	// a knox rule per cilium endpoint
+	if policies[1].Metadata["type"] != "egress" || len(policies[1].Spec.Egress) != 2 {
+		t.Fatalf("there should be two egress rules %v", policies[1].Spec.Egress)
+	}
+	if !cmp.Equal(map[string]string{"app": "redis-backup"}, policies[1].Spec.Egress[1].MatchLabels) ||
+		!cmp.Equal([]types.SpecPort{{Port: "6379", Protocol: "tcp"}}, policies[1].Spec.Egress[1].ToPorts) {
+		t.Errorf("unexpected egress rule %v", policies[1].Spec.Egress[1])
+	}
+	if !cmp.Equal(map[string]string{"app": "cartservice"}, policies[1].Spec.Selector.MatchLabels) {
+		t.Errorf("unexpected selector %v", policies[1].Spec.Selector)
+	}
+}
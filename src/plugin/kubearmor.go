@@ -66,6 +66,19 @@ func ConvertKnoxSystemPolicyToKubeArmorPolicy(knoxPolicies []types.KnoxSystemPol
 
 		kubePolicy.Spec = policy.Spec
 
+		if template, ok := GetPolicyTemplate(policy.Metadata["namespace"], policy.Spec.Selector.MatchLabels); ok {
+			applyTemplateToKubeArmorPolicy(&kubePolicy, template)
+		}
+
+		if policy.Metadata["namespace"] == types.PolicyDiscoveryNodeNamespace {
+			// k8s node policy selects the node by its labels
+			kubePolicy.Kind = "KubeArmorHostPolicy"
+			kubePolicy.Spec.NodeSelector = &types.Selector{
+				MatchLabels: policy.Spec.Selector.MatchLabels,
+			}
+			kubePolicy.Spec.Selector = types.Selector{}
+		}
+
 		if kubePolicy.Kind == "KubeArmorPolicy" {
 			for _, path := range KubeArmorDefaultRuleFilePath {
 				dirRule := types.KnoxMatchDirectories{
@@ -118,6 +131,30 @@ func ConvertKnoxSystemPolicyToKubeArmorPolicy(knoxPolicies []types.KnoxSystemPol
 	return results
 }
 
+// ConvertKubeArmorPolicyToKnoxSystemPolicy converts a kubearmor policy to a knox system policy,
+// a host policy selects the nodes as the discovered policies of the k8s nodes do
+func ConvertKubeArmorPolicyToKnoxSystemPolicy(kubePolicy types.KubeArmorPolicy) types.KnoxSystemPolicy {
+	policy := types.KnoxSystemPolicy{
+		APIVersion: "v1",
+		Kind:       "KnoxSystemPolicy",
+		Metadata: map[string]string{
+			"name":      kubePolicy.Metadata["name"],
+			"namespace": kubePolicy.Metadata["namespace"],
+		},
+		Spec: kubePolicy.Spec,
+	}
+
+	if kubePolicy.Kind == "KubeArmorHostPolicy" {
+		policy.Metadata["namespace"] = types.PolicyDiscoveryNodeNamespace
+		if kubePolicy.Spec.NodeSelector != nil {
+			policy.Spec.Selector = *kubePolicy.Spec.NodeSelector
+		}
+		policy.Spec.NodeSelector = nil
+	}
+
+	return policy
+}
+
 func ConvertSQLiteKubeArmorLogsToKnoxSystemLogs(docs []map[string]interface{}) []types.KnoxSystemLog {
 	results := []types.KnoxSystemLog{}
 
@@ -165,6 +202,7 @@ func ConvertSQLiteKubeArmorLogsToKnoxSystemLogs(docs []map[string]interface{}) [
 			Data:           syslog.Data,
 			ReadOnly:       readOnly,
 			Result:         syslog.Result,
+			Timestamp:      int64(syslog.Timestamp),
 		}
 
 		results = append(results, knoxSysLog)
@@ -220,6 +258,7 @@ func ConvertMySQLKubeArmorLogsToKnoxSystemLogs(docs []map[string]interface{}) []
 			Data:           syslog.Data,
 			ReadOnly:       readOnly,
 			Result:         syslog.Result,
+			Timestamp:      int64(syslog.Timestamp),
 		}
 
 		results = append(results, knoxSysLog)
@@ -293,12 +332,19 @@ func ConvertKubeArmorLogToKnoxSystemLog(relayLog *pb.Log) (types.KnoxSystemLog,
 		Data:           relayLog.Data,
 		ReadOnly:       readOnly,
 		Result:         relayLog.Result,
+		Timestamp:      relayLog.Timestamp,
 	}
 
 	if relayLog.Type == "HostLog" {
 		knoxSystemLog.ContainerName = relayLog.HostName
-		knoxSystemLog.Namespace = types.PolicyDiscoveryVMNamespace
-		knoxSystemLog.PodName = types.PolicyDiscoveryVMPodName
+		if config.GetCfgClusterInfoFrom() == "k8sclient" {
+			// host log from a k8s node
+			knoxSystemLog.Namespace = types.PolicyDiscoveryNodeNamespace
+			knoxSystemLog.PodName = relayLog.HostName
+		} else {
+			knoxSystemLog.Namespace = types.PolicyDiscoveryVMNamespace
+			knoxSystemLog.PodName = types.PolicyDiscoveryVMPodName
+		}
 	}
 
 	if relayLog.Type == "ContainerLog" && relayLog.NamespaceName == types.PolicyDiscoveryContainerNamespace {
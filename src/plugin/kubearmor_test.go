@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +67,55 @@ func TestConvertSQLiteKubeArmorLogsToKnoxSystemLogs(t *testing.T) {
         results := ConvertSQLiteKubeArmorLogsToKnoxSystemLogs([]map[string]interface{}{doc})
         assert.Equal(t, "fd=6", results[0].Data)
 }
+
+func TestConvertKnoxSystemPolicyToKubeArmorHostPolicy(t *testing.T) {
+	policy := types.KnoxSystemPolicy{
+		Metadata: map[string]string{
+			"namespace":     types.PolicyDiscoveryNodeNamespace,
+			"containername": "node-1",
+			"name":          "autopol-system-1",
+		},
+		Spec: types.KnoxSystemSpec{
+			Selector: types.Selector{
+				MatchLabels: map[string]string{"kubernetes.io/hostname": "node-1"},
+			},
+		},
+	}
+
+	results := ConvertKnoxSystemPolicyToKubeArmorPolicy([]types.KnoxSystemPolicy{policy})
+	assert.Equal(t, "KubeArmorHostPolicy", results[0].Kind)
+	assert.Equal(t, "node-1", results[0].Spec.NodeSelector.MatchLabels["kubernetes.io/hostname"])
+	assert.Empty(t, results[0].Spec.Selector.MatchLabels)
+	assert.Empty(t, results[0].Spec.File.MatchDirectories)
+
+	// the host policy selects the node by its nodeSelector only
+	b, err := json.Marshal(results[0])
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), `"selector"`)
+	assert.Contains(t, string(b), `"nodeSelector":{"matchLabels":{"kubernetes.io/hostname":"node-1"}}`)
+}
+
+func TestGenerateDefaultDenyAuditPolicies(t *testing.T) {
+	policies := []types.KubeArmorPolicy{
+		{
+			Kind:     "KubeArmorPolicy",
+			Metadata: map[string]string{"namespace": "default", "name": "autopol-system-1"},
+			Spec: types.KnoxSystemSpec{
+				Selector: types.Selector{MatchLabels: map[string]string{"app": "test"}},
+			},
+		},
+		{
+			Kind:     "KubeArmorPolicy",
+			Metadata: map[string]string{"namespace": "default", "name": "autopol-system-2"},
+			Spec: types.KnoxSystemSpec{
+				Selector: types.Selector{MatchLabels: map[string]string{"app": "test"}},
+			},
+		},
+	}
+
+	results := GenerateDefaultDenyAuditPolicies(policies)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, PolicyActionAudit, results[0].Spec.Action)
+	assert.Equal(t, "test", results[0].Spec.Selector.MatchLabels["app"])
+	assert.Equal(t, "/", results[0].Spec.File.MatchDirectories[0].Dir)
+}
@@ -0,0 +1,201 @@
+package plugin
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	PolicyActionAllow = "Allow"
+	PolicyActionAudit = "Audit"
+	PolicyActionBlock = "Block"
+)
+
+// ===================== //
+// == Policy Template == //
+// ===================== //
+
+func normalizePolicyAction(action string) string {
+	switch strings.ToLower(action) {
+	case "allow":
+		return PolicyActionAllow
+	case "audit":
+		return PolicyActionAudit
+	case "block", "deny":
+		return PolicyActionBlock
+	}
+	return ""
+}
+
+func matchPolicyTemplate(template types.PolicyTemplate, namespace string, matchLabels map[string]string) bool {
+	if template.Namespace != "" && template.Namespace != namespace {
+		return false
+	}
+
+	for _, label := range template.Labels {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) != 2 {
+			return false
+		}
+		if val, ok := matchLabels[kv[0]]; !ok || val != kv[1] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetPolicyTemplate returns the first configured template matching the namespace and selector labels
+func GetPolicyTemplate(namespace string, matchLabels map[string]string) (types.PolicyTemplate, bool) {
+	for _, template := range config.GetCfgPolicyTemplates() {
+		if matchPolicyTemplate(template, namespace, matchLabels) {
+			return template, true
+		}
+	}
+
+	return types.PolicyTemplate{}, false
+}
+
+func applyTemplateToKubeArmorPolicy(policy *types.KubeArmorPolicy, template types.PolicyTemplate) {
+	if template.Action != "" {
+		action := normalizePolicyAction(template.Action)
+		if action == "" {
+			log.Warn().Msgf("invalid policy template action [%s]", template.Action)
+		} else if action == PolicyActionBlock {
+			// the observed processes and files are kept allowed, the allow rules put the workload in the
+			// default deny posture of kubearmor, which blocks all the rest under the block posture
+			policy.Spec.Action = PolicyActionAllow
+		} else {
+			policy.Spec.Action = action
+		}
+	}
+
+	if template.Severity > 0 {
+		policy.Spec.Severity = template.Severity
+	}
+
+	if len(template.Tags) > 0 {
+		policy.Spec.Tags = append([]string{}, template.Tags...)
+	}
+
+	if template.Message != "" {
+		policy.Spec.Message = template.Message
+	}
+}
+
+// addCiliumDefaultDeny puts the selected endpoints in the default deny of both directions, an empty rule allows
+// nothing but enables the default deny of its direction, the observed allow rules are kept
+func addCiliumDefaultDeny(policy *types.CiliumNetworkPolicy) {
+	if len(policy.Spec.Egress) == 0 {
+		policy.Spec.Egress = []types.CiliumEgress{{}}
+	}
+	if len(policy.Spec.Ingress) == 0 {
+		policy.Spec.Ingress = []types.CiliumIngress{{}}
+	}
+}
+
+func applyTemplateToCiliumPolicy(policy *types.CiliumNetworkPolicy, template types.PolicyTemplate) {
+	labels := []types.CiliumPolicyLabel{}
+
+	if template.Action != "" {
+		action := normalizePolicyAction(template.Action)
+		if action == "" {
+			log.Warn().Msgf("invalid policy template action [%s]", template.Action)
+		} else {
+			// cilium has no per-policy audit mode, so Audit keeps the allow rules and is only labeled,
+			// Block blocks all the traffic but the allowed one
+			if action == PolicyActionBlock {
+				addCiliumDefaultDeny(policy)
+			}
+			labels = append(labels, types.CiliumPolicyLabel{Key: "action", Value: action})
+		}
+	}
+
+	if template.Severity > 0 {
+		labels = append(labels, types.CiliumPolicyLabel{Key: "severity", Value: strconv.Itoa(template.Severity)})
+	}
+
+	for _, tag := range template.Tags {
+		labels = append(labels, types.CiliumPolicyLabel{Key: "tag", Value: tag})
+	}
+
+	if len(labels) > 0 {
+		policy.Spec.Labels = labels
+	}
+
+	if template.Message != "" {
+		policy.Spec.Description = template.Message
+	}
+}
+
+// ========================== //
+// == Default Audit Policy == //
+// ========================== //
+
+func getSelectorKey(namespace string, selector map[string]string) string {
+	labels := []string{}
+	for k, v := range selector {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+
+	return namespace + "/" + strings.Join(labels, ",")
+}
+
+func getDefaultDenyAuditPolicyName(key string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return "autopol-audit-" + strconv.FormatUint(uint64(h.Sum32()), 10)
+}
+
+// GenerateDefaultDenyAuditPolicies builds one companion policy per selected workload of the k8s policies,
+// which audits all the process, file and network accesses not covered by the discovered policies
+func GenerateDefaultDenyAuditPolicies(policies []types.KubeArmorPolicy) []types.KubeArmorPolicy {
+	results := []types.KubeArmorPolicy{}
+	added := map[string]bool{}
+
+	for _, policy := range policies {
+		key := getSelectorKey(policy.Metadata["namespace"], policy.Spec.Selector.MatchLabels)
+		if added[key] {
+			continue
+		}
+		added[key] = true
+
+		auditPolicy := types.KubeArmorPolicy{
+			APIVersion: policy.APIVersion,
+			Kind:       policy.Kind,
+			Metadata:   map[string]string{},
+		}
+
+		for k, v := range policy.Metadata {
+			auditPolicy.Metadata[k] = v
+		}
+		auditPolicy.Metadata["name"] = getDefaultDenyAuditPolicyName(key)
+
+		auditPolicy.Spec = types.KnoxSystemSpec{
+			Severity: policy.Spec.Severity,
+			Tags:     policy.Spec.Tags,
+			Message:  "default deny (audit)",
+			Selector: policy.Spec.Selector,
+			Process: types.KnoxSys{
+				MatchDirectories: []types.KnoxMatchDirectories{{Dir: "/", Recursive: true}},
+			},
+			File: types.KnoxSys{
+				MatchDirectories: []types.KnoxMatchDirectories{{Dir: "/", Recursive: true}},
+			},
+			Network: types.NetworkRule{
+				MatchProtocols: []types.KnoxMatchProtocols{{Protocol: "tcp"}, {Protocol: "udp"}, {Protocol: "icmp"}},
+			},
+			Action: PolicyActionAudit,
+		}
+
+		results = append(results, auditPolicy)
+	}
+
+	return results
+}
@@ -0,0 +1,93 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPolicyTemplate(t *testing.T) {
+	template := types.PolicyTemplate{
+		Namespace: "default",
+		Labels:    []string{"app=checkout"},
+	}
+
+	assert.True(t, matchPolicyTemplate(template, "default", map[string]string{"app": "checkout", "tier": "web"}))
+	assert.False(t, matchPolicyTemplate(template, "default", map[string]string{"app": "cart"}))
+	assert.False(t, matchPolicyTemplate(template, "payments", map[string]string{"app": "checkout"}))
+	assert.True(t, matchPolicyTemplate(types.PolicyTemplate{}, "payments", nil))
+}
+
+func TestApplyTemplateToKubeArmorPolicy(t *testing.T) {
+	policy := types.KubeArmorPolicy{Spec: types.KnoxSystemSpec{Action: "Allow", Severity: 1}}
+
+	applyTemplateToKubeArmorPolicy(&policy, types.PolicyTemplate{
+		Action:   "audit",
+		Severity: 5,
+		Tags:     []string{"PCI-DSS"},
+		Message:  "checkout",
+	})
+
+	assert.Equal(t, PolicyActionAudit, policy.Spec.Action)
+	assert.Equal(t, 5, policy.Spec.Severity)
+	assert.Equal(t, []string{"PCI-DSS"}, policy.Spec.Tags)
+	assert.Equal(t, "checkout", policy.Spec.Message)
+}
+
+func TestConvertKnoxSystemPolicyWithBlockTemplate(t *testing.T) {
+	templates := config.CurrentCfg.ConfigPolicyTemplates
+	defer func() { config.CurrentCfg.ConfigPolicyTemplates = templates }()
+	config.CurrentCfg.ConfigPolicyTemplates = []types.PolicyTemplate{{Namespace: "default", Action: "Block"}}
+
+	policy := types.KnoxSystemPolicy{
+		Metadata: map[string]string{"name": "autopol-system-1", "namespace": "default"},
+		Spec: types.KnoxSystemSpec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "checkout"}},
+			Process:  types.KnoxSys{MatchPaths: []types.KnoxMatchPaths{{Path: "/usr/bin/python3"}}},
+			File:     types.KnoxSys{MatchPaths: []types.KnoxMatchPaths{{Path: "/etc/app.conf"}}},
+			Action:   "Allow",
+		},
+	}
+
+	// the observed paths stay allowed, the rest is blocked by the default deny posture
+	kubePolicies := ConvertKnoxSystemPolicyToKubeArmorPolicy([]types.KnoxSystemPolicy{policy})
+	assert.Equal(t, 1, len(kubePolicies))
+	assert.Equal(t, PolicyActionAllow, kubePolicies[0].Spec.Action)
+	assert.Equal(t, []types.KnoxMatchPaths{{Path: "/usr/bin/python3"}}, kubePolicies[0].Spec.Process.MatchPaths)
+	assert.Equal(t, []types.KnoxMatchPaths{{Path: "/etc/app.conf"}}, kubePolicies[0].Spec.File.MatchPaths)
+}
+
+func TestApplyTemplateToCiliumPolicy(t *testing.T) {
+	policy := types.CiliumNetworkPolicy{
+		Metadata: map[string]string{"name": "autopol-egress-1"},
+		Spec: types.CiliumSpec{
+			Egress: []types.CiliumEgress{
+				{ToCIDRs: []string{"10.0.0.1/32"}},
+				{ToFQDNs: []types.CiliumFQDN{{"matchName": "example.com"}}},
+			},
+			Ingress: []types.CiliumIngress{
+				{FromEntities: []string{"world"}},
+			},
+		},
+	}
+
+	applyTemplateToCiliumPolicy(&policy, types.PolicyTemplate{Action: "Block", Severity: 3, Message: "deny"})
+
+	// the observed rules are still allowed, fqdn rules included
+	assert.Equal(t, 2, len(policy.Spec.Egress))
+	assert.Equal(t, 1, len(policy.Spec.Ingress))
+	assert.Empty(t, policy.Spec.EgressDeny)
+	assert.Empty(t, policy.Spec.IngressDeny)
+	assert.Equal(t, "deny", policy.Spec.Description)
+	assert.Equal(t, types.CiliumPolicyLabel{Key: "severity", Value: "3"}, policy.Spec.Labels[1])
+
+	// the direction without any allow rule is in the default deny as well
+	egressOnly := types.CiliumNetworkPolicy{
+		Spec: types.CiliumSpec{Egress: []types.CiliumEgress{{ToCIDRs: []string{"10.0.0.1/32"}}}},
+	}
+	applyTemplateToCiliumPolicy(&egressOnly, types.PolicyTemplate{Action: "Block"})
+	assert.Equal(t, []types.CiliumIngress{{}}, egressOnly.Spec.Ingress)
+	assert.Equal(t, 1, len(egressOnly.Spec.Egress))
+}
@@ -23,6 +23,8 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type InsightClient interface {
 	GetInsightData(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	GetServiceGraph(ctx context.Context, in *GraphRequest, opts ...grpc.CallOption) (*GraphResponse, error)
+	GetRuleEvidence(ctx context.Context, in *EvidenceRequest, opts ...grpc.CallOption) (*EvidenceResponse, error)
 }
 
 type insightClient struct {
@@ -42,11 +44,31 @@ func (c *insightClient) GetInsightData(ctx context.Context, in *Request, opts ..
 	return out, nil
 }
 
+func (c *insightClient) GetServiceGraph(ctx context.Context, in *GraphRequest, opts ...grpc.CallOption) (*GraphResponse, error) {
+	out := new(GraphResponse)
+	err := c.cc.Invoke(ctx, "/v1.insight.Insight/GetServiceGraph", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *insightClient) GetRuleEvidence(ctx context.Context, in *EvidenceRequest, opts ...grpc.CallOption) (*EvidenceResponse, error) {
+	out := new(EvidenceResponse)
+	err := c.cc.Invoke(ctx, "/v1.insight.Insight/GetRuleEvidence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InsightServer is the server API for Insight service.
 // All implementations must embed UnimplementedInsightServer
 // for forward compatibility
 type InsightServer interface {
 	GetInsightData(context.Context, *Request) (*Response, error)
+	GetServiceGraph(context.Context, *GraphRequest) (*GraphResponse, error)
+	GetRuleEvidence(context.Context, *EvidenceRequest) (*EvidenceResponse, error)
 	mustEmbedUnimplementedInsightServer()
 }
 
@@ -57,6 +79,12 @@ type UnimplementedInsightServer struct {
 func (UnimplementedInsightServer) GetInsightData(context.Context, *Request) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInsightData not implemented")
 }
+func (UnimplementedInsightServer) GetServiceGraph(context.Context, *GraphRequest) (*GraphResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceGraph not implemented")
+}
+func (UnimplementedInsightServer) GetRuleEvidence(context.Context, *EvidenceRequest) (*EvidenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRuleEvidence not implemented")
+}
 func (UnimplementedInsightServer) mustEmbedUnimplementedInsightServer() {}
 
 // UnsafeInsightServer may be embedded to opt out of forward compatibility for this service.
@@ -88,6 +116,42 @@ func _Insight_GetInsightData_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Insight_GetServiceGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InsightServer).GetServiceGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.insight.Insight/GetServiceGraph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InsightServer).GetServiceGraph(ctx, req.(*GraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Insight_GetRuleEvidence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvidenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InsightServer).GetRuleEvidence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.insight.Insight/GetRuleEvidence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InsightServer).GetRuleEvidence(ctx, req.(*EvidenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Insight_ServiceDesc is the grpc.ServiceDesc for Insight service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -99,6 +163,14 @@ var Insight_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetInsightData",
 			Handler:    _Insight_GetInsightData_Handler,
 		},
+		{
+			MethodName: "GetServiceGraph",
+			Handler:    _Insight_GetServiceGraph_Handler,
+		},
+		{
+			MethodName: "GetRuleEvidence",
+			Handler:    _Insight_GetRuleEvidence_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/insight/insight.proto",
@@ -130,3 +130,202 @@ var Summary_ServiceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "v1/observability/observability.proto",
 }
+
+// AnomalyClient is the client API for Anomaly service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AnomalyClient interface {
+	WatchAlerts(ctx context.Context, in *AnomalyRequest, opts ...grpc.CallOption) (Anomaly_WatchAlertsClient, error)
+}
+
+type anomalyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAnomalyClient(cc grpc.ClientConnInterface) AnomalyClient {
+	return &anomalyClient{cc}
+}
+
+func (c *anomalyClient) WatchAlerts(ctx context.Context, in *AnomalyRequest, opts ...grpc.CallOption) (Anomaly_WatchAlertsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Anomaly_ServiceDesc.Streams[0], "/v1.observability.Anomaly/WatchAlerts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &anomalyWatchAlertsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Anomaly_WatchAlertsClient interface {
+	Recv() (*AnomalyAlert, error)
+	grpc.ClientStream
+}
+
+type anomalyWatchAlertsClient struct {
+	grpc.ClientStream
+}
+
+func (x *anomalyWatchAlertsClient) Recv() (*AnomalyAlert, error) {
+	m := new(AnomalyAlert)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AnomalyServer is the server API for Anomaly service.
+// All implementations must embed UnimplementedAnomalyServer
+// for forward compatibility
+type AnomalyServer interface {
+	WatchAlerts(*AnomalyRequest, Anomaly_WatchAlertsServer) error
+	mustEmbedUnimplementedAnomalyServer()
+}
+
+// UnimplementedAnomalyServer must be embedded to have forward compatible implementations.
+type UnimplementedAnomalyServer struct {
+}
+
+func (UnimplementedAnomalyServer) WatchAlerts(*AnomalyRequest, Anomaly_WatchAlertsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAlerts not implemented")
+}
+func (UnimplementedAnomalyServer) mustEmbedUnimplementedAnomalyServer() {}
+
+// UnsafeAnomalyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AnomalyServer will
+// result in compilation errors.
+type UnsafeAnomalyServer interface {
+	mustEmbedUnimplementedAnomalyServer()
+}
+
+func RegisterAnomalyServer(s grpc.ServiceRegistrar, srv AnomalyServer) {
+	s.RegisterService(&Anomaly_ServiceDesc, srv)
+}
+
+func _Anomaly_WatchAlerts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AnomalyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AnomalyServer).WatchAlerts(m, &anomalyWatchAlertsServer{stream})
+}
+
+type Anomaly_WatchAlertsServer interface {
+	Send(*AnomalyAlert) error
+	grpc.ServerStream
+}
+
+type anomalyWatchAlertsServer struct {
+	grpc.ServerStream
+}
+
+func (x *anomalyWatchAlertsServer) Send(m *AnomalyAlert) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Anomaly_ServiceDesc is the grpc.ServiceDesc for Anomaly service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Anomaly_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.observability.Anomaly",
+	HandlerType: (*AnomalyServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAlerts",
+			Handler:       _Anomaly_WatchAlerts_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "v1/observability/observability.proto",
+}
+
+// ExportClient is the client API for Export service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExportClient interface {
+	ExportLogs(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error)
+}
+
+type exportClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExportClient(cc grpc.ClientConnInterface) ExportClient {
+	return &exportClient{cc}
+}
+
+func (c *exportClient) ExportLogs(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error) {
+	out := new(ExportResponse)
+	err := c.cc.Invoke(ctx, "/v1.observability.Export/ExportLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExportServer is the server API for Export service.
+// All implementations must embed UnimplementedExportServer
+// for forward compatibility
+type ExportServer interface {
+	ExportLogs(context.Context, *ExportRequest) (*ExportResponse, error)
+	mustEmbedUnimplementedExportServer()
+}
+
+// UnimplementedExportServer must be embedded to have forward compatible implementations.
+type UnimplementedExportServer struct {
+}
+
+func (UnimplementedExportServer) ExportLogs(context.Context, *ExportRequest) (*ExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportLogs not implemented")
+}
+func (UnimplementedExportServer) mustEmbedUnimplementedExportServer() {}
+
+// UnsafeExportServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExportServer will
+// result in compilation errors.
+type UnsafeExportServer interface {
+	mustEmbedUnimplementedExportServer()
+}
+
+func RegisterExportServer(s grpc.ServiceRegistrar, srv ExportServer) {
+	s.RegisterService(&Export_ServiceDesc, srv)
+}
+
+func _Export_ExportLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExportServer).ExportLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.observability.Export/ExportLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExportServer).ExportLogs(ctx, req.(*ExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Export_ServiceDesc is the grpc.ServiceDesc for Export service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Export_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.observability.Export",
+	HandlerType: (*ExportServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExportLogs",
+			Handler:    _Export_ExportLogs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v1/observability/observability.proto",
+}
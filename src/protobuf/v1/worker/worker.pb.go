@@ -123,6 +123,7 @@ type WorkerResponse struct {
 	Res             string             `protobuf:"bytes,1,opt,name=res,proto3" json:"res,omitempty"`
 	Kubearmorpolicy []*KubeArmorPolicy `protobuf:"bytes,2,rep,name=kubearmorpolicy,proto3" json:"kubearmorpolicy,omitempty"`
 	Ciliumpolicy    []*CiliumPolicy    `protobuf:"bytes,3,rep,name=ciliumpolicy,proto3" json:"ciliumpolicy,omitempty"`
+	Learningstate   []*LearningState   `protobuf:"bytes,4,rep,name=learningstate,proto3" json:"learningstate,omitempty"`
 }
 
 func (x *WorkerResponse) Reset() {
@@ -178,31 +179,578 @@ func (x *WorkerResponse) GetCiliumpolicy() []*CiliumPolicy {
 	return nil
 }
 
+func (x *WorkerResponse) GetLearningstate() []*LearningState {
+	if x != nil {
+		return x.Learningstate
+	}
+	return nil
+}
+
 type KubeArmorPolicy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data []byte `protobuf:"bytes,1,opt,name=Data,proto3" json:"Data,omitempty"`
+	Data []byte `protobuf:"bytes,1,opt,name=Data,proto3" json:"Data,omitempty"`
+}
+
+func (x *KubeArmorPolicy) Reset() {
+	*x = KubeArmorPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_worker_worker_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KubeArmorPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubeArmorPolicy) ProtoMessage() {}
+
+func (x *KubeArmorPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_worker_worker_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubeArmorPolicy.ProtoReflect.Descriptor instead.
+func (*KubeArmorPolicy) Descriptor() ([]byte, []int) {
+	return file_v1_worker_worker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *KubeArmorPolicy) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type CiliumPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []byte `protobuf:"bytes,1,opt,name=Data,proto3" json:"Data,omitempty"`
+}
+
+func (x *CiliumPolicy) Reset() {
+	*x = CiliumPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_worker_worker_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CiliumPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CiliumPolicy) ProtoMessage() {}
+
+func (x *CiliumPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_worker_worker_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CiliumPolicy.ProtoReflect.Descriptor instead.
+func (*CiliumPolicy) Descriptor() ([]byte, []int) {
+	return file_v1_worker_worker_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CiliumPolicy) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type LearningState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Clustername     string `protobuf:"bytes,1,opt,name=clustername,proto3" json:"clustername,omitempty"`
+	Namespace       string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Containername   string `protobuf:"bytes,3,opt,name=containername,proto3" json:"containername,omitempty"`
+	Labels          string `protobuf:"bytes,4,opt,name=labels,proto3" json:"labels,omitempty"`
+	State           string `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	Stableintervals int32  `protobuf:"varint,6,opt,name=stableintervals,proto3" json:"stableintervals,omitempty"`
+	Firstseentime   int64  `protobuf:"varint,7,opt,name=firstseentime,proto3" json:"firstseentime,omitempty"`
+	Lastchangetime  int64  `protobuf:"varint,8,opt,name=lastchangetime,proto3" json:"lastchangetime,omitempty"`
+}
+
+func (x *LearningState) Reset() {
+	*x = LearningState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_worker_worker_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LearningState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LearningState) ProtoMessage() {}
+
+func (x *LearningState) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_worker_worker_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LearningState.ProtoReflect.Descriptor instead.
+func (*LearningState) Descriptor() ([]byte, []int) {
+	return file_v1_worker_worker_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LearningState) GetClustername() string {
+	if x != nil {
+		return x.Clustername
+	}
+	return ""
+}
+
+func (x *LearningState) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *LearningState) GetContainername() string {
+	if x != nil {
+		return x.Containername
+	}
+	return ""
+}
+
+func (x *LearningState) GetLabels() string {
+	if x != nil {
+		return x.Labels
+	}
+	return ""
+}
+
+func (x *LearningState) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *LearningState) GetStableintervals() int32 {
+	if x != nil {
+		return x.Stableintervals
+	}
+	return 0
+}
+
+func (x *LearningState) GetFirstseentime() int64 {
+	if x != nil {
+		return x.Firstseentime
+	}
+	return 0
+}
+
+func (x *LearningState) GetLastchangetime() int64 {
+	if x != nil {
+		return x.Lastchangetime
+	}
+	return 0
+}
+
+type ReviewRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Policytype  string `protobuf:"bytes,1,opt,name=policytype,proto3" json:"policytype,omitempty"`
+	Clustername string `protobuf:"bytes,2,opt,name=clustername,proto3" json:"clustername,omitempty"`
+	Name        string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	State       string `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	Reviewer    string `protobuf:"bytes,5,opt,name=reviewer,proto3" json:"reviewer,omitempty"`
+	Comment     string `protobuf:"bytes,6,opt,name=comment,proto3" json:"comment,omitempty"`
+}
+
+func (x *ReviewRequest) Reset() {
+	*x = ReviewRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_worker_worker_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewRequest) ProtoMessage() {}
+
+func (x *ReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_worker_worker_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewRequest.ProtoReflect.Descriptor instead.
+func (*ReviewRequest) Descriptor() ([]byte, []int) {
+	return file_v1_worker_worker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReviewRequest) GetPolicytype() string {
+	if x != nil {
+		return x.Policytype
+	}
+	return ""
+}
+
+func (x *ReviewRequest) GetClustername() string {
+	if x != nil {
+		return x.Clustername
+	}
+	return ""
+}
+
+func (x *ReviewRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReviewRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ReviewRequest) GetReviewer() string {
+	if x != nil {
+		return x.Reviewer
+	}
+	return ""
+}
+
+func (x *ReviewRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type ReviewResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Res          string `protobuf:"bytes,1,opt,name=res,proto3" json:"res,omitempty"`
+	Name         string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Prevstate    string `protobuf:"bytes,3,opt,name=prevstate,proto3" json:"prevstate,omitempty"`
+	State        string `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	Reviewedtime int64  `protobuf:"varint,5,opt,name=reviewedtime,proto3" json:"reviewedtime,omitempty"`
+}
+
+func (x *ReviewResponse) Reset() {
+	*x = ReviewResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_worker_worker_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewResponse) ProtoMessage() {}
+
+func (x *ReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_worker_worker_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewResponse.ProtoReflect.Descriptor instead.
+func (*ReviewResponse) Descriptor() ([]byte, []int) {
+	return file_v1_worker_worker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReviewResponse) GetRes() string {
+	if x != nil {
+		return x.Res
+	}
+	return ""
+}
+
+func (x *ReviewResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReviewResponse) GetPrevstate() string {
+	if x != nil {
+		return x.Prevstate
+	}
+	return ""
+}
+
+func (x *ReviewResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ReviewResponse) GetReviewedtime() int64 {
+	if x != nil {
+		return x.Reviewedtime
+	}
+	return 0
+}
+
+type ApplyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Policytype  string `protobuf:"bytes,1,opt,name=policytype,proto3" json:"policytype,omitempty"`
+	Clustername string `protobuf:"bytes,2,opt,name=clustername,proto3" json:"clustername,omitempty"`
+	Name        string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Reviewer    string `protobuf:"bytes,4,opt,name=reviewer,proto3" json:"reviewer,omitempty"`
+	Dryrun      bool   `protobuf:"varint,5,opt,name=dryrun,proto3" json:"dryrun,omitempty"`
+}
+
+func (x *ApplyRequest) Reset() {
+	*x = ApplyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_worker_worker_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApplyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyRequest) ProtoMessage() {}
+
+func (x *ApplyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_worker_worker_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyRequest.ProtoReflect.Descriptor instead.
+func (*ApplyRequest) Descriptor() ([]byte, []int) {
+	return file_v1_worker_worker_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ApplyRequest) GetPolicytype() string {
+	if x != nil {
+		return x.Policytype
+	}
+	return ""
+}
+
+func (x *ApplyRequest) GetClustername() string {
+	if x != nil {
+		return x.Clustername
+	}
+	return ""
+}
+
+func (x *ApplyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ApplyRequest) GetReviewer() string {
+	if x != nil {
+		return x.Reviewer
+	}
+	return ""
+}
+
+func (x *ApplyRequest) GetDryrun() bool {
+	if x != nil {
+		return x.Dryrun
+	}
+	return false
+}
+
+type ApplyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Res             string `protobuf:"bytes,1,opt,name=res,proto3" json:"res,omitempty"`
+	Kind            string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Namespace       string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name            string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Action          string `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	Dryrun          bool   `protobuf:"varint,6,opt,name=dryrun,proto3" json:"dryrun,omitempty"`
+	Resourceversion string `protobuf:"bytes,7,opt,name=resourceversion,proto3" json:"resourceversion,omitempty"`
+}
+
+func (x *ApplyResponse) Reset() {
+	*x = ApplyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_worker_worker_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApplyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyResponse) ProtoMessage() {}
+
+func (x *ApplyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_worker_worker_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyResponse.ProtoReflect.Descriptor instead.
+func (*ApplyResponse) Descriptor() ([]byte, []int) {
+	return file_v1_worker_worker_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ApplyResponse) GetRes() string {
+	if x != nil {
+		return x.Res
+	}
+	return ""
+}
+
+func (x *ApplyResponse) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ApplyResponse) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ApplyResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ApplyResponse) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ApplyResponse) GetDryrun() bool {
+	if x != nil {
+		return x.Dryrun
+	}
+	return false
+}
+
+func (x *ApplyResponse) GetResourceversion() string {
+	if x != nil {
+		return x.Resourceversion
+	}
+	return ""
+}
+
+type PolicyChangeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Policytype  string `protobuf:"bytes,1,opt,name=policytype,proto3" json:"policytype,omitempty"`
+	Clustername string `protobuf:"bytes,2,opt,name=clustername,proto3" json:"clustername,omitempty"`
+	Namespace   string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name        string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
 }
 
-func (x *KubeArmorPolicy) Reset() {
-	*x = KubeArmorPolicy{}
+func (x *PolicyChangeRequest) Reset() {
+	*x = PolicyChangeRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_worker_worker_proto_msgTypes[2]
+		mi := &file_v1_worker_worker_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *KubeArmorPolicy) String() string {
+func (x *PolicyChangeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KubeArmorPolicy) ProtoMessage() {}
+func (*PolicyChangeRequest) ProtoMessage() {}
 
-func (x *KubeArmorPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_worker_worker_proto_msgTypes[2]
+func (x *PolicyChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_worker_worker_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -213,43 +761,168 @@ func (x *KubeArmorPolicy) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KubeArmorPolicy.ProtoReflect.Descriptor instead.
-func (*KubeArmorPolicy) Descriptor() ([]byte, []int) {
-	return file_v1_worker_worker_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use PolicyChangeRequest.ProtoReflect.Descriptor instead.
+func (*PolicyChangeRequest) Descriptor() ([]byte, []int) {
+	return file_v1_worker_worker_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *KubeArmorPolicy) GetData() []byte {
+func (x *PolicyChangeRequest) GetPolicytype() string {
 	if x != nil {
-		return x.Data
+		return x.Policytype
+	}
+	return ""
+}
+
+func (x *PolicyChangeRequest) GetClustername() string {
+	if x != nil {
+		return x.Clustername
+	}
+	return ""
+}
+
+func (x *PolicyChangeRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *PolicyChangeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type PolicyChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Policytype    string   `protobuf:"bytes,1,opt,name=policytype,proto3" json:"policytype,omitempty"`
+	Clustername   string   `protobuf:"bytes,2,opt,name=clustername,proto3" json:"clustername,omitempty"`
+	Namespace     string   `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name          string   `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Newpolicyname string   `protobuf:"bytes,5,opt,name=newpolicyname,proto3" json:"newpolicyname,omitempty"`
+	Reason        string   `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Removedrules  []string `protobuf:"bytes,7,rep,name=removedrules,proto3" json:"removedrules,omitempty"`
+	Changedtime   int64    `protobuf:"varint,8,opt,name=changedtime,proto3" json:"changedtime,omitempty"`
+}
+
+func (x *PolicyChange) Reset() {
+	*x = PolicyChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_worker_worker_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PolicyChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyChange) ProtoMessage() {}
+
+func (x *PolicyChange) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_worker_worker_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyChange.ProtoReflect.Descriptor instead.
+func (*PolicyChange) Descriptor() ([]byte, []int) {
+	return file_v1_worker_worker_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PolicyChange) GetPolicytype() string {
+	if x != nil {
+		return x.Policytype
+	}
+	return ""
+}
+
+func (x *PolicyChange) GetClustername() string {
+	if x != nil {
+		return x.Clustername
+	}
+	return ""
+}
+
+func (x *PolicyChange) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *PolicyChange) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PolicyChange) GetNewpolicyname() string {
+	if x != nil {
+		return x.Newpolicyname
+	}
+	return ""
+}
+
+func (x *PolicyChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PolicyChange) GetRemovedrules() []string {
+	if x != nil {
+		return x.Removedrules
 	}
 	return nil
 }
 
-type CiliumPolicy struct {
+func (x *PolicyChange) GetChangedtime() int64 {
+	if x != nil {
+		return x.Changedtime
+	}
+	return 0
+}
+
+type PolicyChangeResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data []byte `protobuf:"bytes,1,opt,name=Data,proto3" json:"Data,omitempty"`
+	Res          string          `protobuf:"bytes,1,opt,name=res,proto3" json:"res,omitempty"`
+	Policychange []*PolicyChange `protobuf:"bytes,2,rep,name=policychange,proto3" json:"policychange,omitempty"`
 }
 
-func (x *CiliumPolicy) Reset() {
-	*x = CiliumPolicy{}
+func (x *PolicyChangeResponse) Reset() {
+	*x = PolicyChangeResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_worker_worker_proto_msgTypes[3]
+		mi := &file_v1_worker_worker_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CiliumPolicy) String() string {
+func (x *PolicyChangeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CiliumPolicy) ProtoMessage() {}
+func (*PolicyChangeResponse) ProtoMessage() {}
 
-func (x *CiliumPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_worker_worker_proto_msgTypes[3]
+func (x *PolicyChangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_worker_worker_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -260,14 +933,21 @@ func (x *CiliumPolicy) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CiliumPolicy.ProtoReflect.Descriptor instead.
-func (*CiliumPolicy) Descriptor() ([]byte, []int) {
-	return file_v1_worker_worker_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use PolicyChangeResponse.ProtoReflect.Descriptor instead.
+func (*PolicyChangeResponse) Descriptor() ([]byte, []int) {
+	return file_v1_worker_worker_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *CiliumPolicy) GetData() []byte {
+func (x *PolicyChangeResponse) GetRes() string {
 	if x != nil {
-		return x.Data
+		return x.Res
+	}
+	return ""
+}
+
+func (x *PolicyChangeResponse) GetPolicychange() []*PolicyChange {
+	if x != nil {
+		return x.Policychange
 	}
 	return nil
 }
@@ -290,7 +970,7 @@ var file_v1_worker_worker_proto_rawDesc = []byte{
 	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x72, 0x6f,
 	0x6d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66,
-	0x72, 0x6f, 0x6d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0xa5, 0x01, 0x0a, 0x0e, 0x57, 0x6f,
+	0x72, 0x6f, 0x6d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0xe5, 0x01, 0x0a, 0x0e, 0x57, 0x6f,
 	0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03,
 	0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x73, 0x12, 0x44,
 	0x0a, 0x0f, 0x6b, 0x75, 0x62, 0x65, 0x61, 0x72, 0x6d, 0x6f, 0x72, 0x70, 0x6f, 0x6c, 0x69, 0x63,
@@ -301,32 +981,147 @@ var file_v1_worker_worker_proto_rawDesc = []byte{
 	0x6c, 0x69, 0x63, 0x79, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x31, 0x2e,
 	0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x43, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x50, 0x6f, 0x6c,
 	0x69, 0x63, 0x79, 0x52, 0x0c, 0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x70, 0x6f, 0x6c, 0x69, 0x63,
-	0x79, 0x22, 0x25, 0x0a, 0x0f, 0x4b, 0x75, 0x62, 0x65, 0x41, 0x72, 0x6d, 0x6f, 0x72, 0x50, 0x6f,
+	0x79, 0x12, 0x3e, 0x0a, 0x0d, 0x6c, 0x65, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f,
+	0x72, 0x6b, 0x65, 0x72, 0x2e, 0x4c, 0x65, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x0d, 0x6c, 0x65, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x22, 0x25, 0x0a, 0x0f, 0x4b, 0x75, 0x62, 0x65, 0x41, 0x72, 0x6d, 0x6f, 0x72, 0x50, 0x6f,
 	0x6c, 0x69, 0x63, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x22, 0x22, 0x0a, 0x0c, 0x43, 0x69, 0x6c, 0x69,
 	0x75, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x32, 0x8b, 0x02, 0x0a,
-	0x06, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x46, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x57, 0x6f,
-	0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e,
-	0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72,
-	0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x3c, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x22, 0x9b, 0x02, 0x0a,
+	0x0d, 0x4c, 0x65, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x24,
+	0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x73, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x73, 0x12, 0x24, 0x0a, 0x0d,
+	0x66, 0x69, 0x72, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0d, 0x66, 0x69, 0x72, 0x73, 0x74, 0x73, 0x65, 0x65, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x6c, 0x61, 0x73, 0x74, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6c, 0x61, 0x73, 0x74,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x74, 0x69, 0x6d, 0x65, 0x22, 0xb1, 0x01, 0x0a, 0x0d, 0x52,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a,
+	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x74, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b,
+	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x8e,
+	0x01, 0x0a, 0x0e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x72, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x76, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x65, 0x76,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x65, 0x64, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0c, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x65, 0x64, 0x74, 0x69, 0x6d, 0x65, 0x22,
+	0x98, 0x01, 0x0a, 0x0c, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x72, 0x79, 0x72, 0x75, 0x6e, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x72, 0x75, 0x6e, 0x22, 0xc1, 0x01, 0x0a, 0x0d, 0x41,
+	0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69,
+	0x6e, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x64, 0x72, 0x79, 0x72, 0x75, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x72,
+	0x79, 0x72, 0x75, 0x6e, 0x12, 0x28, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x89,
+	0x01, 0x0a, 0x13, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x74, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d,
+	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x86, 0x02, 0x0a, 0x0c, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x70,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x74, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x24, 0x0a, 0x0d, 0x6e, 0x65, 0x77, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x77, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x22, 0x0a,
+	0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x72, 0x75, 0x6c, 0x65,
+	0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x74,
+	0x69, 0x6d, 0x65, 0x22, 0x65, 0x0a, 0x14, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x73, 0x12, 0x3b, 0x0a,
+	0x0c, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0c, 0x70, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x32, 0x8b, 0x02, 0x0a, 0x06, 0x57,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x46, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b,
+	0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f,
 	0x72, 0x6b, 0x65, 0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x57,
-	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a,
-	0x04, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65,
-	0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x19, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b,
-	0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x07, 0x43, 0x6f,
-	0x6e, 0x76, 0x65, 0x72, 0x74, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65,
-	0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x19, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b,
-	0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x63, 0x63, 0x75, 0x6b, 0x6e, 0x6f,
-	0x78, 0x2f, 0x6b, 0x6e, 0x6f, 0x78, 0x41, 0x75, 0x74, 0x6f, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x65,
-	0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a,
+	0x05, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b,
+	0x65, 0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x57, 0x6f, 0x72,
+	0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x04, 0x53,
+	0x74, 0x6f, 0x70, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e,
+	0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
+	0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x76,
+	0x65, 0x72, 0x74, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e,
+	0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
+	0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xa9, 0x02, 0x0a, 0x06, 0x52, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x12, 0x43, 0x0a, 0x0c, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e,
+	0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
+	0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0b, 0x41, 0x70, 0x70, 0x6c,
+	0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x17, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72,
+	0x6b, 0x65, 0x72, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x41, 0x70, 0x70,
+	0x6c, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0e, 0x52, 0x6f,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x17, 0x2e, 0x76,
+	0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65,
+	0x72, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x53, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x76, 0x31, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x61, 0x63, 0x63, 0x75, 0x6b, 0x6e, 0x6f, 0x78, 0x2f, 0x6b, 0x6e, 0x6f, 0x78,
+	0x41, 0x75, 0x74, 0x6f, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x73, 0x2f, 0x76, 0x31, 0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -341,29 +1136,47 @@ func file_v1_worker_worker_proto_rawDescGZIP() []byte {
 	return file_v1_worker_worker_proto_rawDescData
 }
 
-var file_v1_worker_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_v1_worker_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_v1_worker_worker_proto_goTypes = []interface{}{
-	(*WorkerRequest)(nil),   // 0: v1.worker.WorkerRequest
-	(*WorkerResponse)(nil),  // 1: v1.worker.WorkerResponse
-	(*KubeArmorPolicy)(nil), // 2: v1.worker.KubeArmorPolicy
-	(*CiliumPolicy)(nil),    // 3: v1.worker.CiliumPolicy
+	(*WorkerRequest)(nil),        // 0: v1.worker.WorkerRequest
+	(*WorkerResponse)(nil),       // 1: v1.worker.WorkerResponse
+	(*KubeArmorPolicy)(nil),      // 2: v1.worker.KubeArmorPolicy
+	(*CiliumPolicy)(nil),         // 3: v1.worker.CiliumPolicy
+	(*LearningState)(nil),        // 4: v1.worker.LearningState
+	(*ReviewRequest)(nil),        // 5: v1.worker.ReviewRequest
+	(*ReviewResponse)(nil),       // 6: v1.worker.ReviewResponse
+	(*ApplyRequest)(nil),         // 7: v1.worker.ApplyRequest
+	(*ApplyResponse)(nil),        // 8: v1.worker.ApplyResponse
+	(*PolicyChangeRequest)(nil),  // 9: v1.worker.PolicyChangeRequest
+	(*PolicyChange)(nil),         // 10: v1.worker.PolicyChange
+	(*PolicyChangeResponse)(nil), // 11: v1.worker.PolicyChangeResponse
 }
 var file_v1_worker_worker_proto_depIdxs = []int32{
-	2, // 0: v1.worker.WorkerResponse.kubearmorpolicy:type_name -> v1.worker.KubeArmorPolicy
-	3, // 1: v1.worker.WorkerResponse.ciliumpolicy:type_name -> v1.worker.CiliumPolicy
-	0, // 2: v1.worker.Worker.GetWorkerStatus:input_type -> v1.worker.WorkerRequest
-	0, // 3: v1.worker.Worker.Start:input_type -> v1.worker.WorkerRequest
-	0, // 4: v1.worker.Worker.Stop:input_type -> v1.worker.WorkerRequest
-	0, // 5: v1.worker.Worker.Convert:input_type -> v1.worker.WorkerRequest
-	1, // 6: v1.worker.Worker.GetWorkerStatus:output_type -> v1.worker.WorkerResponse
-	1, // 7: v1.worker.Worker.Start:output_type -> v1.worker.WorkerResponse
-	1, // 8: v1.worker.Worker.Stop:output_type -> v1.worker.WorkerResponse
-	1, // 9: v1.worker.Worker.Convert:output_type -> v1.worker.WorkerResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2,  // 0: v1.worker.WorkerResponse.kubearmorpolicy:type_name -> v1.worker.KubeArmorPolicy
+	3,  // 1: v1.worker.WorkerResponse.ciliumpolicy:type_name -> v1.worker.CiliumPolicy
+	4,  // 2: v1.worker.WorkerResponse.learningstate:type_name -> v1.worker.LearningState
+	10, // 3: v1.worker.PolicyChangeResponse.policychange:type_name -> v1.worker.PolicyChange
+	0,  // 4: v1.worker.Worker.GetWorkerStatus:input_type -> v1.worker.WorkerRequest
+	0,  // 5: v1.worker.Worker.Start:input_type -> v1.worker.WorkerRequest
+	0,  // 6: v1.worker.Worker.Stop:input_type -> v1.worker.WorkerRequest
+	0,  // 7: v1.worker.Worker.Convert:input_type -> v1.worker.WorkerRequest
+	5,  // 8: v1.worker.Review.ReviewPolicy:input_type -> v1.worker.ReviewRequest
+	7,  // 9: v1.worker.Review.ApplyPolicy:input_type -> v1.worker.ApplyRequest
+	7,  // 10: v1.worker.Review.RollbackPolicy:input_type -> v1.worker.ApplyRequest
+	9,  // 11: v1.worker.Review.GetPolicyChanges:input_type -> v1.worker.PolicyChangeRequest
+	1,  // 12: v1.worker.Worker.GetWorkerStatus:output_type -> v1.worker.WorkerResponse
+	1,  // 13: v1.worker.Worker.Start:output_type -> v1.worker.WorkerResponse
+	1,  // 14: v1.worker.Worker.Stop:output_type -> v1.worker.WorkerResponse
+	1,  // 15: v1.worker.Worker.Convert:output_type -> v1.worker.WorkerResponse
+	6,  // 16: v1.worker.Review.ReviewPolicy:output_type -> v1.worker.ReviewResponse
+	8,  // 17: v1.worker.Review.ApplyPolicy:output_type -> v1.worker.ApplyResponse
+	8,  // 18: v1.worker.Review.RollbackPolicy:output_type -> v1.worker.ApplyResponse
+	11, // 19: v1.worker.Review.GetPolicyChanges:output_type -> v1.worker.PolicyChangeResponse
+	12, // [12:20] is the sub-list for method output_type
+	4,  // [4:12] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_v1_worker_worker_proto_init() }
@@ -420,6 +1233,102 @@ func file_v1_worker_worker_proto_init() {
 				return nil
 			}
 		}
+		file_v1_worker_worker_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LearningState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_worker_worker_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReviewRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_worker_worker_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReviewResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_worker_worker_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApplyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_worker_worker_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApplyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_worker_worker_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PolicyChangeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_worker_worker_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PolicyChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_worker_worker_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PolicyChangeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -427,9 +1336,9 @@ func file_v1_worker_worker_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_v1_worker_worker_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   12,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_v1_worker_worker_proto_goTypes,
 		DependencyIndexes: file_v1_worker_worker_proto_depIdxs,
@@ -211,3 +211,197 @@ var Worker_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/worker/worker.proto",
 }
+
+// ReviewClient is the client API for Review service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ReviewClient interface {
+	ReviewPolicy(ctx context.Context, in *ReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error)
+	ApplyPolicy(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error)
+	RollbackPolicy(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error)
+	GetPolicyChanges(ctx context.Context, in *PolicyChangeRequest, opts ...grpc.CallOption) (*PolicyChangeResponse, error)
+}
+
+type reviewClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReviewClient(cc grpc.ClientConnInterface) ReviewClient {
+	return &reviewClient{cc}
+}
+
+func (c *reviewClient) ReviewPolicy(ctx context.Context, in *ReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error) {
+	out := new(ReviewResponse)
+	err := c.cc.Invoke(ctx, "/v1.worker.Review/ReviewPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewClient) ApplyPolicy(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error) {
+	out := new(ApplyResponse)
+	err := c.cc.Invoke(ctx, "/v1.worker.Review/ApplyPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewClient) RollbackPolicy(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error) {
+	out := new(ApplyResponse)
+	err := c.cc.Invoke(ctx, "/v1.worker.Review/RollbackPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewClient) GetPolicyChanges(ctx context.Context, in *PolicyChangeRequest, opts ...grpc.CallOption) (*PolicyChangeResponse, error) {
+	out := new(PolicyChangeResponse)
+	err := c.cc.Invoke(ctx, "/v1.worker.Review/GetPolicyChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReviewServer is the server API for Review service.
+// All implementations must embed UnimplementedReviewServer
+// for forward compatibility
+type ReviewServer interface {
+	ReviewPolicy(context.Context, *ReviewRequest) (*ReviewResponse, error)
+	ApplyPolicy(context.Context, *ApplyRequest) (*ApplyResponse, error)
+	RollbackPolicy(context.Context, *ApplyRequest) (*ApplyResponse, error)
+	GetPolicyChanges(context.Context, *PolicyChangeRequest) (*PolicyChangeResponse, error)
+	mustEmbedUnimplementedReviewServer()
+}
+
+// UnimplementedReviewServer must be embedded to have forward compatible implementations.
+type UnimplementedReviewServer struct {
+}
+
+func (UnimplementedReviewServer) ReviewPolicy(context.Context, *ReviewRequest) (*ReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReviewPolicy not implemented")
+}
+func (UnimplementedReviewServer) ApplyPolicy(context.Context, *ApplyRequest) (*ApplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyPolicy not implemented")
+}
+func (UnimplementedReviewServer) RollbackPolicy(context.Context, *ApplyRequest) (*ApplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackPolicy not implemented")
+}
+func (UnimplementedReviewServer) GetPolicyChanges(context.Context, *PolicyChangeRequest) (*PolicyChangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPolicyChanges not implemented")
+}
+func (UnimplementedReviewServer) mustEmbedUnimplementedReviewServer() {}
+
+// UnsafeReviewServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReviewServer will
+// result in compilation errors.
+type UnsafeReviewServer interface {
+	mustEmbedUnimplementedReviewServer()
+}
+
+func RegisterReviewServer(s grpc.ServiceRegistrar, srv ReviewServer) {
+	s.RegisterService(&Review_ServiceDesc, srv)
+}
+
+func _Review_ReviewPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).ReviewPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.worker.Review/ReviewPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).ReviewPolicy(ctx, req.(*ReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Review_ApplyPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).ApplyPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.worker.Review/ApplyPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).ApplyPolicy(ctx, req.(*ApplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Review_RollbackPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).RollbackPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.worker.Review/RollbackPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).RollbackPolicy(ctx, req.(*ApplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Review_GetPolicyChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).GetPolicyChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.worker.Review/GetPolicyChanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).GetPolicyChanges(ctx, req.(*PolicyChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Review_ServiceDesc is the grpc.ServiceDesc for Review service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Review_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.worker.Review",
+	HandlerType: (*ReviewServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReviewPolicy",
+			Handler:    _Review_ReviewPolicy_Handler,
+		},
+		{
+			MethodName: "ApplyPolicy",
+			Handler:    _Review_ApplyPolicy_Handler,
+		},
+		{
+			MethodName: "RollbackPolicy",
+			Handler:    _Review_RollbackPolicy_Handler,
+		},
+		{
+			MethodName: "GetPolicyChanges",
+			Handler:    _Review_GetPolicyChanges_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v1/worker/worker.proto",
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/rs/zerolog"
 
 	analyzer "github.com/accuknox/auto-policy-discovery/src/analyzer"
+	"github.com/accuknox/auto-policy-discovery/src/cluster"
 	cfg "github.com/accuknox/auto-policy-discovery/src/config"
 	core "github.com/accuknox/auto-policy-discovery/src/config"
 	fc "github.com/accuknox/auto-policy-discovery/src/feedconsumer"
@@ -13,6 +14,7 @@ import (
 	networker "github.com/accuknox/auto-policy-discovery/src/networkpolicy"
 	obs "github.com/accuknox/auto-policy-discovery/src/observability"
 	sysworker "github.com/accuknox/auto-policy-discovery/src/systempolicy"
+	"github.com/accuknox/auto-policy-discovery/src/telemetry"
 
 	"github.com/accuknox/auto-policy-discovery/src/insight"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
@@ -90,16 +92,18 @@ func (s *workerServer) GetWorkerStatus(ctx context.Context, in *wpb.WorkerReques
 	log.Info().Msg("Get worker status called")
 
 	status := ""
+	var learningStates []*wpb.LearningState
 
 	if in.GetPolicytype() == "network" {
 		status = networker.NetworkWorkerStatus
 	} else if in.GetPolicytype() == "system" {
 		status = sysworker.SystemWorkerStatus
+		learningStates = sysworker.GetLearningStates(in.GetNamespace(), in.GetClustername(), in.GetLabels())
 	} else {
 		return &wpb.WorkerResponse{Res: "No policy type, choose 'network' or 'system', not [" + in.GetPolicytype() + "]"}, nil
 	}
 
-	return &wpb.WorkerResponse{Res: status}, nil
+	return &wpb.WorkerResponse{Res: status, Learningstate: learningStates}, nil
 }
 
 func (s *workerServer) Convert(ctx context.Context, in *wpb.WorkerRequest) (*wpb.WorkerResponse, error) {
@@ -121,6 +125,129 @@ func (s *workerServer) Convert(ctx context.Context, in *wpb.WorkerRequest) (*wpb
 	return &wpb.WorkerResponse{Res: "ok"}, nil
 }
 
+// ==================== //
+// == Review Service == //
+// ==================== //
+
+type reviewServer struct {
+	wpb.ReviewServer
+}
+
+// ReviewPolicy - Service to approve, reject or mark as applied a discovered policy
+func (s *reviewServer) ReviewPolicy(ctx context.Context, in *wpb.ReviewRequest) (*wpb.ReviewResponse, error) {
+	log.Info().Msgf("Review policy called, %s policy [%s] -> %s", in.GetPolicytype(), in.GetName(), in.GetState())
+
+	review := types.PolicyReview{
+		PolicyType:  in.GetPolicytype(),
+		ClusterName: in.GetClustername(),
+		Name:        in.GetName(),
+		State:       in.GetState(),
+		Reviewer:    in.GetReviewer(),
+		Comment:     in.GetComment(),
+	}
+	if err := libs.UpdatePolicyReview(core.CurrentCfg.ConfigDB, &review); err != nil {
+		return nil, err
+	}
+
+	telemetry.PolicyEvent(telemetry.EventPolicyReviewed, review.PolicyType, review.ClusterName, "", review.Name)
+
+	return &wpb.ReviewResponse{
+		Res:          "ok",
+		Name:         review.Name,
+		Prevstate:    review.PrevState,
+		State:        review.State,
+		Reviewedtime: review.ReviewedTime,
+	}, nil
+}
+
+func toApplyResponse(result types.PolicyApplyResult) *wpb.ApplyResponse {
+	return &wpb.ApplyResponse{
+		Res:             "ok",
+		Kind:            result.Kind,
+		Namespace:       result.Namespace,
+		Name:            result.Name,
+		Action:          result.Action,
+		Dryrun:          result.DryRun,
+		Resourceversion: result.ResourceVersion,
+	}
+}
+
+// ApplyPolicy - Service to create or update the object of an approved policy in the cluster
+func (s *reviewServer) ApplyPolicy(ctx context.Context, in *wpb.ApplyRequest) (*wpb.ApplyResponse, error) {
+	log.Info().Msgf("Apply policy called, %s policy [%s] dry run %t", in.GetPolicytype(), in.GetName(), in.GetDryrun())
+
+	applier := cluster.NewPolicyApplier(cluster.ConnectDynamicClient())
+
+	var result types.PolicyApplyResult
+	var err error
+	if in.GetPolicytype() == "network" {
+		result, err = networker.ApplyNetworkPolicy(applier, in.GetClustername(), in.GetName(), in.GetReviewer(), in.GetDryrun())
+	} else if in.GetPolicytype() == "system" {
+		result, err = sysworker.ApplySystemPolicy(applier, in.GetClustername(), in.GetName(), in.GetReviewer(), in.GetDryrun())
+	} else {
+		return &wpb.ApplyResponse{Res: "No policy type, choose 'network' or 'system', not [" + in.GetPolicytype() + "]"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toApplyResponse(result), nil
+}
+
+// RollbackPolicy - Service to restore the previous version of the object of a policy in the cluster
+func (s *reviewServer) RollbackPolicy(ctx context.Context, in *wpb.ApplyRequest) (*wpb.ApplyResponse, error) {
+	log.Info().Msgf("Rollback policy called, %s policy [%s] dry run %t", in.GetPolicytype(), in.GetName(), in.GetDryrun())
+
+	applier := cluster.NewPolicyApplier(cluster.ConnectDynamicClient())
+
+	var result types.PolicyApplyResult
+	var err error
+	if in.GetPolicytype() == "network" {
+		result, err = networker.RollbackNetworkPolicy(applier, in.GetClustername(), in.GetName(), in.GetDryrun())
+	} else if in.GetPolicytype() == "system" {
+		result, err = sysworker.RollbackSystemPolicy(applier, in.GetClustername(), in.GetName(), in.GetDryrun())
+	} else {
+		return &wpb.ApplyResponse{Res: "No policy type, choose 'network' or 'system', not [" + in.GetPolicytype() + "]"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toApplyResponse(result), nil
+}
+
+// GetPolicyChanges - Service to list the change records of the policies, e.g. the rules removed by the rule aging
+func (s *reviewServer) GetPolicyChanges(ctx context.Context, in *wpb.PolicyChangeRequest) (*wpb.PolicyChangeResponse, error) {
+	log.Info().Msgf("Get policy changes called, %s policy [%s]", in.GetPolicytype(), in.GetName())
+
+	filter := types.PolicyChange{
+		PolicyType:  in.GetPolicytype(),
+		ClusterName: in.GetClustername(),
+		Namespace:   in.GetNamespace(),
+		Name:        in.GetName(),
+	}
+	changes, err := libs.GetPolicyChanges(core.CurrentCfg.ConfigDB, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &wpb.PolicyChangeResponse{Res: "ok"}
+	for _, change := range changes {
+		response.Policychange = append(response.Policychange, &wpb.PolicyChange{
+			Policytype:    change.PolicyType,
+			Clustername:   change.ClusterName,
+			Namespace:     change.Namespace,
+			Name:          change.Name,
+			Newpolicyname: change.NewPolicyName,
+			Reason:        change.Reason,
+			Removedrules:  change.RemovedRules,
+			Changedtime:   change.ChangedTime,
+		})
+	}
+
+	return response, nil
+}
+
 // ====================== //
 // == Consumer Service == //
 // ====================== //
@@ -190,10 +317,34 @@ func (s *insightServer) GetInsightData(ctx context.Context, in *ipb.Request) (*i
 		Duration:      in.Duration,
 		Type:          in.Type,
 		Rule:          in.Rule,
+		Since:         in.Since,
+		Until:         in.Until,
+		Limit:         int(in.Limit),
+		PageToken:     in.PageToken,
+		SortOrder:     in.SortOrder,
 	})
 	return &resp, err
 }
 
+func (s *insightServer) GetServiceGraph(ctx context.Context, in *ipb.GraphRequest) (*ipb.GraphResponse, error) {
+	return insight.GetServiceGraph(types.ServiceGraphRequest{
+		ClusterName: in.ClusterName,
+		Namespace:   in.Namespace,
+		Labels:      in.Labels,
+		Format:      in.Format,
+		Export:      in.Export,
+	})
+}
+
+func (s *insightServer) GetRuleEvidence(ctx context.Context, in *ipb.EvidenceRequest) (*ipb.EvidenceResponse, error) {
+	return insight.GetRuleEvidence(types.RuleEvidenceRequest{
+		PolicyType:  in.PolicyType,
+		ClusterName: in.ClusterName,
+		Namespace:   in.Namespace,
+		PolicyName:  in.PolicyName,
+	})
+}
+
 // =================== //
 // == Observability == //
 // =================== //
@@ -209,6 +360,24 @@ func (s *summaryServer) FetchLogs(in *opb.LogsRequest, stream opb.Summary_FetchL
 	return nil
 }
 
+type anomalyServer struct {
+	opb.AnomalyServer
+}
+
+// WatchAlerts - Service to stream the anomaly alerts of the workloads
+func (s *anomalyServer) WatchAlerts(in *opb.AnomalyRequest, stream opb.Anomaly_WatchAlertsServer) error {
+	return obs.WatchAnomalyAlerts(in, stream)
+}
+
+type exportServer struct {
+	opb.ExportServer
+}
+
+// ExportLogs - Service to export the observability logs to csv or parquet files
+func (s *exportServer) ExportLogs(ctx context.Context, in *opb.ExportRequest) (*opb.ExportResponse, error) {
+	return obs.ExportLogs(in)
+}
+
 // ================= //
 // == gRPC server == //
 // ================= //
@@ -221,17 +390,23 @@ func GetNewServer() *grpc.Server {
 
 	// create server instances
 	workerServer := &workerServer{}
+	reviewServer := &reviewServer{}
 	consumerServer := &consumerServer{}
 	analyzerServer := &analyzerServer{}
 	insightServer := &insightServer{}
 	summaryServer := &summaryServer{}
+	anomalyServer := &anomalyServer{}
+	exportServer := &exportServer{}
 
 	// register gRPC servers
 	wpb.RegisterWorkerServer(s, workerServer)
+	wpb.RegisterReviewServer(s, reviewServer)
 	fpb.RegisterConsumerServer(s, consumerServer)
 	apb.RegisterAnalyzerServer(s, analyzerServer)
 	ipb.RegisterInsightServer(s, insightServer)
 	opb.RegisterSummaryServer(s, summaryServer)
+	opb.RegisterAnomalyServer(s, anomalyServer)
+	opb.RegisterExportServer(s, exportServer)
 
 	if cfg.GetCurrentCfg().ConfigClusterMgmt.ClusterInfoFrom != "k8sclient" {
 		// start consumer automatically
@@ -240,6 +415,9 @@ func GetNewServer() *grpc.Server {
 		fc.ConsumerMutex.Unlock()
 	}
 
+	// start telemetry export before the workers emit events
+	telemetry.InitTelemetry()
+
 	// start net worker automatically
 	networker.StartNetworkWorker()
 
@@ -0,0 +1,222 @@
+package systempolicy
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/cluster"
+	"github.com/accuknox/auto-policy-discovery/src/plugin"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ===================================== //
+// == Policies Applied in the Cluster == //
+// ===================================== //
+
+// clusterSystemPolicies the kubearmor policies in the cluster, refreshed at each discovery
+var clusterSystemPolicies []types.KnoxSystemPolicy
+
+// GetClusterSystemPolicies returns the kubearmor policies in the cluster not managed by the auto policy discovery,
+// e.g. the hand-written policies, as knox policies
+func GetClusterSystemPolicies() []types.KnoxSystemPolicy {
+	kubePolicies, err := cluster.NewPolicyApplier(cluster.ConnectDynamicClient()).GetKubeArmorPolicies()
+	if err != nil {
+		log.Warn().Msgf("failed to get the kubearmor policies in the cluster: %s", err.Error())
+		return nil
+	}
+
+	policies := []types.KnoxSystemPolicy{}
+	for _, kubePolicy := range kubePolicies {
+		policies = append(policies, plugin.ConvertKubeArmorPolicyToKnoxSystemPolicy(kubePolicy))
+	}
+
+	return policies
+}
+
+// isClusterPolicySelecting checks if the cluster policy allows the rules of the workload the policy selects,
+// the audit and block policies do not
+func isClusterPolicySelecting(clusterPolicy types.KnoxSystemPolicy, policy types.KnoxSystemPolicy) bool {
+	if !strings.EqualFold(clusterPolicy.Spec.Action, "Allow") ||
+		clusterPolicy.Metadata["namespace"] != policy.Metadata["namespace"] {
+		return false
+	}
+
+	for k, v := range clusterPolicy.Spec.Selector.MatchLabels {
+		if policy.Spec.Selector.MatchLabels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isFromSourceCovered checks the sources of the discovered rule, a cluster rule without a source allows all
+func isFromSourceCovered(fromSource []types.KnoxFromSource, clusterFromSource []types.KnoxFromSource) bool {
+	if len(clusterFromSource) == 0 {
+		return true
+	}
+
+	for _, src := range fromSource {
+		covered := false
+		for _, clusterSrc := range clusterFromSource {
+			if src == clusterSrc || (clusterSrc.Dir != "" && strings.HasPrefix(src.Path, clusterSrc.Dir)) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			return false
+		}
+	}
+
+	return len(fromSource) > 0
+}
+
+// isDirCovering checks if the directory rule contains the path, only the direct children if not recursive
+func isDirCovering(matchDir types.KnoxMatchDirectories, path string) bool {
+	dir := strings.TrimSuffix(matchDir.Dir, "/") + "/"
+	if !strings.HasPrefix(path, dir) {
+		return false
+	}
+
+	return matchDir.Recursive || filepath.Dir(strings.TrimSuffix(path, "/"))+"/" == dir
+}
+
+func isPathCovered(matchPath types.KnoxMatchPaths, clusterSys types.KnoxSys) bool {
+	for _, clusterPath := range clusterSys.MatchPaths {
+		if clusterPath.Path == matchPath.Path && isFromSourceCovered(matchPath.FromSource, clusterPath.FromSource) {
+			return true
+		}
+	}
+
+	for _, clusterDir := range clusterSys.MatchDirectories {
+		if isDirCovering(clusterDir, matchPath.Path) && isFromSourceCovered(matchPath.FromSource, clusterDir.FromSource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isDirCovered(matchDir types.KnoxMatchDirectories, clusterSys types.KnoxSys) bool {
+	for _, clusterDir := range clusterSys.MatchDirectories {
+		if !isFromSourceCovered(matchDir.FromSource, clusterDir.FromSource) {
+			continue
+		}
+
+		if strings.TrimSuffix(clusterDir.Dir, "/") == strings.TrimSuffix(matchDir.Dir, "/") &&
+			(clusterDir.Recursive || !matchDir.Recursive) {
+			return true
+		}
+
+		// a sub directory of a recursive rule
+		if clusterDir.Recursive && isDirCovering(clusterDir, matchDir.Dir) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeClusterCoveredSysPaths(sys types.KnoxSys, clusterSyses []types.KnoxSys) types.KnoxSys {
+	result := types.KnoxSys{}
+
+	for _, matchPath := range sys.MatchPaths {
+		covered := false
+		for _, clusterSys := range clusterSyses {
+			if isPathCovered(matchPath, clusterSys) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			result.MatchPaths = append(result.MatchPaths, matchPath)
+		}
+	}
+
+	for _, matchDir := range sys.MatchDirectories {
+		covered := false
+		for _, clusterSys := range clusterSyses {
+			if isDirCovered(matchDir, clusterSys) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			result.MatchDirectories = append(result.MatchDirectories, matchDir)
+		}
+	}
+
+	return result
+}
+
+// removeClusterCoveredRules removes the rules of the policy already allowed by the policies in the cluster,
+// returns false if no rule is left to propose
+func removeClusterCoveredRules(policy types.KnoxSystemPolicy, clusterPolicies []types.KnoxSystemPolicy) (types.KnoxSystemPolicy, bool) {
+	processes := []types.KnoxSys{}
+	files := []types.KnoxSys{}
+	protocols := []types.KnoxMatchProtocols{}
+
+	for _, clusterPolicy := range clusterPolicies {
+		if isClusterPolicySelecting(clusterPolicy, policy) {
+			processes = append(processes, clusterPolicy.Spec.Process)
+			files = append(files, clusterPolicy.Spec.File)
+			protocols = append(protocols, clusterPolicy.Spec.Network.MatchProtocols...)
+		}
+	}
+	if len(processes) == 0 {
+		return policy, true
+	}
+
+	policy.Spec.Process = removeClusterCoveredSysPaths(policy.Spec.Process, processes)
+	policy.Spec.File = removeClusterCoveredSysPaths(policy.Spec.File, files)
+
+	matchProtocols := []types.KnoxMatchProtocols{}
+	for _, matchProtocol := range policy.Spec.Network.MatchProtocols {
+		covered := false
+		for _, clusterProtocol := range protocols {
+			if strings.EqualFold(clusterProtocol.Protocol, matchProtocol.Protocol) &&
+				isFromSourceCovered(matchProtocol.FromSource, clusterProtocol.FromSource) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			matchProtocols = append(matchProtocols, matchProtocol)
+		}
+	}
+	policy.Spec.Network.MatchProtocols = nil
+	if len(matchProtocols) > 0 {
+		policy.Spec.Network.MatchProtocols = matchProtocols
+	}
+
+	left := len(policy.Spec.Process.MatchPaths) + len(policy.Spec.Process.MatchDirectories) +
+		len(policy.Spec.File.MatchPaths) + len(policy.Spec.File.MatchDirectories) + len(policy.Spec.Network.MatchProtocols)
+
+	return policy, left > 0
+}
+
+// RemoveClusterCoveredPolicies removes the discovered rules the policies in the cluster already allow,
+// so the deduplication does not propose them again
+func RemoveClusterCoveredPolicies(discoveredPolicies []types.KnoxSystemPolicy, clusterPolicies []types.KnoxSystemPolicy) []types.KnoxSystemPolicy {
+	if len(clusterPolicies) == 0 {
+		return discoveredPolicies
+	}
+
+	policies := []types.KnoxSystemPolicy{}
+	for _, policy := range discoveredPolicies {
+		if policy, ok := removeClusterCoveredRules(policy, clusterPolicies); ok {
+			policies = append(policies, policy)
+		}
+	}
+
+	if skipped := len(discoveredPolicies) - len(policies); skipped > 0 {
+		log.Info().Msgf("%d discovered policies are covered by the policies in the cluster", skipped)
+	}
+
+	return policies
+}
@@ -0,0 +1,72 @@
+package systempolicy
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveClusterCoveredPolicies(t *testing.T) {
+	clusterPolicy := types.KnoxSystemPolicy{
+		Metadata: map[string]string{
+			"name":      "allow-nginx",
+			"namespace": "default",
+		},
+		Spec: types.KnoxSystemSpec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "nginx"}},
+			Process: types.KnoxSys{
+				MatchPaths: []types.KnoxMatchPaths{{Path: "/usr/sbin/nginx"}},
+			},
+			File: types.KnoxSys{
+				MatchDirectories: []types.KnoxMatchDirectories{{Dir: "/etc/nginx/", Recursive: true}},
+			},
+			Action: "Allow",
+		},
+	}
+
+	discovered := types.KnoxSystemPolicy{
+		Metadata: map[string]string{
+			"namespace": "default",
+			"type":      SYS_OP_FILE,
+		},
+		Spec: types.KnoxSystemSpec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "nginx", "pod-template-hash": "abc"}},
+			File: types.KnoxSys{
+				MatchPaths: []types.KnoxMatchPaths{
+					{Path: "/etc/nginx/conf.d/default.conf", FromSource: []types.KnoxFromSource{{Path: "/usr/sbin/nginx"}}},
+					{Path: "/var/log/nginx/access.log"},
+				},
+			},
+		},
+	}
+
+	process := types.KnoxSystemPolicy{
+		Metadata: map[string]string{
+			"namespace": "default",
+			"type":      SYS_OP_PROCESS,
+		},
+		Spec: types.KnoxSystemSpec{
+			Selector: discovered.Spec.Selector,
+			Process: types.KnoxSys{
+				MatchPaths: []types.KnoxMatchPaths{{Path: "/usr/sbin/nginx"}},
+			},
+		},
+	}
+
+	policies := RemoveClusterCoveredPolicies([]types.KnoxSystemPolicy{discovered, process}, []types.KnoxSystemPolicy{clusterPolicy})
+	assert.Equal(t, 1, len(policies))
+	assert.Equal(t, []types.KnoxMatchPaths{{Path: "/var/log/nginx/access.log"}}, policies[0].Spec.File.MatchPaths)
+
+	// the block policies do not cover the discovered rules
+	clusterPolicy.Spec.Action = "Block"
+	policies = RemoveClusterCoveredPolicies([]types.KnoxSystemPolicy{discovered, process}, []types.KnoxSystemPolicy{clusterPolicy})
+	assert.Equal(t, 2, len(policies))
+}
+
+func TestIsDirCovering(t *testing.T) {
+	assert.True(t, isDirCovering(types.KnoxMatchDirectories{Dir: "/etc/"}, "/etc/hosts"))
+	assert.False(t, isDirCovering(types.KnoxMatchDirectories{Dir: "/etc/"}, "/etc/nginx/nginx.conf"))
+	assert.True(t, isDirCovering(types.KnoxMatchDirectories{Dir: "/etc", Recursive: true}, "/etc/nginx/nginx.conf"))
+	assert.False(t, isDirCovering(types.KnoxMatchDirectories{Dir: "/etc/", Recursive: true}, "/etcd/data"))
+}
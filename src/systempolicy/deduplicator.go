@@ -7,6 +7,7 @@ import (
 
 	"github.com/accuknox/auto-policy-discovery/src/config"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/telemetry"
 	types "github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/google/go-cmp/cmp"
 )
@@ -27,35 +28,15 @@ func IsExistingPolicy(existingPolicies []types.KnoxSystemPolicy, newPolicy types
 	return false
 }
 
-// ======================= //
-// == Policy Name Check == //
-// ======================= //
-
-func existPolicyName(policyNamesMap map[string]bool, name string) bool {
-	if _, ok := policyNamesMap[name]; ok {
-		return true
-	}
-
-	return false
-}
+// ================= //
+// == Policy Name == //
+// ================= //
 
 func GeneratePolicyName(policyNamesMap map[string]bool, policy types.KnoxSystemPolicy, clusterName string) types.KnoxSystemPolicy {
-	procPrefix := "autopol-process-"
-	filePrefix := "autopol-file-"
-	netPrefix := "autopol-network-"
-
-	polType := strings.ToLower(policy.Metadata["type"])
-	name := "autopol-" + polType + "-" + libs.RandSeq(15)
-
-	for existPolicyName(policyNamesMap, name) {
-		if polType == "file" {
-			name = filePrefix + libs.RandSeq(15)
-		} else if polType == "process" {
-			name = procPrefix + libs.RandSeq(15)
-		} else { // network
-			name = netPrefix + libs.RandSeq(15)
-		}
-	}
+	// the name of the workload policy, as named by mergeSysPolicies
+	name := libs.GeneratePolicyName("system", policy.Metadata["namespace"], policy.Spec.Selector.MatchLabels,
+		policy.Metadata["containername"])
+	name = libs.UniquePolicyName(policyNamesMap, name)
 
 	policyNamesMap[name] = true
 	policy.Metadata["name"] = name
@@ -158,6 +139,8 @@ func UpdateProcessOperation(newPolicy types.KnoxSystemPolicy, existingPolicies [
 
 	// step 5: update latest -> outdated
 	libs.UpdateOutdatedSystemPolicy(config.GetCfgDB(), latestPolicy.Metadata["name"], newPolicy.Metadata["name"])
+	telemetry.PolicyEvent(telemetry.EventPolicyOutdated, telemetry.PolicyTypeSystem,
+		latestPolicy.Metadata["clusterName"], latestPolicy.Metadata["namespace"], latestPolicy.Metadata["name"])
 
 	return newPolicy, true
 }
@@ -188,7 +171,8 @@ func GetLatestPolicy(existingPolicies []types.KnoxSystemPolicy, policy types.Kno
 
 		if exist.Metadata["namespace"] == policy.Metadata["namespace"] &&
 			existPolicyType == policy.Metadata["type"] &&
-			exist.Metadata["status"] == "latest" {
+			exist.Metadata["status"] == "latest" &&
+			libs.GetReviewState(exist.Metadata) != libs.ReviewStateRejected {
 
 			if strings.Contains(policy.Metadata["type"], "fromSource") {
 				if exist.Metadata["fromSource"] != policy.Metadata["fromSource"] {
@@ -297,10 +281,106 @@ func UpdateFileOperation(newPolicy types.KnoxSystemPolicy, existingPolicies []ty
 
 	// step 5: update latest -> outdated
 	libs.UpdateOutdatedSystemPolicy(config.GetCfgDB(), latestPolicy.Metadata["name"], newPolicy.Metadata["name"])
+	telemetry.PolicyEvent(telemetry.EventPolicyOutdated, telemetry.PolicyTypeSystem,
+		latestPolicy.Metadata["clusterName"], latestPolicy.Metadata["namespace"], latestPolicy.Metadata["name"])
 
 	return newPolicy, true
 }
 
+// ======================= //
+// == Rejected Policies == //
+// ======================= //
+
+// getRejectedPolicies returns the latest policies of the workload a reviewer rejected
+func getRejectedPolicies(existingPolicies []types.KnoxSystemPolicy, policy types.KnoxSystemPolicy) []types.KnoxSystemPolicy {
+	rejectedPolicies := []types.KnoxSystemPolicy{}
+
+	for _, exist := range existingPolicies {
+		if exist.Metadata["clusterName"] == policy.Metadata["clusterName"] &&
+			exist.Metadata["namespace"] == policy.Metadata["namespace"] &&
+			exist.Metadata["type"] == policy.Metadata["type"] &&
+			exist.Metadata["status"] == "latest" &&
+			libs.GetReviewState(exist.Metadata) == libs.ReviewStateRejected &&
+			cmp.Equal(exist.Spec.Selector.MatchLabels, policy.Spec.Selector.MatchLabels) {
+			rejectedPolicies = append(rejectedPolicies, exist)
+		}
+	}
+
+	return rejectedPolicies
+}
+
+func getSysRuleKey(kind, ruleType, value string) string {
+	return kind + ":" + ruleType + ":" + value
+}
+
+// getRejectedSysRules returns the process, file and network rules of the rejected policies
+func getRejectedSysRules(rejectedPolicies []types.KnoxSystemPolicy) map[string]bool {
+	rules := map[string]bool{}
+
+	for _, rejected := range rejectedPolicies {
+		for kind, sys := range map[string]types.KnoxSys{SYS_OP_PROCESS: rejected.Spec.Process, SYS_OP_FILE: rejected.Spec.File} {
+			for _, matchPath := range sys.MatchPaths {
+				rules[getSysRuleKey(kind, "path", matchPath.Path)] = true
+			}
+			for _, matchDir := range sys.MatchDirectories {
+				rules[getSysRuleKey(kind, "dir", matchDir.Dir)] = true
+			}
+		}
+
+		for _, matchProtocol := range rejected.Spec.Network.MatchProtocols {
+			rules[getSysRuleKey(SYS_OP_NETWORK, "protocol", matchProtocol.Protocol)] = true
+		}
+	}
+
+	return rules
+}
+
+func removeRejectedSysPaths(kind string, sys types.KnoxSys, rejectedRules map[string]bool) types.KnoxSys {
+	result := types.KnoxSys{}
+
+	for _, matchPath := range sys.MatchPaths {
+		if !rejectedRules[getSysRuleKey(kind, "path", matchPath.Path)] {
+			result.MatchPaths = append(result.MatchPaths, matchPath)
+		}
+	}
+	for _, matchDir := range sys.MatchDirectories {
+		if !rejectedRules[getSysRuleKey(kind, "dir", matchDir.Dir)] {
+			result.MatchDirectories = append(result.MatchDirectories, matchDir)
+		}
+	}
+
+	return result
+}
+
+// removeRejectedRules removes the rules of the policy a reviewer already rejected for the workload,
+// returns false if no rule is left to propose
+func removeRejectedRules(policy types.KnoxSystemPolicy, rejectedPolicies []types.KnoxSystemPolicy) (types.KnoxSystemPolicy, bool) {
+	if len(rejectedPolicies) == 0 {
+		return policy, true
+	}
+
+	rejectedRules := getRejectedSysRules(rejectedPolicies)
+
+	policy.Spec.Process = removeRejectedSysPaths(SYS_OP_PROCESS, policy.Spec.Process, rejectedRules)
+	policy.Spec.File = removeRejectedSysPaths(SYS_OP_FILE, policy.Spec.File, rejectedRules)
+
+	protocols := []types.KnoxMatchProtocols{}
+	for _, matchProtocol := range policy.Spec.Network.MatchProtocols {
+		if !rejectedRules[getSysRuleKey(SYS_OP_NETWORK, "protocol", matchProtocol.Protocol)] {
+			protocols = append(protocols, matchProtocol)
+		}
+	}
+	policy.Spec.Network.MatchProtocols = nil
+	if len(protocols) > 0 {
+		policy.Spec.Network.MatchProtocols = protocols
+	}
+
+	left := len(policy.Spec.Process.MatchPaths) + len(policy.Spec.Process.MatchDirectories) +
+		len(policy.Spec.File.MatchPaths) + len(policy.Spec.File.MatchDirectories) + len(policy.Spec.Network.MatchProtocols)
+
+	return policy, left > 0
+}
+
 // ====================================== //
 // == Update Duplicated Network Policy == //
 // ====================================== //
@@ -314,15 +394,28 @@ func UpdateDuplicatedPolicy(existingPolicies []types.KnoxSystemPolicy, discovere
 		policyNamesMap[exist.Metadata["name"]] = true
 	}
 
+	// the rules of the discovered policies are observed now
+	seenTime := time.Now().Unix()
+
 	// enumerate discovered network policy
 	for _, policy := range discoveredPolicies {
+		markLatestPoliciesRulesSeen(existingPolicies, policy, seenTime)
+
 		// step 1: compare the total network policy spec
 		if IsExistingPolicy(existingPolicies, policy) {
 			continue
 		}
 
+		// skip the rules a reviewer already rejected for the workload
+		policy.Metadata["clusterName"] = clusterName
+		policy, ok := removeRejectedRules(policy, getRejectedPolicies(existingPolicies, policy))
+		if !ok {
+			continue
+		}
+
 		// step 2: generate policy name
 		namedPolicy := GeneratePolicyName(policyNamesMap, policy, clusterName)
+		latestPolicies := GetLatestPolicy(existingPolicies, policy)
 
 		// step 3: update fild operation system policy
 		if policy.Metadata["type"] == SYS_OP_FILE {
@@ -338,8 +431,9 @@ func UpdateDuplicatedPolicy(existingPolicies []types.KnoxSystemPolicy, discovere
 			}
 		}
 
-		// step 5: update status
+		// step 5: update status and the rule statistics
 		namedPolicy.Metadata["status"] = "latest"
+		namedPolicy.RuleStats = inheritRuleStats(namedPolicy, latestPolicies, seenTime)
 
 		// step 6: update generated time
 		namedPolicy.GeneratedTime = time.Now().Unix()
@@ -39,7 +39,12 @@ func TestIsExistingPolicy(t *testing.T) {
 
 func TestGeneratePolicyName(t *testing.T) {
 	exist := types.KnoxSystemPolicy{
-		Metadata: map[string]string{},
+		Metadata: map[string]string{
+			"namespace":     "default",
+			"labels":        "app=test1",
+			"containername": "nginx",
+			"type":          SYS_OP_FILE,
+		},
 
 		Spec: types.KnoxSystemSpec{
 			Selector: types.Selector{
@@ -53,4 +58,54 @@ func TestGeneratePolicyName(t *testing.T) {
 	updated := GeneratePolicyName(map[string]bool{}, exist, "testcluster")
 
 	assert.Equal(t, updated.Metadata["clusterName"], "testcluster")
+
+	// named like the policies merged from the WPFS records
+	merged := mergeSysPolicies([]types.KnoxSystemPolicy{exist})
+	assert.Equal(t, merged[0].Metadata["name"], updated.Metadata["name"])
+}
+
+// ======================= //
+// == Rejected Policies == //
+// ======================= //
+
+func TestUpdateDuplicatedPolicyRejected(t *testing.T) {
+	rejected := types.KnoxSystemPolicy{
+		Metadata: map[string]string{
+			"name":         "autopol-file-default-test1-1a2b3c4d",
+			"clusterName":  "default",
+			"namespace":    "default",
+			"type":         SYS_OP_FILE,
+			"status":       "latest",
+			"review_state": libs.ReviewStateRejected,
+		},
+
+		Spec: types.KnoxSystemSpec{
+			Selector: types.Selector{
+				MatchLabels: map[string]string{
+					"app": "test1",
+				},
+			},
+			File: types.KnoxSys{
+				MatchPaths: []types.KnoxMatchPaths{
+					types.KnoxMatchPaths{Path: "/etc/passwd"},
+				},
+			},
+		},
+	}
+
+	// the rejected path only, not proposed again
+	samePath := types.KnoxSystemPolicy{}
+	libs.DeepCopy(&samePath, &rejected)
+	samePath.Metadata = map[string]string{"namespace": "default", "type": SYS_OP_FILE}
+
+	// the rejected path and a new one, the new one is proposed
+	newPath := types.KnoxSystemPolicy{}
+	libs.DeepCopy(&newPath, &rejected)
+	newPath.Metadata = map[string]string{"namespace": "default", "type": SYS_OP_FILE}
+	newPath.Spec.File.MatchPaths = append(newPath.Spec.File.MatchPaths, types.KnoxMatchPaths{Path: "/etc/hosts"})
+
+	newPolicies := UpdateDuplicatedPolicy([]types.KnoxSystemPolicy{rejected}, []types.KnoxSystemPolicy{samePath, newPath}, "default")
+	assert.Equal(t, 1, len(newPolicies))
+	assert.Equal(t, []types.KnoxMatchPaths{types.KnoxMatchPaths{Path: "/etc/hosts"}}, newPolicies[0].Spec.File.MatchPaths)
+	assert.Equal(t, "latest", newPolicies[0].Metadata["status"])
 }
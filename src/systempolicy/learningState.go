@@ -0,0 +1,250 @@
+package systempolicy
+
+import (
+	"sync"
+	"time"
+
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	wpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/worker"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ============================= //
+// == Workload Learning State == //
+// ============================= //
+
+// WorkloadKey Structure
+type WorkloadKey struct {
+	ClusterName   string
+	Namespace     string
+	ContainerName string
+	Labels        string
+}
+
+// LearningMinObservationTime minimum time (in seconds) a workload is observed before being stable
+var LearningMinObservationTime int64
+
+// LearningStableIntervals number of intervals without new resources before being stable
+var LearningStableIntervals int
+
+// LearningCandidateIntervals number of additional stable intervals before being an enforced candidate
+var LearningCandidateIntervals int
+
+// ChangedWorkloads workloads whose WPFS sets changed in the current discovery interval
+var ChangedWorkloads map[WorkloadKey]bool
+var ChangedWorkloadsMutex *sync.Mutex
+
+func init() {
+	ChangedWorkloads = map[WorkloadKey]bool{}
+	ChangedWorkloadsMutex = &sync.Mutex{}
+}
+
+func initLearningConfiguration() {
+	LearningMinObservationTime = 0
+	if minObs := cfg.GetCfgSystemLearningMinObservationTime(); minObs != "" {
+		duration, err := time.ParseDuration(minObs)
+		if err != nil {
+			log.Error().Msgf("invalid learning min-observation-time [%s] err=%s", minObs, err.Error())
+		} else {
+			LearningMinObservationTime = int64(duration.Seconds())
+		}
+	}
+
+	LearningStableIntervals = cfg.GetCfgSystemLearningStableIntervals()
+	LearningCandidateIntervals = cfg.GetCfgSystemLearningCandidateIntervals()
+}
+
+func isLearningEnabled() bool {
+	return LearningMinObservationTime > 0 || LearningStableIntervals > 0
+}
+
+func getWorkloadKey(wpfs types.WorkloadProcessFileSet) WorkloadKey {
+	return WorkloadKey{
+		ClusterName:   wpfs.ClusterName,
+		Namespace:     wpfs.Namespace,
+		ContainerName: wpfs.ContainerName,
+		Labels:        wpfs.Labels,
+	}
+}
+
+func getPolicyWorkloadKey(policy types.KnoxSystemPolicy) WorkloadKey {
+	return WorkloadKey{
+		ClusterName:   policy.Metadata["clusterName"],
+		Namespace:     policy.Metadata["namespace"],
+		ContainerName: policy.Metadata["containername"],
+		Labels:        policy.Metadata["labels"],
+	}
+}
+
+func markWorkloadChanged(wpfs types.WorkloadProcessFileSet) {
+	ChangedWorkloadsMutex.Lock()
+	defer ChangedWorkloadsMutex.Unlock()
+
+	ChangedWorkloads[getWorkloadKey(wpfs)] = true
+}
+
+func isWorkloadChanged(key WorkloadKey) bool {
+	ChangedWorkloadsMutex.Lock()
+	defer ChangedWorkloadsMutex.Unlock()
+
+	return ChangedWorkloads[key]
+}
+
+// takeChangedWorkloads returns the workloads changed in the discovery interval, the changes marked later belong
+// to the next interval
+func takeChangedWorkloads() map[WorkloadKey]bool {
+	ChangedWorkloadsMutex.Lock()
+	defer ChangedWorkloadsMutex.Unlock()
+
+	changed := ChangedWorkloads
+	ChangedWorkloads = map[WorkloadKey]bool{}
+
+	return changed
+}
+
+// nextLearningState moves a workload one step forward in learning -> stable -> enforced-candidate
+func nextLearningState(state types.WorkloadLearningState, changed bool, now int64) types.WorkloadLearningState {
+	if changed {
+		state.State = types.LearningStateLearning
+		state.StableIntervals = 0
+		state.LastChangeTime = now
+	} else {
+		state.StableIntervals++
+	}
+
+	if state.State == types.LearningStateLearning {
+		if now-state.FirstSeenTime >= LearningMinObservationTime && state.StableIntervals >= LearningStableIntervals {
+			state.State = types.LearningStateStable
+		}
+	} else if state.State == types.LearningStateStable {
+		if state.StableIntervals >= LearningStableIntervals+LearningCandidateIntervals {
+			state.State = types.LearningStateEnforcedCandidate
+		}
+	}
+
+	state.UpdatedTime = now
+
+	return state
+}
+
+func getLearningStateMap(filter types.WorkloadLearningState) map[WorkloadKey]types.WorkloadLearningState {
+	results := map[WorkloadKey]types.WorkloadLearningState{}
+
+	states, err := libs.GetWorkloadLearningStates(CfgDB, filter)
+	if err != nil {
+		return results
+	}
+
+	for _, state := range states {
+		key := WorkloadKey{
+			ClusterName:   state.ClusterName,
+			Namespace:     state.Namespace,
+			ContainerName: state.ContainerName,
+			Labels:        state.Labels,
+		}
+		results[key] = state
+	}
+
+	return results
+}
+
+// isWorkloadLearning checks if the policy of the workload should still be held back
+func isWorkloadLearning(states map[WorkloadKey]types.WorkloadLearningState, key WorkloadKey) bool {
+	if !isLearningEnabled() {
+		return false
+	}
+
+	// the workload is going back to learning at the end of this interval
+	if isWorkloadChanged(key) {
+		return true
+	}
+
+	state, ok := states[key]
+	if !ok {
+		return true
+	}
+
+	return state.State == types.LearningStateLearning
+}
+
+// updateLearningStates advances the learning state of every workload in WPFS once per discovery interval,
+// and returns true if any workload left the learning state.
+func updateLearningStates() bool {
+	promoted := false
+	changed := takeChangedWorkloads()
+
+	res, _, err := libs.GetWorkloadProcessFileSet(CfgDB, types.WorkloadProcessFileSet{})
+	if err != nil {
+		log.Error().Msgf("could not fetch WPFS err=%s", err.Error())
+		return false
+	}
+
+	workloads := map[WorkloadKey]bool{}
+	for wpfs := range res {
+		workloads[getWorkloadKey(wpfs)] = true
+	}
+
+	states := getLearningStateMap(types.WorkloadLearningState{})
+	now := time.Now().Unix()
+
+	for key := range workloads {
+		state, exist := states[key]
+		if !exist {
+			state = types.WorkloadLearningState{
+				ClusterName:   key.ClusterName,
+				Namespace:     key.Namespace,
+				ContainerName: key.ContainerName,
+				Labels:        key.Labels,
+				State:         types.LearningStateLearning,
+				FirstSeenTime: now,
+			}
+		}
+
+		newState := nextLearningState(state, changed[key] || !exist, now)
+		if state.State == types.LearningStateLearning && newState.State != types.LearningStateLearning {
+			log.Info().Msgf("workload [%s/%s/%s] is now %s", key.ClusterName, key.Namespace, key.Labels, newState.State)
+			promoted = true
+		}
+
+		if !exist {
+			err = libs.InsertWorkloadLearningState(CfgDB, newState)
+		} else {
+			err = libs.UpdateWorkloadLearningState(CfgDB, newState)
+		}
+		if err != nil {
+			log.Error().Msgf("failure add/updt learning state for workload=%+v err=%s", key, err.Error())
+		}
+	}
+
+	return promoted
+}
+
+// GetLearningStates returns the learning states of the workloads, filtered by the namespace, cluster and labels if set
+func GetLearningStates(namespace, clustername, labels string) []*wpb.LearningState {
+	results := []*wpb.LearningState{}
+
+	states, err := libs.GetWorkloadLearningStates(CfgDB, types.WorkloadLearningState{
+		ClusterName: clustername,
+		Namespace:   namespace,
+		Labels:      labels,
+	})
+	if err != nil {
+		return results
+	}
+
+	for _, state := range states {
+		results = append(results, &wpb.LearningState{
+			Clustername:     state.ClusterName,
+			Namespace:       state.Namespace,
+			Containername:   state.ContainerName,
+			Labels:          state.Labels,
+			State:           state.State,
+			Stableintervals: int32(state.StableIntervals),
+			Firstseentime:   state.FirstSeenTime,
+			Lastchangetime:  state.LastChangeTime,
+		})
+	}
+
+	return results
+}
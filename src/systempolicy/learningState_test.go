@@ -0,0 +1,64 @@
+package systempolicy
+
+import (
+	"testing"
+
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// ============================ //
+// == Learning State Tests   == //
+// ============================ //
+
+func TestNextLearningState(t *testing.T) {
+	LearningMinObservationTime = 100
+	LearningStableIntervals = 2
+	LearningCandidateIntervals = 1
+
+	state := types.WorkloadLearningState{State: types.LearningStateLearning, FirstSeenTime: 0}
+
+	// not observed long enough
+	state = nextLearningState(state, false, 10)
+	state = nextLearningState(state, false, 20)
+	assert.Equal(t, types.LearningStateLearning, state.State)
+	assert.Equal(t, 2, state.StableIntervals)
+
+	state = nextLearningState(state, false, 100)
+	assert.Equal(t, types.LearningStateStable, state.State)
+
+	state = nextLearningState(state, false, 110)
+	assert.Equal(t, types.LearningStateEnforcedCandidate, state.State)
+
+	// a new resource sends the workload back to learning
+	state = nextLearningState(state, true, 120)
+	assert.Equal(t, types.LearningStateLearning, state.State)
+	assert.Equal(t, 0, state.StableIntervals)
+	assert.Equal(t, int64(120), state.LastChangeTime)
+	assert.Equal(t, int64(120), state.UpdatedTime)
+}
+
+func TestIsWorkloadLearning(t *testing.T) {
+	key := WorkloadKey{Namespace: "default", Labels: "app=test"}
+	states := map[WorkloadKey]types.WorkloadLearningState{
+		key: {State: types.LearningStateStable},
+	}
+
+	// learning disabled
+	LearningMinObservationTime = 0
+	LearningStableIntervals = 0
+	assert.False(t, isWorkloadLearning(states, WorkloadKey{}))
+
+	LearningStableIntervals = 2
+	assert.False(t, isWorkloadLearning(states, key))
+	assert.True(t, isWorkloadLearning(states, WorkloadKey{}))
+
+	markWorkloadChanged(types.WorkloadProcessFileSet{Namespace: "default", Labels: "app=test"})
+	assert.True(t, isWorkloadLearning(states, key))
+
+	// the changes are taken once per discovery interval
+	assert.Equal(t, map[WorkloadKey]bool{key: true}, takeChangedWorkloads())
+	assert.False(t, isWorkloadLearning(states, key))
+
+	LearningStableIntervals = 0
+}
@@ -0,0 +1,213 @@
+package systempolicy
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ========================= //
+// == Noise Path Rules    == //
+// ========================= //
+
+// NoisePathSampleLimit max number of collapsed paths kept per rule in the report
+const NoisePathSampleLimit = 10
+
+type noisePathMatcher struct {
+	rule string
+	re   *regexp.Regexp
+	dir  string
+}
+
+// NoisePathMatchers global noise path rules
+var NoisePathMatchers []noisePathMatcher
+
+// NoisePathNsMatchers per-namespace noise path rules, global rules are appended unless excluded
+var NoisePathNsMatchers map[string][]noisePathMatcher
+
+// NoisePathReport collapsed paths, key: namespace + rule + dir
+var NoisePathReport map[string]*types.NoisePathCollapse
+var NoisePathReportMutex *sync.Mutex
+
+func init() {
+	NoisePathNsMatchers = map[string][]noisePathMatcher{}
+	NoisePathReport = map[string]*types.NoisePathCollapse{}
+	NoisePathReportMutex = &sync.Mutex{}
+}
+
+// globToRegexp converts a glob to an anchored regexp, '*' and '?' do not cross '/', '**' does
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	return sb.String()
+}
+
+func buildNoisePathMatchers(rules []types.NoisePathRule) []noisePathMatcher {
+	matchers := []noisePathMatcher{}
+
+	for _, rule := range rules {
+		if rule.Dir == "" {
+			log.Error().Msgf("noise path rule without dir %+v", rule)
+			continue
+		}
+
+		dir := rule.Dir
+		if !strings.HasSuffix(dir, "/") {
+			dir = dir + "/"
+		}
+
+		expr, ruleStr := rule.Regex, rule.Regex
+		if rule.Glob != "" {
+			expr, ruleStr = globToRegexp(rule.Glob), rule.Glob
+		}
+		if expr == "" {
+			log.Error().Msgf("noise path rule without glob or regex %+v", rule)
+			continue
+		}
+
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			log.Error().Msgf("failed noise path regexp compile [%s] err=%s", expr, err.Error())
+			continue
+		}
+
+		matchers = append(matchers, noisePathMatcher{rule: ruleStr, re: re, dir: dir})
+	}
+
+	return matchers
+}
+
+func initNoisePathConfiguration() {
+	NoisePathMatchers = buildNoisePathMatchers(cfg.GetCfgSystemNoisePathRules())
+
+	NoisePathNsMatchers = map[string][]noisePathMatcher{}
+	for _, override := range cfg.GetCfgSystemNoisePathOverrides() {
+		matchers := buildNoisePathMatchers(override.Rules)
+		if !override.ExcludeGlobal {
+			matchers = append(matchers, NoisePathMatchers...)
+		}
+		NoisePathNsMatchers[override.Namespace] = append(NoisePathNsMatchers[override.Namespace], matchers...)
+	}
+}
+
+func getNoisePathMatchers(namespace string) []noisePathMatcher {
+	if matchers, ok := NoisePathNsMatchers[namespace]; ok {
+		return matchers
+	}
+	return NoisePathMatchers
+}
+
+func addNoisePathReport(namespace string, matcher noisePathMatcher, dir, path string) {
+	NoisePathReportMutex.Lock()
+	defer NoisePathReportMutex.Unlock()
+
+	key := namespace + types.RecordSeparator + matcher.rule + types.RecordSeparator + dir
+
+	collapse, ok := NoisePathReport[key]
+	if !ok {
+		collapse = &types.NoisePathCollapse{
+			Namespace: namespace,
+			Rule:      matcher.rule,
+			Dir:       dir,
+		}
+		NoisePathReport[key] = collapse
+	}
+
+	collapse.Count++
+
+	for _, sample := range collapse.Samples {
+		if sample == path {
+			return
+		}
+	}
+
+	if len(collapse.Samples) < NoisePathSampleLimit {
+		collapse.Samples = append(collapse.Samples, path)
+	}
+}
+
+// suppressNoisePaths replaces the paths matching a noise path rule of the namespace by the rule dir
+func suppressNoisePaths(namespace string, paths []string) []string {
+	matchers := getNoisePathMatchers(namespace)
+	if len(matchers) == 0 {
+		return paths
+	}
+
+	results := []string{}
+	for _, path := range paths {
+		suppressed := false
+		for _, matcher := range matchers {
+			match := matcher.re.FindStringSubmatchIndex(path)
+			if match == nil {
+				continue
+			}
+
+			// regex capture groups can be referred in the dir, e.g., /home/$1/.cache/
+			dir := string(matcher.re.ExpandString(nil, matcher.dir, path, match))
+			if dir != path {
+				addNoisePathReport(namespace, matcher, dir, path)
+			}
+			results = append(results, dir)
+			suppressed = true
+			break
+		}
+
+		if !suppressed {
+			results = append(results, path)
+		}
+	}
+
+	return results
+}
+
+// resetNoisePathReport clears the collapsed paths, the report covers a single discovery run
+func resetNoisePathReport() {
+	NoisePathReportMutex.Lock()
+	defer NoisePathReportMutex.Unlock()
+
+	NoisePathReport = map[string]*types.NoisePathCollapse{}
+}
+
+// GetNoisePathReport returns what was collapsed by the noise path rules
+func GetNoisePathReport() []types.NoisePathCollapse {
+	NoisePathReportMutex.Lock()
+	defer NoisePathReportMutex.Unlock()
+
+	results := []types.NoisePathCollapse{}
+	for _, collapse := range NoisePathReport {
+		result := *collapse
+		result.Samples = append([]string{}, collapse.Samples...)
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Namespace != results[j].Namespace {
+			return results[i].Namespace < results[j].Namespace
+		}
+		return results[i].Dir < results[j].Dir
+	})
+
+	return results
+}
@@ -0,0 +1,60 @@
+package systempolicy
+
+import (
+	"testing"
+
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// ========================= //
+// == Noise Path Tests    == //
+// ========================= //
+
+func TestGlobToRegexp(t *testing.T) {
+	assert.Equal(t, `^/tmp/[^/]*$`, globToRegexp("/tmp/*"))
+	assert.Equal(t, `^/tmp/.*$`, globToRegexp("/tmp/**"))
+	assert.Equal(t, `^/var/log/[^/]*\.log\.[^/]$`, globToRegexp("/var/log/*.log.?"))
+}
+
+func TestSuppressNoisePaths(t *testing.T) {
+	NoisePathMatchers = buildNoisePathMatchers([]types.NoisePathRule{
+		{Glob: "/tmp/*", Dir: "/tmp"},
+		{Regex: `^/home/([^/]+)/\.cache/.*`, Dir: "/home/$1/.cache/"},
+	})
+	NoisePathNsMatchers = map[string][]noisePathMatcher{
+		"prod": buildNoisePathMatchers([]types.NoisePathRule{{Glob: "/var/log/**", Dir: "/var/log/"}}),
+	}
+	NoisePathReport = map[string]*types.NoisePathCollapse{}
+
+	results := suppressNoisePaths("default", []string{"/tmp/a1b2", "/tmp/x/y", "/home/app/.cache/pip/x", "/etc/hosts"})
+	assert.Equal(t, []string{"/tmp/", "/tmp/x/y", "/home/app/.cache/", "/etc/hosts"}, results)
+
+	// global rules are excluded by the namespace rules
+	results = suppressNoisePaths("prod", []string{"/tmp/a1b2", "/var/log/app/1.log"})
+	assert.Equal(t, []string{"/tmp/a1b2", "/var/log/"}, results)
+
+	report := GetNoisePathReport()
+	assert.Equal(t, 3, len(report))
+	assert.Equal(t, "/home/app/.cache/", report[0].Dir)
+	assert.Equal(t, []string{"/tmp/a1b2"}, report[1].Samples)
+
+	// the counts do not grow across the discovery runs
+	resetNoisePathReport()
+	suppressNoisePaths("default", []string{"/tmp/a1b2"})
+	report = GetNoisePathReport()
+	assert.Equal(t, 1, len(report))
+	assert.Equal(t, 1, report[0].Count)
+
+	NoisePathMatchers = nil
+	NoisePathNsMatchers = map[string][]noisePathMatcher{}
+}
+
+func TestAggregateNamespacePathsExt(t *testing.T) {
+	NoisePathMatchers = buildNoisePathMatchers([]types.NoisePathRule{{Glob: "/tmp/*", Dir: "/tmp/"}})
+
+	results := AggregateNamespacePathsExt("default", []string{"/tmp/a", "/tmp/b", "/etc/hosts"})
+	assert.Equal(t, []string{"/etc/hosts", "/tmp/"}, results)
+
+	NoisePathMatchers = nil
+}
@@ -305,6 +305,13 @@ func MergeAndAggregatePaths(dirs []string, paths []string) []SysPath {
 }
 
 func AggregatePathsExt(paths []string) []string {
+	return AggregateNamespacePathsExt("", paths)
+}
+
+// AggregateNamespacePathsExt collapses the noise paths of the namespace before aggregating the paths
+func AggregateNamespacePathsExt(namespace string, paths []string) []string {
+	paths = suppressNoisePaths(namespace, paths)
+
 	dirlist, filelist := mergeFileInDir(paths)
 
 	results := AggregatePaths(filelist)
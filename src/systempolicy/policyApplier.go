@@ -0,0 +1,104 @@
+package systempolicy
+
+import (
+	"fmt"
+
+	"github.com/accuknox/auto-policy-discovery/src/cluster"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/plugin"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ========================= //
+// == Apply System Policy == //
+// ========================= //
+
+// getLatestSystemPolicy returns the latest version of the system policy in the cluster
+func getLatestSystemPolicy(clusterName, name string) (types.KnoxSystemPolicy, error) {
+	for _, policy := range libs.GetSystemPolicies(CfgDB, "", "latest") {
+		if policy.Metadata["clusterName"] == clusterName && policy.Metadata["name"] == name {
+			return policy, nil
+		}
+	}
+
+	return types.KnoxSystemPolicy{}, fmt.Errorf("no latest system policy %s in cluster %s", name, clusterName)
+}
+
+// getPreviousSystemPolicyNames returns the names of the versions the system policy outdated, from the nearest one
+func getPreviousSystemPolicyNames(clusterName, name string) []string {
+	outdatedPolicies := []types.KnoxSystemPolicy{}
+	for _, policy := range libs.GetSystemPolicies(CfgDB, "", "outdated") {
+		if policy.Metadata["clusterName"] == clusterName {
+			outdatedPolicies = append(outdatedPolicies, policy)
+		}
+	}
+
+	names := []string{}
+	seen := map[string]bool{name: true}
+	for next := []string{name}; len(next) > 0; {
+		current := next
+		next = []string{}
+		for _, policy := range outdatedPolicies {
+			prev := policy.Metadata["name"]
+			if libs.ContainsElement(current, policy.Outdated) && !seen[prev] {
+				seen[prev] = true
+				names = append(names, prev)
+				next = append(next, prev)
+			}
+		}
+	}
+
+	return names
+}
+
+// ApplySystemPolicy creates or updates the kubearmor policy of an approved system policy in the cluster, the kubearmor
+// policies of its previous versions are replaced, the policy is marked as applied unless it is a dry run
+func ApplySystemPolicy(applier *cluster.PolicyApplier, clusterName, name, reviewer string, dryRun bool) (types.PolicyApplyResult, error) {
+	policy, err := getLatestSystemPolicy(clusterName, name)
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	state := libs.GetReviewState(policy.Metadata)
+	if state != libs.ReviewStateApproved && state != libs.ReviewStateApplied {
+		return types.PolicyApplyResult{}, fmt.Errorf("system policy %s is %s, approve it before applying", name, state)
+	}
+
+	kubeArmorPolicies := plugin.ConvertKnoxSystemPolicyToKubeArmorPolicy([]types.KnoxSystemPolicy{policy})
+	result, err := applier.ApplyReplacing(kubeArmorPolicies[0], getPreviousSystemPolicyNames(clusterName, name), dryRun)
+	if err != nil {
+		return result, err
+	}
+	for _, replaced := range result.Replaced {
+		log.Info().Msgf("system policy %s replaced the %s %s", name, result.Kind, replaced)
+	}
+
+	if !dryRun && state == libs.ReviewStateApproved {
+		err = libs.UpdatePolicyReview(CfgDB, &types.PolicyReview{
+			PolicyType:  "system",
+			ClusterName: clusterName,
+			Name:        name,
+			State:       libs.ReviewStateApplied,
+			Reviewer:    reviewer,
+			Comment:     result.Kind + " " + result.Action,
+		})
+	}
+
+	return result, err
+}
+
+// RollbackSystemPolicy restores the previous version of the kubearmor policy of the system policy in the cluster
+func RollbackSystemPolicy(applier *cluster.PolicyApplier, clusterName, name string, dryRun bool) (types.PolicyApplyResult, error) {
+	policy, err := getLatestSystemPolicy(clusterName, name)
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	kubeArmorPolicies := plugin.ConvertKnoxSystemPolicyToKubeArmorPolicy([]types.KnoxSystemPolicy{policy})
+	kind, namespace, objName, err := cluster.GetPolicyObjectKey(kubeArmorPolicies[0])
+	if err != nil {
+		return types.PolicyApplyResult{}, err
+	}
+
+	return applier.Rollback(kind, namespace, objName, dryRun)
+}
@@ -0,0 +1,35 @@
+package systempolicy
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPreviousSystemPolicyNames(t *testing.T) {
+	_, mock := libs.NewMock()
+	defer func() { libs.MockDB = nil }()
+
+	CfgDB = types.ConfigDB{DBDriver: "mysql"}
+	defer func() { CfgDB = types.ConfigDB{} }()
+
+	columns := []string{
+		"apiVersion", "kind", "name", "clusterName", "namespace", "type", "status", "outdated", "spec",
+		"generatedTime", "updatedTime", "latest", "review_state", "rule_stats",
+	}
+	mock.ExpectQuery("^SELECT (.+) FROM system_policy WHERE status = \\?").
+		WithArgs("outdated").
+		WillReturnRows(mock.NewRows(columns).
+			AddRow("v1", "KnoxSystemPolicy", "autopol-system-nginx-2", "default", "default", "file", "outdated",
+				"autopol-system-nginx-3", []byte(`{}`), 200, 200, false, nil, nil).
+			AddRow("v1", "KnoxSystemPolicy", "autopol-system-nginx-1", "default", "default", "file", "outdated",
+				"autopol-system-nginx-2", []byte(`{}`), 100, 100, false, nil, nil).
+			// the versions of the other clusters are not replaced
+			AddRow("v1", "KnoxSystemPolicy", "autopol-system-nginx-1", "staging", "default", "file", "outdated",
+				"autopol-system-nginx-3", []byte(`{}`), 100, 100, false, nil, nil))
+
+	assert.Equal(t, []string{"autopol-system-nginx-2", "autopol-system-nginx-1"},
+		getPreviousSystemPolicyNames("default", "autopol-system-nginx-3"))
+}
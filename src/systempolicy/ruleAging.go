@@ -0,0 +1,235 @@
+package systempolicy
+
+import (
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/telemetry"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ================ //
+// == Rule Aging == //
+// ================ //
+
+// getPolicyRuleKeys returns the keys of the process, file and network rules of the policy
+func getPolicyRuleKeys(policy types.KnoxSystemPolicy) []string {
+	keys := []string{}
+
+	for _, kind := range []string{SYS_OP_PROCESS, SYS_OP_FILE} {
+		sys := policy.Spec.Process
+		if kind == SYS_OP_FILE {
+			sys = policy.Spec.File
+		}
+
+		for _, matchPath := range sys.MatchPaths {
+			keys = append(keys, getSysRuleKey(kind, "path", matchPath.Path))
+		}
+		for _, matchDir := range sys.MatchDirectories {
+			keys = append(keys, getSysRuleKey(kind, "dir", matchDir.Dir))
+		}
+	}
+
+	for _, matchProtocol := range policy.Spec.Network.MatchProtocols {
+		keys = append(keys, getSysRuleKey(SYS_OP_NETWORK, "protocol", matchProtocol.Protocol))
+	}
+
+	return keys
+}
+
+// markLatestPoliciesRulesSeen records the rules of the discovered policy observed in the latest policies
+// of the workload, an unchanged policy is not versioned again but its rules are still seen
+func markLatestPoliciesRulesSeen(existingPolicies []types.KnoxSystemPolicy, policy types.KnoxSystemPolicy, seenTime int64) {
+	seenKeys := getPolicyRuleKeys(policy)
+
+	for _, latest := range GetLatestPolicy(existingPolicies, policy) {
+		keys := []string{}
+		for _, key := range getPolicyRuleKeys(latest) {
+			if libs.ContainsElement(seenKeys, key) {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		stats := libs.MarkRulesSeen(latest.RuleStats, keys, seenTime)
+		if err := libs.UpdateRuleStats(CfgDB, "system", latest.Metadata["clusterName"], latest.Metadata["name"], stats); err != nil {
+			log.Error().Msg(err.Error())
+		}
+
+		for i := range existingPolicies {
+			if existingPolicies[i].Metadata["name"] == latest.Metadata["name"] {
+				existingPolicies[i].RuleStats = stats
+			}
+		}
+	}
+}
+
+// inheritRuleStats returns the rule statistics of a new version of the latest policies, the rules
+// merged from the latest policies keep their statistics and the discovered rules are seen now
+func inheritRuleStats(newPolicy types.KnoxSystemPolicy, latestPolicies []types.KnoxSystemPolicy, seenTime int64) map[string]types.RuleStat {
+	stats := map[string]types.RuleStat{}
+	keys := getPolicyRuleKeys(newPolicy)
+
+	for _, latest := range latestPolicies {
+		for key, stat := range latest.RuleStats {
+			if libs.ContainsElement(keys, key) {
+				stats[key] = stat
+			}
+		}
+	}
+
+	newKeys := []string{}
+	for _, key := range keys {
+		if _, ok := stats[key]; !ok {
+			newKeys = append(newKeys, key)
+		}
+	}
+
+	return libs.MarkRulesSeen(stats, newKeys, seenTime)
+}
+
+func removeAgedSysPaths(kind string, sys types.KnoxSys, agedKeys []string, removedRules *[]string) types.KnoxSys {
+	result := types.KnoxSys{}
+
+	for _, matchPath := range sys.MatchPaths {
+		if key := getSysRuleKey(kind, "path", matchPath.Path); libs.ContainsElement(agedKeys, key) {
+			*removedRules = append(*removedRules, key)
+			continue
+		}
+		result.MatchPaths = append(result.MatchPaths, matchPath)
+	}
+	for _, matchDir := range sys.MatchDirectories {
+		if key := getSysRuleKey(kind, "dir", matchDir.Dir); libs.ContainsElement(agedKeys, key) {
+			*removedRules = append(*removedRules, key)
+			continue
+		}
+		result.MatchDirectories = append(result.MatchDirectories, matchDir)
+	}
+
+	return result
+}
+
+// removeAgedRules returns the policy without the rules last seen before the deadline and the removed rules,
+// returns false if no rule is aged, or all of them are since an empty policy is not a tightened one
+func removeAgedRules(policy types.KnoxSystemPolicy, deadline int64) (types.KnoxSystemPolicy, []string, bool) {
+	defaultLastSeen := policy.UpdatedTime
+	if defaultLastSeen == 0 {
+		defaultLastSeen = policy.GeneratedTime
+	}
+
+	agedKeys := libs.GetAgedRuleKeys(policy.RuleStats, getPolicyRuleKeys(policy), defaultLastSeen, deadline)
+	if len(agedKeys) == 0 {
+		return policy, nil, false
+	}
+
+	removedRules := []string{}
+	aged := policy
+	aged.Spec.Process = removeAgedSysPaths(SYS_OP_PROCESS, policy.Spec.Process, agedKeys, &removedRules)
+	aged.Spec.File = removeAgedSysPaths(SYS_OP_FILE, policy.Spec.File, agedKeys, &removedRules)
+
+	protocols := []types.KnoxMatchProtocols{}
+	for _, matchProtocol := range policy.Spec.Network.MatchProtocols {
+		if key := getSysRuleKey(SYS_OP_NETWORK, "protocol", matchProtocol.Protocol); libs.ContainsElement(agedKeys, key) {
+			removedRules = append(removedRules, key)
+			continue
+		}
+		protocols = append(protocols, matchProtocol)
+	}
+	aged.Spec.Network.MatchProtocols = nil
+	if len(protocols) > 0 {
+		aged.Spec.Network.MatchProtocols = protocols
+	}
+
+	keys := getPolicyRuleKeys(aged)
+	if len(keys) == 0 {
+		return policy, nil, false
+	}
+
+	aged.RuleStats = map[string]types.RuleStat{}
+	for _, key := range keys {
+		if stat, ok := policy.RuleStats[key]; ok {
+			aged.RuleStats[key] = stat
+		}
+	}
+
+	aged.Metadata = map[string]string{}
+	for k, v := range policy.Metadata {
+		aged.Metadata[k] = v
+	}
+	// the tightened version is reviewed again
+	aged.Metadata["review_state"] = libs.ReviewStateDiscovered
+	aged.Outdated = ""
+
+	return aged, removedRules, true
+}
+
+// AgeSystemPolicyRules proposes a tightened version of the latest policies of the cluster without the rules
+// not observed for the configured number of days, the tightened version outdates the policy and is reviewed
+// again, the removed rules are listed in the change record,
+// the policies of the WPFS mode are rebuilt from the file sets of the workloads, so they are not aged
+func AgeSystemPolicyRules(clusterName string) {
+	agingDays := cfg.GetCfgSystemRuleAgingDays()
+	if agingDays <= 0 || cfg.CurrentCfg.ConfigSysPolicy.DeprecateOldMode {
+		return
+	}
+
+	now := libs.ConvertStrToUnixTime("now")
+	deadline := now - int64(agingDays)*24*60*60
+
+	// the names of the outdated versions are taken as well
+	policyNamesMap := map[string]bool{}
+	policies := []types.KnoxSystemPolicy{}
+	for _, policy := range libs.GetSystemPolicies(CfgDB, "", "") {
+		if policy.Metadata["clusterName"] != clusterName {
+			continue
+		}
+
+		policyNamesMap[policy.Metadata["name"]] = true
+		policies = append(policies, policy)
+	}
+
+	for _, policy := range policies {
+		if policy.Metadata["status"] != "latest" || libs.GetReviewState(policy.Metadata) == libs.ReviewStateRejected {
+			continue
+		}
+
+		agedPolicy, removedRules, ok := removeAgedRules(policy, deadline)
+		if !ok {
+			continue
+		}
+
+		agedPolicy = GeneratePolicyName(policyNamesMap, agedPolicy, clusterName)
+
+		libs.InsertSystemPolicies(CfgDB, []types.KnoxSystemPolicy{agedPolicy})
+		telemetry.PolicyEvent(telemetry.EventPolicyCreated, telemetry.PolicyTypeSystem,
+			clusterName, agedPolicy.Metadata["namespace"], agedPolicy.Metadata["name"])
+
+		// the approved or applied version keeps its spec and state to roll back to, the apply of
+		// the tightened version replaces it in the cluster
+		reviewState := libs.GetReviewState(policy.Metadata)
+		if reviewState != libs.ReviewStateApproved && reviewState != libs.ReviewStateApplied {
+			reviewState = libs.ReviewStateSuperseded
+		}
+		libs.UpdateOutdatedSystemPolicyReview(CfgDB, policy.Metadata["name"], agedPolicy.Metadata["name"], reviewState)
+		telemetry.PolicyEvent(telemetry.EventPolicyOutdated, telemetry.PolicyTypeSystem,
+			clusterName, policy.Metadata["namespace"], policy.Metadata["name"])
+
+		change := types.PolicyChange{
+			PolicyType:    "system",
+			ClusterName:   clusterName,
+			Namespace:     policy.Metadata["namespace"],
+			Name:          policy.Metadata["name"],
+			NewPolicyName: agedPolicy.Metadata["name"],
+			Reason:        libs.PolicyChangeReasonAged,
+			RemovedRules:  removedRules,
+			ChangedTime:   now,
+		}
+		if err := libs.InsertPolicyChange(CfgDB, change); err != nil {
+			log.Error().Msg(err.Error())
+		}
+
+		log.Info().Msgf("System policy [%s] tightened to [%s], %d rules not seen for %d days",
+			policy.Metadata["name"], agedPolicy.Metadata["name"], len(removedRules), agingDays)
+	}
+}
@@ -0,0 +1,71 @@
+package systempolicy
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveAgedRules(t *testing.T) {
+	policy := types.KnoxSystemPolicy{
+		Metadata: map[string]string{
+			"name":         "autopol-file-default-nginx-1a2b3c4d",
+			"clusterName":  "default",
+			"namespace":    "default",
+			"type":         SYS_OP_FILE,
+			"status":       "latest",
+			"review_state": libs.ReviewStateApproved,
+		},
+		Spec: types.KnoxSystemSpec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "nginx"}},
+			File: types.KnoxSys{
+				MatchPaths:       []types.KnoxMatchPaths{{Path: "/etc/passwd"}, {Path: "/etc/nginx/nginx.conf"}},
+				MatchDirectories: []types.KnoxMatchDirectories{{Dir: "/var/log/nginx/"}},
+			},
+		},
+		GeneratedTime: 100,
+		UpdatedTime:   100,
+		RuleStats: map[string]types.RuleStat{
+			getSysRuleKey(SYS_OP_FILE, "path", "/etc/nginx/nginx.conf"): {FirstSeen: 100, LastSeen: 1000, Hits: 4},
+			getSysRuleKey(SYS_OP_FILE, "dir", "/var/log/nginx/"):        {FirstSeen: 100, LastSeen: 1000, Hits: 4},
+		},
+	}
+
+	// the rules without statistics were last seen when the policy was updated
+	aged, removed, ok := removeAgedRules(policy, 500)
+	assert.True(t, ok)
+	assert.Equal(t, []string{getSysRuleKey(SYS_OP_FILE, "path", "/etc/passwd")}, removed)
+	assert.Equal(t, []types.KnoxMatchPaths{{Path: "/etc/nginx/nginx.conf"}}, aged.Spec.File.MatchPaths)
+	assert.Equal(t, policy.Spec.File.MatchDirectories, aged.Spec.File.MatchDirectories)
+	assert.Equal(t, libs.ReviewStateDiscovered, aged.Metadata["review_state"])
+	assert.Equal(t, libs.ReviewStateApproved, policy.Metadata["review_state"])
+	// the tightened version is named when it is inserted as the new version
+	assert.Equal(t, "autopol-file-default-nginx-1a2b3c4d", aged.Metadata["name"])
+	assert.Equal(t, "latest", aged.Metadata["status"])
+
+	_, _, ok = removeAgedRules(policy, 5000)
+	assert.False(t, ok)
+}
+
+func TestInheritRuleStats(t *testing.T) {
+	latest := types.KnoxSystemPolicy{
+		Spec: types.KnoxSystemSpec{
+			Process: types.KnoxSys{MatchPaths: []types.KnoxMatchPaths{{Path: "/usr/sbin/nginx"}}},
+		},
+		RuleStats: map[string]types.RuleStat{
+			getSysRuleKey(SYS_OP_PROCESS, "path", "/usr/sbin/nginx"): {FirstSeen: 100, LastSeen: 200, Hits: 2},
+		},
+	}
+
+	newPolicy := types.KnoxSystemPolicy{
+		Spec: types.KnoxSystemSpec{
+			Process: types.KnoxSys{MatchPaths: []types.KnoxMatchPaths{{Path: "/usr/sbin/nginx"}, {Path: "/bin/sh"}}},
+		},
+	}
+
+	stats := inheritRuleStats(newPolicy, []types.KnoxSystemPolicy{latest}, 300)
+	assert.Equal(t, types.RuleStat{FirstSeen: 100, LastSeen: 200, Hits: 2}, stats[getSysRuleKey(SYS_OP_PROCESS, "path", "/usr/sbin/nginx")])
+	assert.Equal(t, types.RuleStat{FirstSeen: 300, LastSeen: 300, Hits: 1}, stats[getSysRuleKey(SYS_OP_PROCESS, "path", "/bin/sh")])
+}
@@ -0,0 +1,187 @@
+package systempolicy
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// =================== //
+// == Rule Evidence == //
+// =================== //
+
+// sysRule a process/file path or directory, or a network protocol of a system policy
+type sysRule struct {
+	kind     string
+	ruleType string
+	value    string
+}
+
+func (r sysRule) key() string {
+	return getSysRuleKey(r.kind, r.ruleType, r.value)
+}
+
+// covers returns true if the log resource recorded by the evidence is allowed by the rule
+func (r sysRule) covers(ruleType, value string) bool {
+	if r.ruleType == "dir" {
+		return strings.HasPrefix(value, r.value)
+	}
+	return r.ruleType == ruleType && r.value == value
+}
+
+func getPolicySysRules(policy types.KnoxSystemPolicy) []sysRule {
+	rules := []sysRule{}
+
+	for _, kind := range []string{SYS_OP_PROCESS, SYS_OP_FILE} {
+		sys := policy.Spec.Process
+		if kind == SYS_OP_FILE {
+			sys = policy.Spec.File
+		}
+
+		for _, matchPath := range sys.MatchPaths {
+			rules = append(rules, sysRule{kind: kind, ruleType: "path", value: matchPath.Path})
+		}
+		for _, matchDir := range sys.MatchDirectories {
+			rules = append(rules, sysRule{kind: kind, ruleType: "dir", value: matchDir.Dir})
+		}
+	}
+
+	for _, matchProtocol := range policy.Spec.Network.MatchProtocols {
+		rules = append(rules, sysRule{kind: SYS_OP_NETWORK, ruleType: "protocol", value: matchProtocol.Protocol})
+	}
+
+	return rules
+}
+
+func isSysOperationDiscovered(operation string) bool {
+	switch operation {
+	case SYS_OP_PROCESS:
+		return SystemPolicyTypes&SYS_OP_PROCESS_INT > 0
+	case SYS_OP_FILE:
+		return SystemPolicyTypes&SYS_OP_FILE_INT > 0
+	case SYS_OP_NETWORK:
+		return SystemPolicyTypes&SYS_OP_NETWORK_INT > 0
+	}
+	return false
+}
+
+// addSystemRuleEvidences records the logs of the pod as the evidences of the paths and protocols they access,
+// the evidences are kept by the labels of the pod, so both the pod and the workload policies find them,
+// the logs without their own time are seen at the time of the run
+func addSystemRuleEvidences(evidences map[string]types.RuleEvidence, clusterName string, pod types.Pod, logs []types.KnoxSystemLog, seenTime int64) {
+	podLabels := append([]string{}, pod.Labels...)
+	sort.Strings(podLabels)
+	labels := strings.Join(podLabels, ",")
+
+	for _, sysLog := range logs {
+		if !isSysOperationDiscovered(sysLog.Operation) {
+			continue
+		}
+
+		sampleLog, err := json.Marshal(sysLog)
+		if err != nil {
+			log.Error().Msg(err.Error())
+			continue
+		}
+		logTime := libs.GetRuleEvidenceSeenTime(sysLog.Timestamp, seenTime)
+
+		for _, resource := range cleanResource(sysLog.Operation, sysLog.Resource) {
+			rule := sysRule{kind: sysLog.Operation, ruleType: "path", value: resource}
+			if sysLog.Operation == SYS_OP_NETWORK {
+				rule.ruleType = "protocol"
+			} else if strings.HasSuffix(resource, "/") {
+				rule.ruleType = "dir"
+			}
+
+			libs.AddRuleEvidence(evidences, types.RuleEvidence{
+				PolicyType:  "system",
+				ClusterName: clusterName,
+				Namespace:   pod.Namespace,
+				Labels:      labels,
+				RuleKey:     rule.key(),
+				Rule:        resource,
+				FirstSeen:   logTime,
+				LastSeen:    logTime,
+				Count:       1,
+				SrcPods:     []string{sysLog.PodName},
+				SampleLog:   string(sampleLog),
+			})
+		}
+	}
+}
+
+func storeRuleEvidences(evidences map[string]types.RuleEvidence) {
+	records := []types.RuleEvidence{}
+	for _, evidence := range evidences {
+		records = append(records, evidence)
+	}
+
+	if err := libs.UpsertRuleEvidences(CfgDB, records); err != nil {
+		log.Error().Msg(err.Error())
+	}
+}
+
+func getEvidenceLabelMap(labels string) map[string]string {
+	labelMap := map[string]string{}
+	for _, label := range strings.Split(labels, ",") {
+		if kv := strings.SplitN(label, "=", 2); len(kv) == 2 {
+			labelMap[kv[0]] = kv[1]
+		}
+	}
+	return labelMap
+}
+
+// GetSystemPolicyRuleEvidences returns the evidences of the rules of the policy, the evidences of the replicas
+// of the workload and of the paths under a directory rule are merged into the evidence of the rule
+func GetSystemPolicyRuleEvidences(policy types.KnoxSystemPolicy) ([]types.RuleEvidence, error) {
+	stored, err := libs.GetRuleEvidences(CfgDB, types.RuleEvidence{
+		PolicyType:  "system",
+		ClusterName: policy.Metadata["clusterName"],
+		Namespace:   policy.Metadata["namespace"],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []types.RuleEvidence{}
+	for _, evidence := range stored {
+		if includeSelectorLabels(policy.Spec.Selector.MatchLabels, getEvidenceLabelMap(evidence.Labels)) {
+			candidates = append(candidates, evidence)
+		}
+	}
+
+	selector := []string{}
+	for k, v := range policy.Spec.Selector.MatchLabels {
+		selector = append(selector, k+"="+v)
+	}
+	sort.Strings(selector)
+
+	evidences := []types.RuleEvidence{}
+
+	for _, rule := range getPolicySysRules(policy) {
+		merged := types.RuleEvidence{
+			PolicyType:  "system",
+			ClusterName: policy.Metadata["clusterName"],
+			Namespace:   policy.Metadata["namespace"],
+			Labels:      strings.Join(selector, ","),
+			RuleKey:     rule.key(),
+			Rule:        rule.value,
+		}
+
+		for _, evidence := range candidates {
+			// kind:type:value
+			fields := strings.SplitN(evidence.RuleKey, ":", 3)
+			if len(fields) != 3 || fields[0] != rule.kind || !rule.covers(fields[1], fields[2]) {
+				continue
+			}
+			merged = libs.MergeRuleEvidence(merged, evidence)
+		}
+
+		evidences = append(evidences, merged)
+	}
+
+	return evidences, nil
+}
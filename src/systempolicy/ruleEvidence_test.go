@@ -0,0 +1,101 @@
+package systempolicy
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddSystemRuleEvidences(t *testing.T) {
+	pod := types.Pod{Namespace: "default", PodName: "nginx-1", Labels: []string{"pod-template-hash=5ff5974cd4", "app=nginx"}}
+
+	logs := []types.KnoxSystemLog{
+		{PodName: "nginx-1", Operation: SYS_OP_FILE, Source: "/usr/sbin/nginx", Resource: "/etc/nginx/nginx.conf", Timestamp: 120},
+		{PodName: "nginx-1", Operation: SYS_OP_FILE, Source: "/usr/sbin/nginx", Resource: "/etc/nginx/nginx.conf", Timestamp: 180},
+		{PodName: "nginx-1", Operation: SYS_OP_FILE, Source: "/usr/sbin/nginx", Resource: "/proc/1/status"},
+	}
+
+	policyTypes := SystemPolicyTypes
+	defer func() { SystemPolicyTypes = policyTypes }()
+	SystemPolicyTypes = SYS_OP_FILE_INT | SYS_OP_PROCESS_INT
+
+	evidences := map[string]types.RuleEvidence{}
+	addSystemRuleEvidences(evidences, "default", pod, logs, 300)
+	assert.Equal(t, 2, len(evidences))
+
+	for _, evidence := range evidences {
+		assert.Equal(t, "app=nginx,pod-template-hash=5ff5974cd4", evidence.Labels)
+		assert.Equal(t, []string{"nginx-1"}, evidence.SrcPods)
+
+		switch evidence.RuleKey {
+		case getSysRuleKey(SYS_OP_FILE, "path", "/etc/nginx/nginx.conf"):
+			assert.Equal(t, int64(2), evidence.Count)
+			// seen at the time of the logs
+			assert.Equal(t, int64(120), evidence.FirstSeen)
+			assert.Equal(t, int64(180), evidence.LastSeen)
+		case getSysRuleKey(SYS_OP_FILE, "dir", "/proc/"):
+			assert.Equal(t, int64(1), evidence.Count)
+			assert.Equal(t, int64(300), evidence.FirstSeen)
+		default:
+			t.Errorf("unexpected rule key %s", evidence.RuleKey)
+		}
+	}
+}
+
+func TestGetSystemPolicyRuleEvidences(t *testing.T) {
+	_, mock := libs.NewMock()
+	defer func() { libs.MockDB = nil }()
+
+	CfgDB = types.ConfigDB{DBDriver: "mysql"}
+	defer func() { CfgDB = types.ConfigDB{} }()
+
+	columns := []string{
+		"policy_type", "cluster_name", "namespace", "labels", "rule_key", "rule",
+		"first_seen", "last_seen", "count", "src_pods", "dst_pods", "sample_log",
+	}
+	mock.ExpectQuery("^SELECT (.+) FROM rule_evidence WHERE policy_type = \\? and cluster_name = \\? and namespace = \\?").
+		WithArgs("system", "default", "default").
+		WillReturnRows(mock.NewRows(columns).
+			AddRow("system", "default", "default", "app=nginx,pod-template-hash=5ff5974cd4",
+				getSysRuleKey(SYS_OP_FILE, "path", "/var/log/nginx/access.log"), "/var/log/nginx/access.log",
+				100, 200, 2, []byte(`["nginx-1"]`), nil, "a").
+			AddRow("system", "default", "default", "app=nginx,pod-template-hash=6a7b8c9d0e",
+				getSysRuleKey(SYS_OP_FILE, "path", "/var/log/nginx/error.log"), "/var/log/nginx/error.log",
+				150, 300, 1, []byte(`["nginx-2"]`), nil, "b").
+			AddRow("system", "default", "default", "app=redis",
+				getSysRuleKey(SYS_OP_FILE, "path", "/var/log/nginx/access.log"), "/var/log/nginx/access.log",
+				100, 400, 9, []byte(`["redis-1"]`), nil, "c"))
+
+	policy := types.KnoxSystemPolicy{
+		Metadata: map[string]string{"clusterName": "default", "namespace": "default"},
+		Spec: types.KnoxSystemSpec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "nginx"}},
+			File: types.KnoxSys{
+				MatchPaths:       []types.KnoxMatchPaths{{Path: "/etc/nginx/nginx.conf"}},
+				MatchDirectories: []types.KnoxMatchDirectories{{Dir: "/var/log/nginx/"}},
+			},
+		},
+	}
+
+	evidences, err := GetSystemPolicyRuleEvidences(policy)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(evidences))
+
+	// no log observed for the path
+	assert.Equal(t, "/etc/nginx/nginx.conf", evidences[0].Rule)
+	assert.Equal(t, int64(0), evidences[0].Count)
+
+	// the logs of the replicas under the directory, not of the other workload
+	assert.Equal(t, "/var/log/nginx/", evidences[1].Rule)
+	assert.Equal(t, int64(3), evidences[1].Count)
+	assert.Equal(t, int64(100), evidences[1].FirstSeen)
+	assert.Equal(t, int64(300), evidences[1].LastSeen)
+	assert.Equal(t, []string{"nginx-1", "nginx-2"}, evidences[1].SrcPods)
+	assert.Equal(t, "b", evidences[1].SampleLog)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -2,13 +2,11 @@ package systempolicy
 
 import (
 	"errors"
-	"hash/fnv"
 	"io/ioutil"
 	"os"
 	"reflect"
 	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +19,7 @@ import (
 	logger "github.com/accuknox/auto-policy-discovery/src/logging"
 	"github.com/accuknox/auto-policy-discovery/src/plugin"
 	wpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/worker"
+	"github.com/accuknox/auto-policy-discovery/src/telemetry"
 	types "github.com/accuknox/auto-policy-discovery/src/types"
 
 	"github.com/rs/zerolog"
@@ -289,6 +288,24 @@ func WriteSystemPoliciesToFile_Ext(namespace, clustername, labels, fromsource st
 		delete(pol.Metadata, "containername")
 		libs.WriteKubeArmorPolicyToYamlFile(fname, []types.KubeArmorPolicy{pol})
 	}
+
+	kubearmorNodePolicies := extractNodeSystemPolicies(namespace, clustername, labels, fromsource)
+	for _, pol := range kubearmorNodePolicies {
+		fname := "kubearmor_host_policies_" + pol.Metadata["clusterName"] + "_" + pol.Metadata["containername"] + "_" + pol.Metadata["name"]
+		delete(pol.Metadata, "clusterName")
+		delete(pol.Metadata, "containername")
+		delete(pol.Metadata, "namespace")
+		libs.WriteKubeArmorHostPolicyToYamlFile(fname, []types.KubeArmorPolicy{pol})
+	}
+
+	if cfg.GetCfgSystemDefaultDenyAudit() {
+		for _, pol := range plugin.GenerateDefaultDenyAuditPolicies(kubearmorK8SPolicies) {
+			fname := "kubearmor_policies_" + pol.Metadata["namespace"] + "_" + pol.Metadata["name"]
+			delete(pol.Metadata, "clusterName")
+			delete(pol.Metadata, "containername")
+			libs.WriteKubeArmorPolicyToYamlFile(fname, []types.KubeArmorPolicy{pol})
+		}
+	}
 }
 
 func WriteSystemPoliciesToFile(namespace, clustername, labels, fromsource string) {
@@ -298,12 +315,21 @@ func WriteSystemPoliciesToFile(namespace, clustername, labels, fromsource string
 		libs.WriteKubeArmorPolicyToYamlFile("kubearmor_policies", kubeArmorPolicies)
 	}
 	WriteSystemPoliciesToFile_Ext(namespace, clustername, labels, fromsource)
+
+	if report := GetNoisePathReport(); len(report) > 0 {
+		libs.WriteNoisePathReportToJsonFile(report)
+	}
 }
 
 func GetSysPolicy(namespace, clustername, labels, fromsource string) *wpb.WorkerResponse {
 
 	kubearmorK8SPolicies := extractK8SSystemPolicies(namespace, clustername, labels, fromsource)
 	kubearmorVMPolicies, _ := extractVMSystemPolicies(types.PolicyDiscoveryVMNamespace, clustername, labels, fromsource)
+	kubearmorNodePolicies := extractNodeSystemPolicies(namespace, clustername, labels, fromsource)
+
+	if cfg.GetCfgSystemDefaultDenyAudit() {
+		kubearmorK8SPolicies = append(kubearmorK8SPolicies, plugin.GenerateDefaultDenyAuditPolicies(kubearmorK8SPolicies)...)
+	}
 
 	var response wpb.WorkerResponse
 
@@ -339,8 +365,26 @@ func GetSysPolicy(namespace, clustername, labels, fromsource string) *wpb.Worker
 		response.Kubearmorpolicy = append(response.Kubearmorpolicy, &kubearmorpolicy)
 	}
 
+	// system policy for k8s node
+	for i := range kubearmorNodePolicies {
+		kubearmorpolicy := wpb.KubeArmorPolicy{}
+
+		delete(kubearmorNodePolicies[i].Metadata, "clusterName")
+		delete(kubearmorNodePolicies[i].Metadata, "containername")
+		delete(kubearmorNodePolicies[i].Metadata, "namespace")
+
+		val, err := json.Marshal(&kubearmorNodePolicies[i])
+		if err != nil {
+			log.Error().Msgf("kubearmorNodePolicy json marshal failed err=%v", err.Error())
+		}
+		kubearmorpolicy.Data = val
+
+		response.Kubearmorpolicy = append(response.Kubearmorpolicy, &kubearmorpolicy)
+	}
+
 	response.Res = "OK"
 	response.Ciliumpolicy = nil
+	response.Learningstate = GetLearningStates(namespace, clustername, labels)
 
 	return &response
 }
@@ -351,7 +395,25 @@ func extractK8SSystemPolicies(namespace, clustername, labels, fromsource string)
 
 	var result []types.KubeArmorPolicy
 	for _, pol := range policies {
-		if pol.Metadata["namespace"] != types.PolicyDiscoveryVMNamespace {
+		if pol.Metadata["namespace"] != types.PolicyDiscoveryVMNamespace && pol.Metadata["namespace"] != types.PolicyDiscoveryNodeNamespace {
+			result = append(result, pol)
+		}
+	}
+	return result
+}
+
+// extractNodeSystemPolicies returns the host policies of the k8s nodes, only for the node namespace or all the namespaces
+func extractNodeSystemPolicies(namespace, clustername, labels, fromsource string) []types.KubeArmorPolicy {
+	if namespace != "" && namespace != types.PolicyDiscoveryNodeNamespace {
+		return nil
+	}
+
+	sysPols := populateKnoxSysPolicyFromWPFSDb(types.PolicyDiscoveryNodeNamespace, clustername, labels, fromsource)
+	policies := plugin.ConvertKnoxSystemPolicyToKubeArmorPolicy(sysPols)
+
+	var result []types.KubeArmorPolicy
+	for _, pol := range policies {
+		if pol.Metadata["namespace"] == types.PolicyDiscoveryNodeNamespace {
 			result = append(result, pol)
 		}
 	}
@@ -414,11 +476,18 @@ func clusteringSystemLogsByNamespacePod(logs []types.KnoxSystemLog) map[SysLogKe
 	return results
 }
 
+// systemLogDeduplication keeps the duplicates of a log once, at the time it was last seen
 func systemLogDeduplication(logs []types.KnoxSystemLog) []types.KnoxSystemLog {
 	results := []types.KnoxSystemLog{}
+	indexes := map[types.KnoxSystemLog]int{}
 
 	for _, log := range logs {
-		if libs.ContainsElement(results, log) {
+		key := log
+		key.Timestamp = 0
+		if i, ok := indexes[key]; ok {
+			if log.Timestamp > results[i].Timestamp {
+				results[i].Timestamp = log.Timestamp
+			}
 			continue
 		}
 
@@ -432,6 +501,7 @@ func systemLogDeduplication(logs []types.KnoxSystemLog) []types.KnoxSystemLog {
 			continue
 		}
 
+		indexes[key] = len(results)
 		results = append(results, log)
 	}
 
@@ -720,16 +790,11 @@ func mergeFromSource(pols []types.KnoxSystemPolicy) []types.KnoxSystemPolicy {
 	return results
 }
 
-func hashInt(s string) uint32 {
-	h := fnv.New32a()
-	_, _ = h.Write([]byte(s))
-	return h.Sum32()
-}
-
 func mergeSysPolicies(pols []types.KnoxSystemPolicy) []types.KnoxSystemPolicy {
 	var results []types.KnoxSystemPolicy
 	for _, pol := range pols {
-		pol.Metadata["name"] = "autopol-system-" + strconv.FormatUint(uint64(hashInt(pol.Metadata["labels"]+pol.Metadata["namespace"]+pol.Metadata["clustername"]+pol.Metadata["containername"])), 10)
+		pol.Metadata["name"] = libs.GeneratePolicyName("system", pol.Metadata["namespace"],
+			libs.LabelStringToMap(pol.Metadata["labels"]), pol.Metadata["containername"])
 		i := checkIfMetadataMatches(pol, results)
 		if i < 0 {
 			results = append(results, pol)
@@ -984,15 +1049,47 @@ func updateSysPolicies() {
 	var isPolicyExist bool
 
 	wpfsPolicies := populateKnoxSysPolicyFromWPFSDb("", "", "", "")
+	learningStates := getLearningStateMap(types.WorkloadLearningState{})
 
 	for _, wpfsPolicy := range wpfsPolicies {
+		// hold back the policy until the workload behavior is stable
+		if isWorkloadLearning(learningStates, getPolicyWorkloadKey(wpfsPolicy)) {
+			continue
+		}
+
+		// skip the rules the policies in the cluster already allow
+		var ok bool
+		if wpfsPolicy, ok = removeClusterCoveredRules(wpfsPolicy, clusterSystemPolicies); !ok {
+			continue
+		}
+
 		isPolicyExist = false
 		sysPoliciesDb := libs.GetSystemPolicies(CfgDB, "", "")
 
 		for _, sysPolicyDb := range sysPoliciesDb {
 			if sysPolicyDb.Metadata["name"] == wpfsPolicy.Metadata["name"] {
-				libs.UpdateSystemPolicy(CfgDB, wpfsPolicy)
 				isPolicyExist = true
+
+				// a rejected policy stays rejected until the workload shows a new rule
+				if libs.GetReviewState(sysPolicyDb.Metadata) == libs.ReviewStateRejected {
+					if _, ok := removeRejectedRules(wpfsPolicy, []types.KnoxSystemPolicy{sysPolicyDb}); !ok {
+						break
+					}
+				}
+
+				// the changed rules are reviewed again
+				changed := !reflect.DeepEqual(sysPolicyDb.Spec, wpfsPolicy.Spec)
+				wpfsPolicy.Metadata["review_state"] = sysPolicyDb.Metadata["review_state"]
+				wpfsPolicy.RuleStats = sysPolicyDb.RuleStats
+				if changed {
+					wpfsPolicy.Metadata["review_state"] = libs.ReviewStateDiscovered
+				}
+
+				libs.UpdateSystemPolicy(CfgDB, wpfsPolicy)
+				if changed {
+					telemetry.PolicyEvent(telemetry.EventPolicyUpdated, telemetry.PolicyTypeSystem,
+						wpfsPolicy.Metadata["clusterName"], wpfsPolicy.Metadata["namespace"], wpfsPolicy.Metadata["name"])
+				}
 				break
 			}
 		}
@@ -1003,6 +1100,10 @@ func updateSysPolicies() {
 	}
 
 	libs.InsertSystemPolicies(CfgDB, locSysPolicies)
+	for _, policy := range locSysPolicies {
+		telemetry.PolicyEvent(telemetry.EventPolicyCreated, telemetry.PolicyTypeSystem,
+			policy.Metadata["clusterName"], policy.Metadata["namespace"], policy.Metadata["name"])
+	}
 }
 
 // ============================= //
@@ -1025,11 +1126,18 @@ func InitSysPolicyDiscoveryConfiguration() {
 
 	ProcessFromSource = cfg.GetCfgSystemProcFromSource()
 	FileFromSource = cfg.GetCfgSystemFileFromSource()
+
+	initLearningConfiguration()
+	initNoisePathConfiguration()
 }
 
 func PopulateSystemPoliciesFromSystemLogs(sysLogs []types.KnoxSystemLog) []types.KnoxSystemPolicy {
 
 	discoveredSystemPolicies := []types.KnoxSystemPolicy{}
+	startTime, totalLogs := time.Now(), len(sysLogs)
+
+	// the noise path report covers the paths collapsed in this run
+	resetNoisePathReport()
 
 	// delete duplicate logs
 	sysLogs = systemLogDeduplication(sysLogs)
@@ -1039,6 +1147,13 @@ func PopulateSystemPoliciesFromSystemLogs(sysLogs []types.KnoxSystemLog) []types
 
 	existingPolicies := libs.GetSystemPolicies(CfgDB, "", "")
 	log.Info().Msgf("len(tot-syslogs):%d len(existingPolicies):%d", len(sysLogs), len(existingPolicies))
+
+	// the policies already in the cluster seed the deduplication
+	clusterSystemPolicies = nil
+	if cfg.GetCfgSystemClusterPolicySeed() {
+		clusterSystemPolicies = GetClusterSystemPolicies()
+	}
+
 	for clusterName, sysLogs := range clusteredLogs {
 		// get existing system policies in db
 		log.Info().Msgf("system policy discovery cluster [%s] len(sysLogs):%d", clusterName, len(sysLogs))
@@ -1046,12 +1161,21 @@ func PopulateSystemPoliciesFromSystemLogs(sysLogs []types.KnoxSystemLog) []types
 		// get k8s pods
 		pods := cluster.GetPods(clusterName)
 
+		// merge the WPFS entries of old replicasets into their owning workloads
+		if mergeWorkloadProcessFileSets(clusterName, pods) && cfg.CurrentCfg.ConfigSysPolicy.DeprecateOldMode {
+			updateSysPolicies()
+		}
+
 		// filter system logs from configuration
 		cfgFilteredLogs := FilterSystemLogsByConfig(sysLogs, pods)
 
 		// iterate sys log key := [namespace + pod_name]
 		nsPodLogs := clusteringSystemLogsByNamespacePod(cfgFilteredLogs)
 
+		// the logs observed for the discovered rules
+		evidences := map[string]types.RuleEvidence{}
+		seenTime := libs.ConvertStrToUnixTime("now")
+
 		for sysKey, perPodlogs := range nsPodLogs {
 			discoveredSysPolicies := []types.KnoxSystemPolicy{}
 
@@ -1061,6 +1185,8 @@ func PopulateSystemPoliciesFromSystemLogs(sysLogs []types.KnoxSystemLog) []types
 				continue
 			}
 
+			addSystemRuleEvidences(evidences, clusterName, pod, perPodlogs, seenTime)
+
 			polCnt := 0
 			isWpfsDbUpdated := false
 			// 1. discover file operation system policy
@@ -1106,7 +1232,10 @@ func PopulateSystemPoliciesFromSystemLogs(sysLogs []types.KnoxSystemLog) []types
 				discoveredSysPolicies = updateSysPolicySelector(clusterName, pod, discoveredSysPolicies)
 				discoveredSystemPolicies = append(discoveredSystemPolicies, discoveredSysPolicies...)
 
-				// 4. update duplicated policy
+				// 4. skip the rules the policies in the cluster already allow
+				discoveredSysPolicies = RemoveClusterCoveredPolicies(discoveredSysPolicies, clusterSystemPolicies)
+
+				// 5. update duplicated policy
 				newPolicies := UpdateDuplicatedPolicy(existingPolicies, discoveredSysPolicies, clusterName)
 
 				if len(newPolicies) > 0 {
@@ -1115,6 +1244,10 @@ func PopulateSystemPoliciesFromSystemLogs(sysLogs []types.KnoxSystemLog) []types
 						libs.InsertSystemPolicies(CfgDB, newPolicies)
 					}
 
+					for _, policy := range newPolicies {
+						telemetry.PolicyEvent(telemetry.EventPolicyCreated, telemetry.PolicyTypeSystem, clusterName, pod.Namespace, policy.Metadata["name"])
+					}
+
 					log.Info().Msgf("system policy discovery done for [%s/%s/%s], [%d] policies discovered",
 						clusterName, pod.Namespace, pod.PodName, len(newPolicies))
 				}
@@ -1124,15 +1257,29 @@ func PopulateSystemPoliciesFromSystemLogs(sysLogs []types.KnoxSystemLog) []types
 				WriteSystemPoliciesToFile(sysKey.Namespace, "", "", "")
 			}
 		}
+
+		// store the evidences of the discovered rules
+		if strings.Contains(SystemPolicyTo, "db") {
+			storeRuleEvidences(evidences)
+		}
+
+		// propose the policies without the rules not observed for the aging period
+		AgeSystemPolicyRules(clusterName)
 	}
 
+	telemetry.WorkerRunEvent(telemetry.PolicyTypeSystem, totalLogs, len(discoveredSystemPolicies), time.Since(startTime))
+
 	return discoveredSystemPolicies
 }
 
+// GetPodLabels returns the labels of the workload owning the pod, so that replicas share the WPFS key
 func GetPodLabels(cn string, pn string, ns string, pods []types.Pod) ([]string, error) {
 	for _, pod := range pods {
 		if pod.Namespace == ns && pod.PodName == pn {
-			return pod.Labels, nil
+			if len(pod.WorkloadLabels) > 0 {
+				return pod.WorkloadLabels, nil
+			}
+			return cluster.NormalizeWorkloadLabels(pod.Namespace, pod.Labels), nil
 		}
 	}
 	return nil, errors.New("pod not found")
@@ -1244,7 +1391,6 @@ func removeDuplicates(arr []string) []string {
 
 // GenFileSetForAllPodsInCluster Generate process specific fileset across all pods in a cluster
 func GenFileSetForAllPodsInCluster(clusterName string, pods []types.Pod, settype string, slogs []types.KnoxSystemLog) bool {
-	cfgDB := types.ConfigDB{}
 	res := types.ResourceSetMap{} // key: WorkloadProcess - val: Accesss File Set
 	wpfs := types.WorkloadProcessFileSet{}
 	isNetworkOp := false
@@ -1274,7 +1420,7 @@ func GenFileSetForAllPodsInCluster(clusterName string, pods []types.Pod, settype
 		if isNetworkOp {
 			resource = cleanResource(settype, slog.ResourceOrigin)
 		} else {
-			resource = cleanResource(settype, slog.Resource)
+			resource = suppressNoisePaths(slog.Namespace, cleanResource(settype, slog.Resource))
 		}
 		if len(resource) == 0 {
 			continue
@@ -1296,24 +1442,21 @@ func GenFileSetForAllPodsInCluster(clusterName string, pods []types.Pod, settype
 		mergedfs = removeDuplicates(append(fs, out[wpfs]...))
 		if !isNetworkOp {
 			// Path aggregation makes sense for file, process operations only
-			mergedfs = AggregatePathsExt(mergedfs) // merge and sort the filesets
+			mergedfs = AggregateNamespacePathsExt(wpfs.Namespace, mergedfs) // merge and sort the filesets
 		}
 
 		// Add/Update DB Entry
 		if !dbEntry {
 			log.Info().Msgf("adding wpfs db entry for wpfs=%+v", wpfs)
 			err = libs.InsertWorkloadProcessFileSet(CfgDB, wpfs, mergedfs)
+			markWorkloadChanged(wpfs)
 			status = true
 		} else {
 			if !reflect.DeepEqual(mergedfs, out[wpfs]) {
 				log.Info().Msgf("updating wpfs db entry for wpfs=%+v", wpfs)
-				if cfgDB.DBDriver == "mysql" {
-					err = libs.UpdateWorkloadProcessFileSetMySQL(CfgDB, wpfs, mergedfs)
-					status = true
-				} else if cfgDB.DBDriver == "sqlite3" {
-					err = libs.UpdateWorkloadProcessFileSetSQLite(CfgDB, wpfs, mergedfs)
-					status = true
-				}
+				err = libs.UpdateWorkloadProcessFileSet(CfgDB, wpfs, mergedfs)
+				markWorkloadChanged(wpfs)
+				status = true
 			}
 		}
 		if err != nil {
@@ -1339,11 +1482,14 @@ func DiscoverSystemPolicyMain() {
 
 	// get system logs
 	allSystemkLogs := getSystemLogs()
-	if allSystemkLogs == nil {
-		return
+	if allSystemkLogs != nil {
+		PopulateSystemPoliciesFromSystemLogs(allSystemkLogs)
 	}
 
-	PopulateSystemPoliciesFromSystemLogs(allSystemkLogs)
+	// advance the learning state of the workloads, once per interval
+	if updateLearningStates() && cfg.CurrentCfg.ConfigSysPolicy.DeprecateOldMode {
+		updateSysPolicies()
+	}
 }
 
 // ==================================== //
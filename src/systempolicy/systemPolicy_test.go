@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/accuknox/auto-policy-discovery/src/libs"
 	types "github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -171,3 +172,37 @@ func TestMergeSysPoliciesProcess(t *testing.T) {
 	assert.Equal(t, res.Spec.Process.MatchDirectories[1].FromSource[1].Path, "/bin/stash")
 
 }
+
+func TestExtractNodeSystemPoliciesNamespace(t *testing.T) {
+	// the host policies are not returned for the other namespaces
+	assert.Empty(t, extractNodeSystemPolicies("default", "default", "", ""))
+}
+
+func TestExtractNodeSystemPolicies(t *testing.T) {
+	_, mock := libs.NewMock()
+	defer func() { libs.MockDB = nil }()
+
+	CfgDB = types.ConfigDB{DBDriver: "mysql"}
+	defer func() { CfgDB = types.ConfigDB{} }()
+
+	columns := []string{"policyName", "clusterName", "namespace", "containerName", "labels", "fromSource", "settype", "fileset"}
+	mock.ExpectQuery("^SELECT (.+) FROM workload_process_fileset WHERE namespace = \\?").
+		WithArgs(types.PolicyDiscoveryNodeNamespace).
+		WillReturnRows(mock.NewRows(columns).
+			AddRow("autopol-system-node-1", "default", types.PolicyDiscoveryNodeNamespace, "node-1",
+				"kubernetes.io/hostname=node-1", "", SYS_OP_PROCESS, "/usr/bin/kubelet").
+			AddRow("autopol-system-node-1", "default", types.PolicyDiscoveryNodeNamespace, "node-1",
+				"kubernetes.io/hostname=node-1", "", SYS_OP_FILE, "/etc/kubernetes/kubelet.conf"+types.RecordSeparator+"/var/lib/kubelet/"))
+
+	policies := extractNodeSystemPolicies(types.PolicyDiscoveryNodeNamespace, "", "", "")
+	assert.Equal(t, 1, len(policies))
+
+	policy := policies[0]
+	assert.Equal(t, "KubeArmorHostPolicy", policy.Kind)
+	assert.True(t, strings.HasPrefix(policy.Metadata["name"], "autopol-system-"+types.PolicyDiscoveryNodeNamespace+"-node-1-"))
+	assert.Equal(t, map[string]string{"kubernetes.io/hostname": "node-1"}, policy.Spec.NodeSelector.MatchLabels)
+	assert.Empty(t, policy.Spec.Selector.MatchLabels)
+	assert.Equal(t, []types.KnoxMatchPaths{{Path: "/usr/bin/kubelet"}}, policy.Spec.Process.MatchPaths)
+	assert.Equal(t, []types.KnoxMatchPaths{{Path: "/etc/kubernetes/kubelet.conf"}}, policy.Spec.File.MatchPaths)
+	assert.Equal(t, []types.KnoxMatchDirectories{{Dir: "/var/lib/kubelet/", Recursive: true}}, policy.Spec.File.MatchDirectories)
+}
@@ -0,0 +1,130 @@
+package systempolicy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/cluster"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/telemetry"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// ============================== //
+// == Workload Label Merge     == //
+// ============================== //
+
+// getWorkloadOwnedLabels returns the labels of the owning workloads by the namespace and the pod labels, from the
+// pods owned by a workload and the pod templates of the workloads, a pod without an owner is not mapped
+func getWorkloadOwnedLabels(pods []types.Pod, templates map[string][]string) map[string][]string {
+	results := map[string][]string{}
+	for key, labels := range templates {
+		results[key] = labels
+	}
+
+	for _, pod := range pods {
+		if pod.WorkloadName == "" || len(pod.WorkloadLabels) == 0 {
+			continue
+		}
+
+		labels := cluster.NormalizeWorkloadLabels(pod.Namespace, pod.Labels)
+		results[pod.Namespace+"/"+strings.Join(labels, ",")] = pod.WorkloadLabels
+	}
+
+	return results
+}
+
+// getNormalizedWPFSLabels maps the labels of a WPFS entry to the labels of its owning workload
+func getNormalizedWPFSLabels(wpfs types.WorkloadProcessFileSet, owned map[string][]string) string {
+	if wpfs.Labels == "" {
+		return wpfs.Labels
+	}
+
+	labels := cluster.NormalizeWorkloadLabels(wpfs.Namespace, strings.Split(wpfs.Labels, ","))
+
+	if workloadLabels, ok := owned[wpfs.Namespace+"/"+strings.Join(labels, ",")]; ok {
+		labels = append([]string{}, workloadLabels...)
+		sort.Strings(labels)
+	}
+
+	return strings.Join(labels, ",")
+}
+
+// mergeWorkloadProcessFileSets merges the WPFS entries of old replicasets and replicas with
+// controller generated labels into the entry of the owning workload, and returns true if merged
+func mergeWorkloadProcessFileSets(clusterName string, pods []types.Pod) bool {
+	res, pnMap, err := libs.GetWorkloadProcessFileSet(CfgDB, types.WorkloadProcessFileSet{ClusterName: clusterName})
+	if err != nil {
+		log.Error().Msgf("could not fetch WPFS err=%s", err.Error())
+		return false
+	}
+
+	owned := getWorkloadOwnedLabels(pods, cluster.GetWorkloadTemplateLabels())
+
+	targets := types.ResourceSetMap{}                                           // key: merged WPFS - val: file set
+	outdated := map[types.WorkloadProcessFileSet]types.WorkloadProcessFileSet{} // key: old WPFS - val: merged WPFS
+
+	for wpfs, fs := range res {
+		if wpfs.Namespace == types.PolicyDiscoveryVMNamespace || wpfs.Namespace == types.PolicyDiscoveryNodeNamespace {
+			continue
+		}
+
+		labels := getNormalizedWPFSLabels(wpfs, owned)
+		if labels == wpfs.Labels {
+			continue
+		}
+
+		target := wpfs
+		target.Labels = labels
+		if _, ok := targets[target]; !ok {
+			targets[target] = append([]string{}, res[target]...)
+		}
+		targets[target] = append(targets[target], fs...)
+		outdated[wpfs] = target
+	}
+
+	if len(targets) == 0 {
+		return false
+	}
+
+	for target, fs := range targets {
+		mergedfs := removeDuplicates(fs)
+		if target.SetType != SYS_OP_NETWORK {
+			mergedfs = AggregateNamespacePathsExt(target.Namespace, mergedfs)
+		}
+
+		if _, exist := res[target]; exist {
+			err = libs.UpdateWorkloadProcessFileSet(CfgDB, target, mergedfs)
+		} else {
+			err = libs.InsertWorkloadProcessFileSet(CfgDB, target, mergedfs)
+		}
+		if err != nil {
+			log.Error().Msgf("failure merging wpfs=%+v err=%s", target, err.Error())
+			continue
+		}
+		markWorkloadChanged(target)
+	}
+
+	_, mergedPnMap, err := libs.GetWorkloadProcessFileSet(CfgDB, types.WorkloadProcessFileSet{ClusterName: clusterName})
+	if err != nil {
+		log.Error().Msgf("could not fetch WPFS err=%s", err.Error())
+		return true
+	}
+
+	for old, target := range outdated {
+		log.Info().Msgf("merged wpfs labels [%s] into [%s]", old.Labels, target.Labels)
+
+		if pnMap[old] != "" && mergedPnMap[target] != "" {
+			libs.UpdateOutdatedSystemPolicy(CfgDB, pnMap[old], mergedPnMap[target])
+			telemetry.PolicyEvent(telemetry.EventPolicyOutdated, telemetry.PolicyTypeSystem, clusterName, old.Namespace, pnMap[old])
+		}
+
+		oldKey := old
+		oldKey.SetType = ""
+		if err := libs.ClearWPFSDb(CfgDB, oldKey, 0); err != nil {
+			log.Error().Msgf("failure clearing wpfs=%+v err=%s", old, err.Error())
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,42 @@
+package systempolicy
+
+import (
+	"testing"
+
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNormalizedWPFSLabels(t *testing.T) {
+	pods := []types.Pod{
+		{Namespace: "default", PodName: "cart-1", Labels: []string{"app=cart", "version=v2"},
+			WorkloadKind: "Deployment", WorkloadName: "cart", WorkloadLabels: []string{"app=cart"}},
+		{Namespace: "default", PodName: "web-1", Labels: []string{"app=web", "tier=front"},
+			WorkloadKind: "Deployment", WorkloadName: "web", WorkloadLabels: []string{"app=web", "tier=front"}},
+		// a bare pod is not owned by the deployment its labels include
+		{Namespace: "default", PodName: "cart-debug", Labels: []string{"app=cart", "debug=true"}},
+	}
+
+	// the pod template of the replicaset of the previous rollout
+	templates := map[string][]string{"default/app=cart,version=v1": {"app=cart"}}
+
+	owned := getWorkloadOwnedLabels(pods, templates)
+	assert.Equal(t, 3, len(owned))
+
+	// old replicaset entry
+	wpfs := types.WorkloadProcessFileSet{Namespace: "default", Labels: "app=cart,pod-template-hash=5d8f7c,version=v1"}
+	assert.Equal(t, "app=cart", getNormalizedWPFSLabels(wpfs, owned))
+
+	wpfs = types.WorkloadProcessFileSet{Namespace: "default", Labels: "app=cart,pod-template-hash=7c9e1a,version=v2"}
+	assert.Equal(t, "app=cart", getNormalizedWPFSLabels(wpfs, owned))
+
+	wpfs = types.WorkloadProcessFileSet{Namespace: "default", Labels: "app=cart,debug=true"}
+	assert.Equal(t, "app=cart,debug=true", getNormalizedWPFSLabels(wpfs, owned))
+
+	// no owning workload, only the controller labels are dropped
+	wpfs = types.WorkloadProcessFileSet{Namespace: "prod", Labels: "app=cart,controller-revision-hash=abc"}
+	assert.Equal(t, "app=cart", getNormalizedWPFSLabels(wpfs, owned))
+
+	wpfs = types.WorkloadProcessFileSet{Namespace: "default", Labels: "app=web,tier=front"}
+	assert.Equal(t, "app=web,tier=front", getNormalizedWPFSLabels(wpfs, owned))
+}
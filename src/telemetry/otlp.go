@@ -0,0 +1,111 @@
+package telemetry
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// OTLP/HTTP paths of the collector
+const (
+	OTLPLogsPath    = "/v1/logs"
+	OTLPMetricsPath = "/v1/metrics"
+)
+
+// ======================== //
+// == OTLP Proto Messages == //
+// ======================== //
+
+func stringValue(s string) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}}
+}
+
+func unixNano(t time.Time) uint64 {
+	return uint64(t.UnixNano())
+}
+
+// =================== //
+// == OTLP Exporter == //
+// =================== //
+
+// otlpExporter posts the logs and metrics to an OTLP/HTTP collector, in the JSON encoding of the export requests
+type otlpExporter struct {
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+	resource *resourcepb.Resource
+	scope    *commonpb.InstrumentationScope
+}
+
+func newOTLPExporter(telemetryCfg types.ConfigTelemetry) *otlpExporter {
+	return &otlpExporter{
+		endpoint: strings.TrimSuffix(telemetryCfg.Endpoint, "/"),
+		headers:  telemetryCfg.Headers,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+			{Key: "service.name", Value: stringValue(telemetryCfg.ServiceName)},
+		}},
+		scope: &commonpb.InstrumentationScope{Name: ScopeName},
+	}
+}
+
+func (e *otlpExporter) post(path string, body proto.Message) error {
+	data, err := protojson.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("otlp collector " + path + " responded " + resp.Status)
+	}
+	return nil
+}
+
+// exportLogs posts the log records, the logs data message is encoded as the export logs service request
+func (e *otlpExporter) exportLogs(records []*logspb.LogRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	return e.post(OTLPLogsPath, &logspb.LogsData{ResourceLogs: []*logspb.ResourceLogs{{
+		Resource:  e.resource,
+		ScopeLogs: []*logspb.ScopeLogs{{Scope: e.scope, LogRecords: records}},
+	}}})
+}
+
+// exportMetrics posts the metrics, the metrics data message is encoded as the export metrics service request
+func (e *otlpExporter) exportMetrics(metrics []*metricspb.Metric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	return e.post(OTLPMetricsPath, &metricspb.MetricsData{ResourceMetrics: []*metricspb.ResourceMetrics{{
+		Resource:     e.resource,
+		ScopeMetrics: []*metricspb.ScopeMetrics{{Scope: e.scope, Metrics: metrics}},
+	}}})
+}
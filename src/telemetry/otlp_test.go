@@ -0,0 +1,143 @@
+package telemetry
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// otlpReceiver an in-process OTLP/HTTP collector keeping the received requests, decoded by the proto messages
+type otlpReceiver struct {
+	mutex   sync.Mutex
+	logs    []*logspb.LogsData
+	metrics []*metricspb.MetricsData
+	headers http.Header
+}
+
+func (r *otlpReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.headers = req.Header
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch req.URL.Path {
+	case OTLPLogsPath:
+		body := &logspb.LogsData{}
+		if err := protojson.Unmarshal(data, body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.logs = append(r.logs, body)
+	case OTLPMetricsPath:
+		body := &metricspb.MetricsData{}
+		if err := protojson.Unmarshal(data, body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.metrics = append(r.metrics, body)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func getAttribute(attributes []*commonpb.KeyValue, key string) string {
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return kv.Value.GetStringValue()
+		}
+	}
+	return ""
+}
+
+func TestExportTelemetry(t *testing.T) {
+	receiver := &otlpReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	Exporter = newOTLPExporter(types.ConfigTelemetry{
+		Endpoint:    server.URL + "/",
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+		ServiceName: "knoxautopolicy",
+	})
+	defer func() {
+		Exporter, LogRecords, Metrics = nil, nil, map[string]*metricPoint{}
+	}()
+
+	PolicyEvent(EventPolicyCreated, PolicyTypeNetwork, "default", "wordpress-mysql", "autopol-ingress-abc")
+	PolicyEvent(EventPolicyCreated, PolicyTypeNetwork, "default", "wordpress-mysql", "autopol-egress-def")
+	WorkerRunEvent(PolicyTypeNetwork, 100, 2, 1500*time.Millisecond)
+	ObservabilitySummary("system", "wordpress-mysql", 10, 3)
+	ExportTelemetry()
+
+	assert.Len(t, receiver.logs, 1)
+	assert.Len(t, receiver.metrics, 1)
+	assert.Equal(t, "Bearer token", receiver.headers.Get("Authorization"))
+
+	resourceLogs := receiver.logs[0].ResourceLogs[0]
+	assert.Equal(t, "knoxautopolicy", getAttribute(resourceLogs.Resource.Attributes, "service.name"))
+	records := resourceLogs.ScopeLogs[0].LogRecords
+	assert.Len(t, records, 4)
+	assert.Equal(t, EventPolicyCreated, getAttribute(records[0].Attributes, "event"))
+	assert.Equal(t, "autopol-ingress-abc", getAttribute(records[0].Attributes, "policy.name"))
+	assert.Equal(t, EventWorkerRun, getAttribute(records[2].Attributes, "event"))
+	assert.Equal(t, EventObservabilitySummary, getAttribute(records[3].Attributes, "event"))
+
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_INFO, records[0].SeverityNumber)
+	assert.NotZero(t, records[0].TimeUnixNano)
+
+	metrics := map[string]*metricspb.Metric{}
+	for _, metric := range receiver.metrics[0].ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		metrics[metric.Name] = metric
+	}
+	assert.Equal(t, int64(2), metrics[MetricPolicies].GetSum().DataPoints[0].GetAsInt())
+	assert.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, metrics[MetricPolicies].GetSum().AggregationTemporality)
+	assert.Equal(t, int64(1), metrics[MetricWorkerRuns].GetSum().DataPoints[0].GetAsInt())
+	assert.Equal(t, 1.5, metrics[MetricWorkerDuration].GetGauge().DataPoints[0].GetAsDouble())
+	assert.Equal(t, int64(3), metrics[MetricObservabilityNew].GetSum().DataPoints[0].GetAsInt())
+
+	// the log records are sent once, the counters are cumulative
+	PolicyEvent(EventPolicyOutdated, PolicyTypeNetwork, "default", "wordpress-mysql", "autopol-ingress-abc")
+	ExportTelemetry()
+
+	assert.Len(t, receiver.logs[1].ResourceLogs[0].ScopeLogs[0].LogRecords, 1)
+	for _, metric := range receiver.metrics[1].ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		if metric.Name == MetricWorkerRuns {
+			assert.Equal(t, int64(1), metric.GetSum().DataPoints[0].GetAsInt())
+		}
+	}
+}
+
+func TestEvictStaleMetrics(t *testing.T) {
+	now := time.Now()
+	Metrics = map[string]*metricPoint{
+		"live":  {name: MetricObservabilityLogs, time: now.Add(-time.Minute)},
+		"stale": {name: MetricObservabilityLogs, time: now.Add(-2 * MetricStaleInterval)},
+	}
+	defer func() { Metrics = map[string]*metricPoint{} }()
+
+	evictStaleMetrics(now)
+	assert.Len(t, Metrics, 1)
+	assert.Contains(t, Metrics, "live")
+}
+
+func TestTelemetryDisabled(t *testing.T) {
+	PolicyEvent(EventPolicyCreated, PolicyTypeSystem, "default", "default", "autopol-system-abc")
+
+	assert.Empty(t, LogRecords)
+	assert.Empty(t, Metrics)
+}
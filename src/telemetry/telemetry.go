@@ -0,0 +1,327 @@
+package telemetry
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	logger "github.com/accuknox/auto-policy-discovery/src/logging"
+	"github.com/robfig/cron"
+	"github.com/rs/zerolog"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+var log *zerolog.Logger
+
+func init() {
+	log = logger.GetInstance()
+	TelemetryMutex = &sync.Mutex{}
+	Metrics = map[string]*metricPoint{}
+}
+
+// ScopeName instrumentation scope of the exported logs and metrics
+const ScopeName = "github.com/accuknox/auto-policy-discovery"
+
+// MaxLogRecords max number of log records buffered between exports, the oldest are dropped
+const MaxLogRecords = 10000
+
+// MetricStaleInterval the series not updated for the interval are dropped, e.g. of a deleted namespace,
+// a later update starts the series again
+const MetricStaleInterval = time.Hour
+
+// policy types of the events
+const (
+	PolicyTypeNetwork = "network"
+	PolicyTypeSystem  = "system"
+)
+
+// event names
+const (
+	EventPolicyCreated        = "policy.created"
+	EventPolicyUpdated        = "policy.updated"
+	EventPolicyOutdated       = "policy.outdated"
+	EventPolicyReviewed       = "policy.reviewed"
+	EventWorkerRun            = "worker.run"
+	EventObservabilitySummary = "observability.summary"
+)
+
+// metric names
+const (
+	MetricPolicies          = "knoxautopolicy.policies"
+	MetricWorkerRuns        = "knoxautopolicy.worker.runs"
+	MetricWorkerDuration    = "knoxautopolicy.worker.duration"
+	MetricObservabilityLogs = "knoxautopolicy.observability.logs"
+	MetricObservabilityNew  = "knoxautopolicy.observability.new_logs"
+)
+
+// OTLP severity numbers
+const (
+	SeverityInfo = logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	SeverityWarn = logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+)
+
+// metricPoint a cumulative counter or a gauge of a metric and attribute set
+type metricPoint struct {
+	name        string
+	description string
+	unit        string
+	gauge       bool
+	attributes  map[string]string
+	count       int64
+	value       float64
+	start       time.Time
+	time        time.Time
+}
+
+var (
+	Exporter         *otlpExporter
+	TelemetryCronJob *cron.Cron
+
+	TelemetryMutex *sync.Mutex
+	LogRecords     []*logspb.LogRecord
+	// key: metric name + attributes
+	Metrics map[string]*metricPoint
+)
+
+// InitTelemetry starts exporting the buffered events and metrics to the OTLP collector
+func InitTelemetry() {
+	telemetryCfg := config.GetCfgTelemetry()
+	if !telemetryCfg.Enable || Exporter != nil {
+		return
+	}
+
+	Exporter = newOTLPExporter(telemetryCfg)
+
+	TelemetryCronJob = cron.New()
+	if err := TelemetryCronJob.AddFunc(telemetryCfg.ExportTimeInterval, ExportTelemetry); err != nil {
+		log.Error().Msg(err.Error())
+		Exporter = nil
+		return
+	}
+	TelemetryCronJob.Start()
+	log.Info().Msgf("OTLP telemetry export to %s started", telemetryCfg.Endpoint)
+}
+
+func isEnabled() bool {
+	return Exporter != nil
+}
+
+// ==================== //
+// == Event Recorder == //
+// ==================== //
+
+func toKeyValues(attributes map[string]string) []*commonpb.KeyValue {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	results := []*commonpb.KeyValue{}
+	for _, k := range keys {
+		results = append(results, &commonpb.KeyValue{Key: k, Value: stringValue(attributes[k])})
+	}
+	return results
+}
+
+func recordLog(severity logspb.SeverityNumber, body string, attributes map[string]string) {
+	now := time.Now()
+	severityText := "INFO"
+	if severity == SeverityWarn {
+		severityText = "WARN"
+	}
+
+	TelemetryMutex.Lock()
+	defer TelemetryMutex.Unlock()
+
+	if len(LogRecords) >= MaxLogRecords {
+		LogRecords = LogRecords[1:]
+	}
+	LogRecords = append(LogRecords, &logspb.LogRecord{
+		TimeUnixNano:         unixNano(now),
+		ObservedTimeUnixNano: unixNano(now),
+		SeverityNumber:       severity,
+		SeverityText:         severityText,
+		Body:                 stringValue(body),
+		Attributes:           toKeyValues(attributes),
+	})
+}
+
+func getMetricKey(name string, attributes map[string]string) string {
+	key := []string{name}
+	for _, kv := range toKeyValues(attributes) {
+		key = append(key, kv.Key+"="+kv.Value.GetStringValue())
+	}
+	return strings.Join(key, ",")
+}
+
+func getMetricPoint(name, description, unit string, gauge bool, attributes map[string]string) *metricPoint {
+	key := getMetricKey(name, attributes)
+	now := time.Now()
+
+	point, ok := Metrics[key]
+	if !ok {
+		point = &metricPoint{name: name, description: description, unit: unit, gauge: gauge, attributes: attributes, start: now}
+		Metrics[key] = point
+	}
+	point.time = now
+	return point
+}
+
+func addCounter(name, description string, attributes map[string]string, count int64) {
+	TelemetryMutex.Lock()
+	defer TelemetryMutex.Unlock()
+
+	getMetricPoint(name, description, "1", false, attributes).count += count
+}
+
+func setGauge(name, description, unit string, attributes map[string]string, value float64) {
+	TelemetryMutex.Lock()
+	defer TelemetryMutex.Unlock()
+
+	getMetricPoint(name, description, unit, true, attributes).value = value
+}
+
+// PolicyEvent records a created, updated or outdated network/system policy
+func PolicyEvent(event, policyType, clusterName, namespace, name string) {
+	if !isEnabled() {
+		return
+	}
+
+	recordLog(SeverityInfo, policyType+" "+event+" "+name, map[string]string{
+		"event":        event,
+		"policy.type":  policyType,
+		"policy.name":  name,
+		"cluster.name": clusterName,
+		"namespace":    namespace,
+	})
+	addCounter(MetricPolicies, "number of the discovered policy events", map[string]string{
+		"event":       event,
+		"policy.type": policyType,
+	}, 1)
+}
+
+// WorkerRunEvent records a run of the network/system policy discovery worker
+func WorkerRunEvent(policyType string, logs, policies int, duration time.Duration) {
+	if !isEnabled() {
+		return
+	}
+
+	recordLog(SeverityInfo, policyType+" policy discovery run", map[string]string{
+		"event":       EventWorkerRun,
+		"policy.type": policyType,
+		"logs":        strconv.Itoa(logs),
+		"policies":    strconv.Itoa(policies),
+		"duration_ms": strconv.FormatInt(duration.Milliseconds(), 10),
+	})
+	addCounter(MetricWorkerRuns, "number of the policy discovery worker runs", map[string]string{
+		"policy.type": policyType,
+	}, 1)
+	setGauge(MetricWorkerDuration, "duration of the last policy discovery worker run", "s", map[string]string{
+		"policy.type": policyType,
+	}, duration.Seconds())
+}
+
+// ObservabilitySummary records the system/network logs observed in a namespace, and the ones seen for the first time
+func ObservabilitySummary(logType, namespace string, logs, newLogs int) {
+	if !isEnabled() || logs == 0 {
+		return
+	}
+
+	recordLog(SeverityInfo, logType+" observability summary of "+namespace, map[string]string{
+		"event":     EventObservabilitySummary,
+		"log.type":  logType,
+		"namespace": namespace,
+		"logs":      strconv.Itoa(logs),
+		"new_logs":  strconv.Itoa(newLogs),
+	})
+	attributes := map[string]string{"log.type": logType, "namespace": namespace}
+	addCounter(MetricObservabilityLogs, "number of the observed logs", attributes, int64(logs))
+	addCounter(MetricObservabilityNew, "number of the logs seen for the first time", attributes, int64(newLogs))
+}
+
+// ====================== //
+// == Telemetry Export == //
+// ====================== //
+
+// evictStaleMetrics drops the series not updated for the stale interval
+func evictStaleMetrics(now time.Time) {
+	for key, point := range Metrics {
+		if now.Sub(point.time) > MetricStaleInterval {
+			delete(Metrics, key)
+		}
+	}
+}
+
+// getMetrics returns the current value of the counters and gauges
+func getMetrics(now time.Time) []*metricspb.Metric {
+	keys := make([]string, 0, len(Metrics))
+	for key := range Metrics {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	results := []*metricspb.Metric{}
+	indices := map[string]int{}
+	for _, key := range keys {
+		point := Metrics[key]
+
+		idx, ok := indices[point.name]
+		if !ok {
+			metric := &metricspb.Metric{Name: point.name, Description: point.description, Unit: point.unit}
+			if point.gauge {
+				metric.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{}}
+			} else {
+				metric.Data = &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+					AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+					IsMonotonic:            true,
+				}}
+			}
+			idx = len(results)
+			indices[point.name] = idx
+			results = append(results, metric)
+		}
+
+		dataPoint := &metricspb.NumberDataPoint{
+			Attributes:   toKeyValues(point.attributes),
+			TimeUnixNano: unixNano(now),
+		}
+		if point.gauge {
+			dataPoint.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: point.value}
+			results[idx].GetGauge().DataPoints = append(results[idx].GetGauge().DataPoints, dataPoint)
+		} else {
+			dataPoint.Value = &metricspb.NumberDataPoint_AsInt{AsInt: point.count}
+			dataPoint.StartTimeUnixNano = unixNano(point.start)
+			results[idx].GetSum().DataPoints = append(results[idx].GetSum().DataPoints, dataPoint)
+		}
+	}
+
+	return results
+}
+
+// ExportTelemetry sends the buffered log records and the metrics to the collector
+func ExportTelemetry() {
+	if !isEnabled() {
+		return
+	}
+
+	TelemetryMutex.Lock()
+	now := time.Now()
+	records := LogRecords
+	LogRecords = nil
+	evictStaleMetrics(now)
+	metrics := getMetrics(now)
+	TelemetryMutex.Unlock()
+
+	if err := Exporter.exportLogs(records); err != nil {
+		log.Error().Msgf("failed exporting %d otlp log records err=%s", len(records), err.Error())
+	}
+	if err := Exporter.exportMetrics(metrics); err != nil {
+		log.Error().Msgf("failed exporting otlp metrics err=%s", err.Error())
+	}
+}
@@ -8,6 +8,11 @@ type ConfigDB struct {
 	DBPass       string `json:"db_pass,omitempty" bson:"db_pass,omitempty"`
 	DBName       string `json:"db_name,omitempty" bson:"db_name,omitempty"`
 	SQLiteDBPath string `json:"sqlite_db_path,omitempty" bson:"sqlite_db_path,omitempty"`
+
+	// pool settings of the long-lived handles, 0 keeps the driver default
+	MaxOpenConns    int    `json:"max_open_conns,omitempty" bson:"max_open_conns,omitempty"`
+	MaxIdleConns    int    `json:"max_idle_conns,omitempty" bson:"max_idle_conns,omitempty"`
+	ConnMaxLifetime string `json:"conn_max_lifetime,omitempty" bson:"conn_max_lifetime,omitempty"`
 }
 
 type ConfigCiliumHubble struct {
@@ -55,6 +60,18 @@ type ConfigNetworkPolicy struct {
 	NetPolicyL7Level int `json:"network_policy_l7_level,omitempty" bson:"network_policy_l7_level,omitempty"`
 
 	NetSkipCertVerification bool `json:"skip_cert_verification,omitempty" bson:"skip_cert_verification,omitempty"`
+
+	NetClusterPolicySeed bool `json:"network_policy_cluster_seed,omitempty" bson:"network_policy_cluster_seed,omitempty"`
+
+	NetRuleAgingDays int `json:"network_policy_rule_aging_days,omitempty" bson:"network_policy_rule_aging_days,omitempty"`
+
+	NetPortRangeMinPorts int  `json:"network_policy_port_range_min_ports,omitempty" bson:"network_policy_port_range_min_ports,omitempty"`
+	NetPortRangeMaxGap   int  `json:"network_policy_port_range_max_gap,omitempty" bson:"network_policy_port_range_max_gap,omitempty"`
+	NetNamedPorts        bool `json:"network_policy_named_ports,omitempty" bson:"network_policy_named_ports,omitempty"`
+
+	NetFQDNPatternThreshold int `json:"network_policy_fqdn_pattern_threshold,omitempty" bson:"network_policy_fqdn_pattern_threshold,omitempty"`
+
+	NetClusterWideMinWorkloads int `json:"network_policy_cluster_wide_min_workloads,omitempty" bson:"network_policy_cluster_wide_min_workloads,omitempty"`
 }
 
 type SystemLogFilter struct {
@@ -66,6 +83,20 @@ type SystemLogFilter struct {
 	ProcessDirs    []string `json:"process_dirs,omitempty" bson:"process_dirs,omitempty"`
 }
 
+// NoisePathRule collapses the paths matching a glob or a regex into a directory rule
+type NoisePathRule struct {
+	Glob  string `json:"glob,omitempty" bson:"glob,omitempty"`
+	Regex string `json:"regex,omitempty" bson:"regex,omitempty"`
+	Dir   string `json:"dir,omitempty" bson:"dir,omitempty"`
+}
+
+// NoisePathOverride sets the noise path rules of a namespace
+type NoisePathOverride struct {
+	Namespace     string          `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	Rules         []NoisePathRule `json:"rules,omitempty" bson:"rules,omitempty"`
+	ExcludeGlobal bool            `json:"exclude_global,omitempty" bson:"exclude_global,omitempty" mapstructure:"exclude-global"`
+}
+
 type ConfigSystemPolicy struct {
 	OperationMode           int `json:"operation_mode,omitempty" bson:"operation_mode,omitempty"`
 	OperationTrigger        int
@@ -77,6 +108,7 @@ type ConfigSystemPolicy struct {
 	SystemLogFile   string `json:"system_log_file,omitempty" bson:"system_log_file,omitempty"`
 	SystemPolicyTo  string `json:"system_policy_to,omitempty" bson:"system_policy_to,omitempty"`
 	SystemPolicyDir string `json:"system_policy_dir,omitempty" bson:"system_policy_dir,omitempty"`
+	HostPolicyDir   string `json:"host_policy_dir,omitempty" bson:"host_policy_dir,omitempty"`
 
 	SysPolicyTypes   int  `json:"system_policy_types,omitempty" bson:"system_policy_types,omitempty"`
 	DeprecateOldMode bool `json:"deprecate_old_mode,omitempty" bson:"deprecate_old_mode,omitempty"`
@@ -85,6 +117,42 @@ type ConfigSystemPolicy struct {
 
 	ProcessFromSource bool `json:"system_policy_proc_fromsource,omitempty" bson:"system_policy_proc_fromsource,omitempty"`
 	FileFromSource    bool `json:"system_policy_file_fromsource,omitempty" bson:"system_policy_file_fromsource,omitempty"`
+
+	LearningMinObservationTime string `json:"learning_min_observation_time,omitempty" bson:"learning_min_observation_time,omitempty"`
+	LearningStableIntervals    int    `json:"learning_stable_intervals,omitempty" bson:"learning_stable_intervals,omitempty"`
+	LearningCandidateIntervals int    `json:"learning_candidate_intervals,omitempty" bson:"learning_candidate_intervals,omitempty"`
+
+	DefaultDenyAudit bool `json:"default_deny_audit,omitempty" bson:"default_deny_audit,omitempty"`
+
+	SysClusterPolicySeed bool `json:"system_policy_cluster_seed,omitempty" bson:"system_policy_cluster_seed,omitempty"`
+
+	SysRuleAgingDays int `json:"system_policy_rule_aging_days,omitempty" bson:"system_policy_rule_aging_days,omitempty"`
+
+	NoisePathRules     []NoisePathRule     `json:"noise_path_rules,omitempty" bson:"noise_path_rules,omitempty"`
+	NoisePathOverrides []NoisePathOverride `json:"noise_path_overrides,omitempty" bson:"noise_path_overrides,omitempty"`
+}
+
+// LabelPolicyOverride sets the label policy of a namespace, the lists not set are taken from the global policy
+type LabelPolicyOverride struct {
+	Namespace string   `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	Priority  []string `json:"priority,omitempty" bson:"priority,omitempty"`
+	Allow     []string `json:"allow,omitempty" bson:"allow,omitempty"`
+	Deny      []string `json:"deny,omitempty" bson:"deny,omitempty"`
+	Ignore    []string `json:"ignore,omitempty" bson:"ignore,omitempty"`
+}
+
+// ConfigLabelPolicy selects the pod labels used in the selectors and the workload keys
+type ConfigLabelPolicy struct {
+	// the label keys identifying the app, the first one a pod has is its only label
+	Priority []string `json:"priority,omitempty" bson:"priority,omitempty"`
+	// the label keys used, all of them if empty
+	Allow []string `json:"allow,omitempty" bson:"allow,omitempty"`
+	// the label keys never used
+	Deny []string `json:"deny,omitempty" bson:"deny,omitempty"`
+	// the volatile label keys changing across the replicas and rollouts
+	Ignore []string `json:"ignore,omitempty" bson:"ignore,omitempty"`
+
+	Overrides []LabelPolicyOverride `json:"overrides,omitempty" bson:"overrides,omitempty"`
 }
 
 type ConfigClusterMgmt struct {
@@ -92,12 +160,62 @@ type ConfigClusterMgmt struct {
 	ClusterMgmtURL  string `json:"cluster_mgmt_url,omitempty" bson:"cluster_mgmt_url,omitempty"`
 }
 
+// NamespaceMaxRows caps the number of observability log rows of a namespace
+type NamespaceMaxRows struct {
+	Namespace string `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	MaxRows   int    `json:"max_rows,omitempty" bson:"max_rows,omitempty" mapstructure:"max-rows"`
+}
+
+type ConfigObsRetention struct {
+	Enable              bool               `json:"enable,omitempty" bson:"enable,omitempty"`
+	CronJobTimeInterval string             `json:"cronjob_time_interval,omitempty" bson:"cronjob_time_interval,omitempty"`
+	MaxAge              string             `json:"max_age,omitempty" bson:"max_age,omitempty"`
+	MaxRows             int                `json:"max_rows,omitempty" bson:"max_rows,omitempty"`
+	NamespaceMaxRows    []NamespaceMaxRows `json:"namespace_max_rows,omitempty" bson:"namespace_max_rows,omitempty"`
+	HourlyRollupAfter   string             `json:"hourly_rollup_after,omitempty" bson:"hourly_rollup_after,omitempty"`
+	DailyRollupAfter    string             `json:"daily_rollup_after,omitempty" bson:"daily_rollup_after,omitempty"`
+	Vacuum              bool               `json:"vacuum,omitempty" bson:"vacuum,omitempty"`
+}
+
+type ConfigObsAnomaly struct {
+	Enable bool `json:"enable,omitempty" bson:"enable,omitempty"`
+	// kafka|pulsar, empty to only log and stream the alerts
+	PublisherDriver  string   `json:"publisher_driver,omitempty" bson:"publisher_driver,omitempty"`
+	PublisherServers []string `json:"publisher_servers,omitempty" bson:"publisher_servers,omitempty"`
+	PublisherTopic   string   `json:"publisher_topic,omitempty" bson:"publisher_topic,omitempty"`
+}
+
 type ConfigObservability struct {
-	Enable              bool   `json:"enable,omitempty" bson:"enable,omitempty"`
-	CronJobTimeInterval string `json:"cronjob_time_interval,omitempty" bson:"cronjob_time_interval,omitempty"`
-	DBName              string `json:"db_name,omitempty" bson:"db_name,omitempty"`
-	SysObservability    bool   `json:"sys_observability,omitempty" bson:"sys_observability,omitempty"`
-	NetObservability    bool   `json:"net_observability,omitempty" bson:"net_observability,omitempty"`
+	Enable              bool               `json:"enable,omitempty" bson:"enable,omitempty"`
+	CronJobTimeInterval string             `json:"cronjob_time_interval,omitempty" bson:"cronjob_time_interval,omitempty"`
+	DBName              string             `json:"db_name,omitempty" bson:"db_name,omitempty"`
+	ExportDir           string             `json:"export_dir,omitempty" bson:"export_dir,omitempty"`
+	SysObservability    bool               `json:"sys_observability,omitempty" bson:"sys_observability,omitempty"`
+	NetObservability    bool               `json:"net_observability,omitempty" bson:"net_observability,omitempty"`
+	Retention           ConfigObsRetention `json:"retention,omitempty" bson:"retention,omitempty"`
+	Database            ConfigDB           `json:"database,omitempty" bson:"database,omitempty"`
+	Anomaly             ConfigObsAnomaly   `json:"anomaly,omitempty" bson:"anomaly,omitempty"`
+}
+
+// ConfigTelemetry exports the policy discovery events and observability summaries to an OTLP collector
+type ConfigTelemetry struct {
+	Enable bool `json:"enable,omitempty" bson:"enable,omitempty"`
+	// OTLP/HTTP endpoint of the collector, e.g. http://otel-collector:4318
+	Endpoint           string            `json:"endpoint,omitempty" bson:"endpoint,omitempty"`
+	Headers            map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	ServiceName        string            `json:"service_name,omitempty" bson:"service_name,omitempty"`
+	ExportTimeInterval string            `json:"export_time_interval,omitempty" bson:"export_time_interval,omitempty"`
+}
+
+// PolicyTemplate sets the posture of the policies selected by namespace and labels
+type PolicyTemplate struct {
+	Namespace string   `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	Labels    []string `json:"labels,omitempty" bson:"labels,omitempty"`
+
+	Action   string   `json:"action,omitempty" bson:"action,omitempty"` // Allow|Audit|Block
+	Severity int      `json:"severity,omitempty" bson:"severity,omitempty"`
+	Tags     []string `json:"tags,omitempty" bson:"tags,omitempty"`
+	Message  string   `json:"message,omitempty" bson:"message,omitempty"`
 }
 
 type Configuration struct {
@@ -111,5 +229,9 @@ type Configuration struct {
 	ConfigNetPolicy     ConfigNetworkPolicy `json:"config_network_policy,omitempty" bson:"config_network_policy,omitempty"`
 	ConfigSysPolicy     ConfigSystemPolicy  `json:"config_system_policy,omitempty" bson:"config_system_policy,omitempty"`
 	ConfigClusterMgmt   ConfigClusterMgmt   `json:"config_cluster_mgmt,omitempty" bson:"config_cluster_mgmt,omitempty"`
+	ConfigLabelPolicy   ConfigLabelPolicy   `json:"config_label_policy,omitempty" bson:"config_label_policy,omitempty"`
 	ConfigObservability ConfigObservability `json:"config_observability,omitempty" bson:"config_observability,omitempty"`
+	ConfigTelemetry     ConfigTelemetry     `json:"config_telemetry,omitempty" bson:"config_telemetry,omitempty"`
+
+	ConfigPolicyTemplates []PolicyTemplate `json:"config_policy_templates,omitempty" bson:"config_policy_templates,omitempty"`
 }
@@ -5,6 +5,9 @@ const (
 	PolicyDiscoveryVMNamespace = "accuknox-vm-namespace"
 	PolicyDiscoveryVMPodName   = "accuknox-vm-podname"
 
+	// KubeArmor k8s node
+	PolicyDiscoveryNodeNamespace = "accuknox-node-namespace"
+
 	// KubeArmor container
 	PolicyDiscoveryContainerNamespace = "container_namespace"
 	PolicyDiscoveryContainerPodName   = "container_podname"
@@ -14,6 +17,14 @@ const (
 )
 
 const (
-	KindKnoxNetworkPolicy     = "KnoxNetworkPolicy"
-	KindKnoxHostNetworkPolicy = "KnoxHostNetworkPolicy"
+	KindKnoxNetworkPolicy        = "KnoxNetworkPolicy"
+	KindKnoxHostNetworkPolicy    = "KnoxHostNetworkPolicy"
+	KindKnoxClusterNetworkPolicy = "KnoxClusterNetworkPolicy"
+)
+
+const (
+	// system policy learning states of a workload
+	LearningStateLearning          = "learning"
+	LearningStateStable            = "stable"
+	LearningStateEnforcedCandidate = "enforced-candidate"
 )
@@ -100,3 +100,25 @@ type WorkloadProcessFileSet struct {
 
 type PolicyNameMap map[WorkloadProcessFileSet]string
 type ResourceSetMap map[WorkloadProcessFileSet][]string
+
+// WorkloadLearningState = learning progress of a workload (clusterName, namespace, containerName, labels) across all its WPFS sets.
+type WorkloadLearningState struct {
+	ClusterName     string
+	ContainerName   string
+	Namespace       string
+	Labels          string // comma separated list of pod labels
+	State           string // learning, stable or enforced-candidate
+	StableIntervals int    // number of consecutive discovery intervals without new resources
+	FirstSeenTime   int64
+	LastChangeTime  int64
+	UpdatedTime     int64
+}
+
+// NoisePathCollapse Structure
+type NoisePathCollapse struct {
+	Namespace string   `json:"namespace,omitempty"`
+	Rule      string   `json:"rule,omitempty"`
+	Dir       string   `json:"dir,omitempty"`
+	Count     int      `json:"count,omitempty"` // number of collapsed path accesses
+	Samples   []string `json:"samples,omitempty"`
+}
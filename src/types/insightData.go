@@ -11,6 +11,20 @@ type InsightRequest struct {
 	Duration      string
 	Type          string
 	Rule          string
+	Since         int64
+	Until         int64
+	Limit         int
+	PageToken     string
+	SortOrder     string
+}
+
+// InsightQuery the time range and the page of the records of an insight, ordered by their key columns
+type InsightQuery struct {
+	Since     int64
+	Until     int64
+	Limit     int
+	Offset    int
+	SortOrder string
 }
 
 type SystemData struct {
@@ -26,8 +40,50 @@ type SysInsightData struct {
 	Labels             string       `json:"labels,omitempty"`
 	ContainerName      string       `json:"containername,omitempty"`
 	SysProcessFileData []SystemData `json:"system-resources,omitempty"`
+	LearningState      string       `json:"learning-state,omitempty"`
+	StableIntervals    int          `json:"stable-intervals,omitempty"`
 }
 
 type SysInsightResponseData struct {
 	SysData []SysInsightData
 }
+
+type ServiceGraphRequest struct {
+	ClusterName string
+	Namespace   string
+	Labels      string
+	Format      string
+	Export      bool
+}
+
+type ServiceGraphNode struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Labels    string `json:"labels,omitempty"`
+}
+
+type ServiceGraphEdge struct {
+	Source    string   `json:"source"`
+	Target    string   `json:"target"`
+	Protocol  string   `json:"protocol,omitempty"`
+	Port      string   `json:"port,omitempty"`
+	L7        string   `json:"l7,omitempty"`
+	FlowCount int64    `json:"flow-count"`
+	DropCount int64    `json:"drop-count,omitempty"`
+	LastSeen  int64    `json:"last-seen,omitempty"`
+	Policies  []string `json:"policies,omitempty"`
+}
+
+type ServiceGraph struct {
+	Nodes []ServiceGraphNode `json:"nodes"`
+	Edges []ServiceGraphEdge `json:"edges"`
+}
+
+type RuleEvidenceRequest struct {
+	PolicyType  string
+	ClusterName string
+	Namespace   string
+	PolicyName  string
+}
@@ -36,10 +36,31 @@ type Service struct {
 	Selector map[string]string `json:"selector" bson:"selector"`
 }
 
+// Node Structure
+type Node struct {
+	NodeName string   `json:"node_name" bson:"node_name"`
+	Labels   []string `json:"labels" bson:"labels"`
+}
+
+// ContainerPort Structure
+type ContainerPort struct {
+	Name     string `json:"name" bson:"name"`
+	Port     int    `json:"port" bson:"port"`
+	Protocol string `json:"protocol" bson:"protocol"`
+}
+
 // Pod Structure
 type Pod struct {
 	Namespace string   `json:"namespace" bson:"namespace"`
 	PodName   string   `json:"pod_name" bson:"pod_name"`
 	Labels    []string `json:"labels" bson:"labels"`
 	PodIP     string   `json:"pod_ip" bson:"pod_ip"`
+
+	// owning workload (Deployment/StatefulSet/DaemonSet/Job) and its selector labels
+	WorkloadKind   string   `json:"workload_kind,omitempty" bson:"workload_kind,omitempty"`
+	WorkloadName   string   `json:"workload_name,omitempty" bson:"workload_name,omitempty"`
+	WorkloadLabels []string `json:"workload_labels,omitempty" bson:"workload_labels,omitempty"`
+
+	// the named ports of the containers
+	NamedPorts []ContainerPort `json:"named_ports,omitempty" bson:"named_ports,omitempty"`
 }
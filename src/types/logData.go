@@ -38,6 +38,9 @@ type KnoxNetworkLog struct {
 	Direction string `json:"direction,omitempty" bson:"direction"` // ingress or egress
 
 	Action string `json:"action,omitempty" bson:"action"`
+
+	// unix time of the flow, 0 if unknown
+	Timestamp int64 `json:"timestamp,omitempty" bson:"timestamp"`
 }
 
 // KnoxSystemLog Structure
@@ -63,4 +66,7 @@ type KnoxSystemLog struct {
 	ReadOnly bool `json:"read_only,omitempty"`
 
 	Result string `json:"result,omitempty"`
+
+	// unix time of the log, 0 if unknown
+	Timestamp int64 `json:"timestamp,omitempty"`
 }
@@ -138,3 +138,49 @@ type SysNwConnDetail struct {
 	AddFamily string `json:"AddFamily,omitempty"`
 	Path      string `json:"Path,omitempty"`
 }
+
+// LogQuery - Time range, page and sort order of an observability log query
+type LogQuery struct {
+	Since     int64  `json:"since,omitempty"`
+	Until     int64  `json:"until,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+	PageToken string `json:"page_token,omitempty"`
+	SortBy    string `json:"sort_by,omitempty"`
+	SortOrder string `json:"sort_order,omitempty"`
+}
+
+// LogExportRequest - The format, filters and directory of an export of the observability logs
+type LogExportRequest struct {
+	// csv/parquet
+	Format string `json:"format,omitempty"`
+	// system/network, empty for both
+	Type      string `json:"type,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Labels    string `json:"labels,omitempty"`
+	Since     int64  `json:"since,omitempty"`
+	Until     int64  `json:"until,omitempty"`
+	Dir       string `json:"dir,omitempty"`
+}
+
+// LogExportFile - A file written by an export of the observability logs
+type LogExportFile struct {
+	Type string `json:"type,omitempty"`
+	Path string `json:"path,omitempty"`
+	Rows int64  `json:"rows,omitempty"`
+}
+
+// AnomalyAlert - A new behavior of a workload not covered by its learned WPFS sets or discovered policies
+type AnomalyAlert struct {
+	Type          string `json:"type,omitempty"`
+	ClusterName   string `json:"cluster_name,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	PodName       string `json:"pod_name,omitempty"`
+	ContainerName string `json:"container_name,omitempty"`
+	Labels        string `json:"labels,omitempty"`
+	Source        string `json:"source,omitempty"`
+	Resource      string `json:"resource,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+	Port          uint32 `json:"port,omitempty"`
+	Message       string `json:"message,omitempty"`
+	Time          int64  `json:"time,omitempty"`
+}
@@ -1,5 +1,7 @@
 package types
 
+import "encoding/json"
+
 // ========================= //
 // == Knox Network Policy == //
 // ========================= //
@@ -24,10 +26,11 @@ func (x SpecICMP) Equal(y SpecICMP) bool {
 type SpecPort struct {
 	Port     string `json:"port,omitempty" yaml:"port,omitempty" bson:"port,omitempty"`
 	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty" bson:"protocol,omitempty"`
+	EndPort  int    `json:"endPort,omitempty" yaml:"endPort,omitempty" bson:"endPort,omitempty"`
 }
 
 func (x SpecPort) Equal(y SpecPort) bool {
-	return x.Port == y.Port && x.Protocol == y.Protocol
+	return x.Port == y.Port && x.Protocol == y.Protocol && x.EndPort == y.EndPort
 }
 
 // SpecService Structure
@@ -38,7 +41,8 @@ type SpecService struct {
 
 // SpecFQDN Structure
 type SpecFQDN struct {
-	MatchNames []string `json:"matchNames,omitempty" yaml:"matchNames,omitempty" bson:"matchNames,omitempty"`
+	MatchNames    []string `json:"matchNames,omitempty" yaml:"matchNames,omitempty" bson:"matchNames,omitempty"`
+	MatchPatterns []string `json:"matchPatterns,omitempty" yaml:"matchPatterns,omitempty" bson:"matchPatterns,omitempty"`
 }
 
 // SpecHTTP Structure
@@ -50,7 +54,15 @@ type SpecHTTP struct {
 
 // Selector Structure
 type Selector struct {
-	MatchLabels map[string]string `json:"matchLabels,omitempty" yaml:"matchLabels,omitempty" bson:"matchLabels,omitempty"`
+	MatchLabels      map[string]string `json:"matchLabels,omitempty" yaml:"matchLabels,omitempty" bson:"matchLabels,omitempty"`
+	MatchExpressions []MatchExpression `json:"matchExpressions,omitempty" yaml:"matchExpressions,omitempty" bson:"matchExpressions,omitempty"`
+}
+
+// MatchExpression Structure
+type MatchExpression struct {
+	Key      string   `json:"key" yaml:"key" bson:"key"`
+	Operator string   `json:"operator" yaml:"operator" bson:"operator"`
+	Values   []string `json:"values,omitempty" yaml:"values,omitempty" bson:"values,omitempty"`
 }
 
 // Ingress Structure
@@ -129,6 +141,9 @@ type KnoxNetworkPolicy struct {
 
 	GeneratedTime int64 `json:"generatedTime,omitempty" yaml:"generatedTime,omitempty" bson:"generatedTime,omitempty"`
 	UpdatedTime   int64 `json:"updatedTime,omitempty" yaml:"updatedTime,omitempty" bson:"updatedTime,omitempty"`
+
+	// the observations of the rules by rule key, stored with the policy but not part of it
+	RuleStats map[string]RuleStat `json:"-" yaml:"-" bson:"-"`
 }
 
 // =========================== //
@@ -144,6 +159,7 @@ type CiliumCIDRSet struct {
 // CiliumPort Structure
 type CiliumPort struct {
 	Port     string `json:"port,omitempty" yaml:"port,omitempty"`
+	EndPort  int    `json:"endPort,omitempty" yaml:"endPort,omitempty"`
 	Protocol string `json:"protocol" yaml:"protocol"`
 }
 
@@ -206,13 +222,25 @@ type CiliumIngress struct {
 	FromEntities  []string         `json:"fromEntities,omitempty" yaml:"fromEntities,omitempty"`
 }
 
+// CiliumPolicyLabel Structure
+type CiliumPolicyLabel struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value,omitempty" yaml:"value,omitempty"`
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
 // CiliumSpec Structure
 type CiliumSpec struct {
 	NodeSelector     Selector `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
 	EndpointSelector Selector `json:"endpointSelector,omitempty" yaml:"endpointSelector,omitempty"`
 
-	Egress  []CiliumEgress  `json:"egress,omitempty" yaml:"egress,omitempty"`
-	Ingress []CiliumIngress `json:"ingress,omitempty" yaml:"ingress,omitempty"`
+	Description string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Labels      []CiliumPolicyLabel `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	Egress      []CiliumEgress  `json:"egress,omitempty" yaml:"egress,omitempty"`
+	Ingress     []CiliumIngress `json:"ingress,omitempty" yaml:"ingress,omitempty"`
+	EgressDeny  []CiliumEgress  `json:"egressDeny,omitempty" yaml:"egressDeny,omitempty"`
+	IngressDeny []CiliumIngress `json:"ingressDeny,omitempty" yaml:"ingressDeny,omitempty"`
 }
 
 // CiliumNetworkPolicy Structure
@@ -273,7 +301,8 @@ type KnoxSystemSpec struct {
 	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
 	Message  string   `json:"message,omitempty" yaml:"message,omitempty"`
 
-	Selector Selector `json:"selector,omitempty" yaml:"selector,omitempty"`
+	Selector     Selector  `json:"selector,omitempty" yaml:"selector,omitempty"`
+	NodeSelector *Selector `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
 
 	Process KnoxSys     `json:"process,omitempty" yaml:"process,omitempty"`
 	File    KnoxSys     `json:"file,omitempty" yaml:"file,omitempty"`
@@ -282,6 +311,22 @@ type KnoxSystemSpec struct {
 	Action string `json:"action,omitempty" yaml:"action,omitempty"`
 }
 
+// MarshalJSON omits the empty selector, a host policy selects the nodes by its nodeSelector only
+func (spec KnoxSystemSpec) MarshalJSON() ([]byte, error) {
+	type knoxSystemSpec KnoxSystemSpec
+
+	aux := struct {
+		knoxSystemSpec
+		Selector *Selector `json:"selector,omitempty"`
+	}{knoxSystemSpec: knoxSystemSpec(spec)}
+
+	if len(spec.Selector.MatchLabels) > 0 || len(spec.Selector.MatchExpressions) > 0 {
+		aux.Selector = &spec.Selector
+	}
+
+	return json.Marshal(aux)
+}
+
 // KnoxSystemPolicy Structure
 type KnoxSystemPolicy struct {
 	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty" bson:"apiVersion,omitempty"`
@@ -295,6 +340,9 @@ type KnoxSystemPolicy struct {
 	GeneratedTime int64 `json:"generatedTime,omitempty" yaml:"generatedTime,omitempty" bson:"generatedTime,omitempty"`
 	UpdatedTime   int64 `json:"updatedTime,omitempty" yaml:"updatedTime,omitempty" bson:"updatedTime,omitempty"`
 	Latest        bool  `json:"latest,omitempty" yaml:"latest,omitempty" bson:"latest,omitempty"`
+
+	// the observations of the rules by rule key, stored with the policy but not part of it
+	RuleStats map[string]RuleStat `json:"-" yaml:"-" bson:"-"`
 }
 
 // ============================= //
@@ -309,3 +357,84 @@ type KubeArmorPolicy struct {
 
 	Spec KnoxSystemSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
 }
+
+// =================== //
+// == Policy Review == //
+// =================== //
+
+// PolicyReview Structure for a transition of the review state of a discovered policy
+type PolicyReview struct {
+	// network/system
+	PolicyType  string `json:"policy_type,omitempty"`
+	ClusterName string `json:"cluster_name,omitempty"`
+	Name        string `json:"name,omitempty"`
+	State       string `json:"state,omitempty"`
+	Reviewer    string `json:"reviewer,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+	// the state before the transition, set by the update
+	PrevState    string `json:"prev_state,omitempty"`
+	ReviewedTime int64  `json:"reviewed_time,omitempty"`
+}
+
+// PolicyApplyResult Structure for a policy object created, updated or rolled back in the cluster
+type PolicyApplyResult struct {
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// created/updated/unchanged/rolledback
+	Action          string `json:"action,omitempty"`
+	DryRun          bool   `json:"dry_run,omitempty"`
+	ResourceVersion string `json:"resource_version,omitempty"`
+	// the objects of the previous versions deleted by the apply
+	Replaced []string `json:"replaced,omitempty"`
+}
+
+// ================ //
+// == Rule Aging == //
+// ================ //
+
+// RuleStat Structure for the observations of a rule of a policy
+type RuleStat struct {
+	FirstSeen int64 `json:"first_seen,omitempty"`
+	LastSeen  int64 `json:"last_seen,omitempty"`
+	// the number of discoveries observing the rule
+	Hits int64 `json:"hits,omitempty"`
+}
+
+// PolicyChange Structure for a change record of a policy replaced by a new version
+type PolicyChange struct {
+	// network/system
+	PolicyType    string `json:"policy_type,omitempty"`
+	ClusterName   string `json:"cluster_name,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	Name          string `json:"name,omitempty"`
+	NewPolicyName string `json:"new_policy_name,omitempty"`
+	// aged
+	Reason       string   `json:"reason,omitempty"`
+	RemovedRules []string `json:"removed_rules,omitempty"`
+	ChangedTime  int64    `json:"changed_time,omitempty"`
+}
+
+// =================== //
+// == Rule Evidence == //
+// =================== //
+
+// RuleEvidence Structure for the logs observed for a rule of a workload
+type RuleEvidence struct {
+	// network/system
+	PolicyType  string `json:"policy_type,omitempty"`
+	ClusterName string `json:"cluster_name,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	// the sorted labels of the workload, e.g. "app=web,version=v1"
+	Labels  string `json:"labels,omitempty"`
+	RuleKey string `json:"rule_key,omitempty"`
+	// the observed rule, the egress/ingress rule in json or the process/file path
+	Rule      string `json:"rule,omitempty"`
+	FirstSeen int64  `json:"first_seen,omitempty"`
+	LastSeen  int64  `json:"last_seen,omitempty"`
+	Count     int64  `json:"count,omitempty"`
+	// the samples of the observed pods and logs
+	SrcPods   []string `json:"src_pods,omitempty"`
+	DstPods   []string `json:"dst_pods,omitempty"`
+	SampleLog string   `json:"sample_log,omitempty"`
+}
@@ -0,0 +1,13 @@
+package types
+
+// SchemaMigration Structure for the status of a schema migration
+type SchemaMigration struct {
+	Schema      string   `json:"schema,omitempty" bson:"schema,omitempty"`
+	Version     int      `json:"version,omitempty" bson:"version,omitempty"`
+	Description string   `json:"description,omitempty" bson:"description,omitempty"`
+	Statements  []string `json:"statements,omitempty" bson:"statements,omitempty"`
+	// the rows are rewritten by the migration, besides the statements
+	Data        bool  `json:"data,omitempty" bson:"data,omitempty"`
+	Applied     bool  `json:"applied,omitempty" bson:"applied,omitempty"`
+	AppliedTime int64 `json:"applied_time,omitempty" bson:"applied_time,omitempty"`
+}